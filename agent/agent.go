@@ -0,0 +1,404 @@
+// Package agent runs tool-calling conversations: it calls the model, executes the
+// tools it asks for, feeds the results back, and repeats until the model answers.
+//
+//	type weatherArgs struct {
+//		Location string `json:"location"`
+//	}
+//
+//	weather := agent.NewTool("get_weather", "Get the current weather for a location", weatherParams,
+//		func(ctx context.Context, args weatherArgs) (string, error) {
+//			return lookupWeather(ctx, args.Location)
+//		},
+//	)
+//
+//	runner, err := agent.New(provider, []agent.Tool{weather})
+//	result, err := runner.Run(ctx, providers.CompletionParams{
+//		Model:    "gpt-4o-mini",
+//		Messages: []providers.Message{{Role: providers.RoleUser, Content: "Weather in Paris?"}},
+//	})
+//	fmt.Println(result.Content())
+//
+// The runner only relies on the normalized Provider interface, so it behaves the same
+// with every provider that supports tool calling.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"sync"
+
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// Default runner settings.
+const (
+	defaultMaxIterations = 10
+	toolTypeFunction     = "function"
+)
+
+// ErrMaxIterations is returned when the model is still calling tools after the
+// maximum number of iterations.
+var ErrMaxIterations = stderrors.New("agent: maximum iterations reached")
+
+// ErrorFormatter converts a failed tool call into the content of its tool result message.
+type ErrorFormatter func(call providers.ToolCall, err error) string
+
+// HandlerFunc executes a tool call with its raw JSON arguments and returns the tool result.
+type HandlerFunc func(ctx context.Context, arguments string) (string, error)
+
+// Option configures a Runner.
+type Option func(*options) error
+
+// Result is the outcome of a Run.
+type Result struct {
+	// Messages is the full conversation, including the caller's messages, every
+	// assistant message, and every tool result.
+	Messages []providers.Message
+
+	// Response is the last completion returned by the model.
+	Response *providers.ChatCompletion
+
+	// Steps records each model call and the tools executed for it.
+	Steps []Step
+
+	// Usage is the token usage summed across all model calls.
+	Usage providers.Usage
+}
+
+// Runner drives a tool-calling conversation against a provider.
+type Runner struct {
+	opts     *options
+	provider providers.Provider
+	tools    map[string]Tool
+	// toolDefs holds the tool definitions in registration order.
+	toolDefs []providers.Tool
+}
+
+// Step is a single model call and the tool calls executed in response to it.
+type Step struct {
+	// Iteration is the 1-based index of the model call.
+	Iteration int
+
+	// Response is the completion returned by the model.
+	Response *providers.ChatCompletion
+
+	// ToolResults holds the executed tool calls, in the order the model requested them.
+	ToolResults []ToolResult
+}
+
+// StepFunc is called after each step. Returning an error stops the run with that error.
+type StepFunc func(ctx context.Context, step Step) error
+
+// Tool is a tool definition together with the Go handler that executes it.
+type Tool struct {
+	Definition providers.Tool
+	Handler    HandlerFunc
+}
+
+// ToolResult is the outcome of a single tool call.
+type ToolResult struct {
+	// Call is the tool call requested by the model.
+	Call providers.ToolCall
+
+	// Content is the tool result sent back to the model. When Err is set it holds
+	// the formatted error instead.
+	Content string
+
+	// Err is the error returned by the handler, if any.
+	Err error
+}
+
+// options holds the runner configuration.
+type options struct {
+	errorFormatter ErrorFormatter
+	maxIterations  int
+	onStep         StepFunc
+	parallel       bool
+}
+
+// New creates a Runner that can execute the given tools.
+func New(provider providers.Provider, tools []Tool, opts ...Option) (*Runner, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("provider cannot be nil")
+	}
+
+	r := &Runner{
+		opts: &options{
+			errorFormatter: defaultErrorFormatter,
+			maxIterations:  defaultMaxIterations,
+			parallel:       true,
+		},
+		provider: provider,
+		tools:    make(map[string]Tool, len(tools)),
+		toolDefs: make([]providers.Tool, 0, len(tools)),
+	}
+
+	for _, tool := range tools {
+		name := tool.Definition.Function.Name
+		if name == "" {
+			return nil, fmt.Errorf("tool name cannot be empty")
+		}
+		if tool.Handler == nil {
+			return nil, fmt.Errorf("tool %q: handler cannot be nil", name)
+		}
+		if _, exists := r.tools[name]; exists {
+			return nil, fmt.Errorf("tool %q registered twice", name)
+		}
+		if tool.Definition.Type == "" {
+			tool.Definition.Type = toolTypeFunction
+		}
+
+		r.tools[name] = tool
+		r.toolDefs = append(r.toolDefs, tool.Definition)
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(r.opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// NewTool creates a Tool from a typed Go function.
+// The model's arguments are decoded from JSON into Args before fn is called. A string
+// result is sent to the model as-is; any other result is encoded as JSON.
+func NewTool[Args, Result any](
+	name string,
+	description string,
+	parameters map[string]any,
+	fn func(ctx context.Context, args Args) (Result, error),
+) Tool {
+	return Tool{
+		Definition: providers.Tool{
+			Type: toolTypeFunction,
+			Function: providers.Function{
+				Name:        name,
+				Description: description,
+				Parameters:  parameters,
+			},
+		},
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var args Args
+			if arguments != "" {
+				if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+
+			result, err := fn(ctx, args)
+			if err != nil {
+				return "", err
+			}
+
+			return encodeResult(result)
+		},
+	}
+}
+
+// WithErrorFormatter sets how handler errors are reported back to the model.
+// The default sends "error: " followed by the error message.
+func WithErrorFormatter(fn ErrorFormatter) Option {
+	return func(o *options) error {
+		if fn == nil {
+			return fmt.Errorf("error formatter cannot be nil")
+		}
+
+		o.errorFormatter = fn
+		return nil
+	}
+}
+
+// WithMaxIterations sets the maximum number of model calls in a single run.
+func WithMaxIterations(n int) Option {
+	return func(o *options) error {
+		if n < 1 {
+			return fmt.Errorf("max iterations must be at least 1, got %d", n)
+		}
+
+		o.maxIterations = n
+		return nil
+	}
+}
+
+// WithOnStep registers a callback invoked after each step, e.g. for logging or tracing.
+func WithOnStep(fn StepFunc) Option {
+	return func(o *options) error {
+		if fn == nil {
+			return fmt.Errorf("step callback cannot be nil")
+		}
+
+		o.onStep = fn
+		return nil
+	}
+}
+
+// WithParallelToolCalls sets whether the tool calls of a single step run concurrently.
+// Enabled by default.
+func WithParallelToolCalls(parallel bool) Option {
+	return func(o *options) error {
+		o.parallel = parallel
+		return nil
+	}
+}
+
+// Content returns the text content of the final response, or "" if there is none.
+func (r *Result) Content() string {
+	if r.Response == nil || len(r.Response.Choices) == 0 {
+		return ""
+	}
+
+	content, _ := r.Response.Choices[0].Message.Content.(string)
+	return content
+}
+
+// Run calls the model with params, executing requested tools and sending their results back
+// until the model stops calling tools.
+//
+// The runner's tools are appended to params.Tools. The caller's messages are not modified.
+// If the model is still calling tools after the maximum number of iterations, the result so
+// far is returned together with ErrMaxIterations.
+func (r *Runner) Run(ctx context.Context, params providers.CompletionParams) (*Result, error) {
+	params.Messages = append([]providers.Message(nil), params.Messages...)
+	params.Tools = append(append([]providers.Tool(nil), params.Tools...), r.toolDefs...)
+
+	result := &Result{}
+	for iteration := 1; iteration <= r.opts.maxIterations; iteration++ {
+		resp, err := r.provider.Completion(ctx, params)
+		if err != nil {
+			result.Messages = params.Messages
+			return result, err
+		}
+		if len(resp.Choices) == 0 {
+			result.Messages = params.Messages
+			return result, fmt.Errorf("agent: provider %s returned no choices", r.provider.Name())
+		}
+
+		result.Response = resp
+		addUsage(&result.Usage, resp.Usage)
+
+		choice := resp.Choices[0]
+		params.Messages = append(params.Messages, choice.Message)
+
+		step := Step{Iteration: iteration, Response: resp}
+		done := choice.FinishReason != providers.FinishReasonToolCalls || len(choice.Message.ToolCalls) == 0
+		if !done {
+			step.ToolResults = r.executeTools(ctx, choice.Message.ToolCalls)
+			for _, tr := range step.ToolResults {
+				params.Messages = append(params.Messages, providers.Message{
+					Role:       providers.RoleTool,
+					Content:    tr.Content,
+					ToolCallID: tr.Call.ID,
+				})
+			}
+		}
+
+		result.Steps = append(result.Steps, step)
+		result.Messages = params.Messages
+
+		if r.opts.onStep != nil {
+			if err := r.opts.onStep(ctx, step); err != nil {
+				return result, err
+			}
+		}
+		if done {
+			return result, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, ErrMaxIterations
+}
+
+// Tools returns the definitions of the runner's tools, in registration order.
+func (r *Runner) Tools() []providers.Tool {
+	return append([]providers.Tool(nil), r.toolDefs...)
+}
+
+// execute runs a single tool call, converting unknown tools, handler errors, and panics
+// into error results.
+func (r *Runner) execute(ctx context.Context, call providers.ToolCall) (result ToolResult) {
+	result.Call = call
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.Err = fmt.Errorf("tool %q panicked: %v", call.Function.Name, recovered)
+		}
+		if result.Err != nil {
+			result.Content = r.opts.errorFormatter(call, result.Err)
+		}
+	}()
+
+	tool, ok := r.tools[call.Function.Name]
+	if !ok {
+		result.Err = fmt.Errorf("unknown tool %q", call.Function.Name)
+		return result
+	}
+
+	result.Content, result.Err = tool.Handler(ctx, call.Function.Arguments)
+	return result
+}
+
+// executeTools runs the tool calls of a step, concurrently if enabled, and returns
+// the results in call order.
+func (r *Runner) executeTools(ctx context.Context, calls []providers.ToolCall) []ToolResult {
+	results := make([]ToolResult, len(calls))
+
+	if !r.opts.parallel || len(calls) == 1 {
+		for i, call := range calls {
+			results[i] = r.execute(ctx, call)
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Go(func() {
+			results[i] = r.execute(ctx, call)
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+// addUsage adds usage to total.
+func addUsage(total *providers.Usage, usage *providers.Usage) {
+	if usage == nil {
+		return
+	}
+
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+	total.ReasoningTokens += usage.ReasoningTokens
+	total.CacheReadTokens += usage.CacheReadTokens
+	total.CacheWriteTokens += usage.CacheWriteTokens
+}
+
+// defaultErrorFormatter reports the error message to the model.
+func defaultErrorFormatter(_ providers.ToolCall, err error) string {
+	return "error: " + err.Error()
+}
+
+// encodeResult converts a handler result into tool result content.
+func encodeResult(result any) (string, error) {
+	if s, ok := result.(string); ok {
+		return s, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("encoding result: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,430 @@
+package agent
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+type weatherArgs struct {
+	Location string `json:"location"`
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires a provider", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("rejects invalid tools", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+
+		_, err := New(provider, []Tool{{Handler: echoHandler}})
+		require.Error(t, err)
+
+		_, err = New(provider, []Tool{{Definition: testutil.WeatherTool()}})
+		require.Error(t, err)
+
+		weather := Tool{Definition: testutil.WeatherTool(), Handler: echoHandler}
+		_, err = New(provider, []Tool{weather, weather})
+		require.Error(t, err)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+
+		_, err := New(provider, nil, WithMaxIterations(0))
+		require.Error(t, err)
+
+		_, err = New(provider, nil, WithErrorFormatter(nil))
+		require.Error(t, err)
+
+		_, err = New(provider, nil, WithOnStep(nil))
+		require.Error(t, err)
+	})
+
+	t.Run("uses defaults", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := New(testutil.NewMockProvider(), nil)
+		require.NoError(t, err)
+		require.Equal(t, defaultMaxIterations, r.opts.maxIterations)
+		require.True(t, r.opts.parallel)
+	})
+}
+
+func TestNewTool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes arguments into the typed struct", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewTool("get_weather", "Get the weather", nil,
+			func(_ context.Context, args weatherArgs) (string, error) {
+				return "sunny in " + args.Location, nil
+			},
+		)
+
+		require.Equal(t, toolTypeFunction, tool.Definition.Type)
+		require.Equal(t, "get_weather", tool.Definition.Function.Name)
+
+		got, err := tool.Handler(context.Background(), `{"location":"Paris"}`)
+		require.NoError(t, err)
+		require.Equal(t, "sunny in Paris", got)
+	})
+
+	t.Run("encodes non-string results as JSON", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewTool("sum", "", nil, func(_ context.Context, args struct{ A, B int }) (map[string]int, error) {
+			return map[string]int{"sum": args.A + args.B}, nil
+		})
+
+		got, err := tool.Handler(context.Background(), `{"A":1,"B":2}`)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"sum":3}`, got)
+	})
+
+	t.Run("treats empty arguments as the zero value", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewTool("now", "", nil, func(_ context.Context, _ struct{}) (string, error) {
+			return "noon", nil
+		})
+
+		got, err := tool.Handler(context.Background(), "")
+		require.NoError(t, err)
+		require.Equal(t, "noon", got)
+	})
+
+	t.Run("reports malformed arguments", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewTool("get_weather", "", nil, func(_ context.Context, _ weatherArgs) (string, error) {
+			return "", nil
+		})
+
+		_, err := tool.Handler(context.Background(), `{"location":`)
+		require.ErrorContains(t, err, "invalid arguments")
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns immediately when no tools are called", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		r, err := New(provider, []Tool{weatherTool(t)})
+		require.NoError(t, err)
+
+		result, err := r.Run(context.Background(), providers.CompletionParams{
+			Model:    "mock-model",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Hello World", result.Content())
+		require.Len(t, result.Steps, 1)
+		require.Empty(t, result.Steps[0].ToolResults)
+		require.Len(t, result.Messages, 2)
+		require.Equal(t, 15, result.Usage.TotalTokens)
+
+		require.Len(t, provider.CompletionCalls, 1)
+		require.Equal(t, []providers.Tool{weatherTool(t).Definition}, provider.CompletionCalls[0].Tools)
+	})
+
+	t.Run("executes tool calls and sends the results back", func(t *testing.T) {
+		t.Parallel()
+
+		provider := scriptedProvider(
+			testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{weatherCall("call_1", "Paris")}),
+			testutil.MockChatCompletion("It is sunny in Paris."),
+		)
+		r, err := New(provider, []Tool{weatherTool(t)})
+		require.NoError(t, err)
+
+		messages := testutil.SimpleMessages()
+		result, err := r.Run(context.Background(), providers.CompletionParams{Model: "mock-model", Messages: messages})
+		require.NoError(t, err)
+		require.Equal(t, "It is sunny in Paris.", result.Content())
+		require.Len(t, messages, 1, "caller's messages must not be modified")
+
+		require.Len(t, provider.CompletionCalls, 2)
+		second := provider.CompletionCalls[1].Messages
+		require.Len(t, second, 3)
+		require.Equal(t, providers.RoleAssistant, second[1].Role)
+		require.Len(t, second[1].ToolCalls, 1)
+		require.Equal(t, providers.Message{
+			Role:       providers.RoleTool,
+			Content:    "sunny in Paris",
+			ToolCallID: "call_1",
+		}, second[2])
+
+		require.Len(t, result.Messages, 4)
+		require.Len(t, result.Steps, 2)
+		require.Equal(t, "sunny in Paris", result.Steps[0].ToolResults[0].Content)
+		require.Equal(t, 30+15, result.Usage.TotalTokens)
+	})
+
+	t.Run("runs tool calls in parallel and keeps their order", func(t *testing.T) {
+		t.Parallel()
+
+		const calls = 3
+
+		var started sync.WaitGroup
+		started.Add(calls)
+		slow := NewTool("get_weather", "", nil, func(_ context.Context, args weatherArgs) (string, error) {
+			// Every call must be running at the same time for this to return.
+			started.Done()
+			started.Wait()
+			return args.Location, nil
+		})
+
+		provider := scriptedProvider(
+			testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{
+				weatherCall("call_1", "Paris"),
+				weatherCall("call_2", "Rome"),
+				weatherCall("call_3", "Oslo"),
+			}),
+			testutil.MockChatCompletion("done"),
+		)
+		r, err := New(provider, []Tool{slow})
+		require.NoError(t, err)
+
+		result := runWithTimeout(t, r)
+		toolResults := result.Steps[0].ToolResults
+		require.Len(t, toolResults, calls)
+		for i, want := range []string{"Paris", "Rome", "Oslo"} {
+			require.Equal(t, want, toolResults[i].Content)
+			require.Equal(t, toolResults[i].Call.ID, result.Messages[2+i].ToolCallID)
+		}
+	})
+
+	t.Run("runs tool calls sequentially when parallelism is disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu      sync.Mutex
+			running int
+			order   []string
+		)
+		tool := NewTool("get_weather", "", nil, func(_ context.Context, args weatherArgs) (string, error) {
+			mu.Lock()
+			running++
+			require.Equal(t, 1, running)
+			order = append(order, args.Location)
+			running--
+			mu.Unlock()
+			return args.Location, nil
+		})
+
+		provider := scriptedProvider(
+			testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{
+				weatherCall("call_1", "Paris"),
+				weatherCall("call_2", "Rome"),
+			}),
+			testutil.MockChatCompletion("done"),
+		)
+		r, err := New(provider, []Tool{tool}, WithParallelToolCalls(false))
+		require.NoError(t, err)
+
+		runWithTimeout(t, r)
+		require.Equal(t, []string{"Paris", "Rome"}, order)
+	})
+
+	t.Run("maps tool failures to tool results", func(t *testing.T) {
+		t.Parallel()
+
+		failing := NewTool("fail", "", nil, func(_ context.Context, _ struct{}) (string, error) {
+			return "", stderrors.New("service unavailable")
+		})
+		panicking := NewTool("panic", "", nil, func(_ context.Context, _ struct{}) (string, error) {
+			panic("boom")
+		})
+
+		provider := scriptedProvider(
+			testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{
+				{ID: "call_1", Type: "function", Function: providers.FunctionCall{Name: "fail", Arguments: `{}`}},
+				{ID: "call_2", Type: "function", Function: providers.FunctionCall{Name: "panic", Arguments: `{}`}},
+				{ID: "call_3", Type: "function", Function: providers.FunctionCall{Name: "missing", Arguments: `{}`}},
+				{ID: "call_4", Type: "function", Function: providers.FunctionCall{Name: "get_weather", Arguments: `{`}},
+			}),
+			testutil.MockChatCompletion("Sorry, something went wrong."),
+		)
+		r, err := New(provider, []Tool{failing, panicking, weatherTool(t)})
+		require.NoError(t, err)
+
+		result := runWithTimeout(t, r)
+		toolResults := result.Steps[0].ToolResults
+		require.Len(t, toolResults, 4)
+		require.Equal(t, "error: service unavailable", toolResults[0].Content)
+		require.ErrorContains(t, toolResults[1].Err, "panicked: boom")
+		require.Equal(t, `error: unknown tool "missing"`, toolResults[2].Content)
+		require.ErrorContains(t, toolResults[3].Err, "invalid arguments")
+		for _, tr := range toolResults {
+			require.Error(t, tr.Err)
+		}
+	})
+
+	t.Run("uses the custom error formatter", func(t *testing.T) {
+		t.Parallel()
+
+		provider := scriptedProvider(
+			testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{
+				{ID: "call_1", Type: "function", Function: providers.FunctionCall{Name: "missing"}},
+			}),
+			testutil.MockChatCompletion("done"),
+		)
+		r, err := New(provider, nil, WithErrorFormatter(func(call providers.ToolCall, err error) string {
+			return call.ID + " failed"
+		}))
+		require.NoError(t, err)
+
+		result := runWithTimeout(t, r)
+		require.Equal(t, "call_1 failed", result.Messages[2].Content)
+	})
+
+	t.Run("stops after the maximum number of iterations", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		provider.CompletionFunc = func(context.Context, providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{weatherCall("call_1", "Paris")}), nil
+		}
+		r, err := New(provider, []Tool{weatherTool(t)}, WithMaxIterations(2))
+		require.NoError(t, err)
+
+		result, err := r.Run(context.Background(), providers.CompletionParams{Messages: testutil.SimpleMessages()})
+		require.ErrorIs(t, err, ErrMaxIterations)
+		require.Len(t, provider.CompletionCalls, 2)
+		require.Len(t, result.Steps, 2)
+		require.Len(t, result.Messages, 5)
+	})
+
+	t.Run("calls the step callback and stops when it fails", func(t *testing.T) {
+		t.Parallel()
+
+		provider := scriptedProvider(
+			testutil.MockChatCompletionWithToolCalls([]providers.ToolCall{weatherCall("call_1", "Paris")}),
+			testutil.MockChatCompletion("done"),
+		)
+
+		var steps []Step
+		stop := stderrors.New("stop")
+		r, err := New(provider, []Tool{weatherTool(t)}, WithOnStep(func(_ context.Context, step Step) error {
+			steps = append(steps, step)
+			return stop
+		}))
+		require.NoError(t, err)
+
+		_, err = r.Run(context.Background(), providers.CompletionParams{Messages: testutil.SimpleMessages()})
+		require.ErrorIs(t, err, stop)
+		require.Len(t, steps, 1)
+		require.Equal(t, 1, steps[0].Iteration)
+		require.Len(t, steps[0].ToolResults, 1)
+		require.Len(t, provider.CompletionCalls, 1)
+	})
+
+	t.Run("returns provider errors with the conversation so far", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		provider.CompletionFunc = func(context.Context, providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, stderrors.New("provider down")
+		}
+		r, err := New(provider, nil)
+		require.NoError(t, err)
+
+		result, err := r.Run(context.Background(), providers.CompletionParams{Messages: testutil.SimpleMessages()})
+		require.EqualError(t, err, "provider down")
+		require.Len(t, result.Messages, 1)
+	})
+
+	t.Run("keeps caller tools alongside runner tools", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		r, err := New(provider, []Tool{weatherTool(t)})
+		require.NoError(t, err)
+
+		_, err = r.Run(context.Background(), providers.CompletionParams{
+			Messages: testutil.SimpleMessages(),
+			Tools:    []providers.Tool{testutil.DateTool()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []providers.Tool{testutil.DateTool(), testutil.WeatherTool()}, provider.CompletionCalls[0].Tools)
+	})
+}
+
+// echoHandler returns its arguments unchanged.
+func echoHandler(_ context.Context, arguments string) (string, error) {
+	return arguments, nil
+}
+
+// runWithTimeout runs r on a simple conversation and fails the test if it does not finish promptly.
+func runWithTimeout(t *testing.T, r *Runner) *Result {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.Run(ctx, providers.CompletionParams{Model: "mock-model", Messages: testutil.SimpleMessages()})
+	require.NoError(t, err)
+
+	return result
+}
+
+// scriptedProvider returns a mock provider that replies with responses in order.
+func scriptedProvider(responses ...*providers.ChatCompletion) *testutil.MockProvider {
+	provider := testutil.NewMockProvider()
+	provider.CompletionFunc = func(context.Context, providers.CompletionParams) (*providers.ChatCompletion, error) {
+		resp := responses[0]
+		responses = responses[1:]
+		return resp, nil
+	}
+
+	return provider
+}
+
+// weatherCall returns a get_weather tool call for location.
+func weatherCall(id, location string) providers.ToolCall {
+	return providers.ToolCall{
+		ID:   id,
+		Type: "function",
+		Function: providers.FunctionCall{
+			Name:      "get_weather",
+			Arguments: `{"location":"` + location + `"}`,
+		},
+	}
+}
+
+// weatherTool returns a get_weather tool that reports sunny weather.
+func weatherTool(t *testing.T) Tool {
+	t.Helper()
+
+	definition := testutil.WeatherTool()
+	return NewTool(
+		definition.Function.Name,
+		definition.Function.Description,
+		definition.Function.Parameters,
+		func(_ context.Context, args weatherArgs) (string, error) {
+			return "sunny in " + args.Location, nil
+		},
+	)
+}
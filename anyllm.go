@@ -15,6 +15,9 @@
 //	        {Role: anyllm.RoleUser, Content: "Hello!"},
 //	    },
 //	})
+//
+// Providers can also be created by name with NewProvider, which parses
+// "provider:model" strings using the registry populated by provider packages.
 package anyllm
 
 import (
@@ -39,6 +42,37 @@ const (
 	FinishReasonToolCalls     = providers.FinishReasonToolCalls
 )
 
+// Cache control types and lifetimes.
+const (
+	CacheControlTypeEphemeral = providers.CacheControlTypeEphemeral
+	CacheTTL1h                = providers.CacheTTL1h
+	CacheTTL5m                = providers.CacheTTL5m
+)
+
+// Citation source types.
+const (
+	CitationSourceDocument = providers.CitationSourceDocument
+	CitationSourceTool     = providers.CitationSourceTool
+)
+
+// Image response formats.
+const (
+	ImageResponseFormatB64JSON = providers.ImageResponseFormatB64JSON
+	ImageResponseFormatURL     = providers.ImageResponseFormatURL
+)
+
+// Output modalities.
+const (
+	ModalityAudio = providers.ModalityAudio
+	ModalityText  = providers.ModalityText
+)
+
+// Timestamp granularities.
+const (
+	TimestampGranularitySegment = providers.TimestampGranularitySegment
+	TimestampGranularityWord    = providers.TimestampGranularityWord
+)
+
 // ReasoningEffort levels.
 const (
 	ReasoningEffortAuto   = providers.ReasoningEffortAuto
@@ -50,32 +84,76 @@ const (
 
 // Provider types.
 type (
-	Capabilities       = providers.Capabilities
-	CapabilityProvider = providers.CapabilityProvider
-	EmbeddingProvider  = providers.EmbeddingProvider
-	ModelLister        = providers.ModelLister
-	Provider           = providers.Provider
+	Capabilities            = providers.Capabilities
+	CapabilityProvider      = providers.CapabilityProvider
+	EmbeddingProvider       = providers.EmbeddingProvider
+	ImageGenerationProvider = providers.ImageGenerationProvider
+	ModelLister             = providers.ModelLister
+	ModerationProvider      = providers.ModerationProvider
+	Provider                = providers.Provider
+	ProviderFactory         = providers.Factory
+	RerankProvider          = providers.RerankProvider
+	SpeechProvider          = providers.SpeechProvider
+	TokenCounter            = providers.TokenCounter
+	TranscriptionProvider   = providers.TranscriptionProvider
+)
+
+// Provider registry.
+var (
+	ParseModelString    = providers.ParseModelString
+	RegisterProvider    = providers.Register
+	RegisteredProviders = providers.Registered
 )
 
 // Request/Response types.
 type (
-	ChatCompletion      = providers.ChatCompletion
-	ChatCompletionChunk = providers.ChatCompletionChunk
-	Choice              = providers.Choice
-	ChunkChoice         = providers.ChunkChoice
-	ChunkDelta          = providers.ChunkDelta
-	CompletionParams    = providers.CompletionParams
-	EmbeddingParams     = providers.EmbeddingParams
-	EmbeddingResponse   = providers.EmbeddingResponse
-	ModelsResponse      = providers.ModelsResponse
+	AudioParams           = providers.AudioParams
+	ChatCompletion        = providers.ChatCompletion
+	ChatCompletionChunk   = providers.ChatCompletionChunk
+	Choice                = providers.Choice
+	ChunkChoice           = providers.ChunkChoice
+	ChunkDelta            = providers.ChunkDelta
+	CompletionParams      = providers.CompletionParams
+	EmbeddingParams       = providers.EmbeddingParams
+	EmbeddingResponse     = providers.EmbeddingResponse
+	ImageEditParams       = providers.ImageEditParams
+	ImageGenerationParams = providers.ImageGenerationParams
+	ImageResponse         = providers.ImageResponse
+	ImageVariationParams  = providers.ImageVariationParams
+	ModelsResponse        = providers.ModelsResponse
+	ModerationParams      = providers.ModerationParams
+	ModerationResponse    = providers.ModerationResponse
+	ModerationResult      = providers.ModerationResult
+	RerankParams          = providers.RerankParams
+	RerankResponse        = providers.RerankResponse
+	SpeechParams          = providers.SpeechParams
+	TranscriptionParams   = providers.TranscriptionParams
+)
+
+// Stream accumulation.
+type Accumulator = providers.Accumulator
+
+// Streaming helpers.
+var (
+	CollectStream  = providers.Collect
+	NewAccumulator = providers.NewAccumulator
+	Stream         = providers.Stream
+	StreamChannels = providers.StreamChannels
 )
 
 // Message types.
 type (
-	ContentPart = providers.ContentPart
-	ImageURL    = providers.ImageURL
-	Message     = providers.Message
-	Reasoning   = providers.Reasoning
+	Audio          = providers.Audio
+	CacheControl   = providers.CacheControl
+	Citation       = providers.Citation
+	CitationSource = providers.CitationSource
+	ContentPart    = providers.ContentPart
+	Document       = providers.Document
+	File           = providers.File
+	ImageURL       = providers.ImageURL
+	InputAudio     = providers.InputAudio
+	Message        = providers.Message
+	Reasoning      = providers.Reasoning
 )
 
 // Tool types.
@@ -88,6 +166,13 @@ type (
 	ToolChoiceFunction = providers.ToolChoiceFunction
 )
 
+// Log probability types.
+type (
+	Logprobs     = providers.Logprobs
+	TokenLogprob = providers.TokenLogprob
+	TopLogprob   = providers.TopLogprob
+)
+
 // Response format types.
 type (
 	JSONSchema     = providers.JSONSchema
@@ -101,9 +186,25 @@ type (
 	EmbeddingUsage  = providers.EmbeddingUsage
 	Model           = providers.Model
 	ReasoningEffort = providers.ReasoningEffort
+	RerankResult    = providers.RerankResult
+	RerankUsage     = providers.RerankUsage
+	TokenCount      = providers.TokenCount
 	Usage           = providers.Usage
 )
 
+// Image types.
+type (
+	GeneratedImage = providers.GeneratedImage
+	ImageUsage     = providers.ImageUsage
+)
+
+// Transcription types.
+type (
+	Transcription        = providers.Transcription
+	TranscriptionSegment = providers.TranscriptionSegment
+	TranscriptionWord    = providers.TranscriptionWord
+)
+
 // Config types.
 type (
 	Config = config.Config
@@ -116,6 +217,8 @@ var (
 	WithAPIKey     = config.WithAPIKey
 	WithBaseURL    = config.WithBaseURL
 	WithExtra      = config.WithExtra
+	WithHeader     = config.WithHeader
+	WithHeaders    = config.WithHeaders
 	WithHTTPClient = config.WithHTTPClient
 	WithTimeout    = config.WithTimeout
 )
@@ -2,9 +2,11 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +23,10 @@ type Config struct {
 	// Extra holds provider-specific configuration options.
 	Extra map[string]any
 
+	// Headers are sent with every request, such as beta flags, organization IDs, or tracing headers.
+	// They replace any header of the same name that the provider sets.
+	Headers http.Header
+
 	// Timeout is the request timeout. If zero, a default timeout is used.
 	Timeout time.Duration
 
@@ -108,6 +114,37 @@ func WithExtra(key string, value any) Option {
 	}
 }
 
+// WithHeader sets a header sent with every request. Whitespace is automatically trimmed from the key.
+// Setting the same header again replaces its value.
+func WithHeader(key, value string) Option {
+	return func(c *Config) error {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("header key cannot be empty")
+		}
+
+		if c.Headers == nil {
+			c.Headers = make(http.Header)
+		}
+
+		c.Headers.Set(key, value)
+		return nil
+	}
+}
+
+// WithHeaders sets headers sent with every request, as with WithHeader for each key and value.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Config) error {
+		for _, key := range slices.Sorted(maps.Keys(headers)) {
+			if err := WithHeader(key, headers[key])(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client.
 // When a custom client is provided, the Timeout field is ignored for HTTP requests
 // since the custom client manages its own timeout configuration.
@@ -135,6 +172,16 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// ApplyHeaders sets the configured Headers on h, replacing any values h already holds for them.
+func (c *Config) ApplyHeaders(h http.Header) {
+	for key, values := range c.Headers {
+		h.Del(key)
+		for _, value := range values {
+			h.Add(key, value)
+		}
+	}
+}
+
 // ExtraValue retrieves a provider-specific configuration value.
 func (c *Config) ExtraValue(key string) (any, bool) {
 	if c.Extra == nil {
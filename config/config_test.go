@@ -314,6 +314,74 @@ func TestWithExtra(t *testing.T) {
 	}
 }
 
+func TestWithHeader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets canonical header", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := New(WithHeader("  anthropic-beta ", "files-api-2025-04-14"))
+		require.NoError(t, err)
+		require.Equal(t, http.Header{"Anthropic-Beta": {"files-api-2025-04-14"}}, cfg.Headers)
+	})
+
+	t.Run("replaces an earlier value", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := New(WithHeader("X-Trace-Id", "a"), WithHeader("x-trace-id", "b"))
+		require.NoError(t, err)
+		require.Equal(t, http.Header{"X-Trace-Id": {"b"}}, cfg.Headers)
+	})
+
+	t.Run("rejects empty key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(WithHeader("   ", "value"))
+		require.Error(t, err)
+	})
+}
+
+func TestWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges headers", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := New(
+			WithHeader("OpenAI-Organization", "org-1"),
+			WithHeaders(map[string]string{"OpenAI-Project": "proj-1", "openai-organization": "org-2"}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, http.Header{
+			"Openai-Organization": {"org-2"},
+			"Openai-Project":      {"proj-1"},
+		}, cfg.Headers)
+	})
+
+	t.Run("rejects empty key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(WithHeaders(map[string]string{"": "value"}))
+		require.Error(t, err)
+	})
+}
+
+func TestApplyHeaders(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := New(WithHeader("User-Agent", "my-app/1.0"), WithHeader("X-Trace-Id", "abc"))
+	require.NoError(t, err)
+
+	header := http.Header{"Content-Type": {"application/json"}, "User-Agent": {"sdk/2.0"}}
+	cfg.ApplyHeaders(header)
+
+	require.Equal(t, http.Header{
+		"Content-Type": {"application/json"},
+		"User-Agent":   {"my-app/1.0"},
+		"X-Trace-Id":   {"abc"},
+	}, header)
+}
+
 func TestExtraValue(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response headers that carry retry hints.
+const (
+	headerRetryAfter   = "Retry-After"
+	headerRetryAfterMs = "Retry-After-Ms"
+)
+
+// RetryAfterFromHeader returns the number of seconds a server asked the client to wait
+// before retrying, or 0 if the header carries no usable hint.
+//
+// The non-standard retry-after-ms header (used by OpenAI and Azure) takes precedence
+// and is rounded up to the next whole second. Otherwise Retry-After is parsed either
+// as a number of seconds or as an HTTP date.
+func RetryAfterFromHeader(header http.Header) int {
+	if header == nil {
+		return 0
+	}
+
+	if v := strings.TrimSpace(header.Get(headerRetryAfterMs)); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms > 0 {
+			return int(math.Ceil(ms / 1000))
+		}
+	}
+
+	v := strings.TrimSpace(header.Get(headerRetryAfter))
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return int(math.Ceil(seconds))
+	}
+
+	if when, err := http.ParseTime(v); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return int(math.Ceil(wait.Seconds()))
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfterFromHeader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{
+			name: "no headers",
+			want: 0,
+		},
+		{
+			name:    "seconds",
+			headers: map[string]string{"Retry-After": "7"},
+			want:    7,
+		},
+		{
+			name:    "fractional seconds round up",
+			headers: map[string]string{"Retry-After": "1.2"},
+			want:    2,
+		},
+		{
+			name:    "milliseconds round up",
+			headers: map[string]string{"retry-after-ms": "1500"},
+			want:    2,
+		},
+		{
+			name:    "milliseconds take precedence",
+			headers: map[string]string{"Retry-After": "10", "retry-after-ms": "250"},
+			want:    1,
+		},
+		{
+			name:    "invalid milliseconds fall back to seconds",
+			headers: map[string]string{"Retry-After": "3", "retry-after-ms": "soon"},
+			want:    3,
+		},
+		{
+			name:    "negative seconds are ignored",
+			headers: map[string]string{"Retry-After": "-5"},
+			want:    0,
+		},
+		{
+			name:    "date in the past is ignored",
+			headers: map[string]string{"Retry-After": "Wed, 21 Oct 2015 07:28:00 GMT"},
+			want:    0,
+		},
+		{
+			name:    "garbage is ignored",
+			headers: map[string]string{"Retry-After": "later"},
+			want:    0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			for k, v := range tc.headers {
+				header.Set(k, v)
+			}
+
+			require.Equal(t, tc.want, RetryAfterFromHeader(header))
+		})
+	}
+
+	t.Run("date in the future", func(t *testing.T) {
+		t.Parallel()
+
+		header := http.Header{}
+		header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+
+		got := RetryAfterFromHeader(header)
+		require.GreaterOrEqual(t, got, 29)
+		require.LessOrEqual(t, got, 31)
+	})
+
+	t.Run("nil header", func(t *testing.T) {
+		t.Parallel()
+
+		require.Zero(t, RetryAfterFromHeader(nil))
+	})
+}
@@ -0,0 +1,97 @@
+// Example: Agent loop
+//
+// This example demonstrates how to let any-llm-go run tools automatically
+// until the model produces a final answer.
+//
+// Run with:
+//
+//	export OPENAI_API_KEY="sk-..."
+//	go run main.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	anyllm "github.com/mozilla-ai/any-llm-go"
+	"github.com/mozilla-ai/any-llm-go/agent"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// weatherArgs holds the arguments of the get_weather tool.
+type weatherArgs struct {
+	Location string `json:"location"`
+	Unit     string `json:"unit"`
+}
+
+// weatherResult is the result of the get_weather tool.
+type weatherResult struct {
+	Condition   string `json:"condition"`
+	Location    string `json:"location"`
+	Temperature int    `json:"temperature"`
+	Unit        string `json:"unit"`
+}
+
+// weatherParams is the JSON Schema of weatherArgs.
+var weatherParams = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"location": map[string]any{
+			"type":        "string",
+			"description": "The city name, e.g., 'Paris' or 'New York'",
+		},
+		"unit": map[string]any{
+			"type":        "string",
+			"enum":        []string{"celsius", "fahrenheit"},
+			"description": "Temperature unit",
+		},
+	},
+	"required": []string{"location"},
+}
+
+// getWeather simulates a weather API call.
+func getWeather(_ context.Context, args weatherArgs) (weatherResult, error) {
+	if args.Unit == "" {
+		args.Unit = "celsius"
+	}
+	// In a real app, this would call an actual weather API.
+	return weatherResult{Condition: "sunny", Location: args.Location, Temperature: 22, Unit: args.Unit}, nil
+}
+
+func main() {
+	provider, err := openai.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	weather := agent.NewTool("get_weather", "Get the current weather for a location", weatherParams, getWeather)
+
+	runner, err := agent.New(provider, []agent.Tool{weather},
+		agent.WithOnStep(func(_ context.Context, step agent.Step) error {
+			for _, tr := range step.ToolResults {
+				fmt.Printf("  Tool: %s(%s) -> %s\n", tr.Call.Function.Name, tr.Call.Function.Arguments, tr.Content)
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("User: What's the weather like in Paris and in Tokyo?")
+	fmt.Println()
+
+	result, err := runner.Run(context.Background(), anyllm.CompletionParams{
+		Model: "gpt-4o-mini",
+		Messages: []anyllm.Message{
+			{Role: anyllm.RoleUser, Content: "What's the weather like in Paris and in Tokyo?"},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println()
+	fmt.Printf("Assistant: %s\n", result.Content())
+}
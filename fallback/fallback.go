@@ -0,0 +1,304 @@
+// Package fallback provides a provider that tries an ordered list of
+// (provider, model) targets until one of them succeeds.
+//
+//	primary, err := openai.New()
+//	secondary, err := anthropic.New()
+//
+//	provider, err := fallback.New([]fallback.Target{
+//		{Provider: primary, Model: "gpt-4o"},
+//		{Provider: secondary, Model: "claude-sonnet-4-20250514"},
+//	})
+//
+//	response, err := provider.Completion(ctx, params)
+//	fmt.Println("served by", response.Provider, response.Model)
+//
+// By default a target is skipped when it fails with a rate limit, a general provider
+// error, or an unknown model. Other errors, such as authentication failures or invalid
+// requests, are returned immediately since the next target would most likely fail too.
+package fallback
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// providerName is the name reported by the fallback provider itself.
+const providerName = "fallback"
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+// OnFallbackFunc is called when a target fails with a fallback error and the next
+// target is about to be tried.
+type OnFallbackFunc func(failed Target, err error)
+
+// Option configures the fallback provider.
+type Option func(*options) error
+
+// Provider tries each of its targets in order until one succeeds.
+type Provider struct {
+	opts    *options
+	targets []Target
+}
+
+// Target is a provider and the model to request from it.
+type Target struct {
+	// Model replaces CompletionParams.Model when this target is tried.
+	// If empty, the caller's model is passed through unchanged.
+	Model string
+
+	// Provider serves the request.
+	Provider providers.Provider
+}
+
+// options holds the fallback configuration.
+type options struct {
+	fallbackOn []error
+	onFallback OnFallbackFunc
+}
+
+// New creates a provider that tries targets in order.
+func New(targets []Target, opts ...Option) (*Provider, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("at least one target is required")
+	}
+	for i, target := range targets {
+		if target.Provider == nil {
+			return nil, fmt.Errorf("target %d: provider cannot be nil", i)
+		}
+	}
+
+	o := &options{
+		fallbackOn: []error{errors.ErrRateLimit, errors.ErrProvider, errors.ErrModelNotFound},
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Provider{opts: o, targets: targets}, nil
+}
+
+// WithFallbackOn sets the error classes that cause the next target to be tried.
+// Errors are matched with errors.Is, so sentinel errors such as errors.ErrRateLimit are expected.
+// The default is errors.ErrRateLimit, errors.ErrProvider, and errors.ErrModelNotFound.
+func WithFallbackOn(errs ...error) Option {
+	return func(o *options) error {
+		if len(errs) == 0 {
+			return fmt.Errorf("at least one error class is required")
+		}
+		for _, err := range errs {
+			if err == nil {
+				return fmt.Errorf("error class cannot be nil")
+			}
+		}
+
+		o.fallbackOn = errs
+		return nil
+	}
+}
+
+// WithOnFallback registers a callback invoked each time a target is skipped, e.g. for logging.
+func WithOnFallback(fn OnFallbackFunc) Option {
+	return func(o *options) error {
+		if fn == nil {
+			return fmt.Errorf("fallback callback cannot be nil")
+		}
+
+		o.onFallback = fn
+		return nil
+	}
+}
+
+// Capabilities returns the capabilities that every target supports, since any target may serve a request.
+// CompletionInto, for example, only uses native structured output when no target would need a forced tool.
+func (p *Provider) Capabilities() providers.Capabilities {
+	caps := targetCapabilities(p.targets[0])
+	for _, target := range p.targets[1:] {
+		caps = intersect(caps, targetCapabilities(target))
+	}
+
+	return caps
+}
+
+// Completion tries each target in order and returns the first successful response.
+// The returned ChatCompletion reports the serving target in its Provider field, unless the target already
+// reported a provider, as a nested fallback or a wrapped provider does.
+func (p *Provider) Completion(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.ChatCompletion, error) {
+	var lastErr error
+	for i, target := range p.targets {
+		resp, err := target.Provider.Completion(ctx, target.params(params))
+		if err == nil {
+			if resp.Provider == "" {
+				resp.Provider = target.Provider.Name()
+			}
+			if resp.Model == "" {
+				resp.Model = target.params(params).Model
+			}
+			return resp, nil
+		}
+
+		lastErr = err
+		if !p.shouldFallback(ctx, i, err) {
+			break
+		}
+		if p.opts.onFallback != nil {
+			p.opts.onFallback(target, err)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// CompletionStream tries each target in order until one produces its first chunk.
+// Once a chunk has been forwarded the stream is committed to that target, and later
+// errors are returned as-is, so callers never receive output from more than one target.
+func (p *Provider) CompletionStream(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		for i, target := range p.targets {
+			upstreamChunks, upstreamErrs := target.Provider.CompletionStream(ctx, target.params(params))
+
+			first, ok := <-upstreamChunks
+			if !ok {
+				err := <-upstreamErrs
+				if err == nil {
+					return
+				}
+				if !p.shouldFallback(ctx, i, err) {
+					errs <- err
+					return
+				}
+				if p.opts.onFallback != nil {
+					p.opts.onFallback(target, err)
+				}
+				continue
+			}
+
+			name := target.Provider.Name()
+			if first.Provider == "" {
+				first.Provider = name
+			}
+			if !send(ctx, chunks, first) {
+				errs <- ctx.Err()
+				return
+			}
+			for chunk := range upstreamChunks {
+				if chunk.Provider == "" {
+					chunk.Provider = name
+				}
+				if !send(ctx, chunks, chunk) {
+					errs <- ctx.Err()
+					return
+				}
+			}
+			if err := <-upstreamErrs; err != nil {
+				errs <- err
+			}
+			return
+		}
+	}()
+
+	return chunks, errs
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// Targets returns a copy of the configured targets.
+func (p *Provider) Targets() []Target {
+	return append([]Target(nil), p.targets...)
+}
+
+// shouldFallback reports whether the error from target i should move on to the next target.
+func (p *Provider) shouldFallback(ctx context.Context, i int, err error) bool {
+	if i == len(p.targets)-1 || ctx.Err() != nil {
+		return false
+	}
+
+	for _, class := range p.opts.fallbackOn {
+		if stderrors.Is(err, class) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// params returns params with the target's model applied.
+func (t Target) params(params providers.CompletionParams) providers.CompletionParams {
+	if t.Model != "" {
+		params.Model = t.Model
+	}
+
+	return params
+}
+
+// intersect returns the capabilities supported by both a and b.
+func intersect(a, b providers.Capabilities) providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 a.Completion && b.Completion,
+		CompletionAudio:            a.CompletionAudio && b.CompletionAudio,
+		CompletionImage:            a.CompletionImage && b.CompletionImage,
+		CompletionPDF:              a.CompletionPDF && b.CompletionPDF,
+		CompletionReasoning:        a.CompletionReasoning && b.CompletionReasoning,
+		CompletionStreaming:        a.CompletionStreaming && b.CompletionStreaming,
+		CompletionStructuredOutput: a.CompletionStructuredOutput && b.CompletionStructuredOutput,
+		CountTokens:                a.CountTokens && b.CountTokens,
+		Embedding:                  a.Embedding && b.Embedding,
+		ImageGeneration:            a.ImageGeneration && b.ImageGeneration,
+		ListModels:                 a.ListModels && b.ListModels,
+		Moderation:                 a.Moderation && b.Moderation,
+		Rerank:                     a.Rerank && b.Rerank,
+		Speech:                     a.Speech && b.Speech,
+		Transcription:              a.Transcription && b.Transcription,
+	}
+}
+
+// send delivers chunk unless ctx is done first.
+func send(ctx context.Context, chunks chan<- providers.ChatCompletionChunk, chunk providers.ChatCompletionChunk) bool {
+	select {
+	case chunks <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// targetCapabilities returns the capabilities of a target's provider, assuming plain completions and
+// streaming for providers that do not report them.
+func targetCapabilities(target Target) providers.Capabilities {
+	if cp, ok := target.Provider.(providers.CapabilityProvider); ok {
+		return cp.Capabilities()
+	}
+
+	return providers.Capabilities{
+		Completion:          true,
+		CompletionStreaming: true,
+	}
+}
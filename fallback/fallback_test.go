@@ -0,0 +1,369 @@
+package fallback
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires at least one target", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("rejects nil providers", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New([]Target{{Model: "m"}})
+		require.Error(t, err)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		targets := []Target{{Provider: testutil.NewMockProvider()}}
+
+		_, err := New(targets, WithFallbackOn())
+		require.Error(t, err)
+
+		_, err = New(targets, WithFallbackOn(nil))
+		require.Error(t, err)
+
+		_, err = New(targets, WithOnFallback(nil))
+		require.Error(t, err)
+	})
+
+	t.Run("uses default fallback classes", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := New([]Target{{Provider: testutil.NewMockProvider()}})
+		require.NoError(t, err)
+		require.Equal(t, providerName, p.Name())
+		require.Len(t, p.opts.fallbackOn, 3)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uses the first target when it succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+		secondary := newNamedMock("secondary")
+
+		p, err := New([]Target{
+			{Provider: primary, Model: "model-a"},
+			{Provider: secondary, Model: "model-b"},
+		})
+		require.NoError(t, err)
+
+		resp, err := p.Completion(context.Background(), providers.CompletionParams{Model: "ignored"})
+		require.NoError(t, err)
+		require.Equal(t, "primary", resp.Provider)
+		require.Equal(t, "model-a", resp.Model)
+		require.Len(t, primary.CompletionCalls, 1)
+		require.Equal(t, "model-a", primary.CompletionCalls[0].Model)
+		require.Empty(t, secondary.CompletionCalls)
+	})
+
+	t.Run("keeps the provider reported by nested targets", func(t *testing.T) {
+		t.Parallel()
+
+		inner, err := New([]Target{{Provider: newNamedMock("inner")}})
+		require.NoError(t, err)
+
+		p, err := New([]Target{{Provider: inner}})
+		require.NoError(t, err)
+
+		resp, err := p.Completion(context.Background(), providers.CompletionParams{Model: "model-a"})
+		require.NoError(t, err)
+		require.Equal(t, "inner", resp.Provider)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{Model: "model-a"})
+		for chunk := range chunks {
+			require.Equal(t, "inner", chunk.Provider)
+		}
+		require.NoError(t, <-errs)
+	})
+
+	t.Run("falls back on configured error classes", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			err  error
+		}{
+			{name: "rate limit", err: errors.NewRateLimitError("primary", stderrors.New("slow down"))},
+			{name: "provider error", err: errors.NewProviderError("primary", stderrors.New("unavailable"))},
+			{name: "model not found", err: errors.NewModelNotFoundError("primary", stderrors.New("no such model"))},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				primary := newNamedMock("primary")
+				primary.CompletionFunc = func(
+					ctx context.Context,
+					params providers.CompletionParams,
+				) (*providers.ChatCompletion, error) {
+					return nil, tc.err
+				}
+				secondary := newNamedMock("secondary")
+
+				var skipped []string
+				p, err := New(
+					[]Target{{Provider: primary, Model: "model-a"}, {Provider: secondary, Model: "model-b"}},
+					WithOnFallback(func(failed Target, err error) {
+						skipped = append(skipped, failed.Provider.Name())
+					}),
+				)
+				require.NoError(t, err)
+
+				resp, err := p.Completion(context.Background(), providers.CompletionParams{})
+				require.NoError(t, err)
+				require.Equal(t, "secondary", resp.Provider)
+				require.Equal(t, "model-b", resp.Model)
+				require.Equal(t, []string{"primary"}, skipped)
+			})
+		}
+	})
+
+	t.Run("returns errors outside the configured classes", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+		primary.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewAuthenticationError("primary", stderrors.New("bad key"))
+		}
+		secondary := newNamedMock("secondary")
+
+		p, err := New([]Target{{Provider: primary}, {Provider: secondary}})
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrAuthentication)
+		require.Empty(t, secondary.CompletionCalls)
+	})
+
+	t.Run("honors custom error classes", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+		primary.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewRateLimitError("primary", stderrors.New("slow down"))
+		}
+		secondary := newNamedMock("secondary")
+
+		p, err := New([]Target{{Provider: primary}, {Provider: secondary}}, WithFallbackOn(errors.ErrProvider))
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrRateLimit)
+		require.Empty(t, secondary.CompletionCalls)
+	})
+
+	t.Run("returns the last error when every target fails", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+		primary.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewRateLimitError("primary", stderrors.New("slow down"))
+		}
+		secondary := newNamedMock("secondary")
+		secondary.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewProviderError("secondary", stderrors.New("down"))
+		}
+
+		p, err := New([]Target{{Provider: primary}, {Provider: secondary}})
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrProvider)
+		require.Contains(t, err.Error(), "[secondary]")
+	})
+
+	t.Run("keeps the caller's model when the target has none", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+
+		p, err := New([]Target{{Provider: primary}})
+		require.NoError(t, err)
+
+		resp, err := p.Completion(context.Background(), providers.CompletionParams{Model: "caller-model"})
+		require.NoError(t, err)
+		require.Equal(t, "caller-model", resp.Model)
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		primary := newNamedMock("primary")
+		primary.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			cancel()
+			return nil, errors.NewProviderError("primary", ctx.Err())
+		}
+		secondary := newNamedMock("secondary")
+
+		p, err := New([]Target{{Provider: primary}, {Provider: secondary}})
+		require.NoError(t, err)
+
+		_, err = p.Completion(ctx, providers.CompletionParams{})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, secondary.CompletionCalls)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails over before the first chunk", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+		primary.CompletionStreamFunc = func(
+			ctx context.Context,
+			params providers.CompletionParams,
+		) (<-chan providers.ChatCompletionChunk, <-chan error) {
+			return failedStream(errors.NewRateLimitError("primary", stderrors.New("slow down")))
+		}
+		secondary := newNamedMock("secondary")
+
+		p, err := New([]Target{{Provider: primary, Model: "model-a"}, {Provider: secondary, Model: "model-b"}})
+		require.NoError(t, err)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{})
+
+		var content string
+		for chunk := range chunks {
+			require.Equal(t, "secondary", chunk.Provider)
+			require.Equal(t, "model-b", chunk.Model)
+			for _, choice := range chunk.Choices {
+				content += choice.Delta.Content
+			}
+		}
+		require.NoError(t, <-errs)
+		require.Equal(t, "Hello World", content)
+	})
+
+	t.Run("does not fail over after the first chunk", func(t *testing.T) {
+		t.Parallel()
+
+		primary := newNamedMock("primary")
+		primary.CompletionStreamFunc = func(
+			ctx context.Context,
+			params providers.CompletionParams,
+		) (<-chan providers.ChatCompletionChunk, <-chan error) {
+			chunks := make(chan providers.ChatCompletionChunk, 1)
+			errs := make(chan error, 1)
+			chunks <- providers.ChatCompletionChunk{
+				Choices: []providers.ChunkChoice{{Delta: providers.ChunkDelta{Content: "partial"}}},
+			}
+			errs <- errors.NewProviderError("primary", stderrors.New("connection lost"))
+			close(chunks)
+			close(errs)
+			return chunks, errs
+		}
+		secondary := newNamedMock("secondary")
+
+		p, err := New([]Target{{Provider: primary}, {Provider: secondary}})
+		require.NoError(t, err)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{})
+
+		var received []providers.ChatCompletionChunk
+		for chunk := range chunks {
+			received = append(received, chunk)
+		}
+		require.ErrorIs(t, <-errs, errors.ErrProvider)
+		require.Len(t, received, 1)
+		require.Equal(t, "primary", received[0].Provider)
+		require.Empty(t, secondary.CompletionStreamCalls)
+	})
+
+	t.Run("returns the last error when every target fails", func(t *testing.T) {
+		t.Parallel()
+
+		failing := func(
+			ctx context.Context,
+			params providers.CompletionParams,
+		) (<-chan providers.ChatCompletionChunk, <-chan error) {
+			return failedStream(errors.NewModelNotFoundError("mock", stderrors.New("no such model")))
+		}
+		primary := newNamedMock("primary")
+		primary.CompletionStreamFunc = failing
+		secondary := newNamedMock("secondary")
+		secondary.CompletionStreamFunc = failing
+
+		p, err := New([]Target{{Provider: primary}, {Provider: secondary}})
+		require.NoError(t, err)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{})
+		for range chunks {
+			t.Fatal("expected no chunks")
+		}
+		require.ErrorIs(t, <-errs, errors.ErrModelNotFound)
+		require.Len(t, primary.CompletionStreamCalls, 1)
+		require.Len(t, secondary.CompletionStreamCalls, 1)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	primary := newNamedMock("primary")
+	primary.CapabilitiesFunc = func() providers.Capabilities {
+		return providers.Capabilities{Completion: true, CompletionReasoning: true}
+	}
+
+	secondary := newNamedMock("secondary")
+	secondary.CapabilitiesFunc = func() providers.Capabilities {
+		return providers.Capabilities{Completion: true, CompletionStreaming: true}
+	}
+
+	p, err := New([]Target{{Provider: primary}, {Provider: secondary}})
+	require.NoError(t, err)
+	require.Equal(t, providers.Capabilities{Completion: true}, p.Capabilities())
+	require.Len(t, p.Targets(), 2)
+
+	p, err = New([]Target{{Provider: primary}, {Provider: plainProvider{}}})
+	require.NoError(t, err)
+	require.Equal(t, providers.Capabilities{Completion: true}, p.Capabilities())
+}
+
+// failedStream returns a stream that closes without chunks and reports err.
+func failedStream(err error) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+	errs <- err
+	close(chunks)
+	close(errs)
+	return chunks, errs
+}
+
+// newNamedMock creates a mock provider that reports the given name.
+func newNamedMock(name string) *testutil.MockProvider {
+	mock := testutil.NewMockProvider()
+	mock.NameFunc = func() string { return name }
+	return mock
+}
+
+// plainProvider is a provider that does not report its capabilities.
+type plainProvider struct {
+	providers.Provider
+}
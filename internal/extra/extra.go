@@ -0,0 +1,31 @@
+// Package extra helps providers read provider-specific parameters from
+// providers.CompletionParams.Extra.
+package extra
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Decode converts the value of the Extra parameter key into dst, which must be a pointer.
+// Values are converted through JSON, so Go literals and values decoded from JSON are
+// accepted alike, e.g. an int or a float64 for an integer parameter.
+func Decode(key string, value any, dst any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("extra parameter %q: %w", key, err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("extra parameter %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Keys returns the keys of params in sorted order, so that providers process them
+// and report errors deterministically.
+func Keys(params map[string]any) []string {
+	return slices.Sorted(maps.Keys(params))
+}
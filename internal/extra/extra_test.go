@@ -0,0 +1,46 @@
+package extra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("converts between numeric types", func(t *testing.T) {
+		t.Parallel()
+
+		var n int64
+		require.NoError(t, Decode("n", float64(3), &n))
+		require.Equal(t, int64(3), n)
+
+		var f float64
+		require.NoError(t, Decode("f", 2, &f))
+		require.InDelta(t, 2.0, f, 0)
+	})
+
+	t.Run("converts maps", func(t *testing.T) {
+		t.Parallel()
+
+		var m map[string]int64
+		require.NoError(t, Decode("m", map[string]any{"a": 1.0}, &m))
+		require.Equal(t, map[string]int64{"a": 1}, m)
+	})
+
+	t.Run("reports mismatched types with the key", func(t *testing.T) {
+		t.Parallel()
+
+		var n int64
+		require.ErrorContains(t, Decode("n", "three", &n), `extra parameter "n"`)
+		require.ErrorContains(t, Decode("n", 1.5, &n), `extra parameter "n"`)
+	})
+}
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a", "b", "c"}, Keys(map[string]any{"c": 1, "a": 2, "b": 3}))
+	require.Empty(t, Keys(nil))
+}
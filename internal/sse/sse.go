@@ -0,0 +1,90 @@
+// Package sse reads server-sent event streams for providers that are called over plain HTTP.
+package sse
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Field names of the event stream format.
+const (
+	fieldData  = "data"
+	fieldEvent = "event"
+)
+
+// maxLineSize is the longest line the reader accepts. Providers send whole JSON
+// responses on a single data line, so the bufio default of 64KiB is too small.
+const maxLineSize = 4 << 20
+
+// Event is a single server-sent event.
+type Event struct {
+	// Data is the event payload. Multiple data lines are joined with newlines.
+	Data string
+
+	// Type is the event type, or "" if the stream did not name it.
+	Type string
+}
+
+// Reader reads events from a server-sent event stream.
+type Reader struct {
+	err     error
+	event   Event
+	scanner *bufio.Scanner
+}
+
+// NewReader returns a Reader that reads events from r.
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	return &Reader{scanner: scanner}
+}
+
+// Err returns the first error other than io.EOF encountered while reading.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Event returns the event read by the last call to Next.
+func (r *Reader) Event() Event {
+	return r.event
+}
+
+// Next reads the next event, returning false at the end of the stream or on error.
+// Events without data, comments, and unknown fields are skipped.
+func (r *Reader) Next() bool {
+	var data []string
+	eventType := ""
+
+	for r.scanner.Scan() {
+		line := strings.TrimSuffix(r.scanner.Text(), "\r")
+		if line == "" {
+			if len(data) > 0 {
+				r.event = Event{Data: strings.Join(data, "\n"), Type: eventType}
+				return true
+			}
+			eventType = ""
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case fieldData:
+			data = append(data, value)
+		case fieldEvent:
+			eventType = value
+		}
+	}
+
+	r.err = r.scanner.Err()
+	if r.err == nil && len(data) > 0 {
+		// The stream ended without a trailing blank line.
+		r.event = Event{Data: strings.Join(data, "\n"), Type: eventType}
+		return true
+	}
+
+	return false
+}
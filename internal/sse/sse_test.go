@@ -0,0 +1,88 @@
+package sse
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+		want  []Event
+	}{
+		"data only": {
+			input: "data: {\"a\":1}\n\ndata: {\"b\":2}\n\n",
+			want:  []Event{{Data: `{"a":1}`}, {Data: `{"b":2}`}},
+		},
+		"named events": {
+			input: "event: message_start\ndata: one\n\nevent: ping\ndata: two\n\n",
+			want:  []Event{{Type: "message_start", Data: "one"}, {Type: "ping", Data: "two"}},
+		},
+		"multi-line data": {
+			input: "data: first\ndata: second\n\n",
+			want:  []Event{{Data: "first\nsecond"}},
+		},
+		"CRLF line endings": {
+			input: "data: one\r\n\r\ndata: two\r\n\r\n",
+			want:  []Event{{Data: "one"}, {Data: "two"}},
+		},
+		"comments, unknown fields, and empty events are skipped": {
+			input: ": keep-alive\nid: 1\nretry: 10\n\nevent: ping\n\ndata: one\n\n",
+			want:  []Event{{Data: "one"}},
+		},
+		"no space after colon": {
+			input: "data:one\n\n",
+			want:  []Event{{Data: "one"}},
+		},
+		"missing trailing blank line": {
+			input: "data: one\n\ndata: two",
+			want:  []Event{{Data: "one"}, {Data: "two"}},
+		},
+		"empty stream": {
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewReader(strings.NewReader(tc.input))
+
+			var got []Event
+			for r.Next() {
+				got = append(got, r.Event())
+			}
+
+			require.NoError(t, r.Err())
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("reads long lines", func(t *testing.T) {
+		t.Parallel()
+
+		payload := strings.Repeat("x", 1<<20)
+		r := NewReader(strings.NewReader("data: " + payload + "\n\n"))
+
+		require.True(t, r.Next())
+		require.Equal(t, payload, r.Event().Data)
+		require.False(t, r.Next())
+		require.NoError(t, r.Err())
+	})
+
+	t.Run("reports read errors", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewReader(iotest.ErrReader(iotest.ErrTimeout))
+
+		require.False(t, r.Next())
+		require.ErrorIs(t, r.Err(), iotest.ErrTimeout)
+	})
+}
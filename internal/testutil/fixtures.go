@@ -14,7 +14,7 @@ var ProviderModelMap = map[string]string{
 	"openai":     "gpt-4o-mini",
 	"anthropic":  "claude-3-5-haiku-latest",
 	"mistral":    "mistral-small-latest",
-	"gemini":     "gemini-1.5-flash",
+	"gemini":     "gemini-2.0-flash",
 	"cohere":     "command-r",
 	"groq":       "llama-3.1-8b-instant",
 	"ollama":     "llama3.2",
@@ -26,6 +26,8 @@ var ProviderModelMap = map[string]string{
 	"xai":        "grok-beta",
 	"cerebras":   "llama3.1-8b",
 	"openrouter": "meta-llama/llama-3.1-8b-instruct",
+	"bedrock":    "us.anthropic.claude-3-5-haiku-20241022-v1:0",
+	"azure":      "gpt-4o-mini",
 }
 
 // ProviderReasoningModelMap maps providers to reasoning-capable models.
@@ -34,6 +36,7 @@ var ProviderReasoningModelMap = map[string]string{
 	"anthropic": "claude-sonnet-4-20250514",
 	"mistral":   "magistral-small-latest",
 	"deepseek":  "deepseek-reasoner",
+	"gemini":    "gemini-2.5-flash",
 	"ollama":    "deepseek-r1",
 }
 
@@ -41,7 +44,7 @@ var ProviderReasoningModelMap = map[string]string{
 var ProviderImageModelMap = map[string]string{
 	"openai":    "gpt-4o-mini",
 	"anthropic": "claude-3-5-haiku-latest",
-	"gemini":    "gemini-1.5-flash",
+	"gemini":    "gemini-2.0-flash",
 	"ollama":    "llava",
 }
 
@@ -49,6 +52,7 @@ var ProviderImageModelMap = map[string]string{
 var EmbeddingProviderModelMap = map[string]string{
 	"openai":    "text-embedding-3-small",
 	"cohere":    "embed-english-v3.0",
+	"gemini":    "text-embedding-004",
 	"mistral":   "mistral-embed",
 	"together":  "togethercomputer/m2-bert-80M-8k-retrieval",
 	"ollama":    "nomic-embed-text",
@@ -72,6 +76,8 @@ var LocalProviders = map[string]bool{
 // providerEnvKeys maps provider names to their API key environment variable names.
 var providerEnvKeys = map[string]string{
 	"anthropic":  "ANTHROPIC_API_KEY",
+	"azure":      "AZURE_OPENAI_API_KEY",
+	"bedrock":    "AWS_ACCESS_KEY_ID",
 	"cerebras":   "CEREBRAS_API_KEY",
 	"cohere":     "COHERE_API_KEY",
 	"deepseek":   "DEEPSEEK_API_KEY",
@@ -13,6 +13,7 @@ type MockProvider struct {
 	CompletionStreamFunc func(ctx context.Context, params providers.CompletionParams) (<-chan providers.ChatCompletionChunk, <-chan error)
 	EmbeddingFunc        func(ctx context.Context, params providers.EmbeddingParams) (*providers.EmbeddingResponse, error)
 	ListModelsFunc       func(ctx context.Context) (*providers.ModelsResponse, error)
+	ModerationFunc       func(ctx context.Context, params providers.ModerationParams) (*providers.ModerationResponse, error)
 	CapabilitiesFunc     func() providers.Capabilities
 
 	// Track calls for assertions.
@@ -20,6 +21,7 @@ type MockProvider struct {
 	CompletionStreamCalls []providers.CompletionParams
 	EmbeddingCalls        []providers.EmbeddingParams
 	ListModelsCalls       int
+	ModerationCalls       []providers.ModerationParams
 }
 
 // Ensure MockProvider implements all interfaces.
@@ -27,6 +29,7 @@ var (
 	_ providers.Provider           = (*MockProvider)(nil)
 	_ providers.EmbeddingProvider  = (*MockProvider)(nil)
 	_ providers.ModelLister        = (*MockProvider)(nil)
+	_ providers.ModerationProvider = (*MockProvider)(nil)
 	_ providers.CapabilityProvider = (*MockProvider)(nil)
 )
 
@@ -118,12 +121,21 @@ func NewMockProvider() *MockProvider {
 				},
 			}, nil
 		},
+		ModerationFunc: func(ctx context.Context, params providers.ModerationParams) (*providers.ModerationResponse, error) {
+			results := make([]providers.ModerationResult, len(params.Input))
+			return &providers.ModerationResponse{
+				ID:      "mock-moderation-id",
+				Model:   params.Model,
+				Results: results,
+			}, nil
+		},
 		CapabilitiesFunc: func() providers.Capabilities {
 			return providers.Capabilities{
 				Completion:          true,
 				CompletionStreaming: true,
 				Embedding:           true,
 				ListModels:          true,
+				Moderation:          true,
 			}
 		},
 	}
@@ -162,6 +174,14 @@ func (m *MockProvider) ListModels(ctx context.Context) (*providers.ModelsRespons
 	return m.ListModelsFunc(ctx)
 }
 
+func (m *MockProvider) Moderation(
+	ctx context.Context,
+	params providers.ModerationParams,
+) (*providers.ModerationResponse, error) {
+	m.ModerationCalls = append(m.ModerationCalls, params)
+	return m.ModerationFunc(ctx, params)
+}
+
 func (m *MockProvider) Capabilities() providers.Capabilities {
 	return m.CapabilitiesFunc()
 }
@@ -0,0 +1,46 @@
+package testutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+)
+
+// NewTestProvider starts a server running handler until the test ends and returns the provider
+// that newProvider creates with the server as its base URL. opts are applied after the base URL.
+func NewTestProvider[P any](
+	t *testing.T,
+	newProvider func(opts ...config.Option) (P, error),
+	handler http.HandlerFunc,
+	opts ...config.Option,
+) P {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := newProvider(append([]config.Option{config.WithBaseURL(server.URL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("creating provider: %v", err)
+	}
+
+	return provider
+}
+
+// RecordRequest returns a handler that decodes the JSON body of each request into body and
+// replies with the JSON response.
+func RecordRequest(t *testing.T, body *map[string]any, response string) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}
+}
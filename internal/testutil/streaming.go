@@ -0,0 +1,95 @@
+package testutil
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Goroutine leak detection settings.
+const (
+	leakPollInterval = 10 * time.Millisecond
+	leakTimeout      = 2 * time.Second
+)
+
+// streamInterval is the delay between lines written by NewEndlessStreamServer.
+const streamInterval = 5 * time.Millisecond
+
+// NewEndlessStreamServer starts a server that writes the preamble lines and then the
+// lines produced by next, one every few milliseconds, until the client disconnects.
+// It simulates a long-running stream so tests can check that consumers stop cleanly.
+func NewEndlessStreamServer(
+	t *testing.T,
+	contentType string,
+	preamble []string,
+	next func(i int) string,
+) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			return
+		}
+
+		for _, line := range preamble {
+			_, _ = fmt.Fprint(w, line)
+		}
+		flusher.Flush()
+
+		for i := 0; ; i++ {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(streamInterval):
+			}
+
+			if _, err := fmt.Fprint(w, next(i)); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// RequireGoroutineExits fails the test if a goroutine whose stack contains function
+// is still running after a short grace period.
+//
+// Tests using it should not call t.Parallel(), since it inspects every goroutine in the process.
+func RequireGoroutineExits(t *testing.T, function string) {
+	t.Helper()
+
+	deadline := time.Now().Add(leakTimeout)
+	for {
+		stacks := allStacks()
+		if !bytes.Contains(stacks, []byte(function)) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutine running %s did not exit:\n%s", function, stacks)
+		}
+		time.Sleep(leakPollInterval)
+	}
+}
+
+// allStacks returns the stack traces of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,369 @@
+// Package moderation provides a provider wrapper that screens the input messages of completion
+// requests, and the text inputs of embedding requests, with a moderation provider before they
+// reach the wrapped provider.
+//
+// The moderation provider and the wrapped provider are independent, so the same safeguard works
+// in front of any provider:
+//
+//	base, err := anthropic.New()
+//	moderator, err := openai.New()
+//	provider, err := moderation.New(base, moderator)
+//
+//	response, err := provider.Completion(ctx, params)
+//	if errors.Is(err, errors.ErrContentFilter) {
+//		// The input was flagged and never sent to Anthropic.
+//	}
+//
+// The text of each message is moderated as a separate input; images and other non-text content
+// parts are not screened. If the moderation request itself fails, its error is returned and the
+// request is not attempted, so unscreened input is never sent.
+//
+// Only completions, embeddings, and model listing are forwarded, so the wrapper's capabilities
+// report no other features even if the wrapped provider has them.
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// contentTypeText is the type of text content parts, the only parts that are moderated.
+const contentTypeText = "text"
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+// FlaggedError is the underlying error of the errors.ContentFilterError returned when a message is blocked.
+type FlaggedError struct {
+	// Message is the index of the blocked message in CompletionParams.Messages, or of the
+	// blocked input in EmbeddingParams.Input.
+	Message int
+
+	// Result is the moderation result of the message.
+	Result providers.ModerationResult
+
+	// embedding reports whether the blocked text is an embedding input.
+	embedding bool
+}
+
+// Option configures the moderation wrapper.
+type Option func(*options) error
+
+// Provider wraps another provider and moderates the input messages of its completion requests.
+type Provider struct {
+	moderator providers.ModerationProvider
+	opts      *options
+	provider  providers.Provider
+}
+
+// options holds the moderation configuration.
+type options struct {
+	blockIf func(providers.ModerationResult) bool
+	model   string
+	roles   []string
+}
+
+// New wraps provider so that completion requests are moderated by moderator first.
+// It returns an errors.UnsupportedParamError if moderator reports that it does not support moderation,
+// rather than failing every completion later.
+func New(provider providers.Provider, moderator providers.ModerationProvider, opts ...Option) (*Provider, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("provider cannot be nil")
+	}
+	if moderator == nil {
+		return nil, fmt.Errorf("moderator cannot be nil")
+	}
+	if cp, ok := moderator.(providers.CapabilityProvider); ok && !cp.Capabilities().Moderation {
+		return nil, errors.NewUnsupportedParamError(moderator.Name(), "moderation")
+	}
+
+	o := &options{
+		blockIf: IsFlagged,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Provider{moderator: moderator, opts: o, provider: provider}, nil
+}
+
+// WithBlockIf replaces the predicate that decides whether a moderation result blocks the request,
+// e.g. to block on category scores below the provider's own thresholds. The default is IsFlagged.
+func WithBlockIf(fn func(providers.ModerationResult) bool) Option {
+	return func(o *options) error {
+		if fn == nil {
+			return fmt.Errorf("block predicate cannot be nil")
+		}
+
+		o.blockIf = fn
+		return nil
+	}
+}
+
+// WithModel sets the moderation model. The moderator's default model is used if it is not set.
+func WithModel(model string) Option {
+	return func(o *options) error {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			return fmt.Errorf("model cannot be empty")
+		}
+
+		o.model = model
+		return nil
+	}
+}
+
+// WithRoles limits moderation to messages with the given roles, such as providers.RoleUser.
+// Messages of every role are moderated by default.
+func WithRoles(roles ...string) Option {
+	return func(o *options) error {
+		if len(roles) == 0 {
+			return fmt.Errorf("at least one role is required")
+		}
+
+		o.roles = roles
+		return nil
+	}
+}
+
+// IsFlagged reports whether the moderator flagged the input.
+func IsFlagged(result providers.ModerationResult) bool {
+	return result.Flagged
+}
+
+// Error implements the error interface.
+func (e *FlaggedError) Error() string {
+	blocked := "message"
+	if e.embedding {
+		blocked = "input"
+	}
+
+	categories := flaggedCategories(e.Result)
+	if len(categories) == 0 {
+		return fmt.Sprintf("%s %d blocked by moderation", blocked, e.Message)
+	}
+
+	return fmt.Sprintf("%s %d blocked by moderation: %s", blocked, e.Message, strings.Join(categories, ", "))
+}
+
+// Capabilities returns the capabilities of the wrapped provider, without the features the
+// wrapper does not forward.
+func (p *Provider) Capabilities() providers.Capabilities {
+	cp, ok := p.provider.(providers.CapabilityProvider)
+	if !ok {
+		return providers.Capabilities{
+			Completion:          true,
+			CompletionStreaming: true,
+		}
+	}
+
+	caps := cp.Capabilities()
+	caps.CountTokens = false
+	caps.ImageGeneration = false
+	caps.Moderation = false
+	caps.Rerank = false
+	caps.Speech = false
+	caps.Transcription = false
+
+	return caps
+}
+
+// Completion moderates the input messages and, if none is blocked, performs the chat completion request.
+func (p *Provider) Completion(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.ChatCompletion, error) {
+	indexes, inputs := p.messageInputs(params.Messages)
+	if err := p.moderate(ctx, indexes, inputs, false); err != nil {
+		return nil, err
+	}
+
+	return p.provider.Completion(ctx, params)
+}
+
+// CompletionStream moderates the input messages and, if none is blocked, performs the streaming chat
+// completion request. Moderation finishes before the stream is opened.
+func (p *Provider) CompletionStream(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	indexes, inputs := p.messageInputs(params.Messages)
+	if err := p.moderate(ctx, indexes, inputs, false); err != nil {
+		chunks := make(chan providers.ChatCompletionChunk)
+		errs := make(chan error, 1)
+		close(chunks)
+		errs <- err
+		close(errs)
+		return chunks, errs
+	}
+
+	return p.provider.CompletionStream(ctx, params)
+}
+
+// ConvertError delegates to the wrapped provider when it implements providers.ErrorConverter.
+func (p *Provider) ConvertError(err error) error {
+	if ec, ok := p.provider.(providers.ErrorConverter); ok {
+		return ec.ConvertError(err)
+	}
+
+	return err
+}
+
+// Embedding moderates the text inputs and, if none is blocked, generates embeddings with the wrapped
+// provider. Token inputs are not moderated. It returns an errors.UnsupportedParamError if the wrapped
+// provider does not support embeddings.
+func (p *Provider) Embedding(
+	ctx context.Context,
+	params providers.EmbeddingParams,
+) (*providers.EmbeddingResponse, error) {
+	ep, ok := p.provider.(providers.EmbeddingProvider)
+	if !ok {
+		return nil, errors.NewUnsupportedParamError(p.provider.Name(), "embedding")
+	}
+
+	indexes, inputs := embeddingInputs(params.Input)
+	if err := p.moderate(ctx, indexes, inputs, true); err != nil {
+		return nil, err
+	}
+
+	return ep.Embedding(ctx, params)
+}
+
+// ListModels lists the wrapped provider's models.
+func (p *Provider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
+	ml, ok := p.provider.(providers.ModelLister)
+	if !ok {
+		return nil, errors.NewUnsupportedParamError(p.provider.Name(), "list_models")
+	}
+
+	return ml.ListModels(ctx)
+}
+
+// Name returns the name of the wrapped provider.
+func (p *Provider) Name() string {
+	return p.provider.Name()
+}
+
+// Unwrap returns the wrapped provider.
+func (p *Provider) Unwrap() providers.Provider {
+	return p.provider
+}
+
+// messageInputs returns the text of each moderated message, along with the message indexes.
+func (p *Provider) messageInputs(messages []providers.Message) (indexes []int, inputs []string) {
+	for i, msg := range messages {
+		if len(p.opts.roles) > 0 && !slices.Contains(p.opts.roles, msg.Role) {
+			continue
+		}
+		if text := messageText(msg); text != "" {
+			indexes = append(indexes, i)
+			inputs = append(inputs, text)
+		}
+	}
+
+	return indexes, inputs
+}
+
+// moderate sends the inputs to the moderator in one request and returns an errors.ContentFilterError
+// for the first blocked input. indexes holds the position of each input in the request.
+func (p *Provider) moderate(ctx context.Context, indexes []int, inputs []string, embedding bool) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	resp, err := p.moderator.Moderation(ctx, providers.ModerationParams{
+		Model: p.opts.model,
+		Input: inputs,
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.Results) != len(inputs) {
+		return errors.NewProviderError(p.moderator.Name(), fmt.Errorf(
+			"moderation returned %d results for %d inputs", len(resp.Results), len(inputs),
+		))
+	}
+
+	for i, result := range resp.Results {
+		if p.opts.blockIf(result) {
+			return errors.NewContentFilterError(p.moderator.Name(), &FlaggedError{
+				Message:   indexes[i],
+				Result:    result,
+				embedding: embedding,
+			})
+		}
+	}
+
+	return nil
+}
+
+// embeddingInputs returns the text inputs of an embedding request, along with their indexes.
+// Token inputs are skipped.
+func embeddingInputs(input any) (indexes []int, inputs []string) {
+	var items []any
+	switch v := input.(type) {
+	case string:
+		items = []any{v}
+	case []string:
+		for _, s := range v {
+			items = append(items, s)
+		}
+	case []any:
+		items = v
+	}
+
+	for i, item := range items {
+		if text, ok := item.(string); ok && text != "" {
+			indexes = append(indexes, i)
+			inputs = append(inputs, text)
+		}
+	}
+
+	return indexes, inputs
+}
+
+// flaggedCategories returns the sorted names of the categories flagged in result.
+func flaggedCategories(result providers.ModerationResult) []string {
+	var categories []string
+	for category, flagged := range result.Categories {
+		if flagged {
+			categories = append(categories, category)
+		}
+	}
+	slices.Sort(categories)
+
+	return categories
+}
+
+// messageText returns the text of a message, joining its text content parts.
+func messageText(msg providers.Message) string {
+	if !msg.IsMultiModal() {
+		return msg.ContentString()
+	}
+
+	var texts []string
+	for _, part := range msg.ContentParts() {
+		if part.Type == contentTypeText && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+
+	return strings.Join(texts, "\n")
+}
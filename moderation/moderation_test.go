@@ -0,0 +1,364 @@
+package moderation
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil provider and moderator", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := New(nil, testutil.NewMockProvider())
+		require.Nil(t, p)
+		require.Error(t, err)
+
+		p, err = New(testutil.NewMockProvider(), nil)
+		require.Nil(t, p)
+		require.Error(t, err)
+	})
+
+	t.Run("rejects moderators without moderation", func(t *testing.T) {
+		t.Parallel()
+
+		moderator := testutil.NewMockProvider()
+		moderator.CapabilitiesFunc = func() providers.Capabilities {
+			return providers.Capabilities{Completion: true, Moderation: false}
+		}
+
+		p, err := New(testutil.NewMockProvider(), moderator)
+		require.Nil(t, p)
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			opt  Option
+		}{
+			{name: "nil block predicate", opt: WithBlockIf(nil)},
+			{name: "empty model", opt: WithModel(" ")},
+			{name: "no roles", opt: WithRoles()},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := New(testutil.NewMockProvider(), testutil.NewMockProvider(), tc.opt)
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	messages := []providers.Message{
+		{Role: providers.RoleSystem, Content: "You are a helpful assistant."},
+		{Role: providers.RoleUser, Content: []providers.ContentPart{
+			{Type: "text", Text: "What is in this picture?"},
+			{Type: "image_url", ImageURL: &providers.ImageURL{URL: "https://example.com/cat.png"}},
+			{Type: "text", Text: "Be brief."},
+		}},
+		{Role: providers.RoleAssistant, Content: ""},
+		{Role: providers.RoleUser, Content: "Something harmful."},
+	}
+
+	t.Run("sends unflagged requests", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		moderator := testutil.NewMockProvider()
+		p, err := New(provider, moderator, WithModel("omni-moderation-latest"))
+		require.NoError(t, err)
+
+		resp, err := p.Completion(context.Background(), providers.CompletionParams{Model: "m", Messages: messages})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		require.Len(t, provider.CompletionCalls, 1)
+		require.Equal(t, []providers.ModerationParams{{
+			Model: "omni-moderation-latest",
+			Input: []string{
+				"You are a helpful assistant.",
+				"What is in this picture?\nBe brief.",
+				"Something harmful.",
+			},
+		}}, moderator.ModerationCalls)
+	})
+
+	t.Run("blocks flagged messages", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		moderator := flaggingModerator("Something harmful.")
+		p, err := New(provider, moderator)
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{Model: "m", Messages: messages})
+		require.ErrorIs(t, err, errors.ErrContentFilter)
+		require.Empty(t, provider.CompletionCalls)
+
+		var contentFilterErr *errors.ContentFilterError
+		require.ErrorAs(t, err, &contentFilterErr)
+		require.Equal(t, "mock", contentFilterErr.Provider)
+
+		var flaggedErr *FlaggedError
+		require.ErrorAs(t, err, &flaggedErr)
+		require.Equal(t, 3, flaggedErr.Message)
+		require.Equal(t, "message 3 blocked by moderation: harassment, violence", flaggedErr.Error())
+	})
+
+	t.Run("moderates only the configured roles", func(t *testing.T) {
+		t.Parallel()
+
+		moderator := testutil.NewMockProvider()
+		p, err := New(testutil.NewMockProvider(), moderator, WithRoles(providers.RoleSystem))
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{Model: "m", Messages: messages})
+		require.NoError(t, err)
+		require.Len(t, moderator.ModerationCalls, 1)
+		require.Equal(t, []string{"You are a helpful assistant."}, moderator.ModerationCalls[0].Input)
+	})
+
+	t.Run("skips moderation without text", func(t *testing.T) {
+		t.Parallel()
+
+		moderator := testutil.NewMockProvider()
+		p, err := New(testutil.NewMockProvider(), moderator)
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{
+			Model:    "m",
+			Messages: []providers.Message{{Role: providers.RoleUser, Content: ""}},
+		})
+		require.NoError(t, err)
+		require.Empty(t, moderator.ModerationCalls)
+	})
+
+	t.Run("blocks with a custom predicate", func(t *testing.T) {
+		t.Parallel()
+
+		moderator := testutil.NewMockProvider()
+		moderator.ModerationFunc = func(
+			ctx context.Context,
+			params providers.ModerationParams,
+		) (*providers.ModerationResponse, error) {
+			results := make([]providers.ModerationResult, len(params.Input))
+			results[1].CategoryScores = map[string]float64{"violence": 0.4}
+			return &providers.ModerationResponse{Results: results}, nil
+		}
+
+		p, err := New(testutil.NewMockProvider(), moderator, WithBlockIf(func(result providers.ModerationResult) bool {
+			return result.CategoryScores["violence"] > 0.3
+		}))
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{Model: "m", Messages: messages})
+		require.ErrorIs(t, err, errors.ErrContentFilter)
+
+		var flaggedErr *FlaggedError
+		require.ErrorAs(t, err, &flaggedErr)
+		require.Equal(t, 1, flaggedErr.Message)
+		require.Equal(t, "message 1 blocked by moderation", flaggedErr.Error())
+	})
+
+	t.Run("returns moderation errors without sending the request", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		moderator := testutil.NewMockProvider()
+		moderator.ModerationFunc = func(
+			ctx context.Context,
+			params providers.ModerationParams,
+		) (*providers.ModerationResponse, error) {
+			return nil, errors.NewRateLimitError("mock", stderrors.New("slow down"))
+		}
+
+		p, err := New(provider, moderator)
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{Model: "m", Messages: messages})
+		require.ErrorIs(t, err, errors.ErrRateLimit)
+		require.Empty(t, provider.CompletionCalls)
+	})
+
+	t.Run("rejects missing results", func(t *testing.T) {
+		t.Parallel()
+
+		moderator := testutil.NewMockProvider()
+		moderator.ModerationFunc = func(
+			ctx context.Context,
+			params providers.ModerationParams,
+		) (*providers.ModerationResponse, error) {
+			return &providers.ModerationResponse{}, nil
+		}
+
+		p, err := New(testutil.NewMockProvider(), moderator)
+		require.NoError(t, err)
+
+		_, err = p.Completion(context.Background(), providers.CompletionParams{Model: "m", Messages: messages})
+		require.ErrorIs(t, err, errors.ErrProvider)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("streams unflagged requests", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := New(testutil.NewMockProvider(), testutil.NewMockProvider())
+		require.NoError(t, err)
+
+		resp, err := providers.Collect(p.CompletionStream(context.Background(), providers.CompletionParams{
+			Model:    "m",
+			Messages: testutil.SimpleMessages(),
+		}))
+		require.NoError(t, err)
+		require.Equal(t, "Hello World", resp.Choices[0].Message.ContentString())
+	})
+
+	t.Run("blocks flagged messages before streaming", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		p, err := New(provider, flaggingModerator("Something harmful."))
+		require.NoError(t, err)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{
+			Model:    "m",
+			Messages: []providers.Message{{Role: providers.RoleUser, Content: "Something harmful."}},
+		})
+
+		_, ok := <-chunks
+		require.False(t, ok)
+		require.ErrorIs(t, <-errs, errors.ErrContentFilter)
+		require.Empty(t, provider.CompletionStreamCalls)
+	})
+}
+
+func TestEmbedding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("embeds unflagged inputs", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		moderator := testutil.NewMockProvider()
+		p, err := New(provider, moderator)
+		require.NoError(t, err)
+
+		_, err = p.Embedding(context.Background(), providers.EmbeddingParams{
+			Model: "m",
+			Input: []any{"first", []int{1, 2}, "second"},
+		})
+		require.NoError(t, err)
+		require.Len(t, provider.EmbeddingCalls, 1)
+		require.Equal(t, []string{"first", "second"}, moderator.ModerationCalls[0].Input)
+	})
+
+	t.Run("blocks flagged inputs", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewMockProvider()
+		p, err := New(provider, flaggingModerator("Something harmful."))
+		require.NoError(t, err)
+
+		_, err = p.Embedding(context.Background(), providers.EmbeddingParams{
+			Model: "m",
+			Input: []string{"Something fine.", "Something harmful."},
+		})
+		require.ErrorIs(t, err, errors.ErrContentFilter)
+		require.Empty(t, provider.EmbeddingCalls)
+
+		var flaggedErr *FlaggedError
+		require.ErrorAs(t, err, &flaggedErr)
+		require.Equal(t, 1, flaggedErr.Message)
+		require.Equal(t, "input 1 blocked by moderation: harassment, violence", flaggedErr.Error())
+	})
+
+	t.Run("rejects providers without embeddings", func(t *testing.T) {
+		t.Parallel()
+
+		moderator := testutil.NewMockProvider()
+		p, err := New(completionOnly{testutil.NewMockProvider()}, moderator)
+		require.NoError(t, err)
+
+		_, err = p.Embedding(context.Background(), providers.EmbeddingParams{Model: "m", Input: "text"})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+		require.Empty(t, moderator.ModerationCalls)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewMockProvider()
+	provider.CapabilitiesFunc = func() providers.Capabilities {
+		return providers.Capabilities{
+			Completion:      true,
+			CountTokens:     true,
+			Embedding:       true,
+			ImageGeneration: true,
+			ListModels:      true,
+			Moderation:      true,
+			Rerank:          true,
+			Speech:          true,
+			Transcription:   true,
+		}
+	}
+
+	p, err := New(provider, testutil.NewMockProvider())
+	require.NoError(t, err)
+	require.Equal(t, providers.Capabilities{
+		Completion: true,
+		Embedding:  true,
+		ListModels: true,
+	}, p.Capabilities())
+
+	p, err = New(completionOnly{provider}, testutil.NewMockProvider())
+	require.NoError(t, err)
+	require.Equal(t, providers.Capabilities{Completion: true, CompletionStreaming: true}, p.Capabilities())
+}
+
+// completionOnly hides every interface of a provider but providers.Provider.
+type completionOnly struct {
+	providers.Provider
+}
+
+// flaggingModerator returns a moderator that flags the given input for harassment and violence.
+func flaggingModerator(flagged string) *testutil.MockProvider {
+	moderator := testutil.NewMockProvider()
+	moderator.ModerationFunc = func(
+		ctx context.Context,
+		params providers.ModerationParams,
+	) (*providers.ModerationResponse, error) {
+		results := make([]providers.ModerationResult, len(params.Input))
+		for i, input := range params.Input {
+			if input == flagged {
+				results[i] = providers.ModerationResult{
+					Flagged:        true,
+					Categories:     map[string]bool{"hate": false, "harassment": true, "violence": true},
+					CategoryScores: map[string]float64{"hate": 0.01, "harassment": 0.8, "violence": 0.9},
+				}
+			}
+		}
+		return &providers.ModerationResponse{Results: results}, nil
+	}
+
+	return moderator
+}
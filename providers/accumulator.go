@@ -0,0 +1,271 @@
+package providers
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// objectChatCompletion is the object type of accumulated completions.
+const objectChatCompletion = "chat.completion"
+
+// Accumulator rebuilds a ChatCompletion from streaming chunks.
+//
+// It merges content, reasoning, audio, citations, and tool calls per choice, keeps the last non-empty
+// finish reason of each choice, and keeps the last usage report (usually sent in the
+// final chunk).
+//
+// Tool call fragments are matched by ID, falling back to ToolCall.Index for fragments
+// without an ID. Both streaming conventions are supported: incremental argument deltas
+// (OpenAI), and updates that re-send the whole accumulated call under the same ID
+// (Anthropic), which are detected because their arguments extend what has been seen.
+//
+// An Accumulator is not safe for concurrent use.
+type Accumulator struct {
+	choices    map[int]*choiceAccumulator
+	completion ChatCompletion
+	count      int
+}
+
+// choiceAccumulator holds the accumulated state of a single choice.
+type choiceAccumulator struct {
+	audio        *Audio
+	audioData    strings.Builder
+	audioText    strings.Builder
+	citations    []Citation
+	content      strings.Builder
+	finishReason string
+	logprobs     []TokenLogprob
+	reasoning    strings.Builder
+	role         string
+	toolCalls    []ToolCall
+	toolsByID    map[string]int
+	toolsByIndex map[int]int
+}
+
+// NewAccumulator creates an empty Accumulator.
+func NewAccumulator() *Accumulator {
+	return &Accumulator{
+		choices: make(map[int]*choiceAccumulator),
+	}
+}
+
+// Collect drains a stream and returns the accumulated completion.
+// If the stream reports an error, the completion accumulated so far is returned with it.
+func Collect(chunks <-chan ChatCompletionChunk, errs <-chan error) (*ChatCompletion, error) {
+	acc := NewAccumulator()
+	for chunk := range chunks {
+		acc.Add(chunk)
+	}
+
+	return acc.ChatCompletion(), <-errs
+}
+
+// Add merges a chunk into the accumulated completion.
+func (a *Accumulator) Add(chunk ChatCompletionChunk) {
+	a.count++
+
+	if chunk.ID != "" {
+		a.completion.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		a.completion.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		a.completion.Model = chunk.Model
+	}
+	if chunk.Provider != "" {
+		a.completion.Provider = chunk.Provider
+	}
+	if chunk.SystemFingerprint != "" {
+		a.completion.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		usage := *chunk.Usage
+		a.completion.Usage = &usage
+	}
+
+	for _, choice := range chunk.Choices {
+		a.choice(choice.Index).add(choice)
+	}
+}
+
+// ChatCompletion returns the completion accumulated so far, or nil if no chunks were added.
+// The returned value is a snapshot; adding further chunks does not modify it.
+func (a *Accumulator) ChatCompletion() *ChatCompletion {
+	if a.count == 0 {
+		return nil
+	}
+
+	completion := a.completion
+	completion.Object = objectChatCompletion
+
+	if a.completion.Usage != nil {
+		usage := *a.completion.Usage
+		completion.Usage = &usage
+	}
+
+	indexes := make([]int, 0, len(a.choices))
+	for index := range a.choices {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	completion.Choices = make([]Choice, 0, len(indexes))
+	for _, index := range indexes {
+		completion.Choices = append(completion.Choices, a.choices[index].choice(index))
+	}
+
+	return &completion
+}
+
+// choice returns the accumulator for the choice with the given index, creating it if needed.
+func (a *Accumulator) choice(index int) *choiceAccumulator {
+	c, ok := a.choices[index]
+	if !ok {
+		c = &choiceAccumulator{
+			toolsByID:    make(map[string]int),
+			toolsByIndex: make(map[int]int),
+		}
+		a.choices[index] = c
+	}
+
+	return c
+}
+
+// add merges a chunk choice into the accumulated choice.
+func (c *choiceAccumulator) add(choice ChunkChoice) {
+	if choice.Delta.Role != "" {
+		c.role = choice.Delta.Role
+	}
+	c.content.WriteString(choice.Delta.Content)
+	if choice.Delta.Reasoning != nil {
+		c.reasoning.WriteString(choice.Delta.Reasoning.Content)
+	}
+	if choice.Delta.Audio != nil {
+		c.addAudio(choice.Delta.Audio)
+	}
+	for _, tc := range choice.Delta.ToolCalls {
+		c.addToolCall(tc)
+	}
+	c.citations = append(c.citations, choice.Delta.Citations...)
+	if choice.FinishReason != "" {
+		c.finishReason = choice.FinishReason
+	}
+	if choice.Logprobs != nil {
+		c.logprobs = append(c.logprobs, choice.Logprobs.Content...)
+	}
+}
+
+// addAudio merges an audio fragment into the accumulated audio.
+func (c *choiceAccumulator) addAudio(audio *Audio) {
+	if c.audio == nil {
+		c.audio = &Audio{}
+	}
+	if audio.ID != "" {
+		c.audio.ID = audio.ID
+	}
+	if audio.Format != "" {
+		c.audio.Format = audio.Format
+	}
+	if audio.ExpiresAt != 0 {
+		c.audio.ExpiresAt = audio.ExpiresAt
+	}
+	c.audioData.WriteString(audio.Data)
+	c.audioText.WriteString(audio.Transcript)
+}
+
+// addToolCall merges a tool call fragment into the accumulated tool calls.
+func (c *choiceAccumulator) addToolCall(tc ToolCall) {
+	slot, ok := c.toolSlot(tc)
+	if !ok {
+		c.toolCalls = append(c.toolCalls, ToolCall{Index: tc.Index})
+		slot = len(c.toolCalls) - 1
+	}
+
+	c.toolsByIndex[tc.Index] = slot
+	existing := &c.toolCalls[slot]
+
+	if tc.ID != "" {
+		c.toolsByID[tc.ID] = slot
+		existing.ID = tc.ID
+	}
+	if tc.Type != "" {
+		existing.Type = tc.Type
+	}
+	if tc.Function.Name != "" {
+		existing.Function.Name = tc.Function.Name
+	}
+	if tc.Signature != "" {
+		existing.Signature = tc.Signature
+	}
+
+	// A fragment carrying the call's ID whose arguments extend the accumulated arguments
+	// is a cumulative re-send; anything else is an incremental delta.
+	if tc.ID != "" && strings.HasPrefix(tc.Function.Arguments, existing.Function.Arguments) {
+		existing.Function.Arguments = tc.Function.Arguments
+		return
+	}
+	existing.Function.Arguments += tc.Function.Arguments
+}
+
+// choice builds the accumulated Choice.
+func (c *choiceAccumulator) choice(index int) Choice {
+	role := c.role
+	if role == "" {
+		role = RoleAssistant
+	}
+
+	msg := Message{
+		Role:    role,
+		Content: c.content.String(),
+	}
+	if c.reasoning.Len() > 0 {
+		msg.Reasoning = &Reasoning{Content: c.reasoning.String()}
+	}
+	if c.audio != nil {
+		audio := *c.audio
+		audio.Data = c.audioData.String()
+		audio.Transcript = c.audioText.String()
+		msg.Audio = &audio
+	}
+	if len(c.toolCalls) > 0 {
+		msg.ToolCalls = make([]ToolCall, len(c.toolCalls))
+		copy(msg.ToolCalls, c.toolCalls)
+	}
+	if len(c.citations) > 0 {
+		msg.Citations = slices.Clone(c.citations)
+	}
+
+	result := Choice{
+		Index:        index,
+		Message:      msg,
+		FinishReason: c.finishReason,
+	}
+	if len(c.logprobs) > 0 {
+		result.Logprobs = &Logprobs{Content: slices.Clone(c.logprobs)}
+	}
+
+	return result
+}
+
+// toolSlot finds the accumulated tool call a fragment belongs to.
+// Fragments with an ID match by ID only, since a new ID always starts a new call.
+// Fragments without an ID continue the call most recently seen at the same index.
+func (c *choiceAccumulator) toolSlot(tc ToolCall) (int, bool) {
+	if tc.ID != "" {
+		if slot, ok := c.toolsByID[tc.ID]; ok {
+			return slot, true
+		}
+
+		// The first fragment for an index may arrive without an ID and the ID later.
+		if slot, ok := c.toolsByIndex[tc.Index]; ok && c.toolCalls[slot].ID == "" {
+			return slot, true
+		}
+
+		return 0, false
+	}
+
+	slot, ok := c.toolsByIndex[tc.Index]
+	return slot, ok
+}
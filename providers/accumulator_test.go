@@ -0,0 +1,297 @@
+package providers
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccumulator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns nil without chunks", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, NewAccumulator().ChatCompletion())
+	})
+
+	t.Run("merges content, reasoning, finish reason and usage", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{
+			ID:      "chatcmpl-1",
+			Created: 1700000000,
+			Model:   "gpt-4o",
+			Choices: []ChunkChoice{{Delta: ChunkDelta{Role: RoleAssistant}}},
+		})
+		acc.Add(ChatCompletionChunk{
+			ID:      "chatcmpl-1",
+			Choices: []ChunkChoice{{Delta: ChunkDelta{Reasoning: &Reasoning{Content: "Let me "}}}},
+		})
+		acc.Add(ChatCompletionChunk{
+			ID:      "chatcmpl-1",
+			Choices: []ChunkChoice{{Delta: ChunkDelta{Reasoning: &Reasoning{Content: "think."}}}},
+		})
+		acc.Add(ChatCompletionChunk{
+			ID:      "chatcmpl-1",
+			Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "Hello"}}},
+		})
+		acc.Add(ChatCompletionChunk{
+			ID:      "chatcmpl-1",
+			Choices: []ChunkChoice{{Delta: ChunkDelta{Content: ", world"}, FinishReason: FinishReasonStop}},
+		})
+		acc.Add(ChatCompletionChunk{
+			ID:      "chatcmpl-1",
+			Choices: []ChunkChoice{},
+			Usage:   &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		})
+
+		got := acc.ChatCompletion()
+		require.Equal(t, "chatcmpl-1", got.ID)
+		require.Equal(t, objectChatCompletion, got.Object)
+		require.Equal(t, int64(1700000000), got.Created)
+		require.Equal(t, "gpt-4o", got.Model)
+		require.Len(t, got.Choices, 1)
+		require.Equal(t, RoleAssistant, got.Choices[0].Message.Role)
+		require.Equal(t, "Hello, world", got.Choices[0].Message.Content)
+		require.Equal(t, "Let me think.", got.Choices[0].Message.Reasoning.Content)
+		require.Equal(t, FinishReasonStop, got.Choices[0].FinishReason)
+		require.Equal(t, &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, got.Usage)
+	})
+
+	t.Run("merges audio", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{
+			Role:  RoleAssistant,
+			Audio: &Audio{ID: "audio_1", Format: "wav", Transcript: "Hel"},
+		}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Audio: &Audio{Data: "UklG"}}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Audio: &Audio{Transcript: "lo"}}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{
+			Audio: &Audio{Data: "RiQA", ExpiresAt: 1700003600},
+		}}}})
+
+		got := acc.ChatCompletion()
+		require.Equal(t, &Audio{
+			ID:         "audio_1",
+			Data:       "UklGRiQA",
+			Transcript: "Hello",
+			Format:     "wav",
+			ExpiresAt:  1700003600,
+		}, got.Choices[0].Message.Audio)
+
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Audio: &Audio{Data: "AAAA"}}}}})
+		require.Equal(t, "UklGRiQA", got.Choices[0].Message.Audio.Data, "snapshot changed by later chunks")
+	})
+
+	t.Run("keeps choices separate and ordered by index", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{
+			{Index: 1, Delta: ChunkDelta{Content: "B"}},
+			{Index: 0, Delta: ChunkDelta{Content: "A"}},
+		}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{
+			{Index: 0, Delta: ChunkDelta{Content: "a"}, FinishReason: FinishReasonLength},
+			{Index: 1, Delta: ChunkDelta{Content: "b"}, FinishReason: FinishReasonStop},
+		}})
+
+		got := acc.ChatCompletion()
+		require.Len(t, got.Choices, 2)
+		require.Equal(t, 0, got.Choices[0].Index)
+		require.Equal(t, "Aa", got.Choices[0].Message.Content)
+		require.Equal(t, FinishReasonLength, got.Choices[0].FinishReason)
+		require.Equal(t, 1, got.Choices[1].Index)
+		require.Equal(t, "Bb", got.Choices[1].Message.Content)
+		require.Equal(t, FinishReasonStop, got.Choices[1].FinishReason)
+		require.Nil(t, got.Choices[0].Message.Reasoning)
+	})
+
+	t.Run("merges incremental tool call fragments keyed by index", func(t *testing.T) {
+		t.Parallel()
+
+		// OpenAI sends the ID and name once, then argument fragments keyed only by index.
+		acc := NewAccumulator()
+		for _, tc := range []ToolCall{
+			{Index: 0, ID: "call_a", Type: "function", Function: FunctionCall{Name: "get_weather"}},
+			{Index: 0, Function: FunctionCall{Arguments: `{"loc`}},
+			{Index: 1, ID: "call_b", Type: "function", Function: FunctionCall{Name: "get_time"}},
+			{Index: 0, Function: FunctionCall{Arguments: `ation":"Paris"}`}},
+			{Index: 1, Function: FunctionCall{Arguments: `{"tz":"CET"}`}},
+		} {
+			acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{ToolCalls: []ToolCall{tc}}}}})
+		}
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{FinishReason: FinishReasonToolCalls}}})
+
+		got := acc.ChatCompletion()
+		toolCalls := got.Choices[0].Message.ToolCalls
+		require.Len(t, toolCalls, 2)
+		require.Equal(t, "call_a", toolCalls[0].ID)
+		require.Equal(t, "function", toolCalls[0].Type)
+		require.Equal(t, "get_weather", toolCalls[0].Function.Name)
+		require.JSONEq(t, `{"location":"Paris"}`, toolCalls[0].Function.Arguments)
+		require.Equal(t, "call_b", toolCalls[1].ID)
+		require.Equal(t, "get_time", toolCalls[1].Function.Name)
+		require.JSONEq(t, `{"tz":"CET"}`, toolCalls[1].Function.Arguments)
+		require.Equal(t, FinishReasonToolCalls, got.Choices[0].FinishReason)
+	})
+
+	t.Run("merges incremental fragments that repeat the ID", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		for _, args := range []string{`{"a":`, `1}`} {
+			acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{ToolCalls: []ToolCall{
+				{ID: "call_a", Function: FunctionCall{Name: "f", Arguments: args}},
+			}}}}})
+		}
+
+		got := acc.ChatCompletion()
+		require.JSONEq(t, `{"a":1}`, got.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	})
+
+	t.Run("keeps tool call signatures", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		for _, tc := range []ToolCall{
+			{ID: "call_a", Function: FunctionCall{Name: "f", Arguments: `{"a":`}, Signature: "sig"},
+			{ID: "call_a", Function: FunctionCall{Arguments: `1}`}},
+		} {
+			acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{ToolCalls: []ToolCall{tc}}}}})
+		}
+
+		toolCall := acc.ChatCompletion().Choices[0].Message.ToolCalls[0]
+		require.JSONEq(t, `{"a":1}`, toolCall.Function.Arguments)
+		require.Equal(t, "sig", toolCall.Signature)
+	})
+
+	t.Run("replaces cumulative tool call re-sends", func(t *testing.T) {
+		t.Parallel()
+
+		// Anthropic re-sends the whole accumulated tool call on every input_json_delta.
+		acc := NewAccumulator()
+		for _, tc := range []ToolCall{
+			{Index: 0, ID: "toolu_1", Type: "function", Function: FunctionCall{Name: "search"}},
+			{Index: 0, ID: "toolu_1", Type: "function", Function: FunctionCall{Name: "search", Arguments: `{"q":`}},
+			{Index: 0, ID: "toolu_1", Type: "function", Function: FunctionCall{Name: "search", Arguments: `{"q":"go"}`}},
+			{Index: 1, ID: "toolu_2", Type: "function", Function: FunctionCall{Name: "fetch"}},
+			{Index: 1, ID: "toolu_2", Type: "function", Function: FunctionCall{Name: "fetch", Arguments: `{}`}},
+		} {
+			acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{ToolCalls: []ToolCall{tc}}}}})
+		}
+
+		toolCalls := acc.ChatCompletion().Choices[0].Message.ToolCalls
+		require.Len(t, toolCalls, 2)
+		require.Equal(t, `{"q":"go"}`, toolCalls[0].Function.Arguments)
+		require.Equal(t, "search", toolCalls[0].Function.Name)
+		require.Equal(t, `{}`, toolCalls[1].Function.Arguments)
+		require.Equal(t, "toolu_2", toolCalls[1].ID)
+	})
+
+	t.Run("distinguishes calls with different IDs at the same index", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{ToolCalls: []ToolCall{
+			{ID: "call_0", Function: FunctionCall{Name: "a", Arguments: `{}`}},
+		}}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{ToolCalls: []ToolCall{
+			{ID: "call_1", Function: FunctionCall{Name: "b", Arguments: `{}`}},
+		}}}}})
+
+		toolCalls := acc.ChatCompletion().Choices[0].Message.ToolCalls
+		require.Len(t, toolCalls, 2)
+		require.Equal(t, "a", toolCalls[0].Function.Name)
+		require.Equal(t, "b", toolCalls[1].Function.Name)
+	})
+
+	t.Run("concatenates logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{
+			Delta:    ChunkDelta{Content: "Hi"},
+			Logprobs: &Logprobs{Content: []TokenLogprob{{Token: "Hi", Logprob: -0.1}}},
+		}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "!"}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{
+			Delta:    ChunkDelta{Content: "?"},
+			Logprobs: &Logprobs{Content: []TokenLogprob{{Token: "?", Logprob: -2}}},
+		}}})
+
+		got := acc.ChatCompletion().Choices[0].Logprobs
+		require.Equal(t, []TokenLogprob{{Token: "Hi", Logprob: -0.1}, {Token: "?", Logprob: -2}}, got.Content)
+
+		require.Nil(t, NewAccumulator().ChatCompletion())
+	})
+
+	t.Run("appends citations", func(t *testing.T) {
+		t.Parallel()
+
+		first := Citation{Start: 0, End: 5, Text: "Paris", Sources: []CitationSource{
+			{Type: CitationSourceDocument, ID: "doc:0"},
+		}}
+		second := Citation{Start: 10, End: 14, Text: "1889", Sources: []CitationSource{
+			{Type: CitationSourceTool, ID: "call_1"},
+		}}
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "Paris ..."}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Citations: []Citation{first}}}}})
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Citations: []Citation{second}}}}})
+
+		require.Equal(t, []Citation{first, second}, acc.ChatCompletion().Choices[0].Message.Citations)
+	})
+
+	t.Run("returns snapshots", func(t *testing.T) {
+		t.Parallel()
+
+		acc := NewAccumulator()
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "one"}}}})
+		first := acc.ChatCompletion()
+
+		acc.Add(ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: " two"}}}})
+		require.Equal(t, "one", first.Choices[0].Message.Content)
+		require.Equal(t, "one two", acc.ChatCompletion().Choices[0].Message.Content)
+	})
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drains the stream", func(t *testing.T) {
+		t.Parallel()
+
+		chunks := make(chan ChatCompletionChunk, 2)
+		errs := make(chan error, 1)
+		chunks <- ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "Hi"}}}}
+		chunks <- ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "!"}}}}
+		close(chunks)
+		close(errs)
+
+		got, err := Collect(chunks, errs)
+		require.NoError(t, err)
+		require.Equal(t, "Hi!", got.Choices[0].Message.Content)
+	})
+
+	t.Run("returns partial output with the stream error", func(t *testing.T) {
+		t.Parallel()
+
+		chunks := make(chan ChatCompletionChunk, 1)
+		errs := make(chan error, 1)
+		chunks <- ChatCompletionChunk{Choices: []ChunkChoice{{Delta: ChunkDelta{Content: "partial"}}}}
+		errs <- stderrors.New("connection lost")
+		close(chunks)
+		close(errs)
+
+		got, err := Collect(chunks, errs)
+		require.EqualError(t, err, "connection lost")
+		require.Equal(t, "partial", got.Choices[0].Message.Content)
+	})
+}
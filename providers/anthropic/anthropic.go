@@ -3,9 +3,12 @@ package anthropic
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	stderrors "errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/mozilla-ai/any-llm-go/config"
 	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
 	"github.com/mozilla-ai/any-llm-go/providers"
 )
 
@@ -23,6 +27,14 @@ const (
 	providerName     = "anthropic"
 )
 
+// Anthropic models API constants.
+const (
+	listModelsPageSize = 1000
+	objectList         = "list"
+	objectModel        = "model"
+	ownerAnthropic     = "anthropic"
+)
+
 // Anthropic content block types.
 const (
 	blockTypeText     = "text"
@@ -30,6 +42,16 @@ const (
 	blockTypeToolUse  = "tool_use"
 )
 
+// Content part types and the document MIME types Anthropic accepts.
+const (
+	contentTypeFile       = "file"
+	contentTypeImageURL   = "image_url"
+	contentTypeInputAudio = "input_audio"
+	contentTypeText       = "text"
+	mimeTypePDF           = "application/pdf"
+	mimeTypeText          = "text/plain"
+)
+
 // Anthropic delta types.
 const (
 	deltaTypeInputJSON = "input_json_delta"
@@ -37,6 +59,15 @@ const (
 	deltaTypeThinking  = "thinking_delta"
 )
 
+// Extra parameters for the Messages API. Of metadata, only user_id is accepted, as in the API.
+// The request is built from typed SDK params, so other keys in CompletionParams.Extra are rejected.
+const (
+	extraMetadata       = "metadata"
+	extraMetadataUserID = "user_id"
+	extraServiceTier    = "service_tier"
+	extraTopK           = "top_k"
+)
+
 // Anthropic error response patterns (checked in raw JSON).
 const (
 	errorPatternContextLength = "context_length"
@@ -65,9 +96,17 @@ const (
 var (
 	_ providers.CapabilityProvider = (*Provider)(nil)
 	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
 	_ providers.Provider           = (*Provider)(nil)
+	_ providers.TokenCounter       = (*Provider)(nil)
 )
 
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
 // Provider implements the providers.Provider interface for Anthropic.
 type Provider struct {
 	client *anthropic.Client
@@ -84,6 +123,8 @@ type streamState struct {
 	toolCalls      []providers.ToolCall
 	currentToolIdx int
 	inputUsage     int64
+	cacheRead      int64
+	cacheWrite     int64
 }
 
 // New creates a new Anthropic provider.
@@ -100,12 +141,21 @@ func New(opts ...config.Option) (*Provider, error) {
 
 	clientOpts := []option.RequestOption{
 		option.WithAPIKey(apiKey),
+		option.WithHTTPClient(cfg.HTTPClient()),
 	}
 
 	if cfg.BaseURL != "" {
 		clientOpts = append(clientOpts, option.WithBaseURL(cfg.BaseURL))
 	}
 
+	// Configured headers replace the SDK's defaults, e.g. to set anthropic-beta flags.
+	for _, key := range slices.Sorted(maps.Keys(cfg.Headers)) {
+		clientOpts = append(clientOpts, option.WithHeaderDel(key))
+		for _, value := range cfg.Headers[key] {
+			clientOpts = append(clientOpts, option.WithHeaderAdd(key, value))
+		}
+	}
+
 	client := anthropic.NewClient(clientOpts...)
 
 	return &Provider{
@@ -117,13 +167,21 @@ func New(opts ...config.Option) (*Provider, error) {
 // Capabilities returns the provider's capabilities.
 func (p *Provider) Capabilities() providers.Capabilities {
 	return providers.Capabilities{
-		Completion:          true,
-		CompletionStreaming: true,
-		CompletionReasoning: true,
-		CompletionImage:     true,
-		CompletionPDF:       true,
-		Embedding:           false,
-		ListModels:          false,
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionStreaming:        true,
+		CompletionReasoning:        true,
+		CompletionImage:            true,
+		CompletionPDF:              true,
+		CompletionStructuredOutput: false, // Structured output is emulated with a forced tool call.
+		CountTokens:                true,
+		Embedding:                  false,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
 	}
 }
 
@@ -132,7 +190,14 @@ func (p *Provider) Completion(
 	ctx context.Context,
 	params providers.CompletionParams,
 ) (*providers.ChatCompletion, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
 	req := p.convertParams(params)
+	if err := applyExtra(&req, params.Extra); err != nil {
+		return nil, err
+	}
 
 	resp, err := p.client.Messages.New(ctx, req)
 	if err != nil {
@@ -157,10 +222,8 @@ func (p *Provider) convertParams(params providers.CompletionParams) anthropic.Me
 		MaxTokens: maxTokens,
 	}
 
-	if system != "" {
-		req.System = []anthropic.TextBlockParam{
-			{Text: system},
-		}
+	if len(system) > 0 {
+		req.System = system
 	}
 
 	if params.Temperature != nil {
@@ -204,27 +267,48 @@ func (p *Provider) CompletionStream(
 		defer close(chunks)
 		defer close(errs)
 
+		if err := validateParams(params); err != nil {
+			errs <- err
+			return
+		}
+
 		req := p.convertParams(params)
+		if err := applyExtra(&req, params.Extra); err != nil {
+			errs <- err
+			return
+		}
+
 		stream := p.client.Messages.NewStreaming(ctx, req)
 		state := newStreamState()
 
 		for stream.Next() {
 			event := stream.Current()
 
+			var chunk *providers.ChatCompletionChunk
 			switch event.Type {
 			case eventMessageStart:
-				chunks <- state.handleMessageStart(event.AsMessageStart())
+				start := state.handleMessageStart(event.AsMessageStart())
+				chunk = &start
 
 			case eventContentBlockStart:
 				state.handleContentBlockStart(event.AsContentBlockStart())
 
 			case eventContentBlockDelta:
-				if chunk := state.handleContentBlockDelta(event.AsContentBlockDelta()); chunk != nil {
-					chunks <- *chunk
-				}
+				chunk = state.handleContentBlockDelta(event.AsContentBlockDelta())
 
 			case eventMessageDelta:
-				chunks <- state.handleMessageDelta(event.AsMessageDelta())
+				final := state.handleMessageDelta(event.AsMessageDelta())
+				chunk = &final
+			}
+
+			if chunk == nil {
+				continue
+			}
+
+			select {
+			case chunks <- *chunk:
+			case <-ctx.Done():
+				return
 			}
 		}
 
@@ -236,6 +320,67 @@ func (p *Provider) CompletionStream(
 	return chunks, errs
 }
 
+// CountTokens counts the input tokens of a completion request through the token counting API.
+// Only the messages, tools, tool choice, and reasoning effort of params are taken into account.
+func (p *Provider) CountTokens(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.TokenCount, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
+	msgReq := p.convertParams(params)
+	req := anthropic.MessageCountTokensParams{
+		Messages:   msgReq.Messages,
+		Model:      msgReq.Model,
+		Thinking:   msgReq.Thinking,
+		ToolChoice: msgReq.ToolChoice,
+	}
+	if len(msgReq.System) > 0 {
+		req.System = anthropic.MessageCountTokensParamsSystemUnion{OfTextBlockArray: msgReq.System}
+	}
+	for _, tool := range msgReq.Tools {
+		req.Tools = append(req.Tools, anthropic.MessageCountTokensToolUnionParam{OfTool: tool.OfTool})
+	}
+
+	resp, err := p.client.Messages.CountTokens(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return &providers.TokenCount{
+		Model:       params.Model,
+		InputTokens: int(resp.InputTokens),
+	}, nil
+}
+
+// ListModels returns a list of available models, following pagination to the last page.
+func (p *Provider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
+	models := make([]providers.Model, 0)
+
+	pager := p.client.Models.ListAutoPaging(ctx, anthropic.ModelListParams{
+		Limit: anthropic.Int(listModelsPageSize),
+	})
+	for pager.Next() {
+		m := pager.Current()
+		models = append(models, providers.Model{
+			ID:      m.ID,
+			Object:  objectModel,
+			Created: m.CreatedAt.Unix(),
+			OwnedBy: ownerAnthropic,
+		})
+	}
+	if err := pager.Err(); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return &providers.ModelsResponse{
+		Object: objectList,
+		Data:   models,
+	}, nil
+}
+
 // Name returns the provider name.
 func (p *Provider) Name() string {
 	return providerName
@@ -284,8 +429,9 @@ func (s *streamState) handleContentBlockStart(event anthropic.ContentBlockStartE
 		s.currentToolIdx++
 		// TODO: Extract to newToolCallFromBlock() if this pattern is needed elsewhere.
 		tc := providers.ToolCall{
-			ID:   event.ContentBlock.ID,
-			Type: "function",
+			Index: s.currentToolIdx,
+			ID:    event.ContentBlock.ID,
+			Type:  "function",
 			Function: providers.FunctionCall{
 				Name: event.ContentBlock.Name,
 			},
@@ -312,11 +458,7 @@ func (s *streamState) handleMessageDelta(event anthropic.MessageDeltaEvent) prov
 	finishReason := convertStopReason(string(event.Delta.StopReason))
 	chunk := s.chunk(providers.ChunkDelta{})
 	chunk.Choices[0].FinishReason = finishReason
-	chunk.Usage = &providers.Usage{
-		PromptTokens:     int(s.inputUsage),
-		CompletionTokens: int(event.Usage.OutputTokens),
-		TotalTokens:      int(s.inputUsage + event.Usage.OutputTokens),
-	}
+	chunk.Usage = convertUsage(s.inputUsage, s.cacheRead, s.cacheWrite, event.Usage.OutputTokens)
 	return chunk
 }
 
@@ -325,6 +467,8 @@ func (s *streamState) handleMessageStart(event anthropic.MessageStartEvent) prov
 	s.messageID = event.Message.ID
 	s.model = string(event.Message.Model)
 	s.inputUsage = event.Message.Usage.InputTokens
+	s.cacheRead = event.Message.Usage.CacheReadInputTokens
+	s.cacheWrite = event.Message.Usage.CacheCreationInputTokens
 
 	return s.chunk(providers.ChunkDelta{Role: providers.RoleAssistant})
 }
@@ -345,6 +489,43 @@ func (s *streamState) handleTextDelta(text string) *providers.ChatCompletionChun
 	return &chunk
 }
 
+// applyExtra decodes provider-specific parameters into the SDK params of req.
+func applyExtra(req *anthropic.MessageNewParams, params map[string]any) error {
+	for _, key := range extra.Keys(params) {
+		value := params[key]
+
+		switch key {
+		case extraMetadata:
+			var v map[string]string
+			if err := extra.Decode(key, value, &v); err != nil {
+				return errors.NewInvalidRequestError(providerName, err)
+			}
+			for field, fieldValue := range v {
+				if field != extraMetadataUserID {
+					return errors.NewUnsupportedParamError(providerName, key+"."+field)
+				}
+				req.Metadata.UserID = anthropic.String(fieldValue)
+			}
+		case extraServiceTier:
+			var v string
+			if err := extra.Decode(key, value, &v); err != nil {
+				return errors.NewInvalidRequestError(providerName, err)
+			}
+			req.ServiceTier = anthropic.MessageNewParamsServiceTier(v)
+		case extraTopK:
+			var v int64
+			if err := extra.Decode(key, value, &v); err != nil {
+				return errors.NewInvalidRequestError(providerName, err)
+			}
+			req.TopK = anthropic.Int(v)
+		default:
+			return errors.NewUnsupportedParamError(providerName, key)
+		}
+	}
+
+	return nil
+}
+
 // applyThinking configures thinking/reasoning on the request if applicable.
 func applyThinking(req *anthropic.MessageNewParams, effort providers.ReasoningEffort, maxTokens int64) {
 	if effort == "" || effort == providers.ReasoningEffortNone {
@@ -385,6 +566,35 @@ func convertAssistantMessage(msg providers.Message) *anthropic.MessageParam {
 	return &m
 }
 
+// convertCacheControl converts a cache marker to an Anthropic cache_control block.
+func convertCacheControl(cacheControl *providers.CacheControl) anthropic.CacheControlEphemeralParam {
+	param := anthropic.NewCacheControlEphemeralParam()
+	param.TTL = anthropic.CacheControlEphemeralTTL(cacheControl.TTL)
+	return param
+}
+
+// convertFilePart converts a file to an Anthropic document block.
+// Plain text is decoded and sent as text; other data is sent as a base64 PDF.
+func convertFilePart(file *providers.File) anthropic.ContentBlockParamUnion {
+	var source anthropic.DocumentBlockParamSourceUnion
+	switch {
+	case file.Data != "" && file.MIMEType == mimeTypeText:
+		text, _ := base64.StdEncoding.DecodeString(file.Data) // Checked by validateFile.
+		source.OfText = &anthropic.PlainTextSourceParam{Data: string(text)}
+	case file.Data != "":
+		source.OfBase64 = &anthropic.Base64PDFSourceParam{Data: file.Data}
+	default:
+		source.OfURL = &anthropic.URLPDFSourceParam{URL: file.URL}
+	}
+
+	block := anthropic.DocumentBlockParam{Source: source}
+	if file.Filename != "" {
+		block.Title = anthropic.String(file.Filename)
+	}
+
+	return anthropic.ContentBlockParamUnion{OfDocument: &block}
+}
+
 // convertImagePart converts an image URL to Anthropic format.
 func convertImagePart(img *providers.ImageURL) anthropic.ContentBlockParamUnion {
 	url := img.URL
@@ -408,28 +618,57 @@ func convertImagePart(img *providers.ImageURL) anthropic.ContentBlockParamUnion
 }
 
 // convertMessage converts a single message to Anthropic format.
+// A cache marker on the message is set on its last content block.
 func convertMessage(msg providers.Message) *anthropic.MessageParam {
+	var m *anthropic.MessageParam
 	switch msg.Role {
 	case providers.RoleUser:
-		return convertUserMessage(msg)
+		m = convertUserMessage(msg)
 	case providers.RoleAssistant:
-		return convertAssistantMessage(msg)
+		m = convertAssistantMessage(msg)
 	case providers.RoleTool:
-		return convertToolMessage(msg)
+		m = convertToolMessage(msg)
 	default:
 		return nil
 	}
+
+	if msg.CacheControl != nil && len(m.Content) > 0 {
+		if cacheControl := m.Content[len(m.Content)-1].GetCacheControl(); cacheControl != nil {
+			*cacheControl = convertCacheControl(msg.CacheControl)
+		}
+	}
+
+	return m
 }
 
 // convertMessages converts providers messages to Anthropic format.
-// Returns the messages and the combined system message.
-func convertMessages(messages []providers.Message) ([]anthropic.MessageParam, string) {
+// Returns the messages and the system prompt. System messages are joined into one text block,
+// which is split after each system message with a cache marker so the marker ends a block.
+func convertMessages(messages []providers.Message) ([]anthropic.MessageParam, []anthropic.TextBlockParam) {
 	result := make([]anthropic.MessageParam, 0, len(messages))
+	var system []anthropic.TextBlockParam
 	var systemParts []string
 
+	addSystemBlock := func(cacheControl *providers.CacheControl) {
+		text := strings.Join(systemParts, "\n")
+		systemParts = nil
+		if text == "" {
+			return
+		}
+
+		block := anthropic.TextBlockParam{Text: text}
+		if cacheControl != nil {
+			block.CacheControl = convertCacheControl(cacheControl)
+		}
+		system = append(system, block)
+	}
+
 	for _, msg := range messages {
 		if msg.Role == providers.RoleSystem {
 			systemParts = append(systemParts, msg.ContentString())
+			if msg.CacheControl != nil {
+				addSystemBlock(msg.CacheControl)
+			}
 			continue
 		}
 
@@ -437,8 +676,9 @@ func convertMessages(messages []providers.Message) ([]anthropic.MessageParam, st
 			result = append(result, *converted)
 		}
 	}
+	addSystemBlock(nil)
 
-	return result, strings.Join(systemParts, "\n")
+	return result, system
 }
 
 // convertResponse converts an Anthropic response to providers format.
@@ -491,11 +731,12 @@ func convertResponse(resp *anthropic.Message) *providers.ChatCompletion {
 			Message:      message,
 			FinishReason: finishReason,
 		}},
-		Usage: &providers.Usage{
-			PromptTokens:     int(resp.Usage.InputTokens),
-			CompletionTokens: int(resp.Usage.OutputTokens),
-			TotalTokens:      int(resp.Usage.InputTokens + resp.Usage.OutputTokens),
-		},
+		Usage: convertUsage(
+			resp.Usage.InputTokens,
+			resp.Usage.CacheReadInputTokens,
+			resp.Usage.CacheCreationInputTokens,
+			resp.Usage.OutputTokens,
+		),
 	}
 }
 
@@ -535,13 +776,16 @@ func convertTool(tool providers.Tool) anthropic.ToolUnionParam {
 		}
 	}
 
-	return anthropic.ToolUnionParam{
-		OfTool: &anthropic.ToolParam{
-			Name:        tool.Function.Name,
-			Description: anthropic.String(tool.Function.Description),
-			InputSchema: inputSchema,
-		},
+	toolParam := &anthropic.ToolParam{
+		Name:        tool.Function.Name,
+		Description: anthropic.String(tool.Function.Description),
+		InputSchema: inputSchema,
 	}
+	if tool.CacheControl != nil {
+		toolParam.CacheControl = convertCacheControl(tool.CacheControl)
+	}
+
+	return anthropic.ToolUnionParam{OfTool: toolParam}
 }
 
 // convertToolCall converts a tool call to Anthropic content block format.
@@ -618,19 +862,48 @@ func convertUserMessage(msg providers.Message) *anthropic.MessageParam {
 
 	content := make([]anthropic.ContentBlockParamUnion, 0)
 	for _, part := range msg.ContentParts() {
+		var block anthropic.ContentBlockParamUnion
 		switch part.Type {
-		case "text":
-			content = append(content, anthropic.NewTextBlock(part.Text))
-		case "image_url":
-			if part.ImageURL != nil {
-				content = append(content, convertImagePart(part.ImageURL))
+		case contentTypeText:
+			block = anthropic.NewTextBlock(part.Text)
+		case contentTypeImageURL:
+			if part.ImageURL == nil {
+				continue
+			}
+			block = convertImagePart(part.ImageURL)
+		case contentTypeFile:
+			if part.File == nil {
+				continue
+			}
+			block = convertFilePart(part.File)
+		default:
+			continue
+		}
+
+		if part.CacheControl != nil {
+			if cacheControl := block.GetCacheControl(); cacheControl != nil {
+				*cacheControl = convertCacheControl(part.CacheControl)
 			}
 		}
+		content = append(content, block)
 	}
 	m := anthropic.NewUserMessage(content...)
 	return &m
 }
 
+// convertUsage converts Anthropic token counts to providers format.
+// Anthropic counts cached prompt tokens separately from input tokens, so they are added to PromptTokens.
+func convertUsage(input, cacheRead, cacheWrite, output int64) *providers.Usage {
+	prompt := input + cacheRead + cacheWrite
+	return &providers.Usage{
+		PromptTokens:     int(prompt),
+		CompletionTokens: int(output),
+		TotalTokens:      int(prompt + output),
+		CacheReadTokens:  int(cacheRead),
+		CacheWriteTokens: int(cacheWrite),
+	}
+}
+
 // thinkingBudget returns the token budget for the given reasoning effort.
 // Returns the budget and true if the effort level is supported, or 0 and false otherwise.
 func thinkingBudget(effort providers.ReasoningEffort) (int64, bool) {
@@ -646,6 +919,99 @@ func thinkingBudget(effort providers.ReasoningEffort) (int64, bool) {
 	}
 }
 
+// validateParams rejects parameters that Anthropic cannot honor.
+func validateParams(params providers.CompletionParams) error {
+	if params.TopLogprobs != nil {
+		return errors.NewUnsupportedParamError(providerName, "top_logprobs")
+	}
+	if params.Logprobs {
+		return errors.NewUnsupportedParamError(providerName, "logprobs")
+	}
+	if params.Audio != nil || slices.Contains(params.Modalities, providers.ModalityAudio) {
+		return errors.NewUnsupportedParamError(providerName, "audio")
+	}
+
+	for _, msg := range params.Messages {
+		if err := validateCacheControl(msg.CacheControl); err != nil {
+			return err
+		}
+		for _, part := range msg.ContentParts() {
+			if err := validateCacheControl(part.CacheControl); err != nil {
+				return err
+			}
+			switch part.Type {
+			case contentTypeFile:
+				if err := validateFile(part.File); err != nil {
+					return err
+				}
+			case contentTypeInputAudio:
+				return errors.NewUnsupportedParamError(providerName, contentTypeInputAudio)
+			}
+		}
+	}
+	for _, tool := range params.Tools {
+		if err := validateCacheControl(tool.CacheControl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateCacheControl rejects cache markers with a type or lifetime that Anthropic does not support.
+func validateCacheControl(cacheControl *providers.CacheControl) error {
+	if cacheControl == nil {
+		return nil
+	}
+
+	if cacheControl.Type != "" && cacheControl.Type != providers.CacheControlTypeEphemeral {
+		return errors.NewInvalidRequestError(
+			providerName,
+			fmt.Errorf("unsupported cache control type %q", cacheControl.Type),
+		)
+	}
+
+	switch cacheControl.TTL {
+	case "", providers.CacheTTL5m, providers.CacheTTL1h:
+		return nil
+	default:
+		return errors.NewInvalidRequestError(
+			providerName,
+			fmt.Errorf("unsupported cache TTL %q, want %q or %q", cacheControl.TTL, providers.CacheTTL5m, providers.CacheTTL1h),
+		)
+	}
+}
+
+// validateFile rejects files that cannot be sent as a document block.
+// Anthropic reads PDFs by data or URL, and plain text by data; uploaded file IDs need the beta Files API.
+func validateFile(file *providers.File) error {
+	switch {
+	case file == nil:
+		return errors.NewInvalidRequestError(providerName, fmt.Errorf("file content part requires a file"))
+	case file.FileID != "":
+		return errors.NewUnsupportedParamError(providerName, "file_id")
+	case file.Data == "" && file.URL == "":
+		return errors.NewInvalidRequestError(providerName, fmt.Errorf("file content part requires data or a URL"))
+	case file.Data == "":
+		return nil
+	}
+
+	switch file.MIMEType {
+	case "", mimeTypePDF:
+		return nil
+	case mimeTypeText:
+		if _, err := base64.StdEncoding.DecodeString(file.Data); err != nil {
+			return errors.NewInvalidRequestError(providerName, fmt.Errorf("decoding file data: %w", err))
+		}
+		return nil
+	default:
+		return errors.NewInvalidRequestError(
+			providerName,
+			fmt.Errorf("unsupported file MIME type %q, want %q or %q", file.MIMEType, mimeTypePDF, mimeTypeText),
+		)
+	}
+}
+
 // ConvertError converts an Anthropic SDK error to a unified error type.
 // Implements providers.ErrorConverter.
 func (p *Provider) ConvertError(err error) error {
@@ -665,7 +1031,11 @@ func (p *Provider) ConvertError(err error) error {
 	case 401:
 		return errors.NewAuthenticationError(providerName, err)
 	case 429:
-		return errors.NewRateLimitError(providerName, err)
+		rateLimitErr := errors.NewRateLimitError(providerName, err)
+		if apiErr.Response != nil {
+			rateLimitErr.RetryAfter = errors.RetryAfterFromHeader(apiErr.Response.Header)
+		}
+		return rateLimitErr
 	case 404:
 		return errors.NewModelNotFoundError(providerName, err)
 	case 400:
@@ -684,6 +1054,8 @@ func (p *Provider) ConvertError(err error) error {
 		}
 		return errors.NewAuthenticationError(providerName, err)
 	default:
-		return errors.NewProviderError(providerName, err)
+		providerErr := errors.NewProviderError(providerName, err)
+		providerErr.StatusCode = apiErr.StatusCode
+		return providerErr
 	}
 }
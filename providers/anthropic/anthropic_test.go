@@ -2,11 +2,16 @@ package anthropic
 
 import (
 	"context"
+	"encoding/json"
 	stderrors "errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/stretchr/testify/require"
@@ -57,11 +62,14 @@ func TestCapabilities(t *testing.T) {
 
 	require.True(t, caps.Completion)
 	require.True(t, caps.CompletionStreaming)
+	require.False(t, caps.CompletionStructuredOutput)
 	require.True(t, caps.CompletionReasoning)
 	require.True(t, caps.CompletionImage)
 	require.True(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
 	require.False(t, caps.Embedding) // Anthropic doesn't support embeddings.
-	require.False(t, caps.ListModels)
+	require.True(t, caps.ListModels)
+	require.True(t, caps.CountTokens)
 }
 
 func TestConvertMessages(t *testing.T) {
@@ -77,7 +85,7 @@ func TestConvertMessages(t *testing.T) {
 
 		result, system := convertMessages(messages)
 
-		require.Equal(t, "You are a helpful assistant.", system)
+		require.Equal(t, []anthropic.TextBlockParam{{Text: "You are a helpful assistant."}}, system)
 		require.Len(t, result, 1) // Only user message.
 	})
 
@@ -92,7 +100,7 @@ func TestConvertMessages(t *testing.T) {
 
 		result, system := convertMessages(messages)
 
-		require.Equal(t, "First part.\nSecond part.", system)
+		require.Equal(t, []anthropic.TextBlockParam{{Text: "First part.\nSecond part."}}, system)
 		require.Len(t, result, 1)
 	})
 
@@ -194,6 +202,57 @@ func TestConvertImagePart(t *testing.T) {
 	})
 }
 
+func TestConvertFilePart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("converts base64 PDF", func(t *testing.T) {
+		t.Parallel()
+
+		result := convertFilePart(&providers.File{Data: "JVBERi0=", Filename: "report.pdf"})
+		require.NotNil(t, result.OfDocument)
+		require.NotNil(t, result.OfDocument.Source.OfBase64)
+		require.Equal(t, "JVBERi0=", result.OfDocument.Source.OfBase64.Data)
+		require.Equal(t, "report.pdf", result.OfDocument.Title.Value)
+	})
+
+	t.Run("decodes plain text", func(t *testing.T) {
+		t.Parallel()
+
+		result := convertFilePart(&providers.File{Data: "aGVsbG8=", MIMEType: "text/plain"})
+		require.NotNil(t, result.OfDocument)
+		require.NotNil(t, result.OfDocument.Source.OfText)
+		require.Equal(t, "hello", result.OfDocument.Source.OfText.Data)
+		require.False(t, result.OfDocument.Title.Valid())
+	})
+
+	t.Run("converts URL", func(t *testing.T) {
+		t.Parallel()
+
+		result := convertFilePart(&providers.File{URL: "https://example.com/report.pdf"})
+		require.NotNil(t, result.OfDocument)
+		require.NotNil(t, result.OfDocument.Source.OfURL)
+		require.Equal(t, "https://example.com/report.pdf", result.OfDocument.Source.OfURL.URL)
+	})
+
+	t.Run("applies part cache marker", func(t *testing.T) {
+		t.Parallel()
+
+		msg := providers.Message{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{{
+				Type:         "file",
+				File:         &providers.File{Data: "JVBERi0="},
+				CacheControl: &providers.CacheControl{Type: providers.CacheControlTypeEphemeral},
+			}},
+		}
+
+		result := convertUserMessage(msg)
+		require.Len(t, result.Content, 1)
+		require.NotNil(t, result.Content[0].OfDocument)
+		require.Equal(t, "ephemeral", string(result.Content[0].OfDocument.CacheControl.Type))
+	})
+}
+
 func TestConvertStopReason(t *testing.T) {
 	t.Parallel()
 
@@ -334,6 +393,59 @@ func TestStreamStateHandleInputJSONDelta(t *testing.T) {
 	})
 }
 
+func TestApplyExtra(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps known keys onto typed fields", func(t *testing.T) {
+		t.Parallel()
+
+		req := anthropic.MessageNewParams{}
+		err := applyExtra(&req, map[string]any{
+			"metadata":     map[string]any{"user_id": "user-123"},
+			"service_tier": "standard_only",
+			"top_k":        float64(20),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "user-123", req.Metadata.UserID.Value)
+		require.Equal(t, anthropic.MessageNewParamsServiceTierStandardOnly, req.ServiceTier)
+		require.Equal(t, int64(20), req.TopK.Value)
+	})
+
+	t.Run("rejects unsupported keys", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			extra     map[string]any
+			wantParam string
+		}{
+			"unknown key":        {extra: map[string]any{"presence_penalty": 0.5}, wantParam: "presence_penalty"},
+			"unknown metadata":   {extra: map[string]any{"metadata": map[string]any{"team": "x"}}, wantParam: "metadata.team"},
+			"first key reported": {extra: map[string]any{"min_p": 0.1, "frequency_penalty": 1}, wantParam: "frequency_penalty"},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				req := anthropic.MessageNewParams{}
+				err := applyExtra(&req, tc.extra)
+
+				var unsupportedErr *errors.UnsupportedParamError
+				require.ErrorAs(t, err, &unsupportedErr)
+				require.Equal(t, tc.wantParam, unsupportedErr.Param)
+			})
+		}
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		req := anthropic.MessageNewParams{}
+		err := applyExtra(&req, map[string]any{"top_k": "many"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
 func TestApplyThinking(t *testing.T) {
 	t.Parallel()
 
@@ -574,6 +686,452 @@ func TestThinkingBudget(t *testing.T) {
 	}
 }
 
+func TestValidateParams(t *testing.T) {
+	t.Parallel()
+
+	top := 2
+	tests := map[string]struct {
+		params    providers.CompletionParams
+		wantParam string
+	}{
+		"logprobs":     {params: providers.CompletionParams{Logprobs: true}, wantParam: "logprobs"},
+		"top logprobs": {params: providers.CompletionParams{Logprobs: true, TopLogprobs: &top}, wantParam: "top_logprobs"},
+		"audio output": {
+			params:    providers.CompletionParams{Modalities: []string{providers.ModalityText, providers.ModalityAudio}},
+			wantParam: "audio",
+		},
+		"audio input": {
+			params: providers.CompletionParams{Messages: []providers.Message{{
+				Role: providers.RoleUser,
+				Content: []providers.ContentPart{
+					{Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg==", Format: "wav"}},
+				},
+			}}},
+			wantParam: "input_audio",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var unsupportedErr *errors.UnsupportedParamError
+			require.ErrorAs(t, validateParams(tc.params), &unsupportedErr)
+			require.Equal(t, tc.wantParam, unsupportedErr.Param)
+		})
+	}
+
+	t.Run("accepts supported params", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, validateParams(providers.CompletionParams{Model: "claude-3-5-haiku-latest"}))
+	})
+}
+
+func TestValidateFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts supported files", func(t *testing.T) {
+		t.Parallel()
+
+		for _, file := range []*providers.File{
+			{Data: "JVBERi0="},
+			{Data: "JVBERi0=", MIMEType: "application/pdf"},
+			{Data: "aGVsbG8=", MIMEType: "text/plain"},
+			{URL: "https://example.com/report.pdf"},
+		} {
+			require.NoError(t, validateFile(file))
+		}
+	})
+
+	t.Run("rejects file IDs", func(t *testing.T) {
+		t.Parallel()
+
+		var unsupportedErr *errors.UnsupportedParamError
+		require.ErrorAs(t, validateFile(&providers.File{FileID: "file-123"}), &unsupportedErr)
+		require.Equal(t, "file_id", unsupportedErr.Param)
+	})
+
+	tests := map[string]*providers.File{
+		"missing file":        nil,
+		"missing source":      {Filename: "report.pdf"},
+		"unsupported type":    {Data: "JVBERi0=", MIMEType: "image/png"},
+		"invalid text base64": {Data: "not base64!", MIMEType: "text/plain"},
+	}
+
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var invalidErr *errors.InvalidRequestError
+			require.ErrorAs(t, validateFile(file), &invalidErr)
+		})
+	}
+
+	t.Run("is checked by validateParams", func(t *testing.T) {
+		t.Parallel()
+
+		params := providers.CompletionParams{
+			Messages: []providers.Message{{
+				Role:    providers.RoleUser,
+				Content: []providers.ContentPart{{Type: "file", File: &providers.File{FileID: "file-123"}}},
+			}},
+		}
+		require.ErrorIs(t, validateParams(params), errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "anthropic.(*Provider).CompletionStream"
+
+	preamble := []string{
+		"event: message_start\n" +
+			"data: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"type\":\"message\",\"role\":\"assistant\"," +
+			"\"model\":\"claude-3-5-haiku-latest\",\"content\":[],\"stop_reason\":null," +
+			"\"usage\":{\"input_tokens\":1,\"output_tokens\":1}}}\n\n",
+		"event: content_block_start\n" +
+			"data: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n",
+	}
+	server := testutil.NewEndlessStreamServer(t, "text/event-stream", preamble, func(i int) string {
+		return fmt.Sprintf(
+			"event: content_block_delta\n"+
+				"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"%d \"}}\n\n",
+			i,
+		)
+	})
+
+	provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: "claude-3-5-haiku-latest", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, providers.RoleAssistant, chunk.Choices[0].Delta.Role)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
+func TestListModels(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/v1/models", r.URL.Path)
+		require.Equal(t, "test-api-key", r.Header.Get("X-Api-Key"))
+		require.Equal(t, "1000", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("after_id") {
+		case "":
+			_, _ = io.WriteString(w, `{"data":[{"id":"claude-sonnet-4-5","type":"model",`+
+				`"display_name":"Claude Sonnet 4.5","created_at":"2025-09-29T00:00:00Z"}],`+
+				`"has_more":true,"first_id":"claude-sonnet-4-5","last_id":"claude-sonnet-4-5"}`)
+		case "claude-sonnet-4-5":
+			_, _ = io.WriteString(w, `{"data":[{"id":"claude-3-5-haiku-20241022","type":"model",`+
+				`"display_name":"Claude Haiku 3.5","created_at":"2024-10-22T00:00:00Z"}],`+
+				`"has_more":false,"first_id":"claude-3-5-haiku-20241022","last_id":"claude-3-5-haiku-20241022"}`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("after_id"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	resp, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "list", resp.Object)
+	require.Equal(t, []providers.Model{
+		{
+			ID:      "claude-sonnet-4-5",
+			Object:  "model",
+			Created: time.Date(2025, 9, 29, 0, 0, 0, 0, time.UTC).Unix(),
+			OwnedBy: "anthropic",
+		},
+		{
+			ID:      "claude-3-5-haiku-20241022",
+			Object:  "model",
+			Created: time.Date(2024, 10, 22, 0, 0, 0, 0, time.UTC).Unix(),
+			OwnedBy: "anthropic",
+		},
+	}, resp.Data)
+}
+
+func TestCompletionIntoWithReasoning(t *testing.T) {
+	t.Parallel()
+
+	type city struct {
+		Name string `json:"name"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		// Anthropic rejects thinking together with a forced tool choice.
+		require.NotContains(t, body, "thinking")
+		toolChoice := body["tool_choice"].(map[string]any)
+		require.Equal(t, "tool", toolChoice["type"])
+		require.Equal(t, "response", toolChoice["name"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-5",`+
+			`"content":[{"type":"tool_use","id":"toolu_1","name":"response","input":{"name":"Lima"}}],`+
+			`"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":5}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	got, _, err := providers.CompletionInto[city](context.Background(), provider, providers.CompletionParams{
+		Model:           "claude-sonnet-4-5",
+		Messages:        testutil.SimpleMessages(),
+		ReasoningEffort: providers.ReasoningEffortHigh,
+	})
+	require.NoError(t, err)
+	require.Equal(t, city{Name: "Lima"}, got)
+}
+
+func TestCountTokens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts the tokens of the request", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/v1/messages/count_tokens", r.URL.Path)
+
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, "claude-sonnet-4-5", req["model"])
+			require.Equal(t, []any{map[string]any{
+				"type": "text",
+				"text": "You are a helpful assistant that follows instructions exactly.",
+			}}, req["system"])
+			require.Len(t, req["messages"], 1)
+			require.Len(t, req["tools"], 1)
+			require.Equal(t, "get_weather", req["tools"].([]any)[0].(map[string]any)["name"])
+			require.NotContains(t, req, "max_tokens")
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"input_tokens":42}`)
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+		require.NoError(t, err)
+
+		resp, err := provider.CountTokens(context.Background(), providers.CompletionParams{
+			Model:    "claude-sonnet-4-5",
+			Messages: testutil.MessagesWithSystem(),
+			Tools:    []providers.Tool{testutil.WeatherTool()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.TokenCount{Model: "claude-sonnet-4-5", InputTokens: 42}, resp)
+	})
+
+	t.Run("rejects unsupported parameters before sending", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL("http://127.0.0.1:0"))
+		require.NoError(t, err)
+
+		_, err = provider.CountTokens(context.Background(), providers.CompletionParams{
+			Model:    "claude-sonnet-4-5",
+			Messages: testutil.SimpleMessages(),
+			Logprobs: true,
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCacheControl(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends cache markers and reports cache usage", func(t *testing.T) {
+		t.Parallel()
+
+		var req map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-5",`+
+				`"content":[{"type":"text","text":"Hi"}],"stop_reason":"end_turn",`+
+				`"usage":{"input_tokens":10,"cache_read_input_tokens":2000,"cache_creation_input_tokens":300,`+
+				`"output_tokens":5}}`)
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+		require.NoError(t, err)
+
+		tool := testutil.WeatherTool()
+		tool.CacheControl = &providers.CacheControl{TTL: providers.CacheTTL1h}
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model: "claude-sonnet-4-5",
+			Messages: []providers.Message{
+				{Role: providers.RoleSystem, Content: "Long instructions.", CacheControl: &providers.CacheControl{}},
+				{Role: providers.RoleSystem, Content: "Today is Monday."},
+				{Role: providers.RoleUser, Content: []providers.ContentPart{
+					{Type: "text", Text: "Long document.", CacheControl: &providers.CacheControl{}},
+					{Type: "text", Text: "Summarize it."},
+				}},
+				{Role: providers.RoleAssistant, Content: "It is about caching."},
+				{Role: providers.RoleUser, Content: "Thanks!", CacheControl: &providers.CacheControl{
+					Type: providers.CacheControlTypeEphemeral,
+					TTL:  providers.CacheTTL5m,
+				}},
+			},
+			Tools: []providers.Tool{tool},
+		})
+		require.NoError(t, err)
+
+		ephemeral := map[string]any{"type": "ephemeral"}
+		require.Equal(t, []any{
+			map[string]any{"type": "text", "text": "Long instructions.", "cache_control": ephemeral},
+			map[string]any{"type": "text", "text": "Today is Monday."},
+		}, req["system"])
+
+		messages := req["messages"].([]any)
+		require.Len(t, messages, 3)
+		require.Equal(t, []any{
+			map[string]any{"type": "text", "text": "Long document.", "cache_control": ephemeral},
+			map[string]any{"type": "text", "text": "Summarize it."},
+		}, messages[0].(map[string]any)["content"])
+		require.NotContains(t, messages[1].(map[string]any)["content"].([]any)[0], "cache_control")
+		require.Equal(t, map[string]any{"type": "ephemeral", "ttl": "5m"},
+			messages[2].(map[string]any)["content"].([]any)[0].(map[string]any)["cache_control"])
+		require.Equal(t, map[string]any{"type": "ephemeral", "ttl": "1h"},
+			req["tools"].([]any)[0].(map[string]any)["cache_control"])
+
+		require.Equal(t, &providers.Usage{
+			PromptTokens:     2310,
+			CompletionTokens: 5,
+			TotalTokens:      2315,
+			CacheReadTokens:  2000,
+			CacheWriteTokens: 300,
+		}, resp.Usage)
+	})
+
+	t.Run("rejects unsupported cache markers", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]*providers.CacheControl{
+			"type": {Type: "persistent"},
+			"ttl":  {TTL: "24h"},
+		}
+
+		for name, cacheControl := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				err := validateParams(providers.CompletionParams{
+					Messages: []providers.Message{
+						{Role: providers.RoleUser, Content: "Hello", CacheControl: cacheControl},
+					},
+				})
+				require.ErrorIs(t, err, errors.ErrInvalidRequest)
+			})
+		}
+	})
+}
+
+func TestStreamStateCacheUsage(t *testing.T) {
+	t.Parallel()
+
+	var start anthropic.MessageStartEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"message_start","message":{"id":"msg_1",`+
+		`"model":"claude-sonnet-4-5","usage":{"input_tokens":10,"cache_read_input_tokens":2000,`+
+		`"cache_creation_input_tokens":300,"output_tokens":1}}}`), &start))
+
+	var delta anthropic.MessageDeltaEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"message_delta","delta":{"stop_reason":"end_turn"},`+
+		`"usage":{"output_tokens":5}}`), &delta))
+
+	state := newStreamState()
+	state.handleMessageStart(start)
+	chunk := state.handleMessageDelta(delta)
+
+	require.Equal(t, &providers.Usage{
+		PromptTokens:     2310,
+		CompletionTokens: 5,
+		TotalTokens:      2315,
+		CacheReadTokens:  2000,
+		CacheWriteTokens: 300,
+	}, chunk.Usage)
+}
+
+func TestHTTPClientAndHeaders(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		require.Equal(t, "https://proxy.example.com/v1/messages/count_tokens", r.URL.String())
+		require.Equal(t, "test-api-key", r.Header.Get("X-Api-Key"))
+		require.Equal(t, "token-counting-2024-11-01", r.Header.Get("Anthropic-Beta"))
+		require.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"input_tokens":3}`)),
+			Request:    r,
+		}, nil
+	})}
+
+	provider, err := New(
+		config.WithAPIKey("test-api-key"),
+		config.WithBaseURL("https://proxy.example.com"),
+		config.WithHTTPClient(client),
+		config.WithHeaders(map[string]string{
+			"anthropic-beta": "token-counting-2024-11-01",
+			"X-Trace-Id":     "trace-1",
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = provider.CountTokens(context.Background(), providers.CompletionParams{
+		Model:    "claude-sonnet-4-5",
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+}
+
+// roundTripFunc is an http.RoundTripper that calls itself.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
 // Integration tests - only run if API key is available.
 
 func TestIntegrationCompletion(t *testing.T) {
@@ -926,6 +1484,29 @@ func TestConvertError(t *testing.T) {
 			require.Contains(t, result.Error(), "["+providerName+"]")
 		})
 	}
+
+	t.Run("429 populates RetryAfter from headers", func(t *testing.T) {
+		t.Parallel()
+
+		apiErr := newTestAPIError(t, 429)
+		apiErr.Response.Header = http.Header{"Retry-After": []string{"12"}}
+
+		p := &Provider{}
+
+		var rateLimitErr *errors.RateLimitError
+		require.ErrorAs(t, p.ConvertError(apiErr), &rateLimitErr)
+		require.Equal(t, 12, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("ProviderError carries the status code", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+
+		var providerErr *errors.ProviderError
+		require.ErrorAs(t, p.ConvertError(newTestAPIError(t, 529)), &providerErr)
+		require.Equal(t, 529, providerErr.StatusCode)
+	})
 }
 
 // newTestAPIError creates an Anthropic API error for testing.
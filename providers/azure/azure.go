@@ -0,0 +1,406 @@
+// Package azure provides an Azure OpenAI provider implementation for any-llm.
+//
+// Azure OpenAI serves the OpenAI API under per-deployment paths, so the provider is built on
+// openai.CompatibleProvider and rewrites each request for its deployment and API version.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	openaisdk "github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultAPIVersion = "2024-10-21"
+	envAPIKey         = "AZURE_OPENAI_API_KEY"
+	envAPIVersion     = "AZURE_OPENAI_API_VERSION"
+	envEndpoint       = "AZURE_OPENAI_ENDPOINT"
+	providerName      = "azure"
+)
+
+// TokenScope is the scope of the Microsoft Entra ID access tokens a TokenSource must supply.
+const TokenScope = "https://cognitiveservices.azure.com/.default"
+
+// Provider-specific configuration keys, set with WithAPIVersion, WithDeployments, and WithTokenSource.
+const (
+	configAPIVersion  = "azure_api_version"
+	configDeployments = "azure_deployments"
+	configTokenSource = "azure_token_source"
+)
+
+// Azure OpenAI API constants.
+const (
+	bearerPrefix        = "Bearer "
+	formFieldModel      = "model"
+	headerAPIKey        = "api-key"
+	headerAuthorization = "Authorization"
+	headerContentType   = "Content-Type"
+	mediaTypeMultipart  = "multipart/form-data"
+	pathDeployments     = "/openai/deployments/"
+	pathOpenAI          = "/openai/"
+	queryAPIVersion     = "api-version"
+)
+
+// Azure content filter error codes.
+const (
+	codeContentFilter            = "content_filter"
+	codeContentFilterCamel       = "contentFilter"
+	codeContentPolicyViolation   = "content_policy_violation"
+	codeResponsibleAIPolicyBlock = "ResponsibleAIPolicyViolation"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider      = (*Provider)(nil)
+	_ providers.EmbeddingProvider       = (*Provider)(nil)
+	_ providers.ErrorConverter          = (*Provider)(nil)
+	_ providers.ImageGenerationProvider = (*Provider)(nil)
+	_ providers.ModelLister             = (*Provider)(nil)
+	_ providers.Provider                = (*Provider)(nil)
+	_ providers.SpeechProvider          = (*Provider)(nil)
+	_ providers.TranscriptionProvider   = (*Provider)(nil)
+)
+
+// deploymentPaths are the OpenAI API paths that Azure serves per deployment.
+// Other paths, such as models, are served for the whole resource.
+var deploymentPaths = map[string]bool{
+	"audio/speech":         true,
+	"audio/transcriptions": true,
+	"audio/translations":   true,
+	"chat/completions":     true,
+	"completions":          true,
+	"embeddings":           true,
+	"images/edits":         true,
+	"images/generations":   true,
+}
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Azure OpenAI.
+// It embeds openai.CompatibleProvider since Azure OpenAI exposes the OpenAI API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// TokenSource supplies Microsoft Entra ID access tokens for TokenScope.
+// It is called for every request, so implementations should cache tokens until they expire.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// errorBody is the part of an Azure error payload that identifies content filtering.
+type errorBody struct {
+	Code       string `json:"code"`
+	InnerError *struct {
+		Code                 string         `json:"code"`
+		ContentFilterResult  map[string]any `json:"content_filter_result"`
+		ContentFilterResults map[string]any `json:"content_filter_results"`
+	} `json:"innererror"`
+}
+
+// router rewrites OpenAI API requests for an Azure OpenAI resource and authenticates them.
+type router struct {
+	apiKey      string
+	apiVersion  string
+	basePath    string
+	deployments map[string]string
+	tokenSource TokenSource
+}
+
+// New creates a new Azure OpenAI provider.
+//
+// The resource endpoint, e.g. "https://my-resource.openai.azure.com", is set with WithBaseURL or the
+// AZURE_OPENAI_ENDPOINT environment variable. Requests are authenticated with the token source set
+// with WithTokenSource or, failing that, with an API key from WithAPIKey or AZURE_OPENAI_API_KEY.
+func New(opts ...config.Option) (*Provider, error) {
+	cfg, err := config.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	endpoint, err := cfg.ResolveBaseURL(envEndpoint, "")
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("azure endpoint is required: set %s or use WithBaseURL", envEndpoint)
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", endpoint, err)
+	}
+
+	r := &router{
+		apiKey:      cfg.ResolveAPIKey(envAPIKey),
+		apiVersion:  resolveAPIVersion(cfg),
+		basePath:    strings.TrimSuffix(endpointURL.Path, "/"),
+		deployments: configDeploymentMap(cfg),
+		tokenSource: configTokenSourceValue(cfg),
+	}
+	if r.tokenSource == nil && r.apiKey == "" {
+		return nil, errors.NewMissingAPIKeyError(providerName, envAPIKey)
+	}
+
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		Capabilities:   azureCapabilities(),
+		ClientOptions:  []option.RequestOption{option.WithMiddleware(r.route)},
+		ConvertError:   convertError,
+		DefaultBaseURL: endpoint,
+		Name:           providerName,
+		RequireAPIKey:  false,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// WithAPIVersion sets the Azure OpenAI API version, instead of AZURE_OPENAI_API_VERSION or the default.
+func WithAPIVersion(version string) config.Option {
+	return func(c *config.Config) error {
+		version = strings.TrimSpace(version)
+		if version == "" {
+			return fmt.Errorf("API version cannot be empty")
+		}
+
+		return config.WithExtra(configAPIVersion, version)(c)
+	}
+}
+
+// WithDeployments maps model names to deployment names. Models without a mapping are sent to
+// the deployment of the same name. Repeated options are merged.
+func WithDeployments(deployments map[string]string) config.Option {
+	return func(c *config.Config) error {
+		merged := configDeploymentMap(c)
+		maps.Copy(merged, deployments)
+
+		return config.WithExtra(configDeployments, merged)(c)
+	}
+}
+
+// WithTokenSource authenticates requests with Microsoft Entra ID access tokens instead of an API key.
+func WithTokenSource(source TokenSource) config.Option {
+	return func(c *config.Config) error {
+		if source == nil {
+			return fmt.Errorf("token source cannot be nil")
+		}
+
+		return config.WithExtra(configTokenSource, source)(c)
+	}
+}
+
+// ImageVariation returns an UnsupportedParamError, since Azure OpenAI does not serve image variations.
+func (p *Provider) ImageVariation(
+	_ context.Context,
+	_ providers.ImageVariationParams,
+) (*providers.ImageResponse, error) {
+	return nil, errors.NewUnsupportedParamError(providerName, "image_variation")
+}
+
+// Token implements TokenSource.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// deployment returns the deployment that serves model.
+func (r *router) deployment(model string) string {
+	if deployment, ok := r.deployments[model]; ok {
+		return deployment
+	}
+
+	return model
+}
+
+// route is an OpenAI SDK middleware that moves a request to its Azure path, adds the API version,
+// and replaces the OpenAI authorization header with Azure's.
+func (r *router) route(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	path := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, r.basePath), "/")
+
+	if deploymentPaths[path] {
+		model, err := requestModel(req)
+		if err != nil {
+			return nil, err
+		}
+		req.URL.Path = r.basePath + pathDeployments + url.PathEscape(r.deployment(model)) + "/" + path
+	} else {
+		req.URL.Path = r.basePath + pathOpenAI + path
+	}
+	req.URL.RawPath = ""
+
+	query := req.URL.Query()
+	query.Set(queryAPIVersion, r.apiVersion)
+	req.URL.RawQuery = query.Encode()
+
+	req.Header.Del(headerAuthorization)
+	if r.tokenSource != nil {
+		token, err := r.tokenSource.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("getting Azure access token: %w", err)
+		}
+		req.Header.Set(headerAuthorization, bearerPrefix+token)
+	} else {
+		req.Header.Set(headerAPIKey, r.apiKey)
+	}
+
+	return next(req)
+}
+
+// azureCapabilities returns the capabilities for the Azure OpenAI provider.
+func azureCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true,
+		CompletionPDF:              false,
+		CompletionReasoning:        true,
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            true,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     true,
+		Transcription:              true,
+	}
+}
+
+// configDeploymentMap returns a copy of the configured model-to-deployment map.
+func configDeploymentMap(cfg *config.Config) map[string]string {
+	deployments := make(map[string]string)
+	if v, ok := cfg.ExtraValue(configDeployments); ok {
+		if m, ok := v.(map[string]string); ok {
+			maps.Copy(deployments, m)
+		}
+	}
+
+	return deployments
+}
+
+// configTokenSourceValue returns the configured token source, or nil.
+func configTokenSourceValue(cfg *config.Config) TokenSource {
+	v, _ := cfg.ExtraValue(configTokenSource)
+	source, _ := v.(TokenSource)
+	return source
+}
+
+// convertError recognizes Azure's content filter errors, which are reported with several codes
+// and, for prompts blocked by Responsible AI policies, only in the inner error.
+func convertError(err error) error {
+	var apiErr *openaisdk.Error
+	if !stderrors.As(err, &apiErr) {
+		return nil
+	}
+
+	var body errorBody
+	if jsonErr := json.Unmarshal([]byte(apiErr.RawJSON()), &body); jsonErr != nil {
+		return nil
+	}
+
+	switch body.Code {
+	case codeContentFilter, codeContentFilterCamel, codeContentPolicyViolation:
+		return errors.NewContentFilterError(providerName, err)
+	}
+
+	if body.InnerError != nil && (body.InnerError.Code == codeResponsibleAIPolicyBlock ||
+		body.InnerError.ContentFilterResult != nil || body.InnerError.ContentFilterResults != nil) {
+		return errors.NewContentFilterError(providerName, err)
+	}
+
+	return nil
+}
+
+// multipartModel returns the model field of a multipart form body, such as a transcription request's.
+func multipartModel(body []byte, boundary string) (string, error) {
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return "", fmt.Errorf("request body does not name a model")
+		}
+		if part.FormName() != formFieldModel {
+			continue
+		}
+
+		model, err := io.ReadAll(part)
+		if err != nil || len(model) == 0 {
+			return "", fmt.Errorf("request body does not name a model")
+		}
+		return string(model), nil
+	}
+}
+
+// requestModel returns the model named in a JSON or multipart form request body, leaving the body readable.
+func requestModel(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", fmt.Errorf("request body is required to route to a deployment")
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading request body: %w", err)
+	}
+	_ = req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	mediaType, mediaParams, _ := mime.ParseMediaType(req.Header.Get(headerContentType))
+	if mediaType == mediaTypeMultipart {
+		return multipartModel(body, mediaParams["boundary"])
+	}
+
+	var fields struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil || fields.Model == "" {
+		return "", fmt.Errorf("request body does not name a model")
+	}
+
+	return fields.Model, nil
+}
+
+// resolveAPIVersion returns the API version to request.
+func resolveAPIVersion(cfg *config.Config) string {
+	if v, ok := cfg.ExtraValue(configAPIVersion); ok {
+		if version, ok := v.(string); ok && version != "" {
+			return version
+		}
+	}
+
+	if version := cfg.ResolveEnv(envAPIVersion); version != "" {
+		return version
+	}
+
+	return defaultAPIVersion
+}
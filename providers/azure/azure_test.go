@@ -0,0 +1,454 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+const chatResponse = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "gpt-4o-mini-2024-07-18",
+	"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hello!"}, "finish_reason": "stop"}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+// writeJSON writes body as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key and endpoint", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL("https://example.openai.azure.com"))
+		require.NoError(t, err)
+		require.Equal(t, "azure", provider.Name())
+	})
+
+	t.Run("creates provider from environment variables", func(t *testing.T) {
+		t.Setenv("AZURE_OPENAI_API_KEY", "env-api-key")
+		t.Setenv("AZURE_OPENAI_ENDPOINT", "https://example.openai.azure.com")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("AZURE_OPENAI_API_KEY", "")
+
+		provider, err := New(config.WithBaseURL("https://example.openai.azure.com"))
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "azure", missingKeyErr.Provider)
+		require.Equal(t, "AZURE_OPENAI_API_KEY", missingKeyErr.EnvVar)
+	})
+
+	t.Run("does not need an API key with a token source", func(t *testing.T) {
+		t.Setenv("AZURE_OPENAI_API_KEY", "")
+
+		provider, err := New(
+			config.WithBaseURL("https://example.openai.azure.com"),
+			WithTokenSource(TokenSourceFunc(func(context.Context) (string, error) { return "token", nil })),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when endpoint is missing", func(t *testing.T) {
+		t.Setenv("AZURE_OPENAI_ENDPOINT", "")
+
+		_, err := New(config.WithAPIKey(testAPIKey))
+		require.ErrorContains(t, err, "AZURE_OPENAI_ENDPOINT")
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		_, err := New(config.WithAPIKey(testAPIKey), WithAPIVersion(" "))
+		require.ErrorContains(t, err, "invalid options")
+
+		_, err = New(config.WithAPIKey(testAPIKey), WithTokenSource(nil))
+		require.ErrorContains(t, err, "invalid options")
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL("https://example.openai.azure.com"))
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ImageGeneration)
+	require.True(t, caps.ListModels)
+	require.True(t, caps.Speech)
+	require.True(t, caps.Transcription)
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("routes to the deployment with the API key header", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/openai/deployments/gpt-4o-mini/chat/completions", r.URL.Path)
+			require.Equal(t, defaultAPIVersion, r.URL.Query().Get("api-version"))
+			require.Equal(t, testAPIKey, r.Header.Get("api-key"))
+			require.Empty(t, r.Header.Get("Authorization"))
+
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "gpt-4o-mini", body["model"])
+
+			writeJSON(w, http.StatusOK, chatResponse)
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gpt-4o-mini",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Hello!", resp.Choices[0].Message.Content)
+	})
+
+	t.Run("maps models to deployments and sets the API version", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/openai/deployments/prod-chat/chat/completions", r.URL.Path)
+			require.Equal(t, "2025-04-01-preview", r.URL.Query().Get("api-version"))
+
+			writeJSON(w, http.StatusOK, chatResponse)
+		},
+			config.WithAPIKey(testAPIKey),
+			WithAPIVersion("2025-04-01-preview"),
+			WithDeployments(map[string]string{"gpt-4o-mini": "prod-chat"}),
+			WithDeployments(map[string]string{"text-embedding-3-small": "prod-embed"}),
+		)
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gpt-4o-mini",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("authenticates with the token source", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		source := TokenSourceFunc(func(ctx context.Context) (string, error) {
+			calls++
+			return "entra-token", nil
+		})
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer entra-token", r.Header.Get("Authorization"))
+			require.Empty(t, r.Header.Get("api-key"))
+
+			writeJSON(w, http.StatusOK, chatResponse)
+		}, config.WithAPIKey(testAPIKey), WithTokenSource(source))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gpt-4o-mini",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("returns token source errors", func(t *testing.T) {
+		t.Parallel()
+
+		source := TokenSourceFunc(func(ctx context.Context) (string, error) {
+			return "", stderrors.New("credential unavailable")
+		})
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Fail(t, "unexpected request")
+		}, config.WithAPIKey(testAPIKey), WithTokenSource(source))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gpt-4o-mini",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.ErrorContains(t, err, "credential unavailable")
+	})
+
+	t.Run("keeps the endpoint path", func(t *testing.T) {
+		t.Parallel()
+
+		var gotPath string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			writeJSON(w, http.StatusOK, chatResponse)
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL(server.URL+"/gateway/"))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gpt-4o-mini",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "/gateway/openai/deployments/gpt-4o-mini/chat/completions", gotPath)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openai/deployments/gpt-4o-mini/chat/completions", r.URL.Path)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Azure sends prompt filter results in a first chunk without choices.
+		_, _ = w.Write([]byte(`data: {"id":"","object":"","created":0,"model":"","choices":[],` +
+			`"prompt_filter_results":[{"prompt_index":0,"content_filter_results":{}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,` +
+			`"model":"gpt-4o-mini","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,` +
+			`"model":"gpt-4o-mini","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}, config.WithAPIKey(testAPIKey))
+
+	acc := providers.NewAccumulator()
+	for chunk, err := range providers.Stream(context.Background(), provider, providers.CompletionParams{
+		Model:    "gpt-4o-mini",
+		Messages: testutil.SimpleMessages(),
+	}) {
+		require.NoError(t, err)
+		acc.Add(chunk)
+	}
+
+	completion := acc.ChatCompletion()
+	require.Equal(t, "Hi", completion.Choices[0].Message.Content)
+	require.Equal(t, providers.FinishReasonStop, completion.Choices[0].FinishReason)
+}
+
+func TestEmbedding(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openai/deployments/prod-embed/embeddings", r.URL.Path)
+
+		writeJSON(w, http.StatusOK, `{
+			"object": "list",
+			"data": [{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}],
+			"model": "text-embedding-3-small",
+			"usage": {"prompt_tokens": 2, "total_tokens": 2}
+		}`)
+	}, config.WithAPIKey(testAPIKey), WithDeployments(map[string]string{"text-embedding-3-small": "prod-embed"}))
+
+	resp, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+		Model: "text-embedding-3-small",
+		Input: "hello",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 0.2}, resp.Data[0].Embedding)
+}
+
+func TestTranscription(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openai/deployments/prod-whisper/audio/transcriptions", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		require.Equal(t, "whisper-1", r.FormValue("model"))
+
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer func() { _ = file.Close() }()
+		require.Equal(t, "hello.wav", header.Filename)
+
+		writeJSON(w, http.StatusOK, `{"text": "Hello."}`)
+	}, config.WithAPIKey(testAPIKey), WithDeployments(map[string]string{"whisper-1": "prod-whisper"}))
+
+	resp, err := provider.Transcription(context.Background(), providers.TranscriptionParams{
+		Model:    "whisper-1",
+		Audio:    strings.NewReader("RIFF"),
+		Filename: "hello.wav",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Hello.", resp.Text)
+}
+
+func TestSpeech(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openai/deployments/tts/audio/speech", r.URL.Path)
+
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("ID3"))
+	}, config.WithAPIKey(testAPIKey))
+
+	audio, err := provider.Speech(context.Background(), providers.SpeechParams{
+		Model: "tts",
+		Input: "Hello.",
+		Voice: "alloy",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = audio.Close() })
+
+	data, err := io.ReadAll(audio)
+	require.NoError(t, err)
+	require.Equal(t, "ID3", string(data))
+}
+
+func TestImageGeneration(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openai/deployments/dall-e-3/images/generations", r.URL.Path)
+
+		writeJSON(w, http.StatusOK, `{"created": 1700000000, "data": [{"url": "https://example.com/image.png"}]}`)
+	}, config.WithAPIKey(testAPIKey))
+
+	resp, err := provider.ImageGeneration(context.Background(), providers.ImageGenerationParams{
+		Model:  "dall-e-3",
+		Prompt: "A lighthouse at dusk.",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, "https://example.com/image.png", resp.Data[0].URL)
+}
+
+func TestImageVariation(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}, config.WithAPIKey(testAPIKey))
+
+	_, err := provider.ImageVariation(context.Background(), providers.ImageVariationParams{
+		Model: "dall-e-2",
+		Image: strings.NewReader("png"),
+	})
+	require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+}
+
+func TestListModels(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/openai/models", r.URL.Path)
+		require.Equal(t, defaultAPIVersion, r.URL.Query().Get("api-version"))
+
+		writeJSON(w, http.StatusOK, `{"object": "list", "data": [{"id": "gpt-4o-mini", "object": "model"}]}`)
+	}, config.WithAPIKey(testAPIKey))
+
+	resp, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, "gpt-4o-mini", resp.Data[0].ID)
+}
+
+func TestConvertError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantSentinel error
+	}{
+		{
+			name:   "content filter code becomes ContentFilterError",
+			status: http.StatusBadRequest,
+			body: `{"error": {"code": "content_filter", "message": "The response was filtered.", ` +
+				`"innererror": {"code": "ResponsibleAIPolicyViolation", "content_filter_result": ` +
+				`{"hate": {"filtered": true, "severity": "high"}}}}}`,
+			wantSentinel: errors.ErrContentFilter,
+		},
+		{
+			name:   "Responsible AI inner error becomes ContentFilterError",
+			status: http.StatusBadRequest,
+			body: `{"error": {"code": "invalid_prompt", "message": "Invalid prompt.", ` +
+				`"innererror": {"code": "ResponsibleAIPolicyViolation"}}}`,
+			wantSentinel: errors.ErrContentFilter,
+		},
+		{
+			name:         "image content filter code becomes ContentFilterError",
+			status:       http.StatusBadRequest,
+			body:         `{"error": {"code": "contentFilter", "message": "Your task failed as a result of our safety system."}}`,
+			wantSentinel: errors.ErrContentFilter,
+		},
+		{
+			name:         "missing deployment becomes ModelNotFoundError",
+			status:       http.StatusNotFound,
+			body:         `{"error": {"code": "DeploymentNotFound", "message": "The API deployment does not exist."}}`,
+			wantSentinel: errors.ErrModelNotFound,
+		},
+		{
+			name:         "invalid key becomes AuthenticationError",
+			status:       http.StatusUnauthorized,
+			body:         `{"error": {"code": "401", "message": "Access denied due to invalid subscription key."}}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, tc.status, tc.body)
+			}, config.WithAPIKey(testAPIKey))
+
+			_, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    "gpt-4o-mini",
+				Messages: testutil.SimpleMessages(),
+			})
+			require.ErrorIs(t, err, tc.wantSentinel)
+		})
+	}
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("azure") {
+		t.Skip("AZURE_OPENAI_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("azure"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+}
@@ -0,0 +1,1158 @@
+// Package bedrock provides an Amazon Bedrock provider implementation for any-llm.
+//
+// The provider calls the Bedrock Runtime Converse and ConverseStream APIs directly, signing
+// requests with AWS Signature Version 4.
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURLFormat = "https://bedrock-runtime.%s.amazonaws.com"
+	defaultRegion        = "us-east-1"
+	envBearerToken       = "AWS_BEARER_TOKEN_BEDROCK"
+	envEndpointURL       = "AWS_ENDPOINT_URL_BEDROCK_RUNTIME"
+	providerName         = "bedrock"
+	signingService       = "bedrock"
+)
+
+// Provider-specific configuration keys, set with WithCredentials, WithProfile, and WithRegion.
+const (
+	configCredentials = "aws_credentials"
+	configProfile     = "aws_profile"
+	configRegion      = "aws_region"
+)
+
+// Bedrock Runtime API constants.
+const (
+	bearerPrefix            = "Bearer "
+	contentTypeEventStream  = "application/vnd.amazon.eventstream"
+	contentTypeJSON         = "application/json"
+	errorTypeSeparator      = ":"
+	headerAccept            = "Accept"
+	headerContentType       = "Content-Type"
+	headerErrorType         = "X-Amzn-ErrorType"
+	maxErrorBodySize        = 64 << 10
+	modelPathPrefix         = "/model/"
+	operationConverse       = "converse"
+	operationConverseStream = "converse-stream"
+)
+
+// Event stream headers and message types.
+const (
+	eventHeaderErrorCode     = ":error-code"
+	eventHeaderErrorMessage  = ":error-message"
+	eventHeaderEventType     = ":event-type"
+	eventHeaderExceptionType = ":exception-type"
+	eventHeaderMessageType   = ":message-type"
+	messageTypeError         = "error"
+	messageTypeException     = "exception"
+)
+
+// ConverseStream event types.
+const (
+	eventContentBlockDelta = "contentBlockDelta"
+	eventContentBlockStart = "contentBlockStart"
+	eventMessageStart      = "messageStart"
+	eventMessageStop       = "messageStop"
+	eventMetadata          = "metadata"
+)
+
+// Bedrock exception types, lowercased. Streams report them in lower camel case,
+// so they are compared case-insensitively.
+const (
+	exceptionAccessDenied         = "accessdeniedexception"
+	exceptionExpiredToken         = "expiredtokenexception"
+	exceptionIncompleteSignature  = "incompletesignatureexception"
+	exceptionInvalidSignature     = "invalidsignatureexception"
+	exceptionMissingAuthToken     = "missingauthenticationtokenexception"
+	exceptionResourceNotFound     = "resourcenotfoundexception"
+	exceptionServiceQuotaExceeded = "servicequotaexceededexception"
+	exceptionThrottling           = "throttlingexception"
+	exceptionTooManyRequests      = "toomanyrequestsexception"
+	exceptionUnrecognizedClient   = "unrecognizedclientexception"
+	exceptionValidation           = "validationexception"
+)
+
+// Bedrock stop reasons.
+const (
+	stopReasonContentFiltered       = "content_filtered"
+	stopReasonContextWindowExceeded = "model_context_window_exceeded"
+	stopReasonEndTurn               = "end_turn"
+	stopReasonGuardrailIntervened   = "guardrail_intervened"
+	stopReasonMaxTokens             = "max_tokens"
+	stopReasonStopSequence          = "stop_sequence"
+	stopReasonToolUse               = "tool_use"
+)
+
+// Converse roles.
+const (
+	roleAssistant = "assistant"
+	roleUser      = "user"
+)
+
+// Extra parameters for the Converse request. The keys of additional_model_request_fields are merged
+// into additionalModelRequestFields, where top_k is also set, as Converse has no top-k field.
+// Other keys in CompletionParams.Extra are rejected; model-specific fields belong in
+// additional_model_request_fields.
+const (
+	extraAdditionalModelRequestFields = "additional_model_request_fields"
+	extraGuardrailConfig              = "guardrail_config"
+	extraTopK                         = "top_k"
+)
+
+// Anthropic extended thinking, sent in additionalModelRequestFields.
+const (
+	anthropicModelMarker = "anthropic."
+	fieldBudgetTokens    = "budget_tokens"
+	fieldThinking        = "thinking"
+	fieldTopK            = "top_k"
+	fieldType            = "type"
+	thinkingBudgetHigh   = 16384
+	thinkingBudgetLow    = 1024
+	thinkingBudgetMedium = 4096
+	thinkingTypeEnabled  = "enabled"
+)
+
+// Tool and content constants.
+const (
+	contentTypeFile       = "file"
+	contentTypeImageURL   = "image_url"
+	contentTypeInputAudio = "input_audio"
+	contentTypeText       = "text"
+	dataURLPrefix         = "data:"
+	emptyJSONObject       = "{}"
+	toolChoiceAny         = "any"
+	toolChoiceAuto        = "auto"
+	toolChoiceRequired    = "required"
+	toolTypeFunction      = "function"
+)
+
+// Object type constants.
+const (
+	objectChatCompletion      = "chat.completion"
+	objectChatCompletionChunk = "chat.completion.chunk"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+// contextLengthPatterns are fragments of the ValidationException messages Bedrock models
+// return for prompts that do not fit their context window.
+var contextLengthPatterns = []string{
+	"context length",
+	"context limit",
+	"input is too long",
+	"prompt is too long",
+	"too many input tokens",
+}
+
+// imageFormats maps image MIME types to Converse image formats.
+var imageFormats = map[string]string{
+	"image/gif":  "gif",
+	"image/jpeg": "jpeg",
+	"image/jpg":  "jpeg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Amazon Bedrock.
+type Provider struct {
+	baseURL     string
+	bearerToken string
+	client      *http.Client
+	config      *config.Config
+	now         func() time.Time
+	signer      *signer
+}
+
+// streamState tracks accumulated state during streaming.
+// Note: Only accessed from a single goroutine, so no synchronization needed.
+type streamState struct {
+	created   int64
+	id        string
+	model     string
+	toolCalls map[int]int // Content block index to tool call index.
+}
+
+// New creates a new Bedrock provider.
+//
+// Requests are authenticated with a Bedrock API key if one is set with WithAPIKey or the
+// AWS_BEARER_TOKEN_BEDROCK environment variable. Otherwise they are signed with AWS credentials,
+// taken from WithCredentials, the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment
+// variables, or the shared credentials and config files, in that order. A profile selected with
+// WithProfile takes precedence over the environment variables. The shared files are not read
+// when a Bedrock API key is used, so the region must then come from WithRegion or the environment.
+func New(opts ...config.Option) (*Provider, error) {
+	cfg, err := config.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	bearerToken := cfg.ResolveAPIKey(envBearerToken)
+
+	var (
+		explicitProfile bool
+		prof            profile
+	)
+	if bearerToken == "" {
+		var profileName string
+		profileName, explicitProfile = configString(cfg, configProfile)
+		if profileName == "" {
+			profileName = cfg.ResolveEnv(envProfile)
+		}
+		if profileName == "" {
+			profileName = defaultProfile
+		}
+
+		prof, err = loadProfile(profileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	region := resolveRegion(cfg, prof)
+
+	baseURL, err := cfg.ResolveBaseURL(envEndpointURL, fmt.Sprintf(defaultBaseURLFormat, region))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		bearerToken: bearerToken,
+		client:      cfg.HTTPClient(),
+		config:      cfg,
+		now:         time.Now,
+	}
+
+	if p.bearerToken != "" {
+		return p, nil
+	}
+
+	creds := resolveCredentials(cfg, prof, explicitProfile)
+	if !creds.valid() {
+		return nil, errors.NewMissingAPIKeyError(providerName, envAccessKeyID)
+	}
+
+	p.signer = &signer{credentials: creds, region: region, service: signingService}
+
+	return p, nil
+}
+
+// WithCredentials sets static AWS credentials, which take precedence over the environment and
+// the shared files. The session token is only needed for temporary credentials.
+func WithCredentials(accessKeyID, secretAccessKey, sessionToken string) config.Option {
+	return func(c *config.Config) error {
+		creds := credentials{
+			AccessKeyID:     strings.TrimSpace(accessKeyID),
+			SecretAccessKey: strings.TrimSpace(secretAccessKey),
+			SessionToken:    strings.TrimSpace(sessionToken),
+		}
+		if !creds.valid() {
+			return fmt.Errorf("AWS access key ID and secret access key cannot be empty")
+		}
+
+		return config.WithExtra(configCredentials, creds)(c)
+	}
+}
+
+// WithProfile selects a profile of the shared config and credentials files, instead of
+// AWS_PROFILE or "default".
+func WithProfile(name string) config.Option {
+	return config.WithExtra(configProfile, strings.TrimSpace(name))
+}
+
+// WithRegion sets the AWS region, instead of AWS_REGION, AWS_DEFAULT_REGION, or the profile's region.
+func WithRegion(region string) config.Option {
+	return config.WithExtra(configRegion, strings.TrimSpace(region))
+}
+
+// Capabilities returns the provider's capabilities.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionStreaming:        true,
+		CompletionReasoning:        true,  // Anthropic models, through extended thinking.
+		CompletionImage:            true,  // Images must be sent as data URLs.
+		CompletionPDF:              false, // Not yet mapped to Converse document blocks.
+		CompletionStructuredOutput: false, // Converse has no response format.
+		CountTokens:                false,
+		Embedding:                  false, // Embedding models use InvokeModel, not Converse.
+		ImageGeneration:            false,
+		ListModels:                 false, // Listing models uses the separate Bedrock control plane API.
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// Completion performs a chat completion request.
+func (p *Provider) Completion(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.ChatCompletion, error) {
+	req, err := convertParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp converseResponse
+	if err := p.do(ctx, modelPath(params.Model, operationConverse), req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertResponse(&resp, params.Model), nil
+}
+
+// CompletionStream performs a streaming chat completion request.
+func (p *Provider) CompletionStream(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		req, err := convertParams(params)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		body, err := p.send(ctx, modelPath(params.Model, operationConverseStream), req, contentTypeEventStream)
+		if err != nil {
+			errs <- p.ConvertError(err)
+			return
+		}
+		defer func() { _ = body.Close() }()
+
+		state := newStreamState(params.Model)
+		reader := newEventStreamReader(body)
+
+		for reader.Next() {
+			chunk, ok, err := state.handleMessage(reader.Message())
+			if err != nil {
+				errs <- p.ConvertError(err)
+				return
+			}
+			if !ok {
+				continue
+			}
+
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := reader.Err(); err != nil {
+			errs <- p.ConvertError(err)
+		}
+	}()
+
+	return chunks, errs
+}
+
+// ConvertError converts a Bedrock API error to a unified error type.
+// Implements providers.ErrorConverter.
+func (p *Provider) ConvertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Errors other than API errors (e.g., network errors) are generic provider errors.
+	var apiErr *apiError
+	if !stderrors.As(err, &apiErr) {
+		return errors.NewProviderError(providerName, err)
+	}
+
+	switch strings.ToLower(apiErr.Type) {
+	case exceptionThrottling, exceptionServiceQuotaExceeded, exceptionTooManyRequests:
+		return newRateLimitError(apiErr)
+	case exceptionAccessDenied,
+		exceptionExpiredToken,
+		exceptionIncompleteSignature,
+		exceptionInvalidSignature,
+		exceptionMissingAuthToken,
+		exceptionUnrecognizedClient:
+		return errors.NewAuthenticationError(providerName, err)
+	case exceptionResourceNotFound:
+		return errors.NewModelNotFoundError(providerName, err)
+	case exceptionValidation:
+		if isContextLengthMessage(apiErr.Message) {
+			return errors.NewContextLengthError(providerName, err)
+		}
+		return errors.NewInvalidRequestError(providerName, err)
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest:
+		return errors.NewInvalidRequestError(providerName, err)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errors.NewAuthenticationError(providerName, err)
+	case http.StatusNotFound:
+		return errors.NewModelNotFoundError(providerName, err)
+	case http.StatusTooManyRequests:
+		return newRateLimitError(apiErr)
+	default:
+		providerErr := errors.NewProviderError(providerName, err)
+		providerErr.StatusCode = apiErr.StatusCode
+		return providerErr
+	}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// do sends a request and decodes the JSON response body into out.
+func (p *Provider) do(ctx context.Context, path string, body, out any) error {
+	respBody, err := p.send(ctx, path, body, contentTypeJSON)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = respBody.Close() }()
+
+	if err := json.NewDecoder(respBody).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// send sends a signed POST request with a JSON body and returns the response body.
+// Error responses are returned as an *apiError.
+func (p *Provider) send(ctx context.Context, path string, body any, accept string) (io.ReadCloser, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerAccept, accept)
+	req.Header.Set(headerContentType, contentTypeJSON)
+	p.config.ApplyHeaders(req.Header) // Before signing, so that configured X-Amz-* headers are signed.
+
+	if p.bearerToken != "" {
+		req.Header.Set(headerAuthorization, bearerPrefix+p.bearerToken)
+	} else {
+		p.signer.sign(req, data, p.now())
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newAPIError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// newStreamState creates a new stream state.
+func newStreamState(model string) *streamState {
+	return &streamState{
+		created:   time.Now().Unix(),
+		id:        generateID(),
+		model:     model,
+		toolCalls: make(map[int]int),
+	}
+}
+
+// chunk returns a chunk with a single choice holding delta.
+func (s *streamState) chunk(delta providers.ChunkDelta, finishReason string) providers.ChatCompletionChunk {
+	return providers.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  objectChatCompletionChunk,
+		Created: s.created,
+		Model:   s.model,
+		Choices: []providers.ChunkChoice{{Delta: delta, FinishReason: finishReason}},
+	}
+}
+
+// handleMessage converts a stream message to a chunk.
+// It reports false for messages that produce no chunk, and returns exceptions as an *apiError.
+func (s *streamState) handleMessage(msg eventStreamMessage) (providers.ChatCompletionChunk, bool, error) {
+	switch msg.Headers[eventHeaderMessageType] {
+	case messageTypeException:
+		return providers.ChatCompletionChunk{}, false, newStreamException(msg)
+	case messageTypeError:
+		return providers.ChatCompletionChunk{}, false, &apiError{
+			Type:    msg.Headers[eventHeaderErrorCode],
+			Message: msg.Headers[eventHeaderErrorMessage],
+		}
+	}
+
+	eventType := msg.Headers[eventHeaderEventType]
+	switch eventType {
+	case eventMessageStart:
+		return s.chunk(providers.ChunkDelta{Role: providers.RoleAssistant}, ""), true, nil
+	case eventContentBlockStart:
+		var event contentBlockStartEvent
+		if err := decodeEvent(eventType, msg.Payload, &event); err != nil {
+			return providers.ChatCompletionChunk{}, false, err
+		}
+		if event.Start.ToolUse == nil {
+			return providers.ChatCompletionChunk{}, false, nil
+		}
+
+		index := len(s.toolCalls)
+		s.toolCalls[event.ContentBlockIndex] = index
+
+		return s.chunk(providers.ChunkDelta{ToolCalls: []providers.ToolCall{{
+			Index: index,
+			ID:    event.Start.ToolUse.ToolUseID,
+			Type:  toolTypeFunction,
+			Function: providers.FunctionCall{
+				Name: event.Start.ToolUse.Name,
+			},
+		}}}, ""), true, nil
+	case eventContentBlockDelta:
+		var event contentBlockDeltaEvent
+		if err := decodeEvent(eventType, msg.Payload, &event); err != nil {
+			return providers.ChatCompletionChunk{}, false, err
+		}
+		return s.handleDelta(event)
+	case eventMessageStop:
+		var event messageStopEvent
+		if err := decodeEvent(eventType, msg.Payload, &event); err != nil {
+			return providers.ChatCompletionChunk{}, false, err
+		}
+		return s.chunk(providers.ChunkDelta{}, convertStopReason(event.StopReason)), true, nil
+	case eventMetadata:
+		var event metadataEvent
+		if err := decodeEvent(eventType, msg.Payload, &event); err != nil {
+			return providers.ChatCompletionChunk{}, false, err
+		}
+
+		// Usage arrives after the finish reason, in a chunk without choices as in OpenAI's format.
+		chunk := s.chunk(providers.ChunkDelta{}, "")
+		chunk.Choices = []providers.ChunkChoice{}
+		chunk.Usage = convertUsage(event.Usage)
+		return chunk, true, nil
+	default:
+		return providers.ChatCompletionChunk{}, false, nil
+	}
+}
+
+// handleDelta converts a content block delta to a chunk.
+func (s *streamState) handleDelta(event contentBlockDeltaEvent) (providers.ChatCompletionChunk, bool, error) {
+	switch {
+	case event.Delta.ToolUse != nil:
+		return s.chunk(providers.ChunkDelta{ToolCalls: []providers.ToolCall{{
+			Index: s.toolCalls[event.ContentBlockIndex],
+			Function: providers.FunctionCall{
+				Arguments: event.Delta.ToolUse.Input,
+			},
+		}}}, ""), true, nil
+	case event.Delta.ReasoningContent != nil:
+		if event.Delta.ReasoningContent.Text == "" {
+			return providers.ChatCompletionChunk{}, false, nil
+		}
+		return s.chunk(providers.ChunkDelta{
+			Reasoning: &providers.Reasoning{Content: event.Delta.ReasoningContent.Text},
+		}, ""), true, nil
+	case event.Delta.Text != "":
+		return s.chunk(providers.ChunkDelta{Content: event.Delta.Text}, ""), true, nil
+	default:
+		return providers.ChatCompletionChunk{}, false, nil
+	}
+}
+
+// applyExtra decodes provider-specific parameters into req, merging model-specific fields.
+func applyExtra(req *converseRequest, params map[string]any) error {
+	for _, key := range extra.Keys(params) {
+		value := params[key]
+
+		var err error
+		switch key {
+		case extraAdditionalModelRequestFields:
+			var fields map[string]any
+			if err = extra.Decode(key, value, &fields); err == nil {
+				for k, v := range fields {
+					setModelRequestField(req, k, v)
+				}
+			}
+		case extraGuardrailConfig:
+			err = extra.Decode(key, value, &req.GuardrailConfig)
+		case extraTopK:
+			var topK int
+			if err = extra.Decode(key, value, &topK); err == nil {
+				setModelRequestField(req, fieldTopK, topK)
+			}
+		default:
+			return errors.NewUnsupportedParamError(providerName, key)
+		}
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	return nil
+}
+
+// applyReasoning enables extended thinking for Anthropic models.
+// Other models reason according to their own defaults and reject explicit effort levels.
+func applyReasoning(req *converseRequest, params providers.CompletionParams) error {
+	budget, ok := thinkingBudget(params.ReasoningEffort)
+	if !ok {
+		return nil
+	}
+
+	if !strings.Contains(params.Model, anthropicModelMarker) {
+		return errors.NewUnsupportedParamError(providerName, "reasoning_effort")
+	}
+
+	setModelRequestField(req, fieldThinking, map[string]any{
+		fieldType:         thinkingTypeEnabled,
+		fieldBudgetTokens: budget,
+	})
+
+	// Increase max tokens to accommodate thinking.
+	minTokens := budget * 2
+	if req.InferenceConfig == nil {
+		req.InferenceConfig = &inferenceConfig{}
+	}
+	if req.InferenceConfig.MaxTokens == nil || *req.InferenceConfig.MaxTokens < minTokens {
+		req.InferenceConfig.MaxTokens = &minTokens
+	}
+
+	return nil
+}
+
+// configString returns a string-valued provider-specific configuration value,
+// and whether it was set to a non-empty value.
+func configString(cfg *config.Config, key string) (string, bool) {
+	v, ok := cfg.ExtraValue(key)
+	if !ok {
+		return "", false
+	}
+
+	s, _ := v.(string)
+	return s, s != ""
+}
+
+// convertAssistantMessage converts an assistant message to Converse format.
+func convertAssistantMessage(msg providers.Message) message {
+	content := make([]contentBlock, 0, 1+len(msg.ToolCalls))
+	if text := msg.ContentString(); text != "" {
+		content = append(content, textBlock(text))
+	}
+
+	for _, tc := range msg.ToolCalls {
+		var input map[string]any
+		if err := json.Unmarshal([]byte(tc.Function.Arguments), &input); err != nil || input == nil {
+			input = map[string]any{}
+		}
+
+		content = append(content, contentBlock{ToolUse: &toolUseBlock{
+			Input:     input,
+			Name:      tc.Function.Name,
+			ToolUseID: tc.ID,
+		}})
+	}
+
+	if len(content) == 0 {
+		content = append(content, textBlock(""))
+	}
+
+	return message{Role: roleAssistant, Content: content}
+}
+
+// convertContent splits Converse content blocks into text, reasoning, and tool calls.
+func convertContent(blocks []contentBlock) (string, string, []providers.ToolCall) {
+	var text, reasoning strings.Builder
+	var toolCalls []providers.ToolCall
+
+	for _, block := range blocks {
+		switch {
+		case block.Text != nil:
+			text.WriteString(*block.Text)
+		case block.ReasoningContent != nil && block.ReasoningContent.ReasoningText != nil:
+			reasoning.WriteString(block.ReasoningContent.ReasoningText.Text)
+		case block.ToolUse != nil:
+			args := emptyJSONObject
+			if block.ToolUse.Input != nil {
+				if argsBytes, err := json.Marshal(block.ToolUse.Input); err == nil {
+					args = string(argsBytes)
+				}
+			}
+
+			toolCalls = append(toolCalls, providers.ToolCall{
+				Index: len(toolCalls),
+				ID:    block.ToolUse.ToolUseID,
+				Type:  toolTypeFunction,
+				Function: providers.FunctionCall{
+					Name:      block.ToolUse.Name,
+					Arguments: args,
+				},
+			})
+		}
+	}
+
+	return text.String(), reasoning.String(), toolCalls
+}
+
+// convertImage converts an image URL to a Converse image block.
+// Converse only accepts image bytes, so the image must be a base64 data URL.
+func convertImage(img *providers.ImageURL) (contentBlock, error) {
+	header, data, ok := strings.Cut(strings.TrimPrefix(img.URL, dataURLPrefix), ",")
+	if !strings.HasPrefix(img.URL, dataURLPrefix) || !ok {
+		return contentBlock{}, fmt.Errorf("images must be base64 data URLs")
+	}
+
+	mimeType, _, _ := strings.Cut(header, ";")
+	format, ok := imageFormats[mimeType]
+	if !ok {
+		return contentBlock{}, fmt.Errorf("unsupported image type %q", mimeType)
+	}
+
+	return contentBlock{Image: &imageBlock{Format: format, Source: imageSource{Bytes: data}}}, nil
+}
+
+// convertMessages converts provider messages to Converse messages.
+// System messages are returned separately, and consecutive messages with the same role, such as
+// the results of parallel tool calls, are merged into one, as Converse requires alternating roles.
+func convertMessages(messages []providers.Message) ([]message, []systemBlock, error) {
+	result := make([]message, 0, len(messages))
+	var system []systemBlock
+
+	for _, msg := range messages {
+		var converted message
+
+		switch msg.Role {
+		case providers.RoleSystem:
+			system = append(system, systemBlock{Text: msg.ContentString()})
+			continue
+		case providers.RoleUser:
+			var err error
+			if converted, err = convertUserMessage(msg); err != nil {
+				return nil, nil, err
+			}
+		case providers.RoleAssistant:
+			converted = convertAssistantMessage(msg)
+		case providers.RoleTool:
+			converted = message{Role: roleUser, Content: []contentBlock{{ToolResult: &toolResultBlock{
+				Content:   []toolResultContent{{Text: msg.ContentString()}},
+				ToolUseID: msg.ToolCallID,
+			}}}}
+		default:
+			return nil, nil, fmt.Errorf("unsupported message role %q", msg.Role)
+		}
+
+		if n := len(result); n > 0 && result[n-1].Role == converted.Role {
+			result[n-1].Content = append(result[n-1].Content, converted.Content...)
+			continue
+		}
+		result = append(result, converted)
+	}
+
+	return result, system, nil
+}
+
+// convertParams converts providers.CompletionParams to a Converse request.
+func convertParams(params providers.CompletionParams) (*converseRequest, error) {
+	if params.Model == "" {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("model is required"))
+	}
+
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
+	messages, system, err := convertMessages(params.Messages)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, err)
+	}
+
+	req := &converseRequest{
+		Messages: messages,
+		System:   system,
+	}
+
+	if params.MaxTokens != nil || len(params.Stop) > 0 || params.Temperature != nil || params.TopP != nil {
+		req.InferenceConfig = &inferenceConfig{
+			MaxTokens:     params.MaxTokens,
+			StopSequences: params.Stop,
+			Temperature:   params.Temperature,
+			TopP:          params.TopP,
+		}
+	}
+
+	if len(params.Tools) > 0 {
+		choice, err := convertToolChoice(params.ToolChoice)
+		if err != nil {
+			return nil, err
+		}
+		req.ToolConfig = &toolConfig{Tools: convertTools(params.Tools), ToolChoice: choice}
+	}
+
+	if err := applyReasoning(req, params); err != nil {
+		return nil, err
+	}
+
+	if err := applyExtra(req, params.Extra); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// convertResponse converts a Converse response to provider format.
+func convertResponse(resp *converseResponse, model string) *providers.ChatCompletion {
+	text, reasoning, toolCalls := convertContent(resp.Output.Message.Content)
+
+	msg := providers.Message{
+		Role:      providers.RoleAssistant,
+		Content:   text,
+		ToolCalls: toolCalls,
+	}
+	if reasoning != "" {
+		msg.Reasoning = &providers.Reasoning{Content: reasoning}
+	}
+
+	return &providers.ChatCompletion{
+		ID:      generateID(),
+		Object:  objectChatCompletion,
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []providers.Choice{{
+			Message:      msg,
+			FinishReason: convertStopReason(resp.StopReason),
+		}},
+		Usage: convertUsage(resp.Usage),
+	}
+}
+
+// convertStopReason converts a Converse stop reason to OpenAI format.
+func convertStopReason(reason string) string {
+	switch reason {
+	case stopReasonEndTurn, stopReasonStopSequence:
+		return providers.FinishReasonStop
+	case stopReasonMaxTokens, stopReasonContextWindowExceeded:
+		return providers.FinishReasonLength
+	case stopReasonToolUse:
+		return providers.FinishReasonToolCalls
+	case stopReasonContentFiltered, stopReasonGuardrailIntervened:
+		return providers.FinishReasonContentFilter
+	case "":
+		return ""
+	default:
+		return providers.FinishReasonStop
+	}
+}
+
+// convertToolChoice converts a provider tool choice to Converse format.
+// Converse cannot forbid tool calls, so "none" is unsupported.
+func convertToolChoice(choice any) (*toolChoice, error) {
+	switch v := choice.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		switch v {
+		case toolChoiceAuto:
+			return &toolChoice{Auto: &autoToolChoice{}}, nil
+		case toolChoiceRequired, toolChoiceAny:
+			return &toolChoice{Any: &anyToolChoice{}}, nil
+		}
+	case providers.ToolChoice:
+		if v.Function != nil {
+			return &toolChoice{Tool: &specificToolChoice{Name: v.Function.Name}}, nil
+		}
+	}
+
+	return nil, errors.NewUnsupportedParamError(providerName, "tool_choice")
+}
+
+// convertTools converts provider tools to Converse tool specifications.
+func convertTools(tools []providers.Tool) []tool {
+	result := make([]tool, 0, len(tools))
+	for _, t := range tools {
+		schema := t.Function.Parameters
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+
+		result = append(result, tool{ToolSpec: toolSpec{
+			Description: t.Function.Description,
+			InputSchema: toolInputSchema{JSON: schema},
+			Name:        t.Function.Name,
+		}})
+	}
+
+	return result
+}
+
+// convertUsage converts Converse token usage to provider format.
+func convertUsage(usage *tokenUsage) *providers.Usage {
+	if usage == nil {
+		return nil
+	}
+
+	totalTokens := usage.TotalTokens
+	if totalTokens == 0 {
+		totalTokens = usage.InputTokens + usage.OutputTokens
+	}
+
+	return &providers.Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      totalTokens,
+	}
+}
+
+// convertUserMessage converts a user message to Converse format.
+func convertUserMessage(msg providers.Message) (message, error) {
+	if !msg.IsMultiModal() {
+		return message{Role: roleUser, Content: []contentBlock{textBlock(msg.ContentString())}}, nil
+	}
+
+	content := make([]contentBlock, 0, len(msg.ContentParts()))
+	for _, p := range msg.ContentParts() {
+		switch p.Type {
+		case contentTypeText:
+			content = append(content, textBlock(p.Text))
+		case contentTypeImageURL:
+			if p.ImageURL == nil {
+				continue
+			}
+			block, err := convertImage(p.ImageURL)
+			if err != nil {
+				return message{}, err
+			}
+			content = append(content, block)
+		}
+	}
+
+	return message{Role: roleUser, Content: content}, nil
+}
+
+// decodeEvent decodes the JSON payload of a stream event.
+func decodeEvent(eventType string, payload []byte, out any) error {
+	if err := json.Unmarshal(payload, out); err != nil {
+		return fmt.Errorf("decoding %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+// generateID generates a unique ID for responses, which Converse does not identify.
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("chatcmpl-%d-%s", time.Now().UnixNano(), hex.EncodeToString(b))
+}
+
+// hasContentPart reports whether any message has a content part of the given type.
+func hasContentPart(messages []providers.Message, partType string) bool {
+	for _, msg := range messages {
+		for _, part := range msg.ContentParts() {
+			if part.Type == partType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isContextLengthMessage reports whether a ValidationException message reports a prompt
+// that does not fit the model's context window.
+func isContextLengthMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, pattern := range contextLengthPatterns {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modelPath returns the API path of an operation on a model. Model IDs may be ARNs,
+// so the whole ID is escaped as a single path segment.
+func modelPath(model, operation string) string {
+	return modelPathPrefix + uriEncode(model, true) + "/" + operation
+}
+
+// newAPIError builds an *apiError from an error response.
+func newAPIError(resp *http.Response) *apiError {
+	apiErr := &apiError{
+		Header:     resp.Header,
+		StatusCode: resp.StatusCode,
+	}
+
+	// The error type header looks like "ValidationException:http://internal.amazon.com/...".
+	apiErr.Type, _, _ = strings.Cut(resp.Header.Get(headerErrorType), errorTypeSeparator)
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		apiErr.Message = errResp.Message
+		if apiErr.Type == "" {
+			_, apiErr.Type, _ = strings.Cut(errResp.Type, "#")
+		}
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
+
+// newRateLimitError creates a RateLimitError, populating RetryAfter from the response headers.
+func newRateLimitError(apiErr *apiError) *errors.RateLimitError {
+	rateLimitErr := errors.NewRateLimitError(providerName, apiErr)
+	if apiErr.Header != nil {
+		rateLimitErr.RetryAfter = errors.RetryAfterFromHeader(apiErr.Header)
+	}
+	return rateLimitErr
+}
+
+// newStreamException builds an *apiError from an exception message of a stream.
+func newStreamException(msg eventStreamMessage) *apiError {
+	apiErr := &apiError{Type: msg.Headers[eventHeaderExceptionType]}
+
+	var errResp errorResponse
+	if err := json.Unmarshal(msg.Payload, &errResp); err == nil && errResp.Message != "" {
+		apiErr.Message = errResp.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(msg.Payload))
+	}
+
+	return apiErr
+}
+
+// resolveCredentials returns the AWS credentials to sign requests with.
+func resolveCredentials(cfg *config.Config, prof profile, explicitProfile bool) credentials {
+	if v, ok := cfg.ExtraValue(configCredentials); ok {
+		if creds, ok := v.(credentials); ok {
+			return creds
+		}
+	}
+
+	if !explicitProfile {
+		creds := credentials{
+			AccessKeyID:     cfg.ResolveEnv(envAccessKeyID),
+			SecretAccessKey: cfg.ResolveEnv(envSecretAccessKey),
+			SessionToken:    cfg.ResolveEnv(envSessionToken),
+		}
+		if creds.valid() {
+			return creds
+		}
+	}
+
+	return prof.credentials
+}
+
+// resolveRegion returns the AWS region to call.
+func resolveRegion(cfg *config.Config, prof profile) string {
+	if region, ok := configString(cfg, configRegion); ok {
+		return region
+	}
+
+	for _, region := range []string{cfg.ResolveEnv(envRegion), cfg.ResolveEnv(envDefaultRegion), prof.region} {
+		if region != "" {
+			return region
+		}
+	}
+
+	return defaultRegion
+}
+
+// setModelRequestField sets a model-specific request field.
+func setModelRequestField(req *converseRequest, key string, value any) {
+	if req.AdditionalModelRequestFields == nil {
+		req.AdditionalModelRequestFields = make(map[string]any)
+	}
+	req.AdditionalModelRequestFields[key] = value
+}
+
+// textBlock returns a text content block.
+func textBlock(text string) contentBlock {
+	return contentBlock{Text: &text}
+}
+
+// thinkingBudget returns the thinking token budget for the given reasoning effort.
+// Returns the budget and true if the effort level enables thinking, or 0 and false otherwise.
+func thinkingBudget(effort providers.ReasoningEffort) (int, bool) {
+	switch effort {
+	case providers.ReasoningEffortLow:
+		return thinkingBudgetLow, true
+	case providers.ReasoningEffortMedium:
+		return thinkingBudgetMedium, true
+	case providers.ReasoningEffortHigh:
+		return thinkingBudgetHigh, true
+	default:
+		return 0, false
+	}
+}
+
+// validateParams rejects parameters that Converse cannot honor.
+func validateParams(params providers.CompletionParams) error {
+	switch {
+	case params.TopLogprobs != nil:
+		return errors.NewUnsupportedParamError(providerName, "top_logprobs")
+	case params.Logprobs:
+		return errors.NewUnsupportedParamError(providerName, "logprobs")
+	case params.ResponseFormat != nil:
+		return errors.NewUnsupportedParamError(providerName, "response_format")
+	case params.Seed != nil:
+		return errors.NewUnsupportedParamError(providerName, "seed")
+	case hasContentPart(params.Messages, contentTypeFile):
+		return errors.NewUnsupportedParamError(providerName, "file content")
+	case hasContentPart(params.Messages, contentTypeInputAudio):
+		return errors.NewUnsupportedParamError(providerName, contentTypeInputAudio)
+	case params.Audio != nil || slices.Contains(params.Modalities, providers.ModalityAudio):
+		return errors.NewUnsupportedParamError(providerName, "audio")
+	}
+
+	return nil
+}
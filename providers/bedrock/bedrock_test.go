@@ -0,0 +1,1021 @@
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const (
+	testAccessKeyID     = "AKIDTEST"
+	testModel           = "anthropic.claude-3-haiku-20240307-v1:0"
+	testModelPath       = "/model/anthropic.claude-3-haiku-20240307-v1%3A0"
+	testRegion          = "us-west-2"
+	testSecretAccessKey = "test-secret"
+)
+
+var testTime = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// clearAWSEnv isolates a test from the AWS configuration of the environment.
+func clearAWSEnv(t *testing.T) {
+	t.Helper()
+
+	for _, env := range []string{
+		envAccessKeyID,
+		envBearerToken,
+		envDefaultRegion,
+		envEndpointURL,
+		envProfile,
+		envRegion,
+		envSecretAccessKey,
+		envSessionToken,
+	} {
+		t.Setenv(env, "")
+	}
+
+	dir := t.TempDir()
+	t.Setenv(envSharedCredentialsFile, filepath.Join(dir, "credentials"))
+	t.Setenv(envConfigFile, filepath.Join(dir, "config"))
+}
+
+// newTestProvider starts a stand-in for the Bedrock Runtime API that checks every request is signed
+// with the test credentials, and returns a provider that calls it at a fixed time.
+func newTestProvider(t *testing.T, handler http.HandlerFunc, opts ...config.Option) *Provider {
+	t.Helper()
+
+	signed := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requireSigned(t, r, body)
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		handler(w, r)
+	}
+
+	opts = append([]config.Option{
+		WithCredentials(testAccessKeyID, testSecretAccessKey, ""),
+		WithRegion(testRegion),
+	}, opts...)
+	provider := testutil.NewTestProvider(t, New, signed, opts...)
+	provider.now = func() time.Time { return testTime }
+
+	return provider
+}
+
+// requireSigned checks that a request received by a stand-in server carries a valid signature.
+func requireSigned(t *testing.T, r *http.Request, body []byte) {
+	t.Helper()
+
+	want := r.Clone(context.Background())
+	want.Header.Del(headerAuthorization)
+
+	s := &signer{
+		credentials: credentials{AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretAccessKey},
+		region:      testRegion,
+		service:     signingService,
+	}
+	s.sign(want, body, testTime)
+
+	require.Equal(t, want.Header.Get(headerAuthorization), r.Header.Get(headerAuthorization))
+	require.Contains(t, r.Header.Get(headerAuthorization), "/20250102/us-west-2/bedrock/aws4_request")
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, v any) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with explicit credentials", func(t *testing.T) {
+		clearAWSEnv(t)
+
+		provider, err := New(WithCredentials(testAccessKeyID, testSecretAccessKey, "token"), WithRegion(testRegion))
+		require.NoError(t, err)
+		require.Equal(t, "bedrock", provider.Name())
+		require.Equal(t, "https://bedrock-runtime.us-west-2.amazonaws.com", provider.baseURL)
+		require.Equal(t, "token", provider.signer.credentials.SessionToken)
+		require.Equal(t, testRegion, provider.signer.region)
+	})
+
+	t.Run("creates provider from environment variables", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv(envAccessKeyID, "AKIDENV")
+		t.Setenv(envSecretAccessKey, "env-secret")
+		t.Setenv(envDefaultRegion, "eu-central-1")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "AKIDENV", provider.signer.credentials.AccessKeyID)
+		require.Equal(t, "eu-central-1", provider.signer.region)
+		require.Equal(t, "https://bedrock-runtime.eu-central-1.amazonaws.com", provider.baseURL)
+	})
+
+	t.Run("prefers AWS_REGION over AWS_DEFAULT_REGION", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv(envAccessKeyID, "AKIDENV")
+		t.Setenv(envSecretAccessKey, "env-secret")
+		t.Setenv(envDefaultRegion, "eu-central-1")
+		t.Setenv(envRegion, "ap-south-1")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "ap-south-1", provider.signer.region)
+	})
+
+	t.Run("defaults to us-east-1", func(t *testing.T) {
+		clearAWSEnv(t)
+
+		provider, err := New(WithCredentials(testAccessKeyID, testSecretAccessKey, ""))
+		require.NoError(t, err)
+		require.Equal(t, "us-east-1", provider.signer.region)
+	})
+
+	t.Run("reads the profile from the shared files", func(t *testing.T) {
+		clearAWSEnv(t)
+		writeSharedFiles(t, "[work]\naws_access_key_id = AKIDWORK\naws_secret_access_key = work-secret\n",
+			"[profile work]\nregion = ca-central-1\n")
+		t.Setenv(envProfile, "work")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "AKIDWORK", provider.signer.credentials.AccessKeyID)
+		require.Equal(t, "ca-central-1", provider.signer.region)
+	})
+
+	t.Run("explicit profile takes precedence over environment credentials", func(t *testing.T) {
+		clearAWSEnv(t)
+		writeSharedFiles(t, "[work]\naws_access_key_id = AKIDWORK\naws_secret_access_key = work-secret\n", "")
+		t.Setenv(envAccessKeyID, "AKIDENV")
+		t.Setenv(envSecretAccessKey, "env-secret")
+
+		provider, err := New(WithProfile("work"))
+		require.NoError(t, err)
+		require.Equal(t, "AKIDWORK", provider.signer.credentials.AccessKeyID)
+	})
+
+	t.Run("uses a Bedrock API key instead of signing", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv(envBearerToken, "bedrock-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "bedrock-api-key", provider.bearerToken)
+		require.Nil(t, provider.signer)
+	})
+
+	t.Run("does not read the shared files with a Bedrock API key", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv(envBearerToken, "bedrock-api-key")
+		t.Setenv(envSharedCredentialsFile, t.TempDir()) // A directory cannot be read as a file.
+
+		provider, err := New(WithRegion("eu-west-1"))
+		require.NoError(t, err)
+		require.Equal(t, "https://bedrock-runtime.eu-west-1.amazonaws.com", provider.baseURL)
+
+		t.Setenv(envBearerToken, "")
+		_, err = New(WithRegion("eu-west-1"))
+		require.Error(t, err)
+	})
+
+	t.Run("uses the endpoint URL from the environment", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv(envEndpointURL, "http://localhost:4566/")
+
+		provider, err := New(WithCredentials(testAccessKeyID, testSecretAccessKey, ""))
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:4566", provider.baseURL)
+	})
+
+	t.Run("returns error when credentials are missing", func(t *testing.T) {
+		clearAWSEnv(t)
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "bedrock", missingKeyErr.Provider)
+		require.Equal(t, "AWS_ACCESS_KEY_ID", missingKeyErr.EnvVar)
+	})
+
+	t.Run("rejects empty explicit credentials", func(t *testing.T) {
+		clearAWSEnv(t)
+
+		_, err := New(WithCredentials(" ", testSecretAccessKey, ""))
+		require.ErrorContains(t, err, "invalid options")
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	caps := (&Provider{}).Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.False(t, caps.CompletionStructuredOutput)
+	require.False(t, caps.Embedding)
+	require.False(t, caps.ListModels)
+}
+
+func TestConvertMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("separates system messages", func(t *testing.T) {
+		t.Parallel()
+
+		messages, system, err := convertMessages(testutil.MessagesWithSystem())
+		require.NoError(t, err)
+		require.Equal(t, []systemBlock{{Text: "You are a helpful assistant that follows instructions exactly."}}, system)
+		require.Len(t, messages, 1)
+		require.Equal(t, roleUser, messages[0].Role)
+	})
+
+	t.Run("converts tool calls and merges tool results into one user turn", func(t *testing.T) {
+		t.Parallel()
+
+		msgs := testutil.AgentLoopMessages()
+		msgs[1].ToolCalls = append(msgs[1].ToolCalls, providers.ToolCall{
+			ID:       "call_456",
+			Type:     "function",
+			Function: providers.FunctionCall{Name: "get_current_date", Arguments: ""},
+		})
+		msgs = append(msgs, providers.Message{Role: providers.RoleTool, Content: "2025-01-02", ToolCallID: "call_456"})
+
+		messages, _, err := convertMessages(msgs)
+		require.NoError(t, err)
+		require.Len(t, messages, 3)
+
+		assistant := messages[1]
+		require.Equal(t, roleAssistant, assistant.Role)
+		require.Len(t, assistant.Content, 2)
+		require.Equal(t, "call_123", assistant.Content[0].ToolUse.ToolUseID)
+		require.Equal(t, "get_weather", assistant.Content[0].ToolUse.Name)
+		require.Equal(t, map[string]any{"location": "Salvaterra"}, assistant.Content[0].ToolUse.Input)
+		require.Equal(t, map[string]any{}, assistant.Content[1].ToolUse.Input)
+
+		results := messages[2]
+		require.Equal(t, roleUser, results.Role)
+		require.Len(t, results.Content, 2)
+		require.Equal(t, "call_123", results.Content[0].ToolResult.ToolUseID)
+		require.Equal(t, []toolResultContent{{Text: "sunny, 22°C"}}, results.Content[0].ToolResult.Content)
+		require.Equal(t, "call_456", results.Content[1].ToolResult.ToolUseID)
+	})
+
+	t.Run("converts data URL images", func(t *testing.T) {
+		t.Parallel()
+
+		messages, _, err := convertMessages([]providers.Message{{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "text", Text: "What is this?"},
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "data:image/png;base64,iVBORw0KGgo="}},
+			},
+		}})
+		require.NoError(t, err)
+		require.Len(t, messages[0].Content, 2)
+		require.Equal(t, "What is this?", *messages[0].Content[0].Text)
+		require.Equal(t, &imageBlock{Format: "png", Source: imageSource{Bytes: "iVBORw0KGgo="}}, messages[0].Content[1].Image)
+	})
+
+	t.Run("rejects remote images", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := convertMessages([]providers.Message{{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "https://example.com/cat.png"}},
+			},
+		}})
+		require.ErrorContains(t, err, "data URLs")
+	})
+
+	t.Run("rejects unsupported image types", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := convertMessages([]providers.Message{{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "data:image/bmp;base64,Qk0="}},
+			},
+		}})
+		require.ErrorContains(t, err, "image/bmp")
+	})
+}
+
+func TestConvertParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets inference configuration", func(t *testing.T) {
+		t.Parallel()
+
+		maxTokens := 100
+		temperature := 0.5
+		topP := 0.9
+		req, err := convertParams(providers.CompletionParams{
+			Model:       testModel,
+			Messages:    testutil.SimpleMessages(),
+			MaxTokens:   &maxTokens,
+			Stop:        []string{"END"},
+			Temperature: &temperature,
+			TopP:        &topP,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &inferenceConfig{
+			MaxTokens:     &maxTokens,
+			StopSequences: []string{"END"},
+			Temperature:   &temperature,
+			TopP:          &topP,
+		}, req.InferenceConfig)
+	})
+
+	t.Run("converts tools and tool choice", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name   string
+			choice any
+			want   *toolChoice
+		}{
+			{name: "unset", choice: nil, want: nil},
+			{name: "auto", choice: "auto", want: &toolChoice{Auto: &autoToolChoice{}}},
+			{name: "required", choice: "required", want: &toolChoice{Any: &anyToolChoice{}}},
+			{
+				name:   "named function",
+				choice: providers.ToolChoice{Type: "function", Function: &providers.ToolChoiceFunction{Name: "get_weather"}},
+				want:   &toolChoice{Tool: &specificToolChoice{Name: "get_weather"}},
+			},
+		}
+
+		for _, tc := range tests {
+			req, err := convertParams(providers.CompletionParams{
+				Model:      testModel,
+				Messages:   testutil.SimpleMessages(),
+				Tools:      []providers.Tool{testutil.WeatherTool()},
+				ToolChoice: tc.choice,
+			})
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.want, req.ToolConfig.ToolChoice, tc.name)
+			require.Equal(t, "get_weather", req.ToolConfig.Tools[0].ToolSpec.Name, tc.name)
+			require.Equal(t, "object", req.ToolConfig.Tools[0].ToolSpec.InputSchema.JSON["type"], tc.name)
+		}
+	})
+
+	t.Run("rejects tool choice none", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := convertParams(providers.CompletionParams{
+			Model:      testModel,
+			Messages:   testutil.SimpleMessages(),
+			Tools:      []providers.Tool{testutil.WeatherTool()},
+			ToolChoice: "none",
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+
+	t.Run("enables thinking for Anthropic models", func(t *testing.T) {
+		t.Parallel()
+
+		maxTokens := 1000
+		req, err := convertParams(providers.CompletionParams{
+			Model:           "us." + testModel,
+			Messages:        testutil.SimpleMessages(),
+			MaxTokens:       &maxTokens,
+			ReasoningEffort: providers.ReasoningEffortMedium,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"thinking": map[string]any{"type": "enabled", "budget_tokens": 4096},
+		}, req.AdditionalModelRequestFields)
+		require.Equal(t, 8192, *req.InferenceConfig.MaxTokens)
+	})
+
+	t.Run("rejects reasoning effort for other models", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := convertParams(providers.CompletionParams{
+			Model:           "amazon.nova-lite-v1:0",
+			Messages:        testutil.SimpleMessages(),
+			ReasoningEffort: providers.ReasoningEffortHigh,
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+		req, err := convertParams(providers.CompletionParams{
+			Model:           "amazon.nova-lite-v1:0",
+			Messages:        testutil.SimpleMessages(),
+			ReasoningEffort: providers.ReasoningEffortNone,
+		})
+		require.NoError(t, err)
+		require.Nil(t, req.AdditionalModelRequestFields)
+	})
+
+	t.Run("rejects unsupported parameters", func(t *testing.T) {
+		t.Parallel()
+
+		seed := 42
+		topLogprobs := 2
+		for _, params := range []providers.CompletionParams{
+			{Logprobs: true},
+			{TopLogprobs: &topLogprobs},
+			{ResponseFormat: &providers.ResponseFormat{Type: "json_object"}},
+			{Seed: &seed},
+			{Modalities: []string{providers.ModalityAudio}},
+		} {
+			params.Model = testModel
+			params.Messages = testutil.SimpleMessages()
+
+			_, err := convertParams(params)
+			require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+		}
+
+		_, err := convertParams(providers.CompletionParams{
+			Model: testModel,
+			Messages: []providers.Message{{
+				Role:    providers.RoleUser,
+				Content: []providers.ContentPart{{Type: "file", File: &providers.File{Data: "JVBERi0="}}},
+			}},
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+		_, err = convertParams(providers.CompletionParams{
+			Model: testModel,
+			Messages: []providers.Message{{
+				Role:    providers.RoleUser,
+				Content: []providers.ContentPart{{Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg=="}}},
+			}},
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+
+	t.Run("requires a model", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := convertParams(providers.CompletionParams{Messages: testutil.SimpleMessages()})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestApplyExtra(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps known keys", func(t *testing.T) {
+		t.Parallel()
+
+		req := &converseRequest{}
+		err := applyExtra(req, map[string]any{
+			"additional_model_request_fields": map[string]any{"anthropic_beta": []string{"beta-1"}},
+			"guardrail_config":                map[string]any{"guardrailIdentifier": "gr-1", "guardrailVersion": "1"},
+			"top_k":                           40,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"anthropic_beta": []any{"beta-1"}, "top_k": 40}, req.AdditionalModelRequestFields)
+		require.Equal(t, map[string]any{"guardrailIdentifier": "gr-1", "guardrailVersion": "1"}, req.GuardrailConfig)
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		t.Parallel()
+
+		err := applyExtra(&converseRequest{}, map[string]any{"frequency_penalty": 0.5})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		err := applyExtra(&converseRequest{}, map[string]any{"top_k": "forty"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestConvertStopReason(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"end_turn":                      providers.FinishReasonStop,
+		"stop_sequence":                 providers.FinishReasonStop,
+		"max_tokens":                    providers.FinishReasonLength,
+		"model_context_window_exceeded": providers.FinishReasonLength,
+		"tool_use":                      providers.FinishReasonToolCalls,
+		"guardrail_intervened":          providers.FinishReasonContentFilter,
+		"content_filtered":              providers.FinishReasonContentFilter,
+		"":                              "",
+	}
+
+	for reason, want := range tests {
+		require.Equal(t, want, convertStopReason(reason), reason)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends a signed request and converts the response", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, testModelPath+"/converse", r.URL.EscapedPath())
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, []any{map[string]any{"text": "Be brief."}}, req["system"])
+			require.Equal(t, []any{
+				map[string]any{"role": "user", "content": []any{map[string]any{"text": "Hello"}}},
+			}, req["messages"])
+			require.Equal(t, map[string]any{"temperature": 0.2}, req["inferenceConfig"])
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"output": map[string]any{"message": map[string]any{
+					"role": "assistant",
+					"content": []any{
+						map[string]any{"reasoningContent": map[string]any{
+							"reasoningText": map[string]any{"text": "Considering a greeting.", "signature": "sig"},
+						}},
+						map[string]any{"text": "Hello"},
+						map[string]any{"text": " there!"},
+					},
+				}},
+				"stopReason": "end_turn",
+				"usage":      map[string]any{"inputTokens": 5, "outputTokens": 3, "totalTokens": 8},
+			})
+		})
+
+		temperature := 0.2
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model: testModel,
+			Messages: []providers.Message{
+				{Role: providers.RoleSystem, Content: "Be brief."},
+				{Role: providers.RoleUser, Content: "Hello"},
+			},
+			Temperature: &temperature,
+		})
+		require.NoError(t, err)
+
+		require.True(t, strings.HasPrefix(resp.ID, "chatcmpl-"))
+		require.Equal(t, "chat.completion", resp.Object)
+		require.Equal(t, testModel, resp.Model)
+		require.Len(t, resp.Choices, 1)
+		require.Equal(t, providers.RoleAssistant, resp.Choices[0].Message.Role)
+		require.Equal(t, "Hello there!", resp.Choices[0].Message.Content)
+		require.Equal(t, "Considering a greeting.", resp.Choices[0].Message.Reasoning.Content)
+		require.Equal(t, providers.FinishReasonStop, resp.Choices[0].FinishReason)
+		require.Equal(t, &providers.Usage{PromptTokens: 5, CompletionTokens: 3, TotalTokens: 8}, resp.Usage)
+	})
+
+	t.Run("converts tool calls", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			var req converseRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.ToolConfig.Tools, 1)
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"output": map[string]any{"message": map[string]any{
+					"role": "assistant",
+					"content": []any{map[string]any{"toolUse": map[string]any{
+						"toolUseId": "tooluse_1",
+						"name":      "get_weather",
+						"input":     map[string]any{"location": "Paris"},
+					}}},
+				}},
+				"stopReason": "tool_use",
+			})
+		})
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    testModel,
+			Messages: testutil.ToolCallMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool()},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, providers.FinishReasonToolCalls, resp.Choices[0].FinishReason)
+		require.Len(t, resp.Choices[0].Message.ToolCalls, 1)
+		toolCall := resp.Choices[0].Message.ToolCalls[0]
+		require.Equal(t, "tooluse_1", toolCall.ID)
+		require.Equal(t, "function", toolCall.Type)
+		require.Equal(t, "get_weather", toolCall.Function.Name)
+		require.JSONEq(t, `{"location":"Paris"}`, toolCall.Function.Arguments)
+	})
+
+	t.Run("authenticates with a Bedrock API key", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer bedrock-api-key", r.Header.Get(headerAuthorization))
+			require.Empty(t, r.Header.Get(headerAmzDate))
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"output":     map[string]any{"message": map[string]any{"role": "assistant", "content": []any{}}},
+				"stopReason": "end_turn",
+			})
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := New(config.WithAPIKey("bedrock-api-key"), config.WithBaseURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    testModel,
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	events := [][]byte{
+		encodeEvent(eventMessageStart, `{"role":"assistant"}`),
+		encodeEvent(eventContentBlockDelta, `{"contentBlockIndex":0,"delta":{"reasoningContent":{"text":"Thinking..."}}}`),
+		encodeEvent(eventContentBlockDelta, `{"contentBlockIndex":0,"delta":{"reasoningContent":{"signature":"sig"}}}`),
+		encodeEvent(eventContentBlockDelta, `{"contentBlockIndex":1,"delta":{"text":"Hello"}}`),
+		encodeEvent("contentBlockStop", `{"contentBlockIndex":1}`),
+		encodeEvent(eventContentBlockStart,
+			`{"contentBlockIndex":2,"start":{"toolUse":{"toolUseId":"tooluse_1","name":"get_weather"}}}`),
+		encodeEvent(eventContentBlockDelta, `{"contentBlockIndex":2,"delta":{"toolUse":{"input":"{\"location\":"}}}`),
+		encodeEvent(eventContentBlockDelta, `{"contentBlockIndex":2,"delta":{"toolUse":{"input":"\"Paris\"}"}}}`),
+		encodeEvent(eventMessageStop, `{"stopReason":"tool_use"}`),
+		encodeEvent(eventMetadata, `{"usage":{"inputTokens":5,"outputTokens":6,"totalTokens":11},"metrics":{}}`),
+	}
+
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, testModelPath+"/converse-stream", r.URL.EscapedPath())
+		require.Equal(t, "application/vnd.amazon.eventstream", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, event := range events {
+			_, _ = w.Write(event)
+		}
+	})
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    testModel,
+		Messages: testutil.SimpleMessages(),
+		Tools:    []providers.Tool{testutil.WeatherTool()},
+	})
+
+	var received []providers.ChatCompletionChunk
+	acc := providers.NewAccumulator()
+	for chunk := range chunks {
+		received = append(received, chunk)
+		acc.Add(chunk)
+	}
+	require.NoError(t, <-errs)
+
+	require.Len(t, received, 8)
+	require.Equal(t, "chat.completion.chunk", received[0].Object)
+	require.Equal(t, testModel, received[0].Model)
+	require.Equal(t, providers.RoleAssistant, received[0].Choices[0].Delta.Role)
+	require.Nil(t, received[0].Usage, "usage is only reported on the final chunk")
+	require.Empty(t, received[7].Choices)
+
+	completion := acc.ChatCompletion()
+	require.Equal(t, "Hello", completion.Choices[0].Message.Content)
+	require.Equal(t, "Thinking...", completion.Choices[0].Message.Reasoning.Content)
+	require.Equal(t, providers.FinishReasonToolCalls, completion.Choices[0].FinishReason)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, "tooluse_1", completion.Choices[0].Message.ToolCalls[0].ID)
+	require.Equal(t, "get_weather", completion.Choices[0].Message.ToolCalls[0].Function.Name)
+	require.Equal(t, `{"location":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, 11, completion.Usage.TotalTokens)
+}
+
+func TestCompletionStreamErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		message      []byte
+		wantSentinel error
+	}{
+		{
+			name: "throttling exception becomes RateLimitError",
+			message: encodeEventStreamMessage(map[string]string{
+				eventHeaderMessageType:   messageTypeException,
+				eventHeaderExceptionType: "throttlingException",
+			}, []byte(`{"message":"Too many requests, please wait before trying again."}`)),
+			wantSentinel: errors.ErrRateLimit,
+		},
+		{
+			name: "validation exception becomes InvalidRequestError",
+			message: encodeEventStreamMessage(map[string]string{
+				eventHeaderMessageType:   messageTypeException,
+				eventHeaderExceptionType: "validationException",
+			}, []byte(`{"message":"Malformed input request."}`)),
+			wantSentinel: errors.ErrInvalidRequest,
+		},
+		{
+			name: "error message becomes ProviderError",
+			message: encodeEventStreamMessage(map[string]string{
+				eventHeaderMessageType:  messageTypeError,
+				eventHeaderErrorCode:    "InternalFailure",
+				eventHeaderErrorMessage: "Something went wrong.",
+			}, nil),
+			wantSentinel: errors.ErrProvider,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+				_, _ = w.Write(encodeEvent(eventMessageStart, `{"role":"assistant"}`))
+				_, _ = w.Write(tc.message)
+			})
+
+			var err error
+			for _, streamErr := range providers.Stream(context.Background(), provider, providers.CompletionParams{
+				Model:    testModel,
+				Messages: testutil.SimpleMessages(),
+			}) {
+				if streamErr != nil {
+					err = streamErr
+				}
+			}
+			require.ErrorIs(t, err, tc.wantSentinel)
+
+			var apiErr *apiError
+			require.ErrorAs(t, err, &apiErr)
+			require.NotEmpty(t, apiErr.Message)
+		})
+	}
+
+	t.Run("corrupted stream becomes ProviderError", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			msg := encodeEvent(eventMessageStart, `{"role":"assistant"}`)
+			msg[len(msg)-1] ^= 0xff
+			_, _ = w.Write(msg)
+		})
+
+		chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+			Model:    testModel,
+			Messages: testutil.SimpleMessages(),
+		})
+		for range chunks {
+			require.Fail(t, "unexpected chunk")
+		}
+		require.ErrorIs(t, <-errs, errors.ErrProvider)
+	})
+}
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "ENABLED", r.Header.Get("X-Amzn-Bedrock-Trace"))
+		require.Equal(t, "123456789012", r.Header.Get("X-Amz-Source-Account"))
+		require.Contains(t, r.Header.Get(headerAuthorization), "x-amz-source-account")
+
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"output":     map[string]any{"message": map[string]any{"role": "assistant", "content": []any{}}},
+			"stopReason": "end_turn",
+		})
+	}, config.WithHeaders(map[string]string{
+		"X-Amzn-Bedrock-Trace": "ENABLED",
+		"X-Amz-Source-Account": "123456789012",
+	}))
+
+	_, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testModel,
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+}
+
+func TestConvertError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		errorType    string
+		body         string
+		wantSentinel error
+	}{
+		{
+			name:         "ThrottlingException becomes RateLimitError",
+			status:       http.StatusTooManyRequests,
+			errorType:    "ThrottlingException:http://internal.amazon.com/coral/com.amazon.bedrock/",
+			body:         `{"message":"Too many requests, please wait before trying again."}`,
+			wantSentinel: errors.ErrRateLimit,
+		},
+		{
+			name:         "ServiceQuotaExceededException becomes RateLimitError",
+			status:       http.StatusBadRequest,
+			errorType:    "ServiceQuotaExceededException",
+			body:         `{"message":"Your request exceeds the service quota for your account."}`,
+			wantSentinel: errors.ErrRateLimit,
+		},
+		{
+			name:   "UnrecognizedClientException becomes AuthenticationError",
+			status: http.StatusForbidden,
+			body: `{"__type":"com.amazon.coral.service#UnrecognizedClientException",` +
+				`"message":"The security token included in the request is invalid."}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+		{
+			name:         "AccessDeniedException becomes AuthenticationError",
+			status:       http.StatusForbidden,
+			errorType:    "AccessDeniedException",
+			body:         `{"message":"You don't have access to the model with the specified model ID."}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+		{
+			name:         "ResourceNotFoundException becomes ModelNotFoundError",
+			status:       http.StatusNotFound,
+			errorType:    "ResourceNotFoundException",
+			body:         `{"message":"Model not found."}`,
+			wantSentinel: errors.ErrModelNotFound,
+		},
+		{
+			name:         "oversized prompt becomes ContextLengthError",
+			status:       http.StatusBadRequest,
+			errorType:    "ValidationException",
+			body:         `{"message":"Input is too long for requested model."}`,
+			wantSentinel: errors.ErrContextLength,
+		},
+		{
+			name:         "other ValidationException becomes InvalidRequestError",
+			status:       http.StatusBadRequest,
+			errorType:    "ValidationException",
+			body:         `{"message":"The provided model identifier is invalid."}`,
+			wantSentinel: errors.ErrInvalidRequest,
+		},
+		{
+			name:         "500 with a non-JSON body becomes ProviderError",
+			status:       http.StatusInternalServerError,
+			body:         "upstream failure",
+			wantSentinel: errors.ErrProvider,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				if tc.errorType != "" {
+					w.Header().Set("X-Amzn-ErrorType", tc.errorType)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.body)
+			})
+
+			_, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    testModel,
+				Messages: testutil.SimpleMessages(),
+			})
+			require.ErrorIs(t, err, tc.wantSentinel)
+
+			var apiErr *apiError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tc.status, apiErr.StatusCode)
+			require.NotEmpty(t, apiErr.Message)
+			require.NotContains(t, apiErr.Type, ":")
+		})
+	}
+
+	t.Run("rate limit errors carry the retry delay", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		err := p.ConvertError(&apiError{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"7"}},
+			Type:       "ThrottlingException",
+		})
+
+		var rateLimitErr *errors.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		require.Equal(t, 7, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("non-API errors become ProviderError", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		require.Nil(t, p.ConvertError(nil))
+		require.ErrorIs(t, p.ConvertError(stderrors.New("connection reset")), errors.ErrProvider)
+	})
+}
+
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "bedrock.(*Provider).CompletionStream"
+
+	preamble := []string{string(encodeEvent(eventMessageStart, `{"role":"assistant"}`))}
+	server := testutil.NewEndlessStreamServer(t, contentTypeEventStream, preamble, func(i int) string {
+		delta := `{"contentBlockIndex":0,"delta":{"text":"` + strconv.Itoa(i) + ` "}}`
+		return string(encodeEvent(eventContentBlockDelta, delta))
+	})
+
+	provider, err := New(
+		WithCredentials(testAccessKeyID, testSecretAccessKey, ""),
+		config.WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: testModel, Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, providers.RoleAssistant, chunk.Choices[0].Delta.Role)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("bedrock") {
+		t.Skip("AWS_ACCESS_KEY_ID not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("bedrock"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestIntegrationCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("bedrock") {
+		t.Skip("AWS_ACCESS_KEY_ID not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("bedrock"),
+		Messages: testutil.SimpleMessages(),
+		Stream:   true,
+	})
+
+	var content strings.Builder
+	for chunk := range chunks {
+		if len(chunk.Choices) > 0 {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+	require.NoError(t, <-errs)
+	require.NotEmpty(t, content.String())
+}
@@ -0,0 +1,160 @@
+package bedrock
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AWS environment variables.
+const (
+	envAccessKeyID           = "AWS_ACCESS_KEY_ID"
+	envConfigFile            = "AWS_CONFIG_FILE"
+	envDefaultRegion         = "AWS_DEFAULT_REGION"
+	envProfile               = "AWS_PROFILE"
+	envRegion                = "AWS_REGION"
+	envSecretAccessKey       = "AWS_SECRET_ACCESS_KEY"
+	envSessionToken          = "AWS_SESSION_TOKEN"
+	envSharedCredentialsFile = "AWS_SHARED_CREDENTIALS_FILE"
+)
+
+// Shared config and credentials file keys.
+const (
+	keyAccessKeyID     = "aws_access_key_id"
+	keyRegion          = "region"
+	keySecretAccessKey = "aws_secret_access_key"
+	keySessionToken    = "aws_session_token"
+)
+
+// Shared file defaults.
+const (
+	defaultProfile        = "default"
+	profileSectionPrefix  = "profile "
+	sharedConfigDir       = ".aws"
+	sharedConfigFile      = "config"
+	sharedCredentialsFile = "credentials"
+)
+
+// credentials are the AWS credentials used to sign requests.
+type credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// profile holds the settings of a profile from the shared config and credentials files.
+type profile struct {
+	credentials credentials
+	region      string
+}
+
+// valid reports whether c holds an access key pair.
+func (c credentials) valid() bool {
+	return c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// loadProfile reads a profile from the shared credentials file, then the shared config file.
+// Settings in the credentials file take precedence. Missing files are skipped.
+func loadProfile(name string) (profile, error) {
+	var p profile
+
+	files := []struct {
+		env     string
+		name    string
+		section string
+	}{
+		{env: envSharedCredentialsFile, name: sharedCredentialsFile, section: name},
+		{env: envConfigFile, name: sharedConfigFile, section: configSection(name)},
+	}
+
+	for _, f := range files {
+		values, err := readINISection(sharedFilePath(f.env, f.name), f.section)
+		if err != nil {
+			return profile{}, err
+		}
+
+		setIfEmpty(&p.credentials.AccessKeyID, values[keyAccessKeyID])
+		setIfEmpty(&p.credentials.SecretAccessKey, values[keySecretAccessKey])
+		setIfEmpty(&p.credentials.SessionToken, values[keySessionToken])
+		setIfEmpty(&p.region, values[keyRegion])
+	}
+
+	return p, nil
+}
+
+// configSection returns the name of a profile's section in the shared config file.
+func configSection(profile string) string {
+	if profile == defaultProfile {
+		return profile
+	}
+
+	return profileSectionPrefix + profile
+}
+
+// readINISection returns the key-value pairs of a section of an INI file.
+// A missing file, or an empty path, has no sections.
+func readINISection(path, section string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading AWS shared file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	values := make(map[string]string)
+	inSection := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			inSection = strings.Join(strings.Fields(line[1:len(line)-1]), " ") == section
+		case inSection:
+			key, value, ok := strings.Cut(line, "=")
+			if ok {
+				values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading AWS shared file: %w", err)
+	}
+
+	return values, nil
+}
+
+// setIfEmpty sets *dst to value if *dst is empty.
+func setIfEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+// sharedFilePath returns the path of a shared config or credentials file,
+// from the environment variable env or the default location under ~/.aws.
+// It returns an empty path if the home directory is unknown.
+func sharedFilePath(env, name string) string {
+	if path := strings.TrimSpace(os.Getenv(env)); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, sharedConfigDir, name)
+}
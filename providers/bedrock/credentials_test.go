@@ -0,0 +1,109 @@
+package bedrock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeSharedFiles writes shared credentials and config files and points the environment at them.
+// Empty contents leave the file missing.
+func writeSharedFiles(t *testing.T, credentialsContent, configContent string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	credentialsPath := filepath.Join(dir, "credentials")
+	configPath := filepath.Join(dir, "config")
+
+	if credentialsContent != "" {
+		require.NoError(t, os.WriteFile(credentialsPath, []byte(credentialsContent), 0o600))
+	}
+	if configContent != "" {
+		require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+	}
+
+	t.Setenv(envSharedCredentialsFile, credentialsPath)
+	t.Setenv(envConfigFile, configPath)
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Run("reads the default profile", func(t *testing.T) {
+		writeSharedFiles(t, `
+# Comment.
+[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = default-secret
+
+[work]
+aws_access_key_id = AKIDWORK
+aws_secret_access_key = work-secret
+`, `
+[default]
+region = eu-west-1
+`)
+
+		p, err := loadProfile(defaultProfile)
+		require.NoError(t, err)
+		require.Equal(t, credentials{AccessKeyID: "AKIDDEFAULT", SecretAccessKey: "default-secret"}, p.credentials)
+		require.Equal(t, "eu-west-1", p.region)
+	})
+
+	t.Run("reads named profiles from the config file", func(t *testing.T) {
+		writeSharedFiles(t, `
+[work]
+aws_access_key_id = AKIDWORK
+aws_secret_access_key = work-secret
+`, `
+[profile   work]
+region = us-west-2
+aws_session_token = work-token
+
+[work]
+region = ignored
+`)
+
+		p, err := loadProfile("work")
+		require.NoError(t, err)
+		require.Equal(t, credentials{
+			AccessKeyID:     "AKIDWORK",
+			SecretAccessKey: "work-secret",
+			SessionToken:    "work-token",
+		}, p.credentials)
+		require.Equal(t, "us-west-2", p.region)
+	})
+
+	t.Run("prefers the credentials file", func(t *testing.T) {
+		writeSharedFiles(t, `
+[default]
+aws_access_key_id = AKIDCREDENTIALS
+aws_secret_access_key = credentials-secret
+`, `
+[default]
+aws_access_key_id = AKIDCONFIG
+aws_secret_access_key = config-secret
+`)
+
+		p, err := loadProfile(defaultProfile)
+		require.NoError(t, err)
+		require.Equal(t, "AKIDCREDENTIALS", p.credentials.AccessKeyID)
+		require.Equal(t, "credentials-secret", p.credentials.SecretAccessKey)
+	})
+
+	t.Run("skips missing files", func(t *testing.T) {
+		writeSharedFiles(t, "", "")
+
+		p, err := loadProfile(defaultProfile)
+		require.NoError(t, err)
+		require.Equal(t, profile{}, p)
+	})
+
+	t.Run("returns an empty profile when the section is missing", func(t *testing.T) {
+		writeSharedFiles(t, "[default]\naws_access_key_id = AKIDDEFAULT\n", "")
+
+		p, err := loadProfile("missing")
+		require.NoError(t, err)
+		require.False(t, p.credentials.valid())
+	})
+}
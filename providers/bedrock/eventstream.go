@@ -0,0 +1,171 @@
+package bedrock
+
+import (
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Event stream framing constants.
+// See https://docs.aws.amazon.com/transcribe/latest/dg/streaming-setting-up.html#streaming-event-stream.
+const (
+	eventStreamMaxMessageSize = 16 << 20
+	eventStreamPreludeSize    = 12
+	eventStreamTrailerSize    = 4
+)
+
+// Event stream header value types.
+const (
+	headerTypeBoolFalse = 1
+	headerTypeBoolTrue  = 0
+	headerTypeByte      = 2
+	headerTypeByteArray = 6
+	headerTypeInt16     = 3
+	headerTypeInt32     = 4
+	headerTypeInt64     = 5
+	headerTypeString    = 7
+	headerTypeTimestamp = 8
+	headerTypeUUID      = 9
+)
+
+// eventStreamMessage is a message of an AWS event stream.
+type eventStreamMessage struct {
+	// Headers holds the message's string-valued headers, e.g. ":event-type".
+	// Headers of other types are skipped.
+	Headers map[string]string
+
+	// Payload is the message body.
+	Payload []byte
+}
+
+// eventStreamReader reads messages in the binary AWS event stream encoding
+// (application/vnd.amazon.eventstream).
+type eventStreamReader struct {
+	err     error
+	message eventStreamMessage
+	r       io.Reader
+}
+
+// newEventStreamReader returns a reader that decodes event stream messages from r.
+func newEventStreamReader(r io.Reader) *eventStreamReader {
+	return &eventStreamReader{r: r}
+}
+
+// Err returns the first error that stopped the reader, or nil at the end of the stream.
+func (r *eventStreamReader) Err() error {
+	return r.err
+}
+
+// Message returns the message read by the last call to Next.
+func (r *eventStreamReader) Message() eventStreamMessage {
+	return r.message
+}
+
+// Next reads the next message. It returns false at the end of the stream or on error.
+func (r *eventStreamReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	message, err := r.read()
+	if err != nil {
+		if !stderrors.Is(err, io.EOF) {
+			r.err = err
+		}
+		return false
+	}
+
+	r.message = message
+	return true
+}
+
+// read reads a single message. It returns io.EOF only when the stream ends between messages.
+func (r *eventStreamReader) read() (eventStreamMessage, error) {
+	prelude := make([]byte, eventStreamPreludeSize)
+	if _, err := io.ReadFull(r.r, prelude); err != nil {
+		if stderrors.Is(err, io.ErrUnexpectedEOF) {
+			return eventStreamMessage{}, fmt.Errorf("reading event stream prelude: %w", err)
+		}
+		return eventStreamMessage{}, err
+	}
+
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return eventStreamMessage{}, fmt.Errorf("event stream prelude checksum mismatch")
+	}
+
+	minLength := uint32(eventStreamPreludeSize + eventStreamTrailerSize)
+	if totalLength < minLength || totalLength > eventStreamMaxMessageSize || headersLength > totalLength-minLength {
+		return eventStreamMessage{}, fmt.Errorf("invalid event stream message length %d", totalLength)
+	}
+
+	rest := make([]byte, totalLength-eventStreamPreludeSize)
+	if _, err := io.ReadFull(r.r, rest); err != nil {
+		return eventStreamMessage{}, fmt.Errorf("reading event stream message: %w", io.ErrUnexpectedEOF)
+	}
+
+	body, trailer := rest[:len(rest)-eventStreamTrailerSize], rest[len(rest)-eventStreamTrailerSize:]
+	checksum := crc32.Update(crc32.ChecksumIEEE(prelude), crc32.IEEETable, body)
+	if checksum != binary.BigEndian.Uint32(trailer) {
+		return eventStreamMessage{}, fmt.Errorf("event stream message checksum mismatch")
+	}
+
+	headers, err := decodeEventStreamHeaders(body[:headersLength])
+	if err != nil {
+		return eventStreamMessage{}, err
+	}
+
+	return eventStreamMessage{Headers: headers, Payload: body[headersLength:]}, nil
+}
+
+// decodeEventStreamHeaders decodes the headers of an event stream message.
+func decodeEventStreamHeaders(b []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for len(b) > 0 {
+		nameLength := int(b[0])
+		if len(b) < 1+nameLength+1 {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		name := string(b[1 : 1+nameLength])
+		valueType := b[1+nameLength]
+		b = b[1+nameLength+1:]
+
+		var size int
+		switch valueType {
+		case headerTypeBoolTrue, headerTypeBoolFalse:
+			size = 0
+		case headerTypeByte:
+			size = 1
+		case headerTypeInt16:
+			size = 2
+		case headerTypeInt32:
+			size = 4
+		case headerTypeInt64, headerTypeTimestamp:
+			size = 8
+		case headerTypeUUID:
+			size = 16
+		case headerTypeByteArray, headerTypeString:
+			if len(b) < 2 {
+				return nil, fmt.Errorf("truncated event stream header %q", name)
+			}
+			size = int(binary.BigEndian.Uint16(b[0:2]))
+			b = b[2:]
+		default:
+			return nil, fmt.Errorf("unknown event stream header type %d", valueType)
+		}
+
+		if len(b) < size {
+			return nil, fmt.Errorf("truncated event stream header %q", name)
+		}
+		if valueType == headerTypeString {
+			headers[name] = string(b[:size])
+		}
+		b = b[size:]
+	}
+
+	return headers, nil
+}
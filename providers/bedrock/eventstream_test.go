@@ -0,0 +1,121 @@
+package bedrock
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encodeEventStreamMessage encodes a message with string-valued headers in the event stream format.
+func encodeEventStreamMessage(headers map[string]string, payload []byte) []byte {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var h bytes.Buffer
+	for _, name := range names {
+		h.WriteByte(byte(len(name)))
+		h.WriteString(name)
+		h.WriteByte(headerTypeString)
+		_ = binary.Write(&h, binary.BigEndian, uint16(len(headers[name])))
+		h.WriteString(headers[name])
+	}
+
+	totalLength := eventStreamPreludeSize + h.Len() + len(payload) + eventStreamTrailerSize
+
+	var msg bytes.Buffer
+	_ = binary.Write(&msg, binary.BigEndian, uint32(totalLength))
+	_ = binary.Write(&msg, binary.BigEndian, uint32(h.Len()))
+	_ = binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(h.Bytes())
+	msg.Write(payload)
+	_ = binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+
+	return msg.Bytes()
+}
+
+// encodeEvent encodes a ConverseStream event message.
+func encodeEvent(eventType, payload string) []byte {
+	return encodeEventStreamMessage(map[string]string{
+		eventHeaderEventType:   eventType,
+		eventHeaderMessageType: "event",
+		":content-type":        contentTypeJSON,
+	}, []byte(payload))
+}
+
+func TestEventStreamReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes messages", func(t *testing.T) {
+		t.Parallel()
+
+		var stream bytes.Buffer
+		stream.Write(encodeEvent(eventMessageStart, `{"role":"assistant"}`))
+		stream.Write(encodeEventStreamMessage(map[string]string{eventHeaderMessageType: "event"}, nil))
+
+		reader := newEventStreamReader(&stream)
+
+		require.True(t, reader.Next())
+		require.Equal(t, eventMessageStart, reader.Message().Headers[eventHeaderEventType])
+		require.Equal(t, "event", reader.Message().Headers[eventHeaderMessageType])
+		require.JSONEq(t, `{"role":"assistant"}`, string(reader.Message().Payload))
+
+		require.True(t, reader.Next())
+		require.Empty(t, reader.Message().Payload)
+
+		require.False(t, reader.Next())
+		require.NoError(t, reader.Err())
+	})
+
+	t.Run("skips headers that are not strings", func(t *testing.T) {
+		t.Parallel()
+
+		// A bool header, followed by an int32 header, followed by a string header.
+		headers := []byte{4, 'f', 'l', 'a', 'g', headerTypeBoolTrue}
+		headers = append(headers, 5, 'c', 'o', 'u', 'n', 't', headerTypeInt32, 0, 0, 0, 7)
+		headers = append(headers, 4, 'n', 'a', 'm', 'e', headerTypeString, 0, 2, 'h', 'i')
+
+		decoded, err := decodeEventStreamHeaders(headers)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"name": "hi"}, decoded)
+	})
+
+	t.Run("rejects a corrupted message", func(t *testing.T) {
+		t.Parallel()
+
+		msg := encodeEvent(eventMessageStart, `{"role":"assistant"}`)
+		msg[len(msg)-6] ^= 0xff
+
+		reader := newEventStreamReader(bytes.NewReader(msg))
+		require.False(t, reader.Next())
+		require.ErrorContains(t, reader.Err(), "checksum mismatch")
+	})
+
+	t.Run("rejects a corrupted prelude", func(t *testing.T) {
+		t.Parallel()
+
+		msg := encodeEvent(eventMessageStart, `{"role":"assistant"}`)
+		msg[3] ^= 0xff
+
+		reader := newEventStreamReader(bytes.NewReader(msg))
+		require.False(t, reader.Next())
+		require.ErrorContains(t, reader.Err(), "prelude checksum mismatch")
+	})
+
+	t.Run("rejects a truncated message", func(t *testing.T) {
+		t.Parallel()
+
+		msg := encodeEvent(eventMessageStart, `{"role":"assistant"}`)
+
+		reader := newEventStreamReader(bytes.NewReader(msg[:len(msg)-2]))
+		require.False(t, reader.Next())
+		require.ErrorIs(t, reader.Err(), io.ErrUnexpectedEOF)
+	})
+}
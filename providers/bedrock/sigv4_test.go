@@ -0,0 +1,121 @@
+package bedrock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Credentials and signing time of the AWS Signature Version 4 test suite.
+const (
+	testSuiteAccessKeyID     = "AKIDEXAMPLE"
+	testSuiteSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+var testSuiteTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches the AWS test suite", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+		require.NoError(t, err)
+
+		s := &signer{
+			credentials: credentials{AccessKeyID: testSuiteAccessKeyID, SecretAccessKey: testSuiteSecretAccessKey},
+			region:      "us-east-1",
+			service:     "service",
+		}
+		s.sign(req, nil, testSuiteTime)
+
+		require.Equal(t, "20150830T123600Z", req.Header.Get(headerAmzDate))
+		require.Equal(t,
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=host;x-amz-date, "+
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+			req.Header.Get(headerAuthorization),
+		)
+	})
+
+	t.Run("matches the AWS documentation example", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+		require.NoError(t, err)
+		req.Header.Set(headerContentType, "application/x-www-form-urlencoded; charset=utf-8")
+
+		s := &signer{
+			credentials: credentials{AccessKeyID: testSuiteAccessKeyID, SecretAccessKey: testSuiteSecretAccessKey},
+			region:      "us-east-1",
+			service:     "iam",
+		}
+		s.sign(req, nil, testSuiteTime)
+
+		require.Equal(t,
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+				"SignedHeaders=content-type;host;x-amz-date, "+
+				"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+			req.Header.Get(headerAuthorization),
+		)
+	})
+
+	t.Run("signs the session token", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/", nil)
+		require.NoError(t, err)
+
+		s := &signer{
+			credentials: credentials{
+				AccessKeyID:     testSuiteAccessKeyID,
+				SecretAccessKey: testSuiteSecretAccessKey,
+				SessionToken:    "session-token",
+			},
+			region:  "us-east-1",
+			service: "service",
+		}
+		s.sign(req, []byte("{}"), testSuiteTime)
+
+		require.Equal(t, "session-token", req.Header.Get(headerAmzSecurityToken))
+		require.Contains(t, req.Header.Get(headerAuthorization), "SignedHeaders=host;x-amz-date;x-amz-security-token,")
+	})
+}
+
+func TestURIEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		encodeSlash bool
+		want        string
+	}{
+		{name: "unreserved characters", input: "AZaz09-._~", want: "AZaz09-._~"},
+		{name: "keeps slashes in paths", input: "/model/a b", want: "/model/a%20b"},
+		{name: "encodes slashes in segments", input: "a/b", encodeSlash: true, want: "a%2Fb"},
+		{name: "re-encodes escapes", input: "/model/a%3Ab", want: "/model/a%253Ab"},
+		{name: "encodes colons", input: "anthropic.claude-v2:1", encodeSlash: true, want: "anthropic.claude-v2%3A1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, uriEncode(tc.input, tc.encodeSlash))
+		})
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/?b=2&a=1&a=0&c=x%20y", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "a=0&a=1&b=2&c=x%20y", canonicalQuery(req.URL.Query()))
+	require.Empty(t, canonicalQuery(nil))
+}
@@ -0,0 +1,231 @@
+package bedrock
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// apiError is an error response from the Bedrock Runtime API, or an exception reported in a stream.
+type apiError struct {
+	// Header holds the response headers, which may carry retry hints.
+	Header http.Header
+
+	// Message is the error message returned by the API.
+	Message string
+
+	// StatusCode is the HTTP status code. It is zero for exceptions reported in a stream.
+	StatusCode int
+
+	// Type is the exception type, e.g. "ThrottlingException".
+	Type string
+}
+
+// Error implements the error interface.
+func (e *apiError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("bedrock API error (%s): %s", e.Type, e.Message)
+	}
+
+	if e.Type == "" {
+		return fmt.Sprintf("bedrock API error (%d): %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("bedrock API error (%d %s): %s", e.StatusCode, e.Type, e.Message)
+}
+
+// anyToolChoice forces the model to call one of the tools.
+type anyToolChoice struct{}
+
+// autoToolChoice lets the model decide whether to call a tool.
+type autoToolChoice struct{}
+
+// contentBlock is a piece of message content. Exactly one of its fields is set.
+type contentBlock struct {
+	Image            *imageBlock       `json:"image,omitempty"`
+	ReasoningContent *reasoningContent `json:"reasoningContent,omitempty"`
+	Text             *string           `json:"text,omitempty"`
+	ToolResult       *toolResultBlock  `json:"toolResult,omitempty"`
+	ToolUse          *toolUseBlock     `json:"toolUse,omitempty"`
+}
+
+// contentBlockDelta is the incremental content of a contentBlockDelta stream event.
+type contentBlockDelta struct {
+	ReasoningContent *reasoningDelta `json:"reasoningContent,omitempty"`
+	Text             string          `json:"text,omitempty"`
+	ToolUse          *toolUseDelta   `json:"toolUse,omitempty"`
+}
+
+// contentBlockDeltaEvent is the payload of a contentBlockDelta stream event.
+type contentBlockDeltaEvent struct {
+	ContentBlockIndex int               `json:"contentBlockIndex"`
+	Delta             contentBlockDelta `json:"delta"`
+}
+
+// contentBlockStart is the start of a content block.
+type contentBlockStart struct {
+	ToolUse *toolUseStart `json:"toolUse,omitempty"`
+}
+
+// contentBlockStartEvent is the payload of a contentBlockStart stream event.
+type contentBlockStartEvent struct {
+	ContentBlockIndex int               `json:"contentBlockIndex"`
+	Start             contentBlockStart `json:"start"`
+}
+
+// converseOutput is the output of a Converse request.
+type converseOutput struct {
+	Message message `json:"message"`
+}
+
+// converseRequest is the request body of the Converse and ConverseStream operations.
+type converseRequest struct {
+	AdditionalModelRequestFields map[string]any   `json:"additionalModelRequestFields,omitempty"`
+	GuardrailConfig              map[string]any   `json:"guardrailConfig,omitempty"`
+	InferenceConfig              *inferenceConfig `json:"inferenceConfig,omitempty"`
+	Messages                     []message        `json:"messages"`
+	System                       []systemBlock    `json:"system,omitempty"`
+	ToolConfig                   *toolConfig      `json:"toolConfig,omitempty"`
+}
+
+// converseResponse is the response body of the Converse operation.
+type converseResponse struct {
+	Output     converseOutput `json:"output"`
+	StopReason string         `json:"stopReason"`
+	Usage      *tokenUsage    `json:"usage,omitempty"`
+}
+
+// errorResponse is the body of an error response, or the payload of a stream exception.
+type errorResponse struct {
+	Message string `json:"message"`
+	Type    string `json:"__type"` //nolint:tagliatelle // AWS JSON protocol field.
+}
+
+// imageBlock is an image sent inline.
+type imageBlock struct {
+	Format string      `json:"format"`
+	Source imageSource `json:"source"`
+}
+
+// imageSource holds the base64-encoded bytes of an image.
+type imageSource struct {
+	Bytes string `json:"bytes"`
+}
+
+// inferenceConfig holds the inference parameters of a request.
+type inferenceConfig struct {
+	MaxTokens     *int     `json:"maxTokens,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+}
+
+// message is a single turn of a conversation.
+type message struct {
+	Content []contentBlock `json:"content"`
+	Role    string         `json:"role"`
+}
+
+// messageStopEvent is the payload of a messageStop stream event.
+type messageStopEvent struct {
+	StopReason string `json:"stopReason"`
+}
+
+// metadataEvent is the payload of a metadata stream event.
+type metadataEvent struct {
+	Usage *tokenUsage `json:"usage,omitempty"`
+}
+
+// reasoningContent is the model's reasoning.
+type reasoningContent struct {
+	ReasoningText   *reasoningText `json:"reasoningText,omitempty"`
+	RedactedContent string         `json:"redactedContent,omitempty"`
+}
+
+// reasoningDelta is incremental reasoning content.
+type reasoningDelta struct {
+	RedactedContent string `json:"redactedContent,omitempty"`
+	Signature       string `json:"signature,omitempty"`
+	Text            string `json:"text,omitempty"`
+}
+
+// reasoningText is the text of the model's reasoning.
+type reasoningText struct {
+	Signature string `json:"signature,omitempty"`
+	Text      string `json:"text"`
+}
+
+// specificToolChoice forces the model to call the named tool.
+type specificToolChoice struct {
+	Name string `json:"name"`
+}
+
+// systemBlock is a system prompt.
+type systemBlock struct {
+	Text string `json:"text"`
+}
+
+// tokenUsage reports token usage.
+type tokenUsage struct {
+	InputTokens  int `json:"inputTokens"`
+	OutputTokens int `json:"outputTokens"`
+	TotalTokens  int `json:"totalTokens"`
+}
+
+// tool is a tool the model may call.
+type tool struct {
+	ToolSpec toolSpec `json:"toolSpec"`
+}
+
+// toolChoice controls how the model calls tools. Exactly one of its fields is set.
+type toolChoice struct {
+	Any  *anyToolChoice      `json:"any,omitempty"`
+	Auto *autoToolChoice     `json:"auto,omitempty"`
+	Tool *specificToolChoice `json:"tool,omitempty"`
+}
+
+// toolConfig configures tool use.
+type toolConfig struct {
+	ToolChoice *toolChoice `json:"toolChoice,omitempty"`
+	Tools      []tool      `json:"tools"`
+}
+
+// toolInputSchema is the JSON Schema of a tool's input.
+type toolInputSchema struct {
+	JSON map[string]any `json:"json"`
+}
+
+// toolResultBlock is the result of a tool call.
+type toolResultBlock struct {
+	Content   []toolResultContent `json:"content"`
+	ToolUseID string              `json:"toolUseId"`
+}
+
+// toolResultContent is a piece of a tool result.
+type toolResultContent struct {
+	Text string `json:"text"`
+}
+
+// toolSpec describes a tool.
+type toolSpec struct {
+	Description string          `json:"description,omitempty"`
+	InputSchema toolInputSchema `json:"inputSchema"`
+	Name        string          `json:"name"`
+}
+
+// toolUseBlock is a tool call made by the model.
+type toolUseBlock struct {
+	Input     any    `json:"input"`
+	Name      string `json:"name"`
+	ToolUseID string `json:"toolUseId"`
+}
+
+// toolUseDelta is an incremental part of a tool call's JSON input.
+type toolUseDelta struct {
+	Input string `json:"input"`
+}
+
+// toolUseStart is the start of a tool call.
+type toolUseStart struct {
+	Name      string `json:"name"`
+	ToolUseID string `json:"toolUseId"`
+}
@@ -0,0 +1,110 @@
+// Package cerebras provides a Cerebras provider implementation for any-llm.
+//
+// Cerebras serves open models through an OpenAI-compatible API, so the provider is built on
+// openai.CompatibleProvider and rejects the OpenAI parameters Cerebras does not support.
+package cerebras
+
+import (
+	openaisdk "github.com/openai/openai-go"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.cerebras.ai/v1"
+	envAPIKey      = "CEREBRAS_API_KEY"
+	providerName   = "cerebras"
+)
+
+// Cerebras API constants.
+const (
+	reasoningField = "reasoning"
+)
+
+// Parameters Cerebras does not support.
+const (
+	paramFrequencyPenalty  = "frequency_penalty"
+	paramLogitBias         = "logit_bias"
+	paramParallelToolCalls = "parallel_tool_calls"
+	paramPresencePenalty   = "presence_penalty"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Cerebras.
+// It embeds openai.CompatibleProvider since Cerebras exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a new Cerebras provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   cerebrasCapabilities(),
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// cerebrasCapabilities returns the capabilities for the Cerebras provider.
+func cerebrasCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            false,
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // GPT-OSS and Qwen 3 models.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  false,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// prepareRequest rejects the OpenAI parameters Cerebras does not support, which it
+// would otherwise answer with a bad request error.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, _ providers.CompletionParams) error {
+	switch {
+	case req.FrequencyPenalty.Valid():
+		return errors.NewUnsupportedParamError(providerName, paramFrequencyPenalty)
+	case req.LogitBias != nil:
+		return errors.NewUnsupportedParamError(providerName, paramLogitBias)
+	case req.ParallelToolCalls.Valid():
+		return errors.NewUnsupportedParamError(providerName, paramParallelToolCalls)
+	case req.PresencePenalty.Valid():
+		return errors.NewUnsupportedParamError(providerName, paramPresencePenalty)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package cerebras
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "cerebras", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("CEREBRAS_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("CEREBRAS_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "cerebras", missingKeyErr.Provider)
+		require.Equal(t, "CEREBRAS_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends the request to Cerebras and reads reasoning", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/chat/completions", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"id": "chatcmpl-1",
+				"object": "chat.completion",
+				"created": 1700000000,
+				"model": "gpt-oss-120b",
+				"choices": [{
+					"index": 0,
+					"message": {"role": "assistant", "content": "4", "reasoning": "2 plus 2 is 4."},
+					"finish_reason": "stop"
+				}],
+				"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+			}`))
+		}, config.WithAPIKey(testAPIKey))
+
+		maxTokens := 100
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:     "gpt-oss-120b",
+			Messages:  testutil.SimpleMessages(),
+			MaxTokens: &maxTokens,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, float64(100), body["max_completion_tokens"])
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	parallel := true
+	tests := map[string]struct {
+		params    providers.CompletionParams
+		wantParam string
+	}{
+		"frequency_penalty": {
+			params:    providers.CompletionParams{Extra: map[string]any{"frequency_penalty": 0.5}},
+			wantParam: "frequency_penalty",
+		},
+		"logit_bias": {
+			params:    providers.CompletionParams{Extra: map[string]any{"logit_bias": map[string]int{"1": 10}}},
+			wantParam: "logit_bias",
+		},
+		"parallel_tool_calls": {
+			params: providers.CompletionParams{
+				Tools:             []providers.Tool{testutil.WeatherTool()},
+				ParallelToolCalls: &parallel,
+			},
+			wantParam: "parallel_tool_calls",
+		},
+		"presence_penalty": {
+			params:    providers.CompletionParams{Extra: map[string]any{"presence_penalty": 0.5}},
+			wantParam: "presence_penalty",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run("rejects "+name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(config.WithAPIKey(testAPIKey))
+			require.NoError(t, err)
+
+			params := tc.params
+			params.Model = "llama3.1-8b"
+			params.Messages = testutil.SimpleMessages()
+
+			_, err = provider.Completion(context.Background(), params)
+
+			var paramErr *errors.UnsupportedParamError
+			require.ErrorAs(t, err, &paramErr)
+			require.Equal(t, tc.wantParam, paramErr.Param)
+		})
+	}
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("cerebras") {
+		t.Skip("CEREBRAS_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("cerebras"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
@@ -0,0 +1,1051 @@
+// Package cohere provides a Cohere provider implementation for any-llm.
+//
+// The provider calls the Cohere v2 REST API (api.cohere.com) directly. Besides chat
+// completions and embeddings it implements providers.RerankProvider, and exposes the
+// full embed API, including quantized embedding types, through Provider.Embed.
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/internal/sse"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// Provider configuration constants.
+const (
+	configInputType  = "cohere_input_type"
+	defaultBaseURL   = "https://api.cohere.com"
+	defaultInputType = InputTypeSearchDocument
+	envAPIKey        = "COHERE_API_KEY"
+	providerName     = "cohere"
+)
+
+// Cohere REST API constants.
+const (
+	authorizationPrefix = "Bearer "
+	contentTypeJSON     = "application/json"
+	headerAuthorization = "Authorization"
+	headerContentType   = "Content-Type"
+	listModelsPageSize  = 1000
+	maxErrorBodySize    = 64 << 10
+	ownerCohere         = "cohere"
+	pathChat            = "v2/chat"
+	pathEmbed           = "v2/embed"
+	pathModels          = "v1/models"
+	pathRerank          = "v2/rerank"
+	statusInvalidToken  = 498
+)
+
+// Embedding types.
+const (
+	EmbeddingTypeBase64  = "base64"
+	EmbeddingTypeBinary  = "binary"
+	EmbeddingTypeFloat   = "float"
+	EmbeddingTypeInt8    = "int8"
+	EmbeddingTypeUbinary = "ubinary"
+	EmbeddingTypeUint8   = "uint8"
+)
+
+// Embedding input types.
+const (
+	InputTypeClassification = "classification"
+	InputTypeClustering     = "clustering"
+	InputTypeImage          = "image"
+	InputTypeSearchDocument = "search_document"
+	InputTypeSearchQuery    = "search_query"
+)
+
+// Cohere finish reasons.
+const (
+	finishReasonMaxTokens = "MAX_TOKENS"
+	finishReasonToolCall  = "TOOL_CALL"
+)
+
+// Cohere stream event types. Events not listed here carry nothing to forward.
+const (
+	eventCitationStart = "citation-start"
+	eventContentDelta  = "content-delta"
+	eventContentStart  = "content-start"
+	eventMessageEnd    = "message-end"
+	eventMessageStart  = "message-start"
+	eventToolCallDelta = "tool-call-delta"
+	eventToolCallStart = "tool-call-start"
+	eventToolPlanDelta = "tool-plan-delta"
+)
+
+// Cohere tool choices.
+const (
+	toolChoiceNone     = "NONE"
+	toolChoiceRequired = "REQUIRED"
+)
+
+// Extra parameters for the v2 chat request; top_k is sent as k.
+// chatRequest has no passthrough for unknown fields, so other keys in CompletionParams.Extra are rejected.
+const (
+	extraCitationOptions  = "citation_options"
+	extraFrequencyPenalty = "frequency_penalty"
+	extraPresencePenalty  = "presence_penalty"
+	extraSafetyMode       = "safety_mode"
+	extraStrictTools      = "strict_tools"
+	extraTopK             = "top_k"
+)
+
+// Completion parameters Cohere does not support.
+const (
+	paramLogprobs    = "logprobs"
+	paramToolChoice  = "tool_choice"
+	paramTopLogprobs = "top_logprobs"
+)
+
+// Cohere error message patterns.
+const (
+	errorPatternTooManyTokens = "too many tokens"
+)
+
+// Thinking configuration constants.
+const (
+	thinkingBudgetHigh   = 24576
+	thinkingBudgetLow    = 1024
+	thinkingBudgetMedium = 8192
+	thinkingTypeDisabled = "disabled"
+	thinkingTypeEnabled  = "enabled"
+)
+
+// Tool and response format constants.
+const (
+	responseFormatJSONObject = "json_object"
+	responseFormatJSONSchema = "json_schema"
+	toolTypeFunction         = "function"
+)
+
+// Object type constants.
+const (
+	objectChatCompletion      = "chat.completion"
+	objectChatCompletionChunk = "chat.completion.chunk"
+	objectEmbedding           = "embedding"
+	objectList                = "list"
+	objectModel               = "model"
+)
+
+// Content part constants.
+const (
+	contentTypeFile       = "file"
+	contentTypeImageURL   = "image_url"
+	contentTypeInputAudio = "input_audio"
+	contentTypeText       = "text"
+	contentTypeThinking   = "thinking"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+	_ providers.RerankProvider     = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Cohere.
+type Provider struct {
+	apiKey    string
+	baseURL   string
+	client    *http.Client
+	config    *config.Config
+	inputType string
+}
+
+// streamState tracks accumulated state during streaming.
+// Note: Only accessed from a single goroutine, so no synchronization needed.
+type streamState struct {
+	id       string
+	model    string
+	created  int64
+	sentRole bool
+}
+
+// New creates a new Cohere provider.
+func New(opts ...config.Option) (*Provider, error) {
+	cfg, err := config.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	apiKey := cfg.ResolveAPIKey(envAPIKey)
+	if apiKey == "" {
+		return nil, errors.NewMissingAPIKeyError(providerName, envAPIKey)
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	inputType := defaultInputType
+	if v, ok := cfg.ExtraValue(configInputType); ok {
+		inputType, _ = v.(string)
+	}
+
+	return &Provider{
+		apiKey:    apiKey,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		client:    cfg.HTTPClient(),
+		config:    cfg,
+		inputType: inputType,
+	}, nil
+}
+
+// WithEmbeddingInputType sets the input type sent by Embedding, e.g. InputTypeSearchQuery.
+// It defaults to InputTypeSearchDocument. Use Embed to choose the input type per request.
+func WithEmbeddingInputType(inputType string) config.Option {
+	return func(c *config.Config) error {
+		inputType = strings.TrimSpace(inputType)
+		if inputType == "" {
+			return fmt.Errorf("embedding input type cannot be empty")
+		}
+
+		return config.WithExtra(configInputType, inputType)(c)
+	}
+}
+
+// Capabilities returns the provider's capabilities.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionStreaming:        true,
+		CompletionReasoning:        true,
+		CompletionImage:            true, // Vision models only.
+		CompletionPDF:              false,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     true,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// Completion performs a chat completion request.
+func (p *Provider) Completion(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.ChatCompletion, error) {
+	req, err := convertParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp chatResponse
+	if err := p.do(ctx, http.MethodPost, pathChat, req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertResponse(&resp, params.Model), nil
+}
+
+// CompletionStream performs a streaming chat completion request.
+func (p *Provider) CompletionStream(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		req, err := convertParams(params)
+		if err != nil {
+			errs <- err
+			return
+		}
+		req.Stream = true
+
+		body, err := p.send(ctx, http.MethodPost, pathChat, req)
+		if err != nil {
+			errs <- p.ConvertError(err)
+			return
+		}
+		defer func() { _ = body.Close() }()
+
+		state := newStreamState(params.Model)
+		reader := sse.NewReader(body)
+
+		for reader.Next() {
+			var event streamEvent
+			if err := json.Unmarshal([]byte(reader.Event().Data), &event); err != nil {
+				errs <- errors.NewProviderError(providerName, fmt.Errorf("decoding stream event: %w", err))
+				return
+			}
+
+			chunk, ok := state.handleEvent(&event)
+			if !ok {
+				continue
+			}
+
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := reader.Err(); err != nil {
+			errs <- p.ConvertError(err)
+		}
+	}()
+
+	return chunks, errs
+}
+
+// ConvertError converts a Cohere API error to a unified error type.
+// Implements providers.ErrorConverter.
+func (p *Provider) ConvertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Errors other than API errors (e.g., network errors) are generic provider errors.
+	var apiErr *apiError
+	if !stderrors.As(err, &apiErr) {
+		return errors.NewProviderError(providerName, err)
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		if strings.Contains(strings.ToLower(apiErr.Message), errorPatternTooManyTokens) {
+			return errors.NewContextLengthError(providerName, err)
+		}
+		return errors.NewInvalidRequestError(providerName, err)
+	case http.StatusUnauthorized, http.StatusForbidden, statusInvalidToken:
+		return errors.NewAuthenticationError(providerName, err)
+	case http.StatusNotFound:
+		return errors.NewModelNotFoundError(providerName, err)
+	case http.StatusTooManyRequests:
+		rateLimitErr := errors.NewRateLimitError(providerName, err)
+		rateLimitErr.RetryAfter = errors.RetryAfterFromHeader(apiErr.Header)
+		return rateLimitErr
+	default:
+		providerErr := errors.NewProviderError(providerName, err)
+		providerErr.StatusCode = apiErr.StatusCode
+		return providerErr
+	}
+}
+
+// Embed generates embeddings with the full set of Cohere embed options.
+func (p *Provider) Embed(ctx context.Context, params EmbedParams) (*EmbedResponse, error) {
+	if params.Model == "" {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("model is required"))
+	}
+
+	embeddingTypes := params.EmbeddingTypes
+	if len(embeddingTypes) == 0 {
+		embeddingTypes = []string{EmbeddingTypeFloat}
+	}
+
+	req := embedRequest{
+		EmbeddingTypes:  embeddingTypes,
+		InputType:       params.InputType,
+		Model:           params.Model,
+		OutputDimension: params.OutputDimension,
+		Texts:           params.Texts,
+		Truncate:        params.Truncate,
+	}
+
+	var resp embedResponse
+	if err := p.do(ctx, http.MethodPost, pathEmbed, req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	result := &EmbedResponse{
+		Embeddings: resp.Embeddings,
+		ID:         resp.ID,
+		Model:      params.Model,
+		Texts:      resp.Texts,
+	}
+	if resp.Meta != nil && resp.Meta.BilledUnits != nil {
+		tokens := int(resp.Meta.BilledUnits.InputTokens)
+		result.Usage = &providers.EmbeddingUsage{PromptTokens: tokens, TotalTokens: tokens}
+	}
+
+	return result, nil
+}
+
+// Embedding generates float embeddings for the given input.
+// The input type is set with WithEmbeddingInputType.
+func (p *Provider) Embedding(
+	ctx context.Context,
+	params providers.EmbeddingParams,
+) (*providers.EmbeddingResponse, error) {
+	texts, err := embeddingInputs(params.Input)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, err)
+	}
+
+	resp, err := p.Embed(ctx, EmbedParams{
+		InputType:       p.inputType,
+		Model:           params.Model,
+		OutputDimension: params.Dimensions,
+		Texts:           texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]providers.EmbeddingData, 0, len(resp.Embeddings.Float))
+	for i, e := range resp.Embeddings.Float {
+		data = append(data, providers.EmbeddingData{
+			Object:    objectEmbedding,
+			Embedding: e,
+			Index:     i,
+		})
+	}
+
+	return &providers.EmbeddingResponse{
+		Object: objectList,
+		Data:   data,
+		Model:  params.Model,
+		Usage:  resp.Usage,
+	}, nil
+}
+
+// ListModels returns a list of available models, following pagination to the last page.
+func (p *Provider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
+	models := make([]providers.Model, 0)
+	query := url.Values{"page_size": {strconv.Itoa(listModelsPageSize)}}
+
+	for {
+		var resp listModelsResponse
+		if err := p.do(ctx, http.MethodGet, pathModels+"?"+query.Encode(), nil, &resp); err != nil {
+			return nil, p.ConvertError(err)
+		}
+
+		for _, m := range resp.Models {
+			models = append(models, providers.Model{
+				ID:      m.Name,
+				Object:  objectModel,
+				OwnedBy: ownerCohere,
+			})
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		query.Set("page_token", resp.NextPageToken)
+	}
+
+	return &providers.ModelsResponse{
+		Object: objectList,
+		Data:   models,
+	}, nil
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// Rerank orders documents by their relevance to a query.
+func (p *Provider) Rerank(ctx context.Context, params providers.RerankParams) (*providers.RerankResponse, error) {
+	if params.Model == "" {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("model is required"))
+	}
+
+	req := rerankRequest{
+		Documents:       params.Documents,
+		MaxTokensPerDoc: params.MaxTokensPerDoc,
+		Model:           params.Model,
+		Query:           params.Query,
+		TopN:            params.TopN,
+	}
+
+	var resp rerankResponse
+	if err := p.do(ctx, http.MethodPost, pathRerank, req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	results := make([]providers.RerankResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		result := providers.RerankResult{
+			Index:          r.Index,
+			RelevanceScore: r.RelevanceScore,
+		}
+		if r.Index >= 0 && r.Index < len(params.Documents) {
+			result.Document = params.Documents[r.Index]
+		}
+		results = append(results, result)
+	}
+
+	rerank := &providers.RerankResponse{
+		ID:      resp.ID,
+		Model:   params.Model,
+		Results: results,
+	}
+	if resp.Meta != nil && resp.Meta.BilledUnits != nil {
+		rerank.Usage = &providers.RerankUsage{SearchUnits: int(resp.Meta.BilledUnits.SearchUnits)}
+	}
+
+	return rerank, nil
+}
+
+// do sends a request and decodes the JSON response body into out.
+func (p *Provider) do(ctx context.Context, method, path string, body, out any) error {
+	respBody, err := p.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = respBody.Close() }()
+
+	if err := json.NewDecoder(respBody).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// send sends a request with an optional JSON body and returns the response body.
+// Error responses are returned as an *apiError.
+func (p *Provider) send(ctx context.Context, method, path string, body any) (io.ReadCloser, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+"/"+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerAuthorization, authorizationPrefix+p.apiKey)
+	if body != nil {
+		req.Header.Set(headerContentType, contentTypeJSON)
+	}
+	p.config.ApplyHeaders(req.Header)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newAPIError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// newStreamState creates a new stream state.
+func newStreamState(model string) *streamState {
+	return &streamState{
+		id:      generateID(),
+		model:   model,
+		created: time.Now().Unix(),
+	}
+}
+
+// handleEvent converts a stream event to a chunk.
+// It returns false for events that carry nothing to forward.
+func (s *streamState) handleEvent(event *streamEvent) (providers.ChatCompletionChunk, bool) {
+	var choice providers.ChunkChoice
+	msg := event.Delta.Message
+
+	switch event.Type {
+	case eventMessageStart:
+		if event.ID != "" {
+			s.id = event.ID
+		}
+	case eventContentStart, eventContentDelta:
+		if msg.Content == nil || (msg.Content.Text == "" && msg.Content.Thinking == "") {
+			return providers.ChatCompletionChunk{}, false
+		}
+		choice.Delta.Content = msg.Content.Text
+		if msg.Content.Thinking != "" {
+			choice.Delta.Reasoning = &providers.Reasoning{Content: msg.Content.Thinking}
+		}
+	case eventToolPlanDelta:
+		choice.Delta.Reasoning = &providers.Reasoning{Content: msg.ToolPlan}
+	case eventToolCallStart, eventToolCallDelta:
+		if msg.ToolCalls == nil {
+			return providers.ChatCompletionChunk{}, false
+		}
+		tc := convertToolCall(*msg.ToolCalls, event.Index)
+		if event.Type == eventToolCallDelta {
+			// Argument fragments are matched to their call by index.
+			tc.ID, tc.Type = "", ""
+		}
+		choice.Delta.ToolCalls = []providers.ToolCall{tc}
+	case eventCitationStart:
+		if msg.Citations == nil {
+			return providers.ChatCompletionChunk{}, false
+		}
+		choice.Delta.Citations = []providers.Citation{convertCitation(*msg.Citations)}
+	case eventMessageEnd:
+		choice.FinishReason = convertFinishReason(event.Delta.FinishReason)
+	default:
+		return providers.ChatCompletionChunk{}, false
+	}
+
+	if !s.sentRole {
+		choice.Delta.Role = providers.RoleAssistant
+		s.sentRole = true
+	}
+
+	chunk := providers.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  objectChatCompletionChunk,
+		Created: s.created,
+		Model:   s.model,
+		Choices: []providers.ChunkChoice{choice},
+	}
+	if event.Type == eventMessageEnd {
+		chunk.Usage = convertUsage(event.Delta.Usage)
+	}
+
+	return chunk, true
+}
+
+// applyExtra decodes provider-specific parameters into the fields of req.
+func applyExtra(req *chatRequest, params map[string]any) error {
+	for _, key := range extra.Keys(params) {
+		value := params[key]
+
+		var err error
+		switch key {
+		case extraCitationOptions:
+			err = extra.Decode(key, value, &req.CitationOptions)
+		case extraFrequencyPenalty:
+			err = extra.Decode(key, value, &req.FrequencyPenalty)
+		case extraPresencePenalty:
+			err = extra.Decode(key, value, &req.PresencePenalty)
+		case extraSafetyMode:
+			err = extra.Decode(key, value, &req.SafetyMode)
+		case extraStrictTools:
+			err = extra.Decode(key, value, &req.StrictTools)
+		case extraTopK:
+			err = extra.Decode(key, value, &req.K)
+		default:
+			return errors.NewUnsupportedParamError(providerName, key)
+		}
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	return nil
+}
+
+// convertAssistantMessage converts an assistant message to Cohere format.
+// Reasoning is sent back as the tool plan of messages that call tools.
+func convertAssistantMessage(msg providers.Message) message {
+	result := message{Role: providers.RoleAssistant}
+	if text := msg.ContentString(); text != "" {
+		result.Content = text
+	}
+
+	for _, tc := range msg.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, toolCall{
+			ID:   tc.ID,
+			Type: toolTypeFunction,
+			Function: functionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+
+	if len(result.ToolCalls) > 0 && msg.Reasoning != nil {
+		result.ToolPlan = msg.Reasoning.Content
+	}
+
+	return result
+}
+
+// convertCitation converts a Cohere citation to provider format.
+func convertCitation(c citation) providers.Citation {
+	sources := make([]providers.CitationSource, 0, len(c.Sources))
+	for _, s := range c.Sources {
+		data := s.Document
+		if s.Type == providers.CitationSourceTool {
+			data = s.ToolOutput
+		}
+		sources = append(sources, providers.CitationSource{Type: s.Type, ID: s.ID, Data: data})
+	}
+
+	return providers.Citation{
+		Start:   c.Start,
+		End:     c.End,
+		Text:    c.Text,
+		Sources: sources,
+	}
+}
+
+// convertFinishReason converts a Cohere finish reason to OpenAI format.
+func convertFinishReason(reason string) string {
+	switch reason {
+	case finishReasonMaxTokens:
+		return providers.FinishReasonLength
+	case finishReasonToolCall:
+		return providers.FinishReasonToolCalls
+	default:
+		return providers.FinishReasonStop
+	}
+}
+
+// convertMessages converts provider messages to Cohere messages.
+// Documents attached to any message are collected into the returned request-level documents.
+func convertMessages(messages []providers.Message) ([]message, []document, error) {
+	result := make([]message, 0, len(messages))
+	var documents []document
+
+	for _, msg := range messages {
+		for _, d := range msg.Documents {
+			documents = append(documents, document{ID: d.ID, Data: d.Data})
+		}
+
+		switch msg.Role {
+		case providers.RoleSystem:
+			result = append(result, message{Role: providers.RoleSystem, Content: msg.ContentString()})
+		case providers.RoleUser:
+			result = append(result, convertUserMessage(msg))
+		case providers.RoleAssistant:
+			result = append(result, convertAssistantMessage(msg))
+		case providers.RoleTool:
+			result = append(result, message{
+				Role:       providers.RoleTool,
+				ToolCallID: msg.ToolCallID,
+				Content:    msg.ContentString(),
+			})
+		default:
+			return nil, nil, fmt.Errorf("unsupported message role %q", msg.Role)
+		}
+	}
+
+	return result, documents, nil
+}
+
+// convertParams converts providers.CompletionParams to a Cohere request.
+func convertParams(params providers.CompletionParams) (*chatRequest, error) {
+	if params.Logprobs {
+		return nil, errors.NewUnsupportedParamError(providerName, paramLogprobs)
+	}
+	if params.TopLogprobs != nil {
+		return nil, errors.NewUnsupportedParamError(providerName, paramTopLogprobs)
+	}
+	if hasContentPart(params.Messages, contentTypeFile) {
+		return nil, errors.NewUnsupportedParamError(providerName, "file content")
+	}
+	if hasContentPart(params.Messages, contentTypeInputAudio) {
+		return nil, errors.NewUnsupportedParamError(providerName, contentTypeInputAudio)
+	}
+	if params.Audio != nil || slices.Contains(params.Modalities, providers.ModalityAudio) {
+		return nil, errors.NewUnsupportedParamError(providerName, "audio")
+	}
+
+	messages, documents, err := convertMessages(params.Messages)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, err)
+	}
+
+	toolChoice, err := convertToolChoice(params.ToolChoice)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &chatRequest{
+		Documents:     documents,
+		MaxTokens:     params.MaxTokens,
+		Messages:      messages,
+		Model:         params.Model,
+		P:             params.TopP,
+		Seed:          params.Seed,
+		StopSequences: params.Stop,
+		Temperature:   params.Temperature,
+		Thinking:      convertReasoningEffort(params.ReasoningEffort),
+		ToolChoice:    toolChoice,
+		Tools:         convertTools(params.Tools),
+	}
+
+	if params.ResponseFormat != nil {
+		switch params.ResponseFormat.Type {
+		case responseFormatJSONObject:
+			req.ResponseFormat = &responseFormat{Type: responseFormatJSONObject}
+		case responseFormatJSONSchema:
+			req.ResponseFormat = &responseFormat{Type: responseFormatJSONObject}
+			if params.ResponseFormat.JSONSchema != nil {
+				req.ResponseFormat.JSONSchema = params.ResponseFormat.JSONSchema.Schema
+			}
+		}
+	}
+
+	if err := applyExtra(req, params.Extra); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// convertReasoningEffort converts a reasoning effort to a Cohere thinking configuration.
+// It returns nil when no effort is set, leaving the model's default.
+func convertReasoningEffort(effort providers.ReasoningEffort) *thinking {
+	var budget int
+	switch effort {
+	case providers.ReasoningEffortNone:
+		return &thinking{Type: thinkingTypeDisabled}
+	case providers.ReasoningEffortAuto:
+		return &thinking{Type: thinkingTypeEnabled}
+	case providers.ReasoningEffortLow:
+		budget = thinkingBudgetLow
+	case providers.ReasoningEffortMedium:
+		budget = thinkingBudgetMedium
+	case providers.ReasoningEffortHigh:
+		budget = thinkingBudgetHigh
+	default:
+		return nil
+	}
+
+	return &thinking{Type: thinkingTypeEnabled, TokenBudget: &budget}
+}
+
+// convertResponse converts a Cohere chat response to provider format.
+// Thinking and the tool plan are both reported as reasoning.
+func convertResponse(resp *chatResponse, model string) *providers.ChatCompletion {
+	var text, reasoning strings.Builder
+	for _, block := range resp.Message.Content {
+		switch block.Type {
+		case contentTypeText:
+			text.WriteString(block.Text)
+		case contentTypeThinking:
+			reasoning.WriteString(block.Thinking)
+		}
+	}
+	reasoning.WriteString(resp.Message.ToolPlan)
+
+	message := providers.Message{
+		Role:    providers.RoleAssistant,
+		Content: text.String(),
+	}
+	if reasoning.Len() > 0 {
+		message.Reasoning = &providers.Reasoning{Content: reasoning.String()}
+	}
+	for i, tc := range resp.Message.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, convertToolCall(tc, i))
+	}
+	for _, c := range resp.Message.Citations {
+		message.Citations = append(message.Citations, convertCitation(c))
+	}
+
+	id := resp.ID
+	if id == "" {
+		id = generateID()
+	}
+
+	return &providers.ChatCompletion{
+		ID:      id,
+		Object:  objectChatCompletion,
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []providers.Choice{{
+			Message:      message,
+			FinishReason: convertFinishReason(resp.FinishReason),
+		}},
+		Usage: convertUsage(resp.Usage),
+	}
+}
+
+// convertToolCall converts a Cohere tool call to provider format.
+func convertToolCall(tc toolCall, index int) providers.ToolCall {
+	return providers.ToolCall{
+		Index: index,
+		ID:    tc.ID,
+		Type:  toolTypeFunction,
+		Function: providers.FunctionCall{
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		},
+	}
+}
+
+// convertToolChoice converts a provider tool choice to a Cohere tool choice.
+// Cohere cannot force a specific tool, so a function choice is unsupported.
+func convertToolChoice(choice any) (string, error) {
+	switch v := choice.(type) {
+	case string:
+		switch v {
+		case "none":
+			return toolChoiceNone, nil
+		case "required", "any":
+			return toolChoiceRequired, nil
+		}
+	case providers.ToolChoice:
+		if v.Function != nil {
+			return "", errors.NewUnsupportedParamError(providerName, paramToolChoice)
+		}
+	}
+
+	return "", nil
+}
+
+// convertTools converts provider tools to Cohere tools.
+func convertTools(tools []providers.Tool) []tool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	result := make([]tool, 0, len(tools))
+	for _, t := range tools {
+		result = append(result, tool{
+			Type: toolTypeFunction,
+			Function: functionDefinition{
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  t.Function.Parameters,
+			},
+		})
+	}
+
+	return result
+}
+
+// convertUsage converts Cohere usage to provider format.
+// Processed tokens are preferred over billed units, which exclude some prompt tokens.
+func convertUsage(u *usage) *providers.Usage {
+	if u == nil {
+		return nil
+	}
+
+	units := u.Tokens
+	if units == nil {
+		units = u.BilledUnits
+	}
+	if units == nil {
+		return nil
+	}
+
+	promptTokens := int(units.InputTokens)
+	completionTokens := int(units.OutputTokens)
+
+	return &providers.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// convertUserMessage converts a user message to Cohere format.
+func convertUserMessage(msg providers.Message) message {
+	if !msg.IsMultiModal() {
+		return message{Role: providers.RoleUser, Content: msg.ContentString()}
+	}
+
+	blocks := make([]contentBlock, 0)
+	for _, p := range msg.ContentParts() {
+		switch p.Type {
+		case contentTypeText:
+			blocks = append(blocks, contentBlock{Type: contentTypeText, Text: p.Text})
+		case contentTypeImageURL:
+			if p.ImageURL != nil {
+				blocks = append(blocks, contentBlock{Type: contentTypeImageURL, ImageURL: &imageURL{URL: p.ImageURL.URL}})
+			}
+		}
+	}
+
+	return message{Role: providers.RoleUser, Content: blocks}
+}
+
+// embeddingInputs returns the texts to embed from an embedding input.
+func embeddingInputs(input any) ([]string, error) {
+	switch v := input.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []any:
+		inputs := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported embedding input element of type %T", item)
+			}
+			inputs = append(inputs, s)
+		}
+		return inputs, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding input of type %T", input)
+	}
+}
+
+// generateID generates a unique ID for responses that do not carry one.
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("chatcmpl-%d-%s", time.Now().UnixNano(), hex.EncodeToString(b))
+}
+
+// hasContentPart reports whether any message has a content part of the given type.
+func hasContentPart(messages []providers.Message, partType string) bool {
+	for _, msg := range messages {
+		for _, part := range msg.ContentParts() {
+			if part.Type == partType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// newAPIError builds an *apiError from an error response.
+func newAPIError(resp *http.Response) *apiError {
+	apiErr := &apiError{
+		Header:     resp.Header,
+		StatusCode: resp.StatusCode,
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		apiErr.Message = errResp.Message
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,1086 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+// writeJSON writes v as a JSON response.
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, v any) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "cohere", provider.Name())
+		require.Equal(t, defaultBaseURL, provider.baseURL)
+		require.Equal(t, InputTypeSearchDocument, provider.inputType)
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("COHERE_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "env-api-key", provider.apiKey)
+	})
+
+	t.Run("uses custom base URL", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL("http://localhost:8080/"))
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:8080", provider.baseURL)
+	})
+
+	t.Run("sets the embedding input type", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey), WithEmbeddingInputType(InputTypeSearchQuery))
+		require.NoError(t, err)
+		require.Equal(t, InputTypeSearchQuery, provider.inputType)
+
+		_, err = New(config.WithAPIKey(testAPIKey), WithEmbeddingInputType(" "))
+		require.ErrorContains(t, err, "embedding input type cannot be empty")
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("COHERE_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "cohere", missingKeyErr.Provider)
+		require.Equal(t, "COHERE_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(config.WithAPIKey(testAPIKey))
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ListModels)
+	require.True(t, caps.Rerank)
+}
+
+func TestConvertMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("converts roles, tool calls, and tool results", func(t *testing.T) {
+		t.Parallel()
+
+		messages, documents, err := convertMessages([]providers.Message{
+			{Role: providers.RoleSystem, Content: "Be brief."},
+			{Role: providers.RoleUser, Content: "Weather in Paris?"},
+			{
+				Role:      providers.RoleAssistant,
+				Reasoning: &providers.Reasoning{Content: "I will look up the weather."},
+				ToolCalls: []providers.ToolCall{{
+					ID:       "get_weather_1",
+					Type:     "function",
+					Function: providers.FunctionCall{Name: "get_weather", Arguments: `{"location":"Paris"}`},
+				}},
+			},
+			{Role: providers.RoleTool, ToolCallID: "get_weather_1", Content: `{"temperature":20}`},
+		})
+		require.NoError(t, err)
+		require.Nil(t, documents)
+		require.Equal(t, []message{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Weather in Paris?"},
+			{
+				Role:     "assistant",
+				ToolPlan: "I will look up the weather.",
+				ToolCalls: []toolCall{{
+					ID:       "get_weather_1",
+					Type:     "function",
+					Function: functionCall{Name: "get_weather", Arguments: `{"location":"Paris"}`},
+				}},
+			},
+			{Role: "tool", ToolCallID: "get_weather_1", Content: `{"temperature":20}`},
+		}, messages)
+	})
+
+	t.Run("collects documents", func(t *testing.T) {
+		t.Parallel()
+
+		_, documents, err := convertMessages([]providers.Message{
+			{Role: providers.RoleSystem, Content: "Answer from the documents.", Documents: []providers.Document{
+				{ID: "eiffel", Data: map[string]any{"text": "The Eiffel Tower was completed in 1889."}},
+			}},
+			{Role: providers.RoleUser, Content: "When was it built?", Documents: []providers.Document{
+				{Data: map[string]any{"title": "Paris"}},
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []document{
+			{ID: "eiffel", Data: map[string]any{"text": "The Eiffel Tower was completed in 1889."}},
+			{Data: map[string]any{"title": "Paris"}},
+		}, documents)
+	})
+
+	t.Run("converts images", func(t *testing.T) {
+		t.Parallel()
+
+		messages, _, err := convertMessages([]providers.Message{{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "text", Text: "What is this?"},
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "data:image/png;base64,aGVsbG8="}},
+			},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, []contentBlock{
+			{Type: "text", Text: "What is this?"},
+			{Type: "image_url", ImageURL: &imageURL{URL: "data:image/png;base64,aGVsbG8="}},
+		}, messages[0].Content)
+	})
+
+	t.Run("rejects unknown roles", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := convertMessages([]providers.Message{{Role: "narrator", Content: "Once upon a time"}})
+		require.ErrorContains(t, err, `unsupported message role "narrator"`)
+	})
+}
+
+func TestConvertParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps generation parameters", func(t *testing.T) {
+		t.Parallel()
+
+		temperature, topP, maxTokens, seed := 0.3, 0.9, 100, 7
+		req, err := convertParams(providers.CompletionParams{
+			Model:       "command-a-03-2025",
+			Messages:    testutil.SimpleMessages(),
+			Temperature: &temperature,
+			TopP:        &topP,
+			MaxTokens:   &maxTokens,
+			Seed:        &seed,
+			Stop:        []string{"END"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "command-a-03-2025", req.Model)
+		require.Equal(t, &temperature, req.Temperature)
+		require.Equal(t, &topP, req.P)
+		require.Equal(t, &maxTokens, req.MaxTokens)
+		require.Equal(t, &seed, req.Seed)
+		require.Equal(t, []string{"END"}, req.StopSequences)
+		require.Nil(t, req.Thinking)
+	})
+
+	t.Run("maps response formats", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := convertParams(providers.CompletionParams{
+			Messages:       testutil.SimpleMessages(),
+			ResponseFormat: &providers.ResponseFormat{Type: "json_object"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &responseFormat{Type: "json_object"}, req.ResponseFormat)
+
+		schema := map[string]any{"type": "object"}
+		req, err = convertParams(providers.CompletionParams{
+			Messages: testutil.SimpleMessages(),
+			ResponseFormat: &providers.ResponseFormat{
+				Type:       "json_schema",
+				JSONSchema: &providers.JSONSchema{Name: "answer", Schema: schema},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &responseFormat{Type: "json_object", JSONSchema: schema}, req.ResponseFormat)
+	})
+
+	t.Run("maps tools and tool choice", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := convertParams(providers.CompletionParams{
+			Messages:   testutil.SimpleMessages(),
+			Tools:      []providers.Tool{testutil.WeatherTool()},
+			ToolChoice: "required",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "REQUIRED", req.ToolChoice)
+		require.Len(t, req.Tools, 1)
+		require.Equal(t, "function", req.Tools[0].Type)
+		require.Equal(t, "get_weather", req.Tools[0].Function.Name)
+
+		tests := map[string]struct {
+			choice any
+			want   string
+		}{
+			"auto":     {choice: "auto", want: ""},
+			"none":     {choice: "none", want: "NONE"},
+			"any":      {choice: "any", want: "REQUIRED"},
+			"required": {choice: "required", want: "REQUIRED"},
+		}
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := convertToolChoice(tc.choice)
+				require.NoError(t, err)
+				require.Equal(t, tc.want, got)
+			})
+		}
+	})
+
+	t.Run("maps reasoning effort to thinking", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, convertReasoningEffort(""))
+		require.Equal(t, &thinking{Type: "disabled"}, convertReasoningEffort(providers.ReasoningEffortNone))
+		require.Equal(t, &thinking{Type: "enabled"}, convertReasoningEffort(providers.ReasoningEffortAuto))
+
+		tests := map[providers.ReasoningEffort]int{
+			providers.ReasoningEffortLow:    1024,
+			providers.ReasoningEffortMedium: 8192,
+			providers.ReasoningEffortHigh:   24576,
+		}
+		for effort, budget := range tests {
+			require.Equal(t, &thinking{Type: "enabled", TokenBudget: &budget}, convertReasoningEffort(effort), effort)
+		}
+	})
+
+	t.Run("rejects unsupported parameters", func(t *testing.T) {
+		t.Parallel()
+
+		topLogprobs := 3
+		tests := map[string]providers.CompletionParams{
+			"logprobs":     {Messages: testutil.SimpleMessages(), Logprobs: true},
+			"top_logprobs": {Messages: testutil.SimpleMessages(), TopLogprobs: &topLogprobs},
+			"tool_choice": {Messages: testutil.SimpleMessages(), ToolChoice: providers.ToolChoice{
+				Type:     "function",
+				Function: &providers.ToolChoiceFunction{Name: "get_weather"},
+			}},
+		}
+		tests["file content"] = providers.CompletionParams{Messages: []providers.Message{{
+			Role:    providers.RoleUser,
+			Content: []providers.ContentPart{{Type: "file", File: &providers.File{Data: "JVBERi0="}}},
+		}}}
+		tests["input_audio"] = providers.CompletionParams{Messages: []providers.Message{{
+			Role:    providers.RoleUser,
+			Content: []providers.ContentPart{{Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg=="}}},
+		}}}
+		tests["audio"] = providers.CompletionParams{
+			Messages: testutil.SimpleMessages(),
+			Audio:    &providers.AudioParams{Voice: "alloy", Format: "wav"},
+		}
+		for param, params := range tests {
+			_, err := convertParams(params)
+
+			var unsupportedErr *errors.UnsupportedParamError
+			require.ErrorAs(t, err, &unsupportedErr)
+			require.Equal(t, param, unsupportedErr.Param)
+		}
+	})
+}
+
+func TestApplyExtra(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps known keys onto typed fields", func(t *testing.T) {
+		t.Parallel()
+
+		req := &chatRequest{}
+		err := applyExtra(req, map[string]any{
+			"citation_options":  map[string]any{"mode": "ACCURATE"},
+			"frequency_penalty": 0.5,
+			"presence_penalty":  0.25,
+			"safety_mode":       "STRICT",
+			"strict_tools":      true,
+			"top_k":             40,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &citationOptions{Mode: "ACCURATE"}, req.CitationOptions)
+		require.Equal(t, 0.5, *req.FrequencyPenalty)
+		require.Equal(t, 0.25, *req.PresencePenalty)
+		require.Equal(t, "STRICT", req.SafetyMode)
+		require.True(t, *req.StrictTools)
+		require.Equal(t, 40, *req.K)
+	})
+
+	t.Run("rejects unsupported keys", func(t *testing.T) {
+		t.Parallel()
+
+		err := applyExtra(&chatRequest{}, map[string]any{"logit_bias": map[string]any{}})
+
+		var unsupportedErr *errors.UnsupportedParamError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, "logit_bias", unsupportedErr.Param)
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		err := applyExtra(&chatRequest{}, map[string]any{"top_k": "forty"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestConvertFinishReason(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"COMPLETE":      providers.FinishReasonStop,
+		"STOP_SEQUENCE": providers.FinishReasonStop,
+		"MAX_TOKENS":    providers.FinishReasonLength,
+		"TOOL_CALL":     providers.FinishReasonToolCalls,
+	}
+	for reason, want := range tests {
+		require.Equal(t, want, convertFinishReason(reason), reason)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends the request and converts the response", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/v2/chat", r.URL.Path)
+			require.Equal(t, "Bearer "+testAPIKey, r.Header.Get("Authorization"))
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, "command-a-03-2025", req["model"])
+			require.Equal(t, []any{
+				map[string]any{"role": "system", "content": "Be brief."},
+				map[string]any{"role": "user", "content": "Hello"},
+			}, req["messages"])
+			require.Equal(t, 0.2, req["temperature"])
+			require.NotContains(t, req, "stream")
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":            "resp-123",
+				"finish_reason": "COMPLETE",
+				"message": map[string]any{
+					"role": "assistant",
+					"content": []any{
+						map[string]any{"type": "thinking", "thinking": "Considering a greeting."},
+						map[string]any{"type": "text", "text": "Hello there!"},
+					},
+				},
+				"usage": map[string]any{
+					"billed_units": map[string]any{"input_tokens": 3, "output_tokens": 3},
+					"tokens":       map[string]any{"input_tokens": 9, "output_tokens": 3},
+				},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		temperature := 0.2
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model: "command-a-03-2025",
+			Messages: []providers.Message{
+				{Role: providers.RoleSystem, Content: "Be brief."},
+				{Role: providers.RoleUser, Content: "Hello"},
+			},
+			Temperature: &temperature,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "resp-123", resp.ID)
+		require.Equal(t, "chat.completion", resp.Object)
+		require.Equal(t, "command-a-03-2025", resp.Model)
+		require.Len(t, resp.Choices, 1)
+		require.Equal(t, providers.RoleAssistant, resp.Choices[0].Message.Role)
+		require.Equal(t, "Hello there!", resp.Choices[0].Message.Content)
+		require.Equal(t, &providers.Reasoning{Content: "Considering a greeting."}, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, providers.FinishReasonStop, resp.Choices[0].FinishReason)
+		require.Equal(t, &providers.Usage{PromptTokens: 9, CompletionTokens: 3, TotalTokens: 12}, resp.Usage)
+	})
+
+	t.Run("converts tool calls and the tool plan", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			var req chatRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.Tools, 1)
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":            "resp-456",
+				"finish_reason": "TOOL_CALL",
+				"message": map[string]any{
+					"role":      "assistant",
+					"tool_plan": "I will check the weather in Paris.",
+					"tool_calls": []any{map[string]any{
+						"id":       "get_weather_abc",
+						"type":     "function",
+						"function": map[string]any{"name": "get_weather", "arguments": `{"location":"Paris"}`},
+					}},
+				},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "command-a-03-2025",
+			Messages: testutil.SimpleMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool()},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, providers.FinishReasonToolCalls, resp.Choices[0].FinishReason)
+		require.Equal(t, "I will check the weather in Paris.", resp.Choices[0].Message.Reasoning.Content)
+		require.Equal(t, []providers.ToolCall{{
+			ID:   "get_weather_abc",
+			Type: "function",
+			Function: providers.FunctionCall{
+				Name:      "get_weather",
+				Arguments: `{"location":"Paris"}`,
+			},
+		}}, resp.Choices[0].Message.ToolCalls)
+		require.Nil(t, resp.Usage)
+	})
+
+	t.Run("sends documents and converts citations", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, []any{map[string]any{
+				"id":   "eiffel",
+				"data": map[string]any{"text": "The Eiffel Tower was completed in 1889."},
+			}}, req["documents"])
+			require.Equal(t, map[string]any{"mode": "FAST"}, req["citation_options"])
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":            "resp-789",
+				"finish_reason": "COMPLETE",
+				"message": map[string]any{
+					"role":    "assistant",
+					"content": []any{map[string]any{"type": "text", "text": "It was completed in 1889."}},
+					"citations": []any{
+						map[string]any{
+							"start": 21,
+							"end":   25,
+							"text":  "1889",
+							"type":  "TEXT_CONTENT",
+							"sources": []any{map[string]any{
+								"type":     "document",
+								"id":       "eiffel",
+								"document": map[string]any{"id": "eiffel", "text": "The Eiffel Tower was completed in 1889."},
+							}},
+						},
+						map[string]any{
+							"start": 0,
+							"end":   2,
+							"text":  "It",
+							"sources": []any{map[string]any{
+								"type":        "tool",
+								"id":          "search_1:0",
+								"tool_output": map[string]any{"name": "Eiffel Tower"},
+							}},
+						},
+					},
+				},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model: "command-a-03-2025",
+			Messages: []providers.Message{{
+				Role:    providers.RoleUser,
+				Content: "When was the Eiffel Tower completed?",
+				Documents: []providers.Document{{
+					ID:   "eiffel",
+					Data: map[string]any{"text": "The Eiffel Tower was completed in 1889."},
+				}},
+			}},
+			Extra: map[string]any{"citation_options": map[string]any{"mode": "FAST"}},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []providers.Citation{
+			{
+				Start: 21,
+				End:   25,
+				Text:  "1889",
+				Sources: []providers.CitationSource{{
+					Type: providers.CitationSourceDocument,
+					ID:   "eiffel",
+					Data: map[string]any{"id": "eiffel", "text": "The Eiffel Tower was completed in 1889."},
+				}},
+			},
+			{
+				Start: 0,
+				End:   2,
+				Text:  "It",
+				Sources: []providers.CitationSource{{
+					Type: providers.CitationSourceTool,
+					ID:   "search_1:0",
+					Data: map[string]any{"name": "Eiffel Tower"},
+				}},
+			},
+		}, resp.Choices[0].Message.Citations)
+	})
+
+	t.Run("rejects unsupported extra parameters before sending", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "command-a-03-2025",
+			Messages: testutil.SimpleMessages(),
+			Extra:    map[string]any{"logit_bias": map[string]any{}},
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	events := []string{
+		`{"type":"message-start","id":"resp-1","delta":{"message":{"role":"assistant"}}}`,
+		`{"type":"tool-plan-delta","delta":{"message":{"tool_plan":"I will check."}}}`,
+		`{"type":"tool-call-start","index":0,"delta":{"message":{"tool_calls":{"id":"get_weather_1",` +
+			`"type":"function","function":{"name":"get_weather","arguments":""}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"{\"location\":"}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"\"Paris\"}"}}}}}`,
+		`{"type":"tool-call-end","index":0}`,
+		`{"type":"content-start","index":0,"delta":{"message":{"content":{"type":"text","text":""}}}}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Sunny"}}}}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":" in Paris."}}}}`,
+		`{"type":"content-end","index":0}`,
+		`{"type":"citation-start","index":0,"delta":{"message":{"citations":{"start":0,"end":5,"text":"Sunny",` +
+			`"sources":[{"type":"tool","id":"get_weather_1:0","tool_output":{"sky":"clear"}}]}}}}`,
+		`{"type":"citation-end","index":0}`,
+		`{"type":"message-end","delta":{"finish_reason":"COMPLETE",` +
+			`"usage":{"tokens":{"input_tokens":20,"output_tokens":8}}}}`,
+	}
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/chat", r.URL.Path)
+
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.True(t, req.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			var e struct {
+				Type string `json:"type"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(event), &e))
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, event)
+		}
+	}, config.WithAPIKey(testAPIKey))
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    "command-a-03-2025",
+		Messages: testutil.SimpleMessages(),
+		Tools:    []providers.Tool{testutil.WeatherTool()},
+	})
+
+	var received []providers.ChatCompletionChunk
+	acc := providers.NewAccumulator()
+	for chunk := range chunks {
+		received = append(received, chunk)
+		acc.Add(chunk)
+	}
+	require.NoError(t, <-errs)
+
+	require.Len(t, received, 9, "end events and empty content are not forwarded")
+	require.Equal(t, "resp-1", received[0].ID)
+	require.Equal(t, "chat.completion.chunk", received[0].Object)
+	require.Equal(t, providers.RoleAssistant, received[0].Choices[0].Delta.Role)
+	require.Empty(t, received[1].Choices[0].Delta.Role)
+	require.Nil(t, received[0].Usage, "usage is only reported on the final chunk")
+
+	completion := acc.ChatCompletion()
+	require.Equal(t, "resp-1", completion.ID)
+	require.Equal(t, "Sunny in Paris.", completion.Choices[0].Message.Content)
+	require.Equal(t, "I will check.", completion.Choices[0].Message.Reasoning.Content)
+	require.Equal(t, providers.FinishReasonStop, completion.Choices[0].FinishReason)
+	require.Equal(t, []providers.ToolCall{{
+		ID:       "get_weather_1",
+		Type:     "function",
+		Function: providers.FunctionCall{Name: "get_weather", Arguments: `{"location":"Paris"}`},
+	}}, completion.Choices[0].Message.ToolCalls)
+	require.Equal(t, []providers.Citation{{
+		Start: 0,
+		End:   5,
+		Text:  "Sunny",
+		Sources: []providers.CitationSource{{
+			Type: providers.CitationSourceTool,
+			ID:   "get_weather_1:0",
+			Data: map[string]any{"sky": "clear"},
+		}},
+	}}, completion.Choices[0].Message.Citations)
+	require.Equal(t, &providers.Usage{PromptTokens: 20, CompletionTokens: 8, TotalTokens: 28}, completion.Usage)
+}
+
+func TestEmbedding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("embeds inputs as float vectors", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/v2/embed", r.URL.Path)
+
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, map[string]any{
+				"model":            "embed-v4.0",
+				"texts":            []any{"first", "second"},
+				"input_type":       "search_query",
+				"embedding_types":  []any{"float"},
+				"output_dimension": float64(2),
+			}, req)
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":         "emb-1",
+				"embeddings": map[string]any{"float": [][]float64{{0.1, 0.2}, {0.3, 0.4}}},
+				"texts":      []string{"first", "second"},
+				"meta":       map[string]any{"billed_units": map[string]any{"input_tokens": 4}},
+			})
+		}, config.WithAPIKey(testAPIKey), WithEmbeddingInputType(InputTypeSearchQuery))
+
+		dimensions := 2
+		resp, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+			Model:      "embed-v4.0",
+			Input:      []string{"first", "second"},
+			Dimensions: &dimensions,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "list", resp.Object)
+		require.Equal(t, "embed-v4.0", resp.Model)
+		require.Equal(t, []providers.EmbeddingData{
+			{Object: "embedding", Embedding: []float64{0.1, 0.2}, Index: 0},
+			{Object: "embedding", Embedding: []float64{0.3, 0.4}, Index: 1},
+		}, resp.Data)
+		require.Equal(t, &providers.EmbeddingUsage{PromptTokens: 4, TotalTokens: 4}, resp.Usage)
+	})
+
+	t.Run("rejects unsupported inputs", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+			Model: "embed-v4.0",
+			Input: []int{1, 2},
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestEmbed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the requested embedding types", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			var req embedRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, embedRequest{
+				EmbeddingTypes: []string{"int8", "binary"},
+				InputType:      "classification",
+				Model:          "embed-v4.0",
+				Texts:          []string{"hello"},
+				Truncate:       "END",
+			}, req)
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id": "emb-2",
+				"embeddings": map[string]any{
+					"int8":   [][]int{{-12, 40}},
+					"binary": [][]int{{-3}},
+				},
+				"texts": []string{"hello"},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Embed(context.Background(), EmbedParams{
+			EmbeddingTypes: []string{EmbeddingTypeInt8, EmbeddingTypeBinary},
+			InputType:      InputTypeClassification,
+			Model:          "embed-v4.0",
+			Texts:          []string{"hello"},
+			Truncate:       "END",
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, &EmbedResponse{
+			Embeddings: Embeddings{Binary: [][]int{{-3}}, Int8: [][]int{{-12, 40}}},
+			ID:         "emb-2",
+			Model:      "embed-v4.0",
+			Texts:      []string{"hello"},
+		}, resp)
+	})
+
+	t.Run("requires a model", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Embed(context.Background(), EmbedParams{Texts: []string{"hello"}})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestRerank(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ranks documents by relevance", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/v2/rerank", r.URL.Path)
+
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, map[string]any{
+				"model":     "rerank-v3.5",
+				"query":     "capital of France",
+				"documents": []any{"Berlin is in Germany.", "Paris is the capital of France."},
+				"top_n":     float64(1),
+			}, req)
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"id":      "rerank-1",
+				"results": []any{map[string]any{"index": 1, "relevance_score": 0.98}},
+				"meta":    map[string]any{"billed_units": map[string]any{"search_units": 1}},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		topN := 1
+		resp, err := provider.Rerank(context.Background(), providers.RerankParams{
+			Model:     "rerank-v3.5",
+			Query:     "capital of France",
+			Documents: []string{"Berlin is in Germany.", "Paris is the capital of France."},
+			TopN:      &topN,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, &providers.RerankResponse{
+			ID:    "rerank-1",
+			Model: "rerank-v3.5",
+			Results: []providers.RerankResult{
+				{Index: 1, RelevanceScore: 0.98, Document: "Paris is the capital of France."},
+			},
+			Usage: &providers.RerankUsage{SearchUnits: 1},
+		}, resp)
+	})
+
+	t.Run("requires a model", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Rerank(context.Background(), providers.RerankParams{Query: "q", Documents: []string{"d"}})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestListModels(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/v1/models", r.URL.Path)
+		require.Equal(t, "1000", r.URL.Query().Get("page_size"))
+
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"models":          []any{map[string]any{"name": "command-a-03-2025", "endpoints": []string{"chat"}}},
+				"next_page_token": "page-2",
+			})
+		case "page-2":
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"models": []any{map[string]any{"name": "embed-v4.0", "endpoints": []string{"embed"}}},
+			})
+		default:
+			t.Errorf("unexpected page token %q", r.URL.Query().Get("page_token"))
+		}
+	}, config.WithAPIKey(testAPIKey))
+
+	resp, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "list", resp.Object)
+	require.Equal(t, []providers.Model{
+		{ID: "command-a-03-2025", Object: "model", OwnedBy: "cohere"},
+		{ID: "embed-v4.0", Object: "model", OwnedBy: "cohere"},
+	}, resp.Data)
+}
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer "+testAPIKey, r.Header.Get("Authorization"))
+		require.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+		require.Equal(t, "my-app/1.0", r.Header.Get("X-Client-Name"))
+
+		writeJSON(t, w, http.StatusOK, map[string]any{"models": []any{}})
+	},
+		config.WithAPIKey(testAPIKey),
+		config.WithHeaders(map[string]string{"X-Trace-Id": "trace-1", "X-Client-Name": "my-app/1.0"}),
+	)
+
+	_, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+}
+
+func TestConvertError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantSentinel error
+	}{
+		{
+			name:   "too many tokens becomes ContextLengthError",
+			status: http.StatusBadRequest,
+			body: `{"id":"err-1","message":"too many tokens: total number of tokens in the prompt cannot ` +
+				`exceed 256000 - received 300000"}`,
+			wantSentinel: errors.ErrContextLength,
+		},
+		{
+			name:         "other 400 becomes InvalidRequestError",
+			status:       http.StatusBadRequest,
+			body:         `{"id":"err-2","message":"invalid request: message must be at least 1 token long"}`,
+			wantSentinel: errors.ErrInvalidRequest,
+		},
+		{
+			name:         "401 becomes AuthenticationError",
+			status:       http.StatusUnauthorized,
+			body:         `{"id":"err-3","message":"invalid api token"}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+		{
+			name:         "498 becomes AuthenticationError",
+			status:       498,
+			body:         `{"id":"err-4","message":"invalid token"}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+		{
+			name:         "404 becomes ModelNotFoundError",
+			status:       http.StatusNotFound,
+			body:         `{"id":"err-5","message":"model 'nope' not found"}`,
+			wantSentinel: errors.ErrModelNotFound,
+		},
+		{
+			name:         "422 becomes InvalidRequestError",
+			status:       http.StatusUnprocessableEntity,
+			body:         `{"id":"err-6","message":"unprocessable entity"}`,
+			wantSentinel: errors.ErrInvalidRequest,
+		},
+		{
+			name:         "429 becomes RateLimitError",
+			status:       http.StatusTooManyRequests,
+			body:         `{"id":"err-7","message":"You are using a Trial key, which is limited"}`,
+			wantSentinel: errors.ErrRateLimit,
+		},
+		{
+			name:         "500 with a non-JSON body becomes ProviderError",
+			status:       http.StatusInternalServerError,
+			body:         "upstream failure",
+			wantSentinel: errors.ErrProvider,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.body)
+			}, config.WithAPIKey(testAPIKey))
+
+			_, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    "command-a-03-2025",
+				Messages: testutil.SimpleMessages(),
+			})
+			require.ErrorIs(t, err, tc.wantSentinel)
+
+			var apiErr *apiError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tc.status, apiErr.StatusCode)
+			require.NotEmpty(t, apiErr.Message)
+		})
+	}
+
+	t.Run("rate limit errors carry the retry delay", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		err := p.ConvertError(&apiError{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"7"}},
+		})
+
+		var rateLimitErr *errors.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		require.Equal(t, 7, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("non-API errors become ProviderError", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		require.Nil(t, p.ConvertError(nil))
+		require.ErrorIs(t, p.ConvertError(stderrors.New("connection reset")), errors.ErrProvider)
+	})
+}
+
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "cohere.(*Provider).CompletionStream"
+
+	server := testutil.NewEndlessStreamServer(t, "text/event-stream", nil, func(i int) string {
+		return fmt.Sprintf("event: content-delta\ndata: {\"type\":\"content-delta\","+
+			"\"delta\":{\"message\":{\"content\":{\"text\":\"%d \"}}}}\n\n", i)
+	})
+
+	provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: "command-a-03-2025", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, providers.RoleAssistant, chunk.Choices[0].Delta.Role)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("cohere") {
+		t.Skip("COHERE_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("cohere"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestIntegrationCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("cohere") {
+		t.Skip("COHERE_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("cohere"),
+		Messages: testutil.SimpleMessages(),
+		Stream:   true,
+	})
+
+	var content strings.Builder
+	for chunk := range chunks {
+		if len(chunk.Choices) > 0 {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+	require.NoError(t, <-errs)
+	require.NotEmpty(t, content.String())
+}
+
+func TestIntegrationEmbedding(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("cohere") {
+		t.Skip("COHERE_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+		Model: testutil.EmbeddingModel("cohere"),
+		Input: "Hello, world!",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.NotEmpty(t, resp.Data[0].Embedding)
+}
@@ -0,0 +1,301 @@
+package cohere
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// EmbedParams are the parameters of an embed request.
+// Fields are ordered alphabetically.
+type EmbedParams struct {
+	// EmbeddingTypes lists the formats to return, e.g. EmbeddingTypeFloat and EmbeddingTypeInt8.
+	// Only float embeddings are returned when it is empty.
+	EmbeddingTypes []string
+
+	// InputType describes how the embeddings will be used, e.g. InputTypeSearchQuery.
+	// Embed models v3 and later require it.
+	InputType string
+
+	// Model is the embedding model to use, e.g. "embed-v4.0".
+	Model string
+
+	// OutputDimension is the number of dimensions of each embedding, for models that support it.
+	OutputDimension *int
+
+	// Texts are the texts to embed.
+	Texts []string
+
+	// Truncate controls how inputs longer than the model's context are handled: "NONE", "START", or "END".
+	Truncate string
+}
+
+// EmbedResponse is the result of an embed request.
+type EmbedResponse struct {
+	// Embeddings holds the embeddings of each requested type, in the order of the input texts.
+	Embeddings Embeddings
+
+	// ID identifies the request.
+	ID string
+
+	// Model is the embedding model used.
+	Model string
+
+	// Texts are the embedded texts.
+	Texts []string
+
+	// Usage reports the billed input tokens.
+	Usage *providers.EmbeddingUsage
+}
+
+// Embeddings holds embeddings by type. Only the requested types are set.
+type Embeddings struct {
+	Base64  []string    `json:"base64,omitempty"`
+	Binary  [][]int     `json:"binary,omitempty"`
+	Float   [][]float64 `json:"float,omitempty"`
+	Int8    [][]int     `json:"int8,omitempty"`
+	Ubinary [][]int     `json:"ubinary,omitempty"`
+	Uint8   [][]int     `json:"uint8,omitempty"`
+}
+
+// apiError is an error response from the Cohere API.
+type apiError struct {
+	// Header holds the response headers, which may carry retry hints.
+	Header http.Header
+
+	// Message is the error message returned by the API.
+	Message string
+
+	// StatusCode is the HTTP status code.
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (e *apiError) Error() string {
+	return fmt.Sprintf("cohere API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// billedUnits reports usage in billed units or tokens.
+type billedUnits struct {
+	InputTokens  float64 `json:"input_tokens,omitempty"`
+	OutputTokens float64 `json:"output_tokens,omitempty"`
+	SearchUnits  float64 `json:"search_units,omitempty"`
+}
+
+// chatRequest is the request body of the v2 chat endpoint.
+type chatRequest struct {
+	CitationOptions  *citationOptions `json:"citation_options,omitempty"`
+	Documents        []document       `json:"documents,omitempty"`
+	FrequencyPenalty *float64         `json:"frequency_penalty,omitempty"`
+	K                *int             `json:"k,omitempty"`
+	MaxTokens        *int             `json:"max_tokens,omitempty"`
+	Messages         []message        `json:"messages"`
+	Model            string           `json:"model"`
+	P                *float64         `json:"p,omitempty"`
+	PresencePenalty  *float64         `json:"presence_penalty,omitempty"`
+	ResponseFormat   *responseFormat  `json:"response_format,omitempty"`
+	SafetyMode       string           `json:"safety_mode,omitempty"`
+	Seed             *int             `json:"seed,omitempty"`
+	StopSequences    []string         `json:"stop_sequences,omitempty"`
+	Stream           bool             `json:"stream,omitempty"`
+	StrictTools      *bool            `json:"strict_tools,omitempty"`
+	Temperature      *float64         `json:"temperature,omitempty"`
+	Thinking         *thinking        `json:"thinking,omitempty"`
+	ToolChoice       string           `json:"tool_choice,omitempty"`
+	Tools            []tool           `json:"tools,omitempty"`
+}
+
+// chatResponse is the response body of the v2 chat endpoint.
+type chatResponse struct {
+	FinishReason string          `json:"finish_reason"`
+	ID           string          `json:"id"`
+	Message      responseMessage `json:"message"`
+	Usage        *usage          `json:"usage,omitempty"`
+}
+
+// citation attributes a span of the response to its sources.
+type citation struct {
+	End     int      `json:"end"`
+	Sources []source `json:"sources"`
+	Start   int      `json:"start"`
+	Text    string   `json:"text"`
+}
+
+// citationOptions controls how citations are generated.
+type citationOptions struct {
+	Mode string `json:"mode"`
+}
+
+// contentBlock is a single block of message content.
+type contentBlock struct {
+	ImageURL *imageURL `json:"image_url,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	Thinking string    `json:"thinking,omitempty"`
+	Type     string    `json:"type,omitempty"`
+}
+
+// document is a source the model can ground its response in.
+type document struct {
+	Data map[string]any `json:"data"`
+	ID   string         `json:"id,omitempty"`
+}
+
+// embedRequest is the request body of the v2 embed endpoint.
+type embedRequest struct {
+	EmbeddingTypes  []string `json:"embedding_types"`
+	InputType       string   `json:"input_type,omitempty"`
+	Model           string   `json:"model"`
+	OutputDimension *int     `json:"output_dimension,omitempty"`
+	Texts           []string `json:"texts"`
+	Truncate        string   `json:"truncate,omitempty"`
+}
+
+// embedResponse is the response body of the v2 embed endpoint.
+type embedResponse struct {
+	Embeddings Embeddings `json:"embeddings"`
+	ID         string     `json:"id"`
+	Meta       *meta      `json:"meta,omitempty"`
+	Texts      []string   `json:"texts"`
+}
+
+// errorResponse is the body of an error response.
+type errorResponse struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// functionCall is the function invoked by a tool call.
+type functionCall struct {
+	Arguments string `json:"arguments"`
+	Name      string `json:"name,omitempty"`
+}
+
+// functionDefinition describes a function the model may call.
+type functionDefinition struct {
+	Description string         `json:"description,omitempty"`
+	Name        string         `json:"name"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// imageURL is an image sent by URL, including data URLs.
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// listModelsResponse is the response body of the v1 models endpoint.
+type listModelsResponse struct {
+	Models        []model `json:"models"`
+	NextPageToken string  `json:"next_page_token"`
+}
+
+// message is a single chat message. Content is a string or a list of content blocks.
+type message struct {
+	Content    any        `json:"content,omitempty"`
+	Role       string     `json:"role"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolPlan   string     `json:"tool_plan,omitempty"`
+}
+
+// meta holds response metadata, including billed usage.
+type meta struct {
+	BilledUnits *billedUnits `json:"billed_units,omitempty"`
+}
+
+// model is a model returned by the models endpoint.
+type model struct {
+	Endpoints []string `json:"endpoints"`
+	Name      string   `json:"name"`
+}
+
+// rerankRequest is the request body of the v2 rerank endpoint.
+type rerankRequest struct {
+	Documents       []string `json:"documents"`
+	MaxTokensPerDoc *int     `json:"max_tokens_per_doc,omitempty"`
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	TopN            *int     `json:"top_n,omitempty"`
+}
+
+// rerankResponse is the response body of the v2 rerank endpoint.
+type rerankResponse struct {
+	ID      string         `json:"id"`
+	Meta    *meta          `json:"meta,omitempty"`
+	Results []rerankResult `json:"results"`
+}
+
+// rerankResult is the relevance of a single document.
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+// responseFormat constrains the format of the response.
+type responseFormat struct {
+	JSONSchema map[string]any `json:"json_schema,omitempty"`
+	Type       string         `json:"type"`
+}
+
+// responseMessage is the assistant message of a chat response.
+type responseMessage struct {
+	Citations []citation     `json:"citations,omitempty"`
+	Content   []contentBlock `json:"content,omitempty"`
+	Role      string         `json:"role"`
+	ToolCalls []toolCall     `json:"tool_calls,omitempty"`
+	ToolPlan  string         `json:"tool_plan,omitempty"`
+}
+
+// source is a document or tool result cited by a citation.
+type source struct {
+	Document   map[string]any `json:"document,omitempty"`
+	ID         string         `json:"id"`
+	ToolOutput map[string]any `json:"tool_output,omitempty"`
+	Type       string         `json:"type"`
+}
+
+// streamDelta is the payload of a stream event.
+// Message fields hold a single block, tool call, or citation rather than lists.
+type streamDelta struct {
+	FinishReason string `json:"finish_reason,omitempty"`
+	Message      struct {
+		Citations *citation     `json:"citations,omitempty"`
+		Content   *contentBlock `json:"content,omitempty"`
+		ToolCalls *toolCall     `json:"tool_calls,omitempty"`
+		ToolPlan  string        `json:"tool_plan,omitempty"`
+	} `json:"message"`
+	Usage *usage `json:"usage,omitempty"`
+}
+
+// streamEvent is a single event of a streamed chat response.
+type streamEvent struct {
+	Delta streamDelta `json:"delta"`
+	ID    string      `json:"id,omitempty"`
+	Index int         `json:"index"`
+	Type  string      `json:"type"`
+}
+
+// thinking configures reasoning for models that support it.
+type thinking struct {
+	TokenBudget *int   `json:"token_budget,omitempty"`
+	Type        string `json:"type"`
+}
+
+// tool is a tool the model may call.
+type tool struct {
+	Function functionDefinition `json:"function"`
+	Type     string             `json:"type"`
+}
+
+// toolCall is a tool call made by the model.
+type toolCall struct {
+	Function functionCall `json:"function"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+}
+
+// usage reports billed units and the tokens actually processed.
+type usage struct {
+	BilledUnits *billedUnits `json:"billed_units,omitempty"`
+	Tokens      *billedUnits `json:"tokens,omitempty"`
+}
@@ -0,0 +1,99 @@
+// Package deepseek provides a DeepSeek provider implementation for any-llm.
+//
+// DeepSeek exposes an OpenAI-compatible API, so the provider is built on openai.CompatibleProvider.
+// The deepseek-reasoner model always reasons and returns its reasoning in reasoning_content.
+package deepseek
+
+import (
+	openaisdk "github.com/openai/openai-go"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.deepseek.com"
+	envAPIKey      = "DEEPSEEK_API_KEY"
+	providerName   = "deepseek"
+)
+
+// DeepSeek API constants.
+const (
+	paramResponseFormat = "response_format"
+	reasoningField      = "reasoning_content"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for DeepSeek.
+// It embeds openai.CompatibleProvider since DeepSeek exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a new DeepSeek provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   deepseekCapabilities(),
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+		UseMaxTokens:   true, // DeepSeek has no max_completion_tokens.
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// deepseekCapabilities returns the capabilities for the DeepSeek provider.
+func deepseekCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            false,
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // deepseek-reasoner.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: false, // JSON mode only, no JSON schemas.
+		CountTokens:                false,
+		Embedding:                  false,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// prepareRequest adapts a request to DeepSeek. DeepSeek supports JSON mode but not JSON schemas,
+// and reasoning is chosen by model rather than by effort, so reasoning_effort is not sent.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, _ providers.CompletionParams) error {
+	if req.ResponseFormat.OfJSONSchema != nil {
+		return errors.NewUnsupportedParamError(providerName, paramResponseFormat)
+	}
+
+	req.ReasoningEffort = ""
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "deepseek", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("DEEPSEEK_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("DEEPSEEK_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "deepseek", missingKeyErr.Provider)
+		require.Equal(t, "DEEPSEEK_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("adapts the request and reads reasoning_content", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"id": "chatcmpl-1",
+				"object": "chat.completion",
+				"created": 1700000000,
+				"model": "deepseek-reasoner",
+				"choices": [{
+					"index": 0,
+					"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."},
+					"finish_reason": "stop"
+				}],
+				"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+			}`))
+		}, config.WithAPIKey(testAPIKey))
+
+		maxTokens := 100
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:           "deepseek-reasoner",
+			Messages:        testutil.SimpleMessages(),
+			MaxTokens:       &maxTokens,
+			ReasoningEffort: providers.ReasoningEffortHigh,
+			ResponseFormat:  &providers.ResponseFormat{Type: "json_object"},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "/chat/completions", path)
+		require.Equal(t, float64(100), body["max_tokens"])
+		require.NotContains(t, body, "max_completion_tokens")
+		require.NotContains(t, body, "reasoning_effort")
+		require.Equal(t, map[string]any{"type": "json_object"}, body["response_format"])
+
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	t.Run("streams reasoning_content", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, data := range []string{
+				`{"id":"1","model":"m","choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"Hmm."}}]}`,
+				`{"id":"1","model":"m","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`,
+			} {
+				_, _ = w.Write([]byte("data: " + data + "\n\n"))
+			}
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}, config.WithAPIKey(testAPIKey))
+
+		chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+			Model:    "deepseek-reasoner",
+			Messages: testutil.SimpleMessages(),
+			Stream:   true,
+		})
+
+		var content, reasoning strings.Builder
+		for chunk := range chunks {
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			content.WriteString(chunk.Choices[0].Delta.Content)
+			if chunk.Choices[0].Delta.Reasoning != nil {
+				reasoning.WriteString(chunk.Choices[0].Delta.Reasoning.Content)
+			}
+		}
+		require.NoError(t, <-errs)
+
+		require.Equal(t, "Hi", content.String())
+		require.Equal(t, "Hmm.", reasoning.String())
+	})
+
+	t.Run("rejects JSON schemas", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "deepseek-chat",
+			Messages: testutil.SimpleMessages(),
+			ResponseFormat: &providers.ResponseFormat{
+				Type:       "json_schema",
+				JSONSchema: &providers.JSONSchema{Name: "answer", Schema: map[string]any{"type": "object"}},
+			},
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("deepseek") {
+		t.Skip("DEEPSEEK_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("deepseek"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestIntegrationReasoning(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("deepseek") {
+		t.Skip("DEEPSEEK_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.ReasoningModel("deepseek"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Choices[0].Message.Reasoning)
+}
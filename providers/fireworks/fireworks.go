@@ -0,0 +1,82 @@
+// Package fireworks provides a Fireworks AI provider implementation for any-llm.
+//
+// Fireworks AI exposes an OpenAI-compatible inference API, so the provider is built on
+// openai.CompatibleProvider.
+package fireworks
+
+import (
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.fireworks.ai/inference/v1"
+	envAPIKey      = "FIREWORKS_API_KEY"
+	providerName   = "fireworks"
+)
+
+// Fireworks AI API constants.
+const (
+	reasoningField = "reasoning_content"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Fireworks AI.
+// It embeds openai.CompatibleProvider since Fireworks AI exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a new Fireworks AI provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   fireworksCapabilities(),
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+		UseMaxTokens:   true, // Fireworks AI has no max_completion_tokens.
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// fireworksCapabilities returns the capabilities for the Fireworks AI provider.
+func fireworksCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Vision models such as Llama 4.
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // DeepSeek R1 and Qwen 3 models.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 false, // Models are listed by the separate account API.
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
@@ -0,0 +1,110 @@
+package fireworks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "fireworks", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("FIREWORKS_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("FIREWORKS_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "fireworks", missingKeyErr.Provider)
+		require.Equal(t, "FIREWORKS_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	var path, auth string
+	var body map[string]any
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"created": 1700000000,
+			"model": "accounts/fireworks/models/deepseek-r1",
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."},
+				"finish_reason": "stop"
+			}],
+			"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+		}`))
+	}, config.WithAPIKey(testAPIKey))
+
+	maxTokens := 100
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:     "accounts/fireworks/models/deepseek-r1",
+		Messages:  testutil.SimpleMessages(),
+		MaxTokens: &maxTokens,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "/chat/completions", path)
+	require.Equal(t, "Bearer "+testAPIKey, auth)
+	require.Equal(t, float64(100), body["max_tokens"])
+	require.NotContains(t, body, "max_completion_tokens")
+
+	require.Equal(t, "4", resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Choices[0].Message.Reasoning)
+	require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("fireworks") {
+		t.Skip("FIREWORKS_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("fireworks"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
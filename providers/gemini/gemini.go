@@ -0,0 +1,1232 @@
+// Package gemini provides a Google Gemini provider implementation for any-llm.
+//
+// The provider calls the Gemini REST API (generativelanguage.googleapis.com) directly.
+package gemini
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/internal/sse"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	envAPIKey      = "GEMINI_API_KEY"
+	providerName   = "gemini"
+)
+
+// Gemini REST API constants.
+const (
+	contentTypeJSON    = "application/json"
+	headerAPIKey       = "x-goog-api-key"
+	headerContentType  = "Content-Type"
+	listModelsPageSize = 1000
+	maxErrorBodySize   = 64 << 10
+	methodBatchEmbed   = "batchEmbedContents"
+	methodGenerate     = "generateContent"
+	methodStream       = "streamGenerateContent?alt=sse"
+	modelPrefix        = "models/"
+	ownerGoogle        = "google"
+)
+
+// Gemini content roles.
+const (
+	roleModel = "model"
+	roleUser  = "user"
+)
+
+// Gemini finish reasons.
+const (
+	finishReasonBlocklist         = "BLOCKLIST"
+	finishReasonImageSafety       = "IMAGE_SAFETY"
+	finishReasonMaxTokens         = "MAX_TOKENS"
+	finishReasonProhibitedContent = "PROHIBITED_CONTENT"
+	finishReasonRecitation        = "RECITATION"
+	finishReasonSafety            = "SAFETY"
+	finishReasonSPII              = "SPII"
+)
+
+// Gemini function calling modes.
+const (
+	functionModeAny  = "ANY"
+	functionModeAuto = "AUTO"
+	functionModeNone = "NONE"
+)
+
+// Extra parameters sent in generationConfig, except safety_settings, which becomes the top-level
+// safetySettings. Gemini fails on unknown fields, so other keys in CompletionParams.Extra are rejected.
+const (
+	extraFrequencyPenalty = "frequency_penalty"
+	extraPresencePenalty  = "presence_penalty"
+	extraSafetySettings   = "safety_settings"
+	extraTopK             = "top_k"
+)
+
+// Gemini error message patterns.
+const (
+	errorPatternAPIKey  = "API key"
+	errorPatternExceeds = "exceeds"
+	errorPatternToken   = "token"
+)
+
+// Thinking budgets. A budget of -1 lets the model decide how much to think.
+const (
+	thinkingBudgetDynamic = -1
+	thinkingBudgetHigh    = 24576
+	thinkingBudgetLow     = 1024
+	thinkingBudgetMedium  = 8192
+	thinkingBudgetNone    = 0
+)
+
+// JSON Schema keywords handled when converting schemas. Gemini does not support references,
+// so recursive schemas are inlined up to maxSchemaRefDepth levels and then left unconstrained.
+const (
+	maxSchemaRefDepth   = 3
+	schemaDefsPrefix    = "#/$defs/"
+	schemaKeyDefs       = "$defs"
+	schemaKeyEnum       = "enum"
+	schemaKeyProperties = "properties"
+	schemaKeyRef        = "$ref"
+	schemaKeyType       = "type"
+	schemaRootRef       = "#"
+	schemaTypeObject    = "object"
+	schemaTypeString    = "string"
+)
+
+// Tool and response format constants.
+const (
+	emptyJSONObject          = "{}"
+	responseFormatJSONObject = "json_object"
+	responseFormatJSONSchema = "json_schema"
+	toolCallIDPrefix         = "call_"
+	toolResultKey            = "result"
+	toolTypeFunction         = "function"
+)
+
+// Object type constants.
+const (
+	objectChatCompletion      = "chat.completion"
+	objectChatCompletionChunk = "chat.completion.chunk"
+	objectEmbedding           = "embedding"
+	objectList                = "list"
+	objectModel               = "model"
+)
+
+// Content part constants.
+const (
+	contentTypeFile       = "file"
+	contentTypeImageURL   = "image_url"
+	contentTypeInputAudio = "input_audio"
+	contentTypeText       = "text"
+	dataURLPrefix         = "data:"
+	defaultFileMimeType   = "application/pdf"
+	defaultImageMimeType  = "image/jpeg"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+// unsupportedSchemaKeys are JSON Schema keywords that the Gemini schema format rejects.
+var unsupportedSchemaKeys = map[string]bool{
+	"$comment":             true,
+	"$defs":                true,
+	"$id":                  true,
+	"$schema":              true,
+	"additionalProperties": true,
+	"contentEncoding":      true,
+	"contentMediaType":     true,
+	"definitions":          true,
+	"examples":             true,
+}
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Google Gemini.
+type Provider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+	config  *config.Config
+}
+
+// schemaCleaner converts one JSON Schema to Gemini's schema format.
+type schemaCleaner struct {
+	defs map[string]any
+	// inlined counts how often each reference is being inlined on the current path, to bound recursion.
+	inlined map[string]int
+	root    map[string]any
+}
+
+// streamState tracks accumulated state during streaming.
+// Note: Only accessed from a single goroutine, so no synchronization needed.
+type streamState struct {
+	id        string
+	model     string
+	created   int64
+	sentRole  bool
+	toolCalls int
+}
+
+// New creates a new Gemini provider.
+func New(opts ...config.Option) (*Provider, error) {
+	cfg, err := config.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	apiKey := cfg.ResolveAPIKey(envAPIKey)
+	if apiKey == "" {
+		return nil, errors.NewMissingAPIKeyError(providerName, envAPIKey)
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &Provider{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  cfg.HTTPClient(),
+		config:  cfg,
+	}, nil
+}
+
+// Capabilities returns the provider's capabilities.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionStreaming:        true,
+		CompletionReasoning:        true,
+		CompletionImage:            true,
+		CompletionPDF:              true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// Completion performs a chat completion request.
+func (p *Provider) Completion(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.ChatCompletion, error) {
+	req, err := convertParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp generateContentResponse
+	if err := p.do(ctx, http.MethodPost, modelPath(params.Model, methodGenerate), req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	if err := promptBlockedError(&resp); err != nil {
+		return nil, err
+	}
+
+	return convertResponse(&resp, params.Model), nil
+}
+
+// CompletionStream performs a streaming chat completion request.
+func (p *Provider) CompletionStream(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		req, err := convertParams(params)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		body, err := p.send(ctx, http.MethodPost, modelPath(params.Model, methodStream), req)
+		if err != nil {
+			errs <- p.ConvertError(err)
+			return
+		}
+		defer func() { _ = body.Close() }()
+
+		state := newStreamState(params.Model)
+		reader := sse.NewReader(body)
+
+		for reader.Next() {
+			var resp generateContentResponse
+			if err := json.Unmarshal([]byte(reader.Event().Data), &resp); err != nil {
+				errs <- errors.NewProviderError(providerName, fmt.Errorf("decoding stream event: %w", err))
+				return
+			}
+
+			if err := promptBlockedError(&resp); err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case chunks <- state.handleResponse(&resp):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := reader.Err(); err != nil {
+			errs <- p.ConvertError(err)
+		}
+	}()
+
+	return chunks, errs
+}
+
+// ConvertError converts a Gemini API error to a unified error type.
+// Implements providers.ErrorConverter.
+func (p *Provider) ConvertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Errors other than API errors (e.g., network errors) are generic provider errors.
+	var apiErr *apiError
+	if !stderrors.As(err, &apiErr) {
+		return errors.NewProviderError(providerName, err)
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest:
+		// Gemini reports invalid API keys and oversized prompts as 400 errors.
+		if strings.Contains(apiErr.Message, errorPatternAPIKey) {
+			return errors.NewAuthenticationError(providerName, err)
+		}
+		if strings.Contains(apiErr.Message, errorPatternExceeds) && strings.Contains(apiErr.Message, errorPatternToken) {
+			return errors.NewContextLengthError(providerName, err)
+		}
+		return errors.NewInvalidRequestError(providerName, err)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errors.NewAuthenticationError(providerName, err)
+	case http.StatusNotFound:
+		return errors.NewModelNotFoundError(providerName, err)
+	case http.StatusTooManyRequests:
+		rateLimitErr := errors.NewRateLimitError(providerName, err)
+		rateLimitErr.RetryAfter = errors.RetryAfterFromHeader(apiErr.Header)
+		return rateLimitErr
+	default:
+		providerErr := errors.NewProviderError(providerName, err)
+		providerErr.StatusCode = apiErr.StatusCode
+		return providerErr
+	}
+}
+
+// Embedding generates embeddings for the given input.
+func (p *Provider) Embedding(
+	ctx context.Context,
+	params providers.EmbeddingParams,
+) (*providers.EmbeddingResponse, error) {
+	inputs, err := embeddingInputs(params.Input)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, err)
+	}
+
+	model := modelName(params.Model)
+	req := batchEmbedContentsRequest{Requests: make([]embedContentRequest, 0, len(inputs))}
+	for _, input := range inputs {
+		req.Requests = append(req.Requests, embedContentRequest{
+			Content:              content{Parts: []part{{Text: input}}},
+			Model:                model,
+			OutputDimensionality: params.Dimensions,
+		})
+	}
+
+	var resp batchEmbedContentsResponse
+	if err := p.do(ctx, http.MethodPost, modelPath(params.Model, methodBatchEmbed), req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertEmbeddingResponse(&resp, params.Model), nil
+}
+
+// ListModels returns a list of available models, following pagination to the last page.
+func (p *Provider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
+	models := make([]providers.Model, 0)
+	query := url.Values{"pageSize": {strconv.Itoa(listModelsPageSize)}}
+
+	for {
+		var resp listModelsResponse
+		if err := p.do(ctx, http.MethodGet, "models?"+query.Encode(), nil, &resp); err != nil {
+			return nil, p.ConvertError(err)
+		}
+
+		for _, m := range resp.Models {
+			models = append(models, providers.Model{
+				ID:      strings.TrimPrefix(m.Name, modelPrefix),
+				Object:  objectModel,
+				OwnedBy: ownerGoogle,
+			})
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", resp.NextPageToken)
+	}
+
+	return &providers.ModelsResponse{
+		Object: objectList,
+		Data:   models,
+	}, nil
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return providerName
+}
+
+// do sends a request and decodes the JSON response body into out.
+func (p *Provider) do(ctx context.Context, method, path string, body, out any) error {
+	respBody, err := p.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = respBody.Close() }()
+
+	if err := json.NewDecoder(respBody).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// send sends a request with an optional JSON body and returns the response body.
+// Error responses are returned as an *apiError.
+func (p *Provider) send(ctx context.Context, method, path string, body any) (io.ReadCloser, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+"/"+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerAPIKey, p.apiKey)
+	if body != nil {
+		req.Header.Set(headerContentType, contentTypeJSON)
+	}
+	p.config.ApplyHeaders(req.Header)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newAPIError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// newStreamState creates a new stream state.
+func newStreamState(model string) *streamState {
+	return &streamState{
+		id:      generateID(),
+		model:   model,
+		created: time.Now().Unix(),
+	}
+}
+
+// handleResponse converts a streamed response to a chunk.
+func (s *streamState) handleResponse(resp *generateContentResponse) providers.ChatCompletionChunk {
+	if resp.ModelVersion != "" {
+		s.model = resp.ModelVersion
+	}
+
+	chunk := providers.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  objectChatCompletionChunk,
+		Created: s.created,
+		Model:   s.model,
+		Choices: make([]providers.ChunkChoice, 0, len(resp.Candidates)),
+	}
+
+	finished := false
+	for _, c := range resp.Candidates {
+		text, reasoning, toolCalls := convertParts(c.Content.Parts, s.toolCalls)
+		s.toolCalls += len(toolCalls)
+
+		delta := providers.ChunkDelta{
+			Content:   text,
+			ToolCalls: toolCalls,
+		}
+		if reasoning != "" {
+			delta.Reasoning = &providers.Reasoning{Content: reasoning}
+		}
+		if !s.sentRole {
+			delta.Role = providers.RoleAssistant
+			s.sentRole = true
+		}
+
+		finishReason := convertFinishReason(c.FinishReason, s.toolCalls > 0)
+		finished = finished || finishReason != ""
+
+		chunk.Choices = append(chunk.Choices, providers.ChunkChoice{
+			Index:        c.Index,
+			Delta:        delta,
+			FinishReason: finishReason,
+			Logprobs:     convertLogprobs(c.LogprobsResult),
+		})
+	}
+
+	// Usage is reported on every event, so only the final one is forwarded.
+	if finished {
+		chunk.Usage = convertUsage(resp.UsageMetadata)
+	}
+
+	return chunk
+}
+
+// clean returns a copy of schema in Gemini's format.
+func (c *schemaCleaner) clean(schema map[string]any) map[string]any {
+	result := make(map[string]any, len(schema))
+	if ref, ok := schema[schemaKeyRef].(string); ok {
+		maps.Copy(result, c.resolve(ref))
+	}
+
+	var enumConverted bool
+	for key, value := range schema {
+		switch {
+		case key == schemaKeyRef || unsupportedSchemaKeys[key]:
+			continue
+		case key == schemaKeyEnum:
+			result[key], enumConverted = enumStrings(value)
+		case key == schemaKeyProperties:
+			properties, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			cleaned := make(map[string]any, len(properties))
+			for name, property := range properties {
+				cleaned[name] = c.value(property)
+			}
+			result[key] = cleaned
+		default:
+			result[key] = c.value(value)
+		}
+	}
+
+	// Gemini only accepts string enums, so an enum of other values also makes the schema a string.
+	if enumConverted {
+		result[schemaKeyType] = schemaTypeString
+	}
+
+	return result
+}
+
+// resolve returns the cleaned schema that ref points to. References that cannot be resolved, and
+// recursive references nested more than maxSchemaRefDepth times, become an unconstrained object.
+func (c *schemaCleaner) resolve(ref string) map[string]any {
+	var target map[string]any
+	switch {
+	case ref == schemaRootRef:
+		target = c.root
+	case strings.HasPrefix(ref, schemaDefsPrefix):
+		target, _ = c.defs[strings.TrimPrefix(ref, schemaDefsPrefix)].(map[string]any)
+	}
+	if target == nil || c.inlined[ref] >= maxSchemaRefDepth {
+		return map[string]any{schemaKeyType: schemaTypeObject}
+	}
+
+	c.inlined[ref]++
+	defer func() { c.inlined[ref]-- }()
+
+	return c.clean(target)
+}
+
+// value cleans the schemas nested in a JSON Schema value.
+func (c *schemaCleaner) value(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return c.clean(v)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = c.value(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// applyExtra decodes provider-specific parameters into req and its generation config.
+func applyExtra(req *generateContentRequest, params map[string]any) error {
+	for _, key := range extra.Keys(params) {
+		value := params[key]
+
+		var err error
+		switch key {
+		case extraFrequencyPenalty:
+			err = extra.Decode(key, value, &req.GenerationConfig.FrequencyPenalty)
+		case extraPresencePenalty:
+			err = extra.Decode(key, value, &req.GenerationConfig.PresencePenalty)
+		case extraSafetySettings:
+			err = extra.Decode(key, value, &req.SafetySettings)
+		case extraTopK:
+			err = extra.Decode(key, value, &req.GenerationConfig.TopK)
+		default:
+			return errors.NewUnsupportedParamError(providerName, key)
+		}
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	return nil
+}
+
+// cleanSchema returns a copy of a JSON Schema in the form Gemini accepts. References are inlined from
+// $defs, keywords Gemini rejects are dropped, and enum values are converted to strings.
+func cleanSchema(schema map[string]any) map[string]any {
+	if schema == nil {
+		return nil
+	}
+
+	defs, _ := schema[schemaKeyDefs].(map[string]any)
+	c := &schemaCleaner{root: schema, defs: defs, inlined: make(map[string]int)}
+	return c.clean(schema)
+}
+
+// convertAssistantMessage converts an assistant message to Gemini format.
+func convertAssistantMessage(msg providers.Message) content {
+	parts := make([]part, 0, 1+len(msg.ToolCalls))
+	if text := msg.ContentString(); text != "" {
+		parts = append(parts, part{Text: text})
+	}
+
+	for _, tc := range msg.ToolCalls {
+		var args map[string]any
+		_ = json.Unmarshal([]byte(tc.Function.Arguments), &args) // Ignore error: use nil on failure.
+
+		parts = append(parts, part{
+			FunctionCall:     &functionCall{Name: tc.Function.Name, Args: args},
+			ThoughtSignature: tc.Signature,
+		})
+	}
+
+	return content{Role: roleModel, Parts: parts}
+}
+
+// convertEmbeddingResponse converts a Gemini embedding response to provider format.
+func convertEmbeddingResponse(resp *batchEmbedContentsResponse, model string) *providers.EmbeddingResponse {
+	data := make([]providers.EmbeddingData, 0, len(resp.Embeddings))
+	for i, e := range resp.Embeddings {
+		data = append(data, providers.EmbeddingData{
+			Object:    objectEmbedding,
+			Embedding: e.Values,
+			Index:     i,
+		})
+	}
+
+	return &providers.EmbeddingResponse{
+		Object: objectList,
+		Data:   data,
+		Model:  model,
+	}
+}
+
+// convertFinishReason converts a Gemini finish reason to OpenAI format.
+// An empty reason means the candidate is not finished and is returned unchanged.
+func convertFinishReason(reason string, hasToolCalls bool) string {
+	switch reason {
+	case "":
+		return ""
+	case finishReasonMaxTokens:
+		return providers.FinishReasonLength
+	case finishReasonBlocklist,
+		finishReasonImageSafety,
+		finishReasonProhibitedContent,
+		finishReasonRecitation,
+		finishReasonSafety,
+		finishReasonSPII:
+		return providers.FinishReasonContentFilter
+	}
+
+	if hasToolCalls {
+		return providers.FinishReasonToolCalls
+	}
+
+	return providers.FinishReasonStop
+}
+
+// convertFilePart converts a file to a Gemini part.
+// Data is sent inline; URLs and the URIs of files uploaded with the Files API are sent as file references.
+func convertFilePart(file *providers.File) part {
+	mimeType := cmp.Or(file.MIMEType, defaultFileMimeType)
+	if file.Data != "" {
+		return part{InlineData: &blob{MimeType: mimeType, Data: file.Data}}
+	}
+
+	return part{FileData: &fileData{FileURI: cmp.Or(file.URL, file.FileID), MimeType: mimeType}}
+}
+
+// convertImagePart converts an image URL to a Gemini part.
+// Data URLs are sent inline; other URLs are sent as file references.
+func convertImagePart(img *providers.ImageURL) part {
+	if strings.HasPrefix(img.URL, dataURLPrefix) {
+		// Parse data URL: data:image/jpeg;base64,<data>.
+		header, data, ok := strings.Cut(strings.TrimPrefix(img.URL, dataURLPrefix), ",")
+		if ok {
+			mimeType, _, _ := strings.Cut(header, ";")
+			return part{InlineData: &blob{MimeType: mimeType, Data: data}}
+		}
+	}
+
+	mimeType := defaultImageMimeType
+	if u, err := url.Parse(img.URL); err == nil {
+		if t := mime.TypeByExtension(path.Ext(u.Path)); t != "" {
+			mimeType = t
+		}
+	}
+
+	return part{FileData: &fileData{FileURI: img.URL, MimeType: mimeType}}
+}
+
+// convertLogprobs converts Gemini log probabilities to provider format.
+// It returns nil when there are none.
+func convertLogprobs(result *logprobsResult) *providers.Logprobs {
+	if result == nil || len(result.ChosenCandidates) == 0 {
+		return nil
+	}
+
+	logprobs := &providers.Logprobs{Content: make([]providers.TokenLogprob, 0, len(result.ChosenCandidates))}
+	for i, chosen := range result.ChosenCandidates {
+		token := providers.TokenLogprob{
+			Token:   chosen.Token,
+			Logprob: chosen.LogProbability,
+		}
+		if i < len(result.TopCandidates) {
+			for _, top := range result.TopCandidates[i].Candidates {
+				token.TopLogprobs = append(token.TopLogprobs, providers.TopLogprob{
+					Token:   top.Token,
+					Logprob: top.LogProbability,
+				})
+			}
+		}
+		logprobs.Content = append(logprobs.Content, token)
+	}
+
+	return logprobs
+}
+
+// convertMessages converts provider messages to Gemini contents.
+// System messages are combined into the returned system instruction, and the results of
+// consecutive tool calls are grouped into a single turn, as Gemini requires.
+func convertMessages(messages []providers.Message) ([]content, *content, error) {
+	contents := make([]content, 0, len(messages))
+	var system []part
+
+	// Gemini identifies function results by name, so remember the name of each tool call.
+	toolNames := make(map[string]string)
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case providers.RoleSystem:
+			system = append(system, part{Text: msg.ContentString()})
+		case providers.RoleUser:
+			contents = append(contents, convertUserMessage(msg))
+		case providers.RoleAssistant:
+			for _, tc := range msg.ToolCalls {
+				toolNames[tc.ID] = tc.Function.Name
+			}
+			contents = append(contents, convertAssistantMessage(msg))
+		case providers.RoleTool:
+			result, err := convertToolResult(msg, toolNames)
+			if err != nil {
+				return nil, nil, err
+			}
+			if n := len(contents); n > 0 && isToolResults(contents[n-1]) {
+				contents[n-1].Parts = append(contents[n-1].Parts, result)
+				continue
+			}
+			contents = append(contents, content{Role: roleUser, Parts: []part{result}})
+		default:
+			return nil, nil, fmt.Errorf("unsupported message role %q", msg.Role)
+		}
+	}
+
+	if len(system) == 0 {
+		return contents, nil, nil
+	}
+
+	return contents, &content{Parts: system}, nil
+}
+
+// convertParams converts providers.CompletionParams to a Gemini request.
+func convertParams(params providers.CompletionParams) (*generateContentRequest, error) {
+	if hasContentPart(params.Messages, contentTypeInputAudio) {
+		return nil, errors.NewUnsupportedParamError(providerName, contentTypeInputAudio)
+	}
+	if params.Audio != nil || slices.Contains(params.Modalities, providers.ModalityAudio) {
+		return nil, errors.NewUnsupportedParamError(providerName, "audio")
+	}
+
+	contents, system, err := convertMessages(params.Messages)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, err)
+	}
+
+	req := &generateContentRequest{
+		Contents:          contents,
+		SystemInstruction: system,
+		GenerationConfig: &generationConfig{
+			MaxOutputTokens: params.MaxTokens,
+			Seed:            params.Seed,
+			StopSequences:   params.Stop,
+			Temperature:     params.Temperature,
+			ThinkingConfig:  convertReasoningEffort(params.ReasoningEffort),
+			TopP:            params.TopP,
+		},
+	}
+
+	if params.Logprobs || params.TopLogprobs != nil {
+		req.GenerationConfig.ResponseLogprobs = true
+		req.GenerationConfig.Logprobs = params.TopLogprobs
+	}
+
+	if params.ResponseFormat != nil {
+		switch params.ResponseFormat.Type {
+		case responseFormatJSONObject:
+			req.GenerationConfig.ResponseMimeType = contentTypeJSON
+		case responseFormatJSONSchema:
+			req.GenerationConfig.ResponseMimeType = contentTypeJSON
+			if params.ResponseFormat.JSONSchema != nil {
+				req.GenerationConfig.ResponseSchema = cleanSchema(params.ResponseFormat.JSONSchema.Schema)
+			}
+		}
+	}
+
+	if len(params.Tools) > 0 {
+		req.Tools = convertTools(params.Tools)
+	}
+
+	if params.ToolChoice != nil {
+		req.ToolConfig = convertToolChoice(params.ToolChoice)
+	}
+
+	if err := applyExtra(req, params.Extra); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// convertParts splits Gemini parts into text, reasoning, and tool calls.
+// Tool calls are numbered from firstIndex, so that calls streamed in separate events get distinct indices.
+func convertParts(parts []part, firstIndex int) (string, string, []providers.ToolCall) {
+	var text, reasoning strings.Builder
+	var toolCalls []providers.ToolCall
+
+	for _, p := range parts {
+		switch {
+		case p.FunctionCall != nil:
+			index := firstIndex + len(toolCalls)
+
+			args := emptyJSONObject
+			if len(p.FunctionCall.Args) > 0 {
+				if argsBytes, err := json.Marshal(p.FunctionCall.Args); err == nil {
+					args = string(argsBytes)
+				}
+			}
+
+			id := p.FunctionCall.ID
+			if id == "" {
+				id = generateToolCallID()
+			}
+
+			toolCalls = append(toolCalls, providers.ToolCall{
+				Index: index,
+				ID:    id,
+				Type:  toolTypeFunction,
+				Function: providers.FunctionCall{
+					Name:      p.FunctionCall.Name,
+					Arguments: args,
+				},
+				Signature: p.ThoughtSignature,
+			})
+		case p.Thought:
+			reasoning.WriteString(p.Text)
+		default:
+			text.WriteString(p.Text)
+		}
+	}
+
+	return text.String(), reasoning.String(), toolCalls
+}
+
+// convertReasoningEffort converts a reasoning effort to a Gemini thinking configuration.
+// It returns nil when no effort is set, leaving the model's default.
+func convertReasoningEffort(effort providers.ReasoningEffort) *thinkingConfig {
+	if effort == "" {
+		return nil
+	}
+
+	budget, ok := thinkingBudget(effort)
+	if !ok {
+		return nil
+	}
+
+	return &thinkingConfig{
+		IncludeThoughts: budget != thinkingBudgetNone,
+		ThinkingBudget:  &budget,
+	}
+}
+
+// convertResponse converts a Gemini response to provider format.
+func convertResponse(resp *generateContentResponse, model string) *providers.ChatCompletion {
+	choices := make([]providers.Choice, 0, len(resp.Candidates))
+	for _, c := range resp.Candidates {
+		text, reasoning, toolCalls := convertParts(c.Content.Parts, 0)
+
+		message := providers.Message{
+			Role:      providers.RoleAssistant,
+			Content:   text,
+			ToolCalls: toolCalls,
+		}
+		if reasoning != "" {
+			message.Reasoning = &providers.Reasoning{Content: reasoning}
+		}
+
+		choices = append(choices, providers.Choice{
+			Index:        c.Index,
+			Message:      message,
+			FinishReason: convertFinishReason(c.FinishReason, len(toolCalls) > 0),
+			Logprobs:     convertLogprobs(c.LogprobsResult),
+		})
+	}
+
+	id := resp.ResponseID
+	if id == "" {
+		id = generateID()
+	}
+
+	if resp.ModelVersion != "" {
+		model = resp.ModelVersion
+	}
+
+	return &providers.ChatCompletion{
+		ID:      id,
+		Object:  objectChatCompletion,
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: choices,
+		Usage:   convertUsage(resp.UsageMetadata),
+	}
+}
+
+// convertToolChoice converts a provider tool choice to a Gemini tool configuration.
+func convertToolChoice(choice any) *toolConfig {
+	switch v := choice.(type) {
+	case string:
+		switch v {
+		case "auto":
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: functionModeAuto}}
+		case "none":
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: functionModeNone}}
+		case "required", "any":
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: functionModeAny}}
+		}
+	case providers.ToolChoice:
+		if v.Function != nil {
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{
+				Mode:                 functionModeAny,
+				AllowedFunctionNames: []string{v.Function.Name},
+			}}
+		}
+	}
+
+	return nil
+}
+
+// convertToolResult converts a tool result message to a Gemini function response part.
+// JSON object results are sent as-is; anything else is wrapped in a "result" field.
+func convertToolResult(msg providers.Message, toolNames map[string]string) (part, error) {
+	name := toolNames[msg.ToolCallID]
+	if name == "" {
+		name = msg.Name
+	}
+	if name == "" {
+		return part{}, fmt.Errorf("tool message refers to unknown tool call %q", msg.ToolCallID)
+	}
+
+	result := msg.ContentString()
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil || response == nil {
+		response = map[string]any{toolResultKey: result}
+	}
+
+	return part{FunctionResponse: &functionResponse{Name: name, Response: response}}, nil
+}
+
+// convertTools converts provider tools to Gemini function declarations.
+func convertTools(tools []providers.Tool) []tool {
+	declarations := make([]functionDeclaration, 0, len(tools))
+	for _, t := range tools {
+		declarations = append(declarations, functionDeclaration{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			Parameters:  cleanSchema(t.Function.Parameters),
+		})
+	}
+
+	return []tool{{FunctionDeclarations: declarations}}
+}
+
+// convertUsage converts Gemini usage metadata to provider format.
+// Completion tokens include thinking tokens, as in OpenAI's format.
+func convertUsage(usage *usageMetadata) *providers.Usage {
+	if usage == nil {
+		return nil
+	}
+
+	completionTokens := usage.CandidatesTokenCount + usage.ThoughtsTokenCount
+
+	totalTokens := usage.TotalTokenCount
+	if totalTokens == 0 {
+		totalTokens = usage.PromptTokenCount + completionTokens
+	}
+
+	return &providers.Usage{
+		PromptTokens:     usage.PromptTokenCount,
+		CompletionTokens: completionTokens,
+		TotalTokens:      totalTokens,
+		ReasoningTokens:  usage.ThoughtsTokenCount,
+	}
+}
+
+// convertUserMessage converts a user message to Gemini format.
+func convertUserMessage(msg providers.Message) content {
+	if !msg.IsMultiModal() {
+		return content{Role: roleUser, Parts: []part{{Text: msg.ContentString()}}}
+	}
+
+	parts := make([]part, 0)
+	for _, p := range msg.ContentParts() {
+		switch p.Type {
+		case contentTypeText:
+			parts = append(parts, part{Text: p.Text})
+		case contentTypeImageURL:
+			if p.ImageURL != nil {
+				parts = append(parts, convertImagePart(p.ImageURL))
+			}
+		case contentTypeFile:
+			if p.File != nil {
+				parts = append(parts, convertFilePart(p.File))
+			}
+		}
+	}
+
+	return content{Role: roleUser, Parts: parts}
+}
+
+// embeddingInputs returns the texts to embed from an embedding input.
+func embeddingInputs(input any) ([]string, error) {
+	switch v := input.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []any:
+		inputs := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported embedding input element of type %T", item)
+			}
+			inputs = append(inputs, s)
+		}
+		return inputs, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding input of type %T", input)
+	}
+}
+
+// enumStrings returns the values of an enum as strings, and whether any value was not a string.
+// Values that are not a list are returned unchanged.
+func enumStrings(value any) (any, bool) {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return value, false
+	}
+
+	var converted bool
+	result := make([]any, list.Len())
+	for i := range list.Len() {
+		item := list.Index(i).Interface()
+		if str, ok := item.(string); ok {
+			result[i] = str
+			continue
+		}
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			encoded = []byte(fmt.Sprint(item))
+		}
+		result[i] = string(encoded)
+		converted = true
+	}
+
+	return result, converted
+}
+
+// generateID generates a unique ID for responses that do not carry one.
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("chatcmpl-%d-%s", time.Now().UnixNano(), hex.EncodeToString(b))
+}
+
+// generateToolCallID generates a unique ID for a function call that Gemini did not identify.
+// IDs must stay unique across turns, since tool results are matched to calls by ID.
+func generateToolCallID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return toolCallIDPrefix + hex.EncodeToString(b)
+}
+
+// hasContentPart reports whether any message has a content part of the given type.
+func hasContentPart(messages []providers.Message, partType string) bool {
+	for _, msg := range messages {
+		for _, part := range msg.ContentParts() {
+			if part.Type == partType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isToolResults reports whether c is a turn holding function responses.
+func isToolResults(c content) bool {
+	return c.Role == roleUser && len(c.Parts) > 0 && c.Parts[0].FunctionResponse != nil
+}
+
+// modelName returns the resource name of a model, e.g. "models/gemini-2.0-flash".
+// Names that already include a collection, such as tuned models, are returned unchanged.
+func modelName(model string) string {
+	if strings.Contains(model, "/") {
+		return model
+	}
+
+	return modelPrefix + model
+}
+
+// modelPath returns the API path of a method called on a model.
+func modelPath(model, method string) string {
+	return modelName(model) + ":" + method
+}
+
+// newAPIError builds an *apiError from an error response.
+func newAPIError(resp *http.Response) *apiError {
+	apiErr := &apiError{
+		Header:     resp.Header,
+		StatusCode: resp.StatusCode,
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		apiErr.Message = errResp.Error.Message
+		apiErr.Status = errResp.Error.Status
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
+
+// promptBlockedError returns a ContentFilterError if Gemini blocked the prompt, or nil.
+func promptBlockedError(resp *generateContentResponse) error {
+	if resp.PromptFeedback == nil || resp.PromptFeedback.BlockReason == "" {
+		return nil
+	}
+
+	return errors.NewContentFilterError(
+		providerName,
+		fmt.Errorf("prompt blocked: %s", resp.PromptFeedback.BlockReason),
+	)
+}
+
+// thinkingBudget returns the thinking token budget for the given reasoning effort.
+// Returns the budget and true if the effort level is supported, or 0 and false otherwise.
+func thinkingBudget(effort providers.ReasoningEffort) (int, bool) {
+	switch effort {
+	case providers.ReasoningEffortNone:
+		return thinkingBudgetNone, true
+	case providers.ReasoningEffortAuto:
+		return thinkingBudgetDynamic, true
+	case providers.ReasoningEffortLow:
+		return thinkingBudgetLow, true
+	case providers.ReasoningEffortMedium:
+		return thinkingBudgetMedium, true
+	case providers.ReasoningEffortHigh:
+		return thinkingBudgetHigh, true
+	default:
+		return 0, false
+	}
+}
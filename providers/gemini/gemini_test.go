@@ -0,0 +1,1040 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+// writeJSON writes v as a JSON response.
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, v any) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "gemini", provider.Name())
+		require.Equal(t, defaultBaseURL, provider.baseURL)
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("GEMINI_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "env-api-key", provider.apiKey)
+	})
+
+	t.Run("uses custom base URL", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL("http://localhost:8080/v1beta/"))
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:8080/v1beta", provider.baseURL)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("GEMINI_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "gemini", missingKeyErr.Provider)
+		require.Equal(t, "GEMINI_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(config.WithAPIKey(testAPIKey))
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.True(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ListModels)
+}
+
+func TestConvertMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves system messages to the system instruction", func(t *testing.T) {
+		t.Parallel()
+
+		contents, system, err := convertMessages([]providers.Message{
+			{Role: providers.RoleSystem, Content: "Be brief."},
+			{Role: providers.RoleSystem, Content: "Be kind."},
+			{Role: providers.RoleUser, Content: "Hello"},
+			{Role: providers.RoleAssistant, Content: "Hi!"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &content{Parts: []part{{Text: "Be brief."}, {Text: "Be kind."}}}, system)
+		require.Equal(t, []content{
+			{Role: roleUser, Parts: []part{{Text: "Hello"}}},
+			{Role: roleModel, Parts: []part{{Text: "Hi!"}}},
+		}, contents)
+	})
+
+	t.Run("omits the system instruction without system messages", func(t *testing.T) {
+		t.Parallel()
+
+		_, system, err := convertMessages(testutil.SimpleMessages())
+		require.NoError(t, err)
+		require.Nil(t, system)
+	})
+
+	t.Run("converts tool calls and groups their results", func(t *testing.T) {
+		t.Parallel()
+
+		contents, _, err := convertMessages([]providers.Message{
+			{Role: providers.RoleUser, Content: "Weather in Paris and London?"},
+			{Role: providers.RoleAssistant, ToolCalls: []providers.ToolCall{
+				{ID: "call_0", Type: "function", Function: providers.FunctionCall{
+					Name: "get_weather", Arguments: `{"location":"Paris"}`,
+				}, Signature: "c2lnbmF0dXJl"},
+				{ID: "call_1", Type: "function", Function: providers.FunctionCall{
+					Name: "get_time", Arguments: `{"location":"London"}`,
+				}},
+			}},
+			{Role: providers.RoleTool, ToolCallID: "call_0", Content: "Sunny"},
+			{Role: providers.RoleTool, ToolCallID: "call_1", Content: `{"time":"12:00"}`},
+		})
+		require.NoError(t, err)
+		require.Len(t, contents, 3)
+
+		require.Equal(t, content{Role: roleModel, Parts: []part{
+			{
+				FunctionCall:     &functionCall{Name: "get_weather", Args: map[string]any{"location": "Paris"}},
+				ThoughtSignature: "c2lnbmF0dXJl",
+			},
+			{FunctionCall: &functionCall{Name: "get_time", Args: map[string]any{"location": "London"}}},
+		}}, contents[1])
+		require.Equal(t, content{Role: roleUser, Parts: []part{
+			{FunctionResponse: &functionResponse{Name: "get_weather", Response: map[string]any{"result": "Sunny"}}},
+			{FunctionResponse: &functionResponse{Name: "get_time", Response: map[string]any{"time": "12:00"}}},
+		}}, contents[2])
+	})
+
+	t.Run("rejects results of unknown tool calls", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := convertMessages([]providers.Message{
+			{Role: providers.RoleTool, ToolCallID: "call_9", Content: "Sunny"},
+		})
+		require.ErrorContains(t, err, "call_9")
+	})
+
+	t.Run("rejects unknown roles", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := convertMessages([]providers.Message{{Role: "narrator", Content: "Once upon a time"}})
+		require.ErrorContains(t, err, "narrator")
+	})
+
+	t.Run("converts images", func(t *testing.T) {
+		t.Parallel()
+
+		contents, _, err := convertMessages([]providers.Message{{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "text", Text: "Compare these."},
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "data:image/png;base64,iVBORw0KGgo="}},
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "https://example.com/cat.png?size=large"}},
+				{Type: "image_url", ImageURL: &providers.ImageURL{URL: "https://example.com/cat"}},
+			},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, []part{
+			{Text: "Compare these."},
+			{InlineData: &blob{MimeType: "image/png", Data: "iVBORw0KGgo="}},
+			{FileData: &fileData{FileURI: "https://example.com/cat.png?size=large", MimeType: "image/png"}},
+			{FileData: &fileData{FileURI: "https://example.com/cat", MimeType: "image/jpeg"}},
+		}, contents[0].Parts)
+	})
+
+	t.Run("converts files", func(t *testing.T) {
+		t.Parallel()
+
+		contents, _, err := convertMessages([]providers.Message{{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "file", File: &providers.File{Data: "JVBERi0="}},
+				{Type: "file", File: &providers.File{Data: "aGVsbG8=", MIMEType: "text/plain"}},
+				{Type: "file", File: &providers.File{URL: "https://example.com/report.pdf"}},
+				{Type: "file", File: &providers.File{FileID: "https://example.com/files/abc", MIMEType: "text/csv"}},
+			},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, []part{
+			{InlineData: &blob{MimeType: "application/pdf", Data: "JVBERi0="}},
+			{InlineData: &blob{MimeType: "text/plain", Data: "aGVsbG8="}},
+			{FileData: &fileData{FileURI: "https://example.com/report.pdf", MimeType: "application/pdf"}},
+			{FileData: &fileData{FileURI: "https://example.com/files/abc", MimeType: "text/csv"}},
+		}, contents[0].Parts)
+	})
+}
+
+func TestConvertParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps generation parameters", func(t *testing.T) {
+		t.Parallel()
+
+		temperature, topP, maxTokens, seed, top := 0.5, 0.9, 100, 42, 3
+		req, err := convertParams(providers.CompletionParams{
+			Model:       "gemini-2.0-flash",
+			Messages:    testutil.SimpleMessages(),
+			Temperature: &temperature,
+			TopP:        &topP,
+			MaxTokens:   &maxTokens,
+			Seed:        &seed,
+			Stop:        []string{"END"},
+			TopLogprobs: &top,
+		})
+		require.NoError(t, err)
+
+		cfg := req.GenerationConfig
+		require.Equal(t, &temperature, cfg.Temperature)
+		require.Equal(t, &topP, cfg.TopP)
+		require.Equal(t, &maxTokens, cfg.MaxOutputTokens)
+		require.Equal(t, &seed, cfg.Seed)
+		require.Equal(t, []string{"END"}, cfg.StopSequences)
+		require.True(t, cfg.ResponseLogprobs)
+		require.Equal(t, &top, cfg.Logprobs)
+		require.Nil(t, cfg.ThinkingConfig)
+	})
+
+	t.Run("maps response formats", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := convertParams(providers.CompletionParams{
+			Messages: testutil.SimpleMessages(),
+			ResponseFormat: &providers.ResponseFormat{
+				Type: "json_schema",
+				JSONSchema: &providers.JSONSchema{Name: "city", Schema: map[string]any{
+					"$schema":              "https://json-schema.org/draft/2020-12/schema",
+					"type":                 "object",
+					"additionalProperties": false,
+					"properties": map[string]any{
+						"tags": map[string]any{
+							"type":  "array",
+							"items": map[string]any{"type": "object", "additionalProperties": false},
+						},
+					},
+				}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "application/json", req.GenerationConfig.ResponseMimeType)
+		require.Equal(t, map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"tags": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+			},
+		}, req.GenerationConfig.ResponseSchema)
+
+		req, err = convertParams(providers.CompletionParams{
+			Messages:       testutil.SimpleMessages(),
+			ResponseFormat: &providers.ResponseFormat{Type: "json_object"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "application/json", req.GenerationConfig.ResponseMimeType)
+		require.Nil(t, req.GenerationConfig.ResponseSchema)
+	})
+
+	t.Run("resolves references and enums in schemas", func(t *testing.T) {
+		t.Parallel()
+
+		schema := cleanSchema(map[string]any{
+			"type": "object",
+			"$defs": map[string]any{
+				"Node": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"children": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/Node"}},
+					},
+				},
+			},
+			"properties": map[string]any{
+				"data":     map[string]any{"type": "string", "contentEncoding": "base64"},
+				"level":    map[string]any{"type": "integer", "enum": []any{1, 2}},
+				"missing":  map[string]any{"$ref": "#/$defs/Missing"},
+				"tree":     map[string]any{"$ref": "#/$defs/Node", "description": "Root node."},
+				"unit":     map[string]any{"type": "string", "enum": []string{"celsius", "fahrenheit"}},
+				"wildcard": map[string]any{"$ref": "#"},
+			},
+		})
+
+		properties := schema["properties"].(map[string]any)
+		require.Equal(t, map[string]any{"type": "string"}, properties["data"])
+		require.Equal(t, map[string]any{"type": "string", "enum": []any{"1", "2"}}, properties["level"])
+		require.Equal(t, map[string]any{"type": "object"}, properties["missing"])
+		require.Equal(t, map[string]any{"type": "string", "enum": []any{"celsius", "fahrenheit"}}, properties["unit"])
+		require.Equal(t, "object", properties["wildcard"].(map[string]any)["type"])
+
+		node := properties["tree"].(map[string]any)
+		require.Equal(t, "Root node.", node["description"])
+		for range maxSchemaRefDepth - 1 {
+			children := node["properties"].(map[string]any)["children"].(map[string]any)
+			node = children["items"].(map[string]any)
+			require.Equal(t, "object", node["type"])
+			require.NotContains(t, node, "$ref")
+		}
+		children := node["properties"].(map[string]any)["children"].(map[string]any)
+		require.Equal(t, map[string]any{"type": "object"}, children["items"])
+		require.NotContains(t, schema, "$defs")
+	})
+
+	t.Run("maps tools and tool choice", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			choice any
+			want   *toolConfig
+		}{
+			"auto":     {choice: "auto", want: &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "AUTO"}}},
+			"none":     {choice: "none", want: &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "NONE"}}},
+			"required": {choice: "required", want: &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "ANY"}}},
+			"function": {
+				choice: providers.ToolChoice{Type: "function", Function: &providers.ToolChoiceFunction{Name: "get_weather"}},
+				want: &toolConfig{FunctionCallingConfig: functionCallingConfig{
+					Mode:                 "ANY",
+					AllowedFunctionNames: []string{"get_weather"},
+				}},
+			},
+			"unset": {choice: nil, want: nil},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				req, err := convertParams(providers.CompletionParams{
+					Messages:   testutil.SimpleMessages(),
+					Tools:      []providers.Tool{testutil.WeatherTool()},
+					ToolChoice: tc.choice,
+				})
+				require.NoError(t, err)
+				require.Equal(t, tc.want, req.ToolConfig)
+				require.Len(t, req.Tools, 1)
+				require.Len(t, req.Tools[0].FunctionDeclarations, 1)
+				require.Equal(t, "get_weather", req.Tools[0].FunctionDeclarations[0].Name)
+				require.Equal(t, "object", req.Tools[0].FunctionDeclarations[0].Parameters["type"])
+			})
+		}
+	})
+
+	t.Run("maps reasoning effort to a thinking budget", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := convertParams(providers.CompletionParams{
+			Messages:        testutil.SimpleMessages(),
+			ReasoningEffort: providers.ReasoningEffortMedium,
+		})
+		require.NoError(t, err)
+		require.True(t, req.GenerationConfig.ThinkingConfig.IncludeThoughts)
+		require.Equal(t, 8192, *req.GenerationConfig.ThinkingConfig.ThinkingBudget)
+
+		req, err = convertParams(providers.CompletionParams{
+			Messages:        testutil.SimpleMessages(),
+			ReasoningEffort: providers.ReasoningEffortNone,
+		})
+		require.NoError(t, err)
+		require.False(t, req.GenerationConfig.ThinkingConfig.IncludeThoughts)
+		require.Equal(t, 0, *req.GenerationConfig.ThinkingConfig.ThinkingBudget)
+	})
+
+	t.Run("rejects invalid messages", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := convertParams(providers.CompletionParams{Messages: []providers.Message{{Role: "narrator"}}})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+
+	t.Run("rejects audio", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := convertParams(providers.CompletionParams{
+			Messages: testutil.SimpleMessages(),
+			Audio:    &providers.AudioParams{Voice: "alloy", Format: "wav"},
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+		_, err = convertParams(providers.CompletionParams{Messages: []providers.Message{{
+			Role:    providers.RoleUser,
+			Content: []providers.ContentPart{{Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg=="}}},
+		}}})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestApplyExtra(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps known keys onto typed fields", func(t *testing.T) {
+		t.Parallel()
+
+		req := &generateContentRequest{GenerationConfig: &generationConfig{}}
+		err := applyExtra(req, map[string]any{
+			"frequency_penalty": 0.5,
+			"presence_penalty":  0.25,
+			"safety_settings": []map[string]any{
+				{"category": "HARM_CATEGORY_HARASSMENT", "threshold": "BLOCK_ONLY_HIGH"},
+			},
+			"top_k": float64(40),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0.5, *req.GenerationConfig.FrequencyPenalty)
+		require.Equal(t, 0.25, *req.GenerationConfig.PresencePenalty)
+		require.Equal(t, 40, *req.GenerationConfig.TopK)
+		require.Equal(t, []safetySetting{
+			{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_ONLY_HIGH"},
+		}, req.SafetySettings)
+	})
+
+	t.Run("rejects unsupported keys", func(t *testing.T) {
+		t.Parallel()
+
+		req := &generateContentRequest{GenerationConfig: &generationConfig{}}
+		err := applyExtra(req, map[string]any{"min_p": 0.1})
+
+		var unsupportedErr *errors.UnsupportedParamError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, "min_p", unsupportedErr.Param)
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		req := &generateContentRequest{GenerationConfig: &generationConfig{}}
+		err := applyExtra(req, map[string]any{"top_k": "many"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestConvertFinishReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reason       string
+		hasToolCalls bool
+		want         string
+	}{
+		{reason: "", want: ""},
+		{reason: "STOP", want: providers.FinishReasonStop},
+		{reason: "STOP", hasToolCalls: true, want: providers.FinishReasonToolCalls},
+		{reason: "MAX_TOKENS", want: providers.FinishReasonLength},
+		{reason: "SAFETY", want: providers.FinishReasonContentFilter},
+		{reason: "RECITATION", want: providers.FinishReasonContentFilter},
+		{reason: "PROHIBITED_CONTENT", want: providers.FinishReasonContentFilter},
+		{reason: "OTHER", want: providers.FinishReasonStop},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s/%t", tc.reason, tc.hasToolCalls), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, convertFinishReason(tc.reason, tc.hasToolCalls))
+		})
+	}
+}
+
+func TestThinkingBudget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		effort     providers.ReasoningEffort
+		wantBudget int
+		wantOK     bool
+	}{
+		{effort: providers.ReasoningEffortNone, wantBudget: 0, wantOK: true},
+		{effort: providers.ReasoningEffortAuto, wantBudget: -1, wantOK: true},
+		{effort: providers.ReasoningEffortLow, wantBudget: 1024, wantOK: true},
+		{effort: providers.ReasoningEffortMedium, wantBudget: 8192, wantOK: true},
+		{effort: providers.ReasoningEffortHigh, wantBudget: 24576, wantOK: true},
+		{effort: "extreme", wantBudget: 0, wantOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.effort), func(t *testing.T) {
+			t.Parallel()
+
+			budget, ok := thinkingBudget(tc.effort)
+			require.Equal(t, tc.wantBudget, budget)
+			require.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends the request and converts the response", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/models/gemini-2.0-flash:generateContent", r.URL.Path)
+			require.Equal(t, testAPIKey, r.Header.Get("x-goog-api-key"))
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, map[string]any{"parts": []any{map[string]any{"text": "Be brief."}}}, req["systemInstruction"])
+			require.Equal(t, []any{
+				map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Hello"}}},
+			}, req["contents"])
+			require.Equal(t, map[string]any{"temperature": 0.2}, req["generationConfig"])
+
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"responseId":   "resp-123",
+				"modelVersion": "gemini-2.0-flash-001",
+				"candidates": []any{map[string]any{
+					"content": map[string]any{"role": "model", "parts": []any{
+						map[string]any{"text": "Considering a greeting.", "thought": true},
+						map[string]any{"text": "Hello"},
+						map[string]any{"text": " there!"},
+					}},
+					"finishReason": "STOP",
+				}},
+				"usageMetadata": map[string]any{
+					"promptTokenCount":     5,
+					"candidatesTokenCount": 3,
+					"thoughtsTokenCount":   4,
+					"totalTokenCount":      12,
+				},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		temperature := 0.2
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model: "gemini-2.0-flash",
+			Messages: []providers.Message{
+				{Role: providers.RoleSystem, Content: "Be brief."},
+				{Role: providers.RoleUser, Content: "Hello"},
+			},
+			Temperature: &temperature,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "resp-123", resp.ID)
+		require.Equal(t, "chat.completion", resp.Object)
+		require.Equal(t, "gemini-2.0-flash-001", resp.Model)
+		require.Len(t, resp.Choices, 1)
+		require.Equal(t, providers.RoleAssistant, resp.Choices[0].Message.Role)
+		require.Equal(t, "Hello there!", resp.Choices[0].Message.Content)
+		require.Equal(t, &providers.Reasoning{Content: "Considering a greeting."}, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, providers.FinishReasonStop, resp.Choices[0].FinishReason)
+		require.Equal(t, &providers.Usage{
+			PromptTokens:     5,
+			CompletionTokens: 7,
+			TotalTokens:      12,
+			ReasoningTokens:  4,
+		}, resp.Usage)
+	})
+
+	t.Run("converts tool calls", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"candidates": []any{map[string]any{
+					"content": map[string]any{"role": "model", "parts": []any{
+						map[string]any{
+							"functionCall": map[string]any{"name": "get_weather", "args": map[string]any{
+								"location": "Paris",
+							}},
+							"thoughtSignature": "c2lnbmF0dXJl",
+						},
+						map[string]any{"functionCall": map[string]any{"id": "fc-1", "name": "get_date"}},
+					}},
+					"finishReason": "STOP",
+				}},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gemini-2.0-flash",
+			Messages: testutil.SimpleMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool(), testutil.DateTool()},
+		})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, resp.ID)
+		require.Equal(t, providers.FinishReasonToolCalls, resp.Choices[0].FinishReason)
+
+		toolCalls := resp.Choices[0].Message.ToolCalls
+		require.Len(t, toolCalls, 2)
+		require.True(t, strings.HasPrefix(toolCalls[0].ID, "call_"))
+		require.Equal(t, []providers.ToolCall{
+			{ID: toolCalls[0].ID, Type: "function", Function: providers.FunctionCall{
+				Name: "get_weather", Arguments: `{"location":"Paris"}`,
+			}, Signature: "c2lnbmF0dXJl"},
+			{Index: 1, ID: "fc-1", Type: "function", Function: providers.FunctionCall{
+				Name: "get_date", Arguments: "{}",
+			}},
+		}, toolCalls)
+
+		again, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gemini-2.0-flash",
+			Messages: testutil.SimpleMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool(), testutil.DateTool()},
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, toolCalls[0].ID, again.Choices[0].Message.ToolCalls[0].ID)
+	})
+
+	t.Run("converts logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"candidates": []any{map[string]any{
+					"content":      map[string]any{"parts": []any{map[string]any{"text": "Hi"}}},
+					"finishReason": "STOP",
+					"logprobsResult": map[string]any{
+						"chosenCandidates": []any{map[string]any{"token": "Hi", "logProbability": -0.1}},
+						"topCandidates": []any{map[string]any{"candidates": []any{
+							map[string]any{"token": "Hi", "logProbability": -0.1},
+							map[string]any{"token": "Hey", "logProbability": -2.5},
+						}}},
+					},
+				}},
+			})
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gemini-2.0-flash",
+			Messages: testutil.SimpleMessages(),
+			Logprobs: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.Logprobs{Content: []providers.TokenLogprob{{
+			Token:   "Hi",
+			Logprob: -0.1,
+			TopLogprobs: []providers.TopLogprob{
+				{Token: "Hi", Logprob: -0.1},
+				{Token: "Hey", Logprob: -2.5},
+			},
+		}}}, resp.Choices[0].Logprobs)
+	})
+
+	t.Run("returns ContentFilterError for blocked prompts", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, http.StatusOK, map[string]any{"promptFeedback": map[string]any{"blockReason": "SAFETY"}})
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gemini-2.0-flash",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.ErrorIs(t, err, errors.ErrContentFilter)
+	})
+
+	t.Run("rejects unsupported extra parameters before sending", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gemini-2.0-flash",
+			Messages: testutil.SimpleMessages(),
+			Extra:    map[string]any{"logit_bias": map[string]any{}},
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	events := []string{
+		`{"responseId":"resp-1","modelVersion":"gemini-2.0-flash-001","candidates":[{"content":{"role":"model",` +
+			`"parts":[{"text":"Thinking...","thought":true}]}}],"usageMetadata":{"promptTokenCount":5}}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"Hello"}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather",` +
+			`"args":{"location":"Paris"}}}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":""}]},"finishReason":"STOP"}],` +
+			`"usageMetadata":{"promptTokenCount":5,"candidatesTokenCount":6,"totalTokenCount":11}}`,
+	}
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/models/gemini-2.0-flash:streamGenerateContent", r.URL.Path)
+		require.Equal(t, "sse", r.URL.Query().Get("alt"))
+		require.Equal(t, testAPIKey, r.Header.Get("x-goog-api-key"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			_, _ = fmt.Fprintf(w, "data: %s\r\n\r\n", event)
+		}
+	}, config.WithAPIKey(testAPIKey))
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    "gemini-2.0-flash",
+		Messages: testutil.SimpleMessages(),
+		Tools:    []providers.Tool{testutil.WeatherTool()},
+	})
+
+	var received []providers.ChatCompletionChunk
+	acc := providers.NewAccumulator()
+	for chunk := range chunks {
+		received = append(received, chunk)
+		acc.Add(chunk)
+	}
+	require.NoError(t, <-errs)
+
+	require.Len(t, received, 4)
+	require.Equal(t, "chat.completion.chunk", received[0].Object)
+	require.Equal(t, "gemini-2.0-flash-001", received[0].Model)
+	require.Equal(t, providers.RoleAssistant, received[0].Choices[0].Delta.Role)
+	require.Empty(t, received[1].Choices[0].Delta.Role)
+	require.Nil(t, received[0].Usage, "usage is only reported on the final chunk")
+
+	completion := acc.ChatCompletion()
+	require.Equal(t, "Hello", completion.Choices[0].Message.Content)
+	require.Equal(t, "Thinking...", completion.Choices[0].Message.Reasoning.Content)
+	require.Equal(t, providers.FinishReasonToolCalls, completion.Choices[0].FinishReason)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	require.Equal(t, `{"location":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	require.Equal(t, 11, completion.Usage.TotalTokens)
+}
+
+func TestEmbedding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("embeds each input", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/models/text-embedding-004:batchEmbedContents", r.URL.Path)
+
+			var req batchEmbedContentsRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.Requests, 2)
+			require.Equal(t, "models/text-embedding-004", req.Requests[0].Model)
+			require.Equal(t, "first", req.Requests[0].Content.Parts[0].Text)
+			require.Equal(t, 2, *req.Requests[1].OutputDimensionality)
+
+			writeJSON(t, w, http.StatusOK, map[string]any{"embeddings": []any{
+				map[string]any{"values": []float64{0.1, 0.2}},
+				map[string]any{"values": []float64{0.3, 0.4}},
+			}})
+		}, config.WithAPIKey(testAPIKey))
+
+		dimensions := 2
+		resp, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+			Model:      "text-embedding-004",
+			Input:      []string{"first", "second"},
+			Dimensions: &dimensions,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "list", resp.Object)
+		require.Equal(t, "text-embedding-004", resp.Model)
+		require.Equal(t, []providers.EmbeddingData{
+			{Object: "embedding", Embedding: []float64{0.1, 0.2}, Index: 0},
+			{Object: "embedding", Embedding: []float64{0.3, 0.4}, Index: 1},
+		}, resp.Data)
+	})
+
+	t.Run("rejects unsupported inputs", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+			Model: "text-embedding-004",
+			Input: []int{1, 2},
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestListModels(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/models", r.URL.Path)
+		require.Equal(t, "1000", r.URL.Query().Get("pageSize"))
+
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"models":        []any{map[string]any{"name": "models/gemini-2.0-flash"}},
+				"nextPageToken": "page-2",
+			})
+		case "page-2":
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"models": []any{map[string]any{"name": "models/text-embedding-004"}},
+			})
+		default:
+			t.Errorf("unexpected page token %q", r.URL.Query().Get("pageToken"))
+		}
+	}, config.WithAPIKey(testAPIKey))
+
+	resp, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "list", resp.Object)
+	require.Equal(t, []providers.Model{
+		{ID: "gemini-2.0-flash", Object: "model", OwnedBy: "google"},
+		{ID: "text-embedding-004", Object: "model", OwnedBy: "google"},
+	}, resp.Data)
+}
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, testAPIKey, r.Header.Get("X-Goog-Api-Key"))
+		require.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+		require.Equal(t, "my-app/1.0", r.Header.Get("User-Agent"))
+
+		writeJSON(t, w, http.StatusOK, map[string]any{"models": []any{}})
+	},
+		config.WithAPIKey(testAPIKey),
+		config.WithHeaders(map[string]string{"X-Trace-Id": "trace-1", "User-Agent": "my-app/1.0"}),
+	)
+
+	_, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+}
+
+func TestConvertError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantSentinel error
+	}{
+		{
+			name:         "invalid API key becomes AuthenticationError",
+			status:       http.StatusBadRequest,
+			body:         `{"error":{"code":400,"message":"API key not valid.","status":"INVALID_ARGUMENT"}}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+		{
+			name:   "oversized prompt becomes ContextLengthError",
+			status: http.StatusBadRequest,
+			body: `{"error":{"code":400,"message":"The input token count (2000000) exceeds the maximum ` +
+				`number of tokens allowed (1048576).","status":"INVALID_ARGUMENT"}}`,
+			wantSentinel: errors.ErrContextLength,
+		},
+		{
+			name:         "other 400 becomes InvalidRequestError",
+			status:       http.StatusBadRequest,
+			body:         `{"error":{"code":400,"message":"Invalid value","status":"INVALID_ARGUMENT"}}`,
+			wantSentinel: errors.ErrInvalidRequest,
+		},
+		{
+			name:         "403 becomes AuthenticationError",
+			status:       http.StatusForbidden,
+			body:         `{"error":{"code":403,"message":"Permission denied","status":"PERMISSION_DENIED"}}`,
+			wantSentinel: errors.ErrAuthentication,
+		},
+		{
+			name:         "404 becomes ModelNotFoundError",
+			status:       http.StatusNotFound,
+			body:         `{"error":{"code":404,"message":"models/nope is not found","status":"NOT_FOUND"}}`,
+			wantSentinel: errors.ErrModelNotFound,
+		},
+		{
+			name:         "429 becomes RateLimitError",
+			status:       http.StatusTooManyRequests,
+			body:         `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`,
+			wantSentinel: errors.ErrRateLimit,
+		},
+		{
+			name:         "500 with a non-JSON body becomes ProviderError",
+			status:       http.StatusInternalServerError,
+			body:         "upstream failure",
+			wantSentinel: errors.ErrProvider,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.body)
+			}, config.WithAPIKey(testAPIKey))
+
+			_, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    "gemini-2.0-flash",
+				Messages: testutil.SimpleMessages(),
+			})
+			require.ErrorIs(t, err, tc.wantSentinel)
+
+			var apiErr *apiError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tc.status, apiErr.StatusCode)
+			require.NotEmpty(t, apiErr.Message)
+		})
+	}
+
+	t.Run("rate limit errors carry the retry delay", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		err := p.ConvertError(&apiError{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"7"}},
+		})
+
+		var rateLimitErr *errors.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		require.Equal(t, 7, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("non-API errors become ProviderError", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		require.Nil(t, p.ConvertError(nil))
+		require.ErrorIs(t, p.ConvertError(stderrors.New("connection reset")), errors.ErrProvider)
+	})
+}
+
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "gemini.(*Provider).CompletionStream"
+
+	server := testutil.NewEndlessStreamServer(t, "text/event-stream", nil, func(i int) string {
+		return fmt.Sprintf("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"%d \"}]}}]}\n\n", i)
+	})
+
+	provider, err := New(config.WithAPIKey(testAPIKey), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: "gemini-2.0-flash", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, providers.RoleAssistant, chunk.Choices[0].Delta.Role)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("gemini") {
+		t.Skip("GEMINI_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("gemini"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestIntegrationCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("gemini") {
+		t.Skip("GEMINI_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("gemini"),
+		Messages: testutil.SimpleMessages(),
+		Stream:   true,
+	})
+
+	var content strings.Builder
+	for chunk := range chunks {
+		if len(chunk.Choices) > 0 {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+	require.NoError(t, <-errs)
+	require.NotEmpty(t, content.String())
+}
+
+func TestIntegrationAgentLoop(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("gemini") {
+		t.Skip("GEMINI_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("gemini"),
+		Messages: testutil.AgentLoopMessages(),
+		Tools:    []providers.Tool{testutil.WeatherTool()},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+}
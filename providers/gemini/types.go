@@ -0,0 +1,225 @@
+package gemini
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// apiError is an error response from the Gemini API.
+type apiError struct {
+	// Header holds the response headers, which may carry retry hints.
+	Header http.Header
+
+	// Message is the error message returned by the API.
+	Message string
+
+	// Status is the canonical error status, e.g. "INVALID_ARGUMENT".
+	Status string
+
+	// StatusCode is the HTTP status code.
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (e *apiError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("gemini API error (%d): %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("gemini API error (%d %s): %s", e.StatusCode, e.Status, e.Message)
+}
+
+// batchEmbedContentsRequest is the request body of models.batchEmbedContents.
+type batchEmbedContentsRequest struct {
+	Requests []embedContentRequest `json:"requests"`
+}
+
+// batchEmbedContentsResponse is the response body of models.batchEmbedContents.
+type batchEmbedContentsResponse struct {
+	Embeddings []embedding `json:"embeddings"`
+}
+
+// blob is inline binary data.
+type blob struct {
+	Data     string `json:"data"`
+	MimeType string `json:"mimeType"`
+}
+
+// candidate is a single response candidate.
+type candidate struct {
+	Content        content         `json:"content"`
+	FinishReason   string          `json:"finishReason,omitempty"`
+	Index          int             `json:"index"`
+	LogprobsResult *logprobsResult `json:"logprobsResult,omitempty"`
+}
+
+// content is a single turn of a conversation.
+type content struct {
+	Parts []part `json:"parts"`
+	Role  string `json:"role,omitempty"`
+}
+
+// embedContentRequest is a single request of a batch embedding request.
+type embedContentRequest struct {
+	Content              content `json:"content"`
+	Model                string  `json:"model"`
+	OutputDimensionality *int    `json:"outputDimensionality,omitempty"`
+}
+
+// embedding is a single embedding vector.
+type embedding struct {
+	Values []float64 `json:"values"`
+}
+
+// errorResponse is the body of an error response.
+type errorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// fileData is a reference to a file by URI.
+type fileData struct {
+	FileURI  string `json:"fileUri"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// functionCall is a function call predicted by the model.
+type functionCall struct {
+	Args map[string]any `json:"args,omitempty"`
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+}
+
+// functionCallingConfig controls how the model calls functions.
+type functionCallingConfig struct {
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+	Mode                 string   `json:"mode"`
+}
+
+// functionDeclaration describes a function the model may call.
+type functionDeclaration struct {
+	Description string         `json:"description,omitempty"`
+	Name        string         `json:"name"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// functionResponse is the result of a function call.
+type functionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+// generateContentRequest is the request body of models.generateContent.
+type generateContentRequest struct {
+	Contents          []content         `json:"contents"`
+	GenerationConfig  *generationConfig `json:"generationConfig,omitempty"`
+	SafetySettings    []safetySetting   `json:"safetySettings,omitempty"`
+	SystemInstruction *content          `json:"systemInstruction,omitempty"`
+	ToolConfig        *toolConfig       `json:"toolConfig,omitempty"`
+	Tools             []tool            `json:"tools,omitempty"`
+}
+
+// generateContentResponse is the response body of models.generateContent, and of each
+// event of models.streamGenerateContent.
+type generateContentResponse struct {
+	Candidates     []candidate     `json:"candidates"`
+	ModelVersion   string          `json:"modelVersion,omitempty"`
+	PromptFeedback *promptFeedback `json:"promptFeedback,omitempty"`
+	ResponseID     string          `json:"responseId,omitempty"`
+	UsageMetadata  *usageMetadata  `json:"usageMetadata,omitempty"`
+}
+
+// generationConfig holds the generation parameters of a request.
+type generationConfig struct {
+	FrequencyPenalty *float64        `json:"frequencyPenalty,omitempty"`
+	Logprobs         *int            `json:"logprobs,omitempty"`
+	MaxOutputTokens  *int            `json:"maxOutputTokens,omitempty"`
+	PresencePenalty  *float64        `json:"presencePenalty,omitempty"`
+	ResponseLogprobs bool            `json:"responseLogprobs,omitempty"`
+	ResponseMimeType string          `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]any  `json:"responseSchema,omitempty"`
+	Seed             *int            `json:"seed,omitempty"`
+	StopSequences    []string        `json:"stopSequences,omitempty"`
+	Temperature      *float64        `json:"temperature,omitempty"`
+	ThinkingConfig   *thinkingConfig `json:"thinkingConfig,omitempty"`
+	TopK             *int            `json:"topK,omitempty"`
+	TopP             *float64        `json:"topP,omitempty"`
+}
+
+// listModelsResponse is the response body of models.list.
+type listModelsResponse struct {
+	Models        []model `json:"models"`
+	NextPageToken string  `json:"nextPageToken,omitempty"`
+}
+
+// logprobsCandidate is a token and its log probability.
+type logprobsCandidate struct {
+	LogProbability float64 `json:"logProbability"`
+	Token          string  `json:"token"`
+}
+
+// logprobsResult holds the log probabilities of a candidate's tokens.
+type logprobsResult struct {
+	ChosenCandidates []logprobsCandidate `json:"chosenCandidates"`
+	TopCandidates    []topCandidates     `json:"topCandidates"`
+}
+
+// model describes a model returned by models.list.
+type model struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Name        string `json:"name"`
+}
+
+// part is a piece of content. Exactly one of its fields is set.
+type part struct {
+	FileData         *fileData         `json:"fileData,omitempty"`
+	FunctionCall     *functionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *functionResponse `json:"functionResponse,omitempty"`
+	InlineData       *blob             `json:"inlineData,omitempty"`
+	Text             string            `json:"text,omitempty"`
+	Thought          bool              `json:"thought,omitempty"`
+	ThoughtSignature string            `json:"thoughtSignature,omitempty"`
+}
+
+// promptFeedback reports whether the prompt was blocked.
+type promptFeedback struct {
+	BlockReason string `json:"blockReason,omitempty"`
+}
+
+// safetySetting sets the blocking threshold of a harm category.
+type safetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// thinkingConfig controls the model's thinking.
+type thinkingConfig struct {
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
+	ThinkingBudget  *int `json:"thinkingBudget,omitempty"`
+}
+
+// tool is a set of function declarations.
+type tool struct {
+	FunctionDeclarations []functionDeclaration `json:"functionDeclarations"`
+}
+
+// toolConfig configures tool use.
+type toolConfig struct {
+	FunctionCallingConfig functionCallingConfig `json:"functionCallingConfig"`
+}
+
+// topCandidates holds the most likely tokens at a position.
+type topCandidates struct {
+	Candidates []logprobsCandidate `json:"candidates"`
+}
+
+// usageMetadata reports token usage.
+type usageMetadata struct {
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	PromptTokenCount     int `json:"promptTokenCount"`
+	ThoughtsTokenCount   int `json:"thoughtsTokenCount,omitempty"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
@@ -0,0 +1,112 @@
+// Package groq provides a Groq provider implementation for any-llm.
+//
+// Groq serves open models through an OpenAI-compatible API, so the provider is built on
+// openai.CompatibleProvider and rejects the OpenAI parameters Groq does not support.
+package groq
+
+import (
+	openaisdk "github.com/openai/openai-go"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.groq.com/openai/v1"
+	envAPIKey      = "GROQ_API_KEY"
+	providerName   = "groq"
+)
+
+// Groq API constants.
+const (
+	reasoningField = "reasoning"
+)
+
+// Parameters Groq does not support.
+const (
+	paramLogitBias   = "logit_bias"
+	paramLogprobs    = "logprobs"
+	paramN           = "n"
+	paramTopLogprobs = "top_logprobs"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider    = (*Provider)(nil)
+	_ providers.ErrorConverter        = (*Provider)(nil)
+	_ providers.ModelLister           = (*Provider)(nil)
+	_ providers.Provider              = (*Provider)(nil)
+	_ providers.SpeechProvider        = (*Provider)(nil)
+	_ providers.TranscriptionProvider = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Groq.
+// It embeds openai.CompatibleProvider since Groq exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a new Groq provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   groqCapabilities(),
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// groqCapabilities returns the capabilities for the Groq provider.
+func groqCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Llama 4 models accept images.
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // GPT-OSS and Qwen 3 models.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  false,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     true,
+		Transcription:              true,
+	}
+}
+
+// prepareRequest rejects the OpenAI parameters Groq does not support.
+// Groq also returns only one choice, so n must be 1.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	switch {
+	case params.TopLogprobs != nil:
+		return errors.NewUnsupportedParamError(providerName, paramTopLogprobs)
+	case params.Logprobs:
+		return errors.NewUnsupportedParamError(providerName, paramLogprobs)
+	case req.LogitBias != nil:
+		return errors.NewUnsupportedParamError(providerName, paramLogitBias)
+	case req.N.Valid() && req.N.Value != 1:
+		return errors.NewUnsupportedParamError(providerName, paramN)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+const chatResponse = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "openai/gpt-oss-20b",
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "content": "4", "reasoning": "2 plus 2 is 4."},
+		"finish_reason": "stop"
+	}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "groq", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "groq", missingKeyErr.Provider)
+		require.Equal(t, "GROQ_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends the request to Groq and reads reasoning", func(t *testing.T) {
+		t.Parallel()
+
+		var path, auth string
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			auth = r.Header.Get("Authorization")
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(chatResponse))
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:           "openai/gpt-oss-20b",
+			Messages:        testutil.SimpleMessages(),
+			ReasoningEffort: providers.ReasoningEffortLow,
+			Extra:           map[string]any{"n": 1},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "/chat/completions", path)
+		require.Equal(t, "Bearer "+testAPIKey, auth)
+		require.Equal(t, "low", body["reasoning_effort"])
+
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	topLogprobs := 2
+	tests := map[string]struct {
+		params    providers.CompletionParams
+		wantParam string
+	}{
+		"logprobs": {
+			params:    providers.CompletionParams{Logprobs: true},
+			wantParam: "logprobs",
+		},
+		"top_logprobs": {
+			params:    providers.CompletionParams{Logprobs: true, TopLogprobs: &topLogprobs},
+			wantParam: "top_logprobs",
+		},
+		"logit_bias": {
+			params:    providers.CompletionParams{Extra: map[string]any{"logit_bias": map[string]int{"1": 10}}},
+			wantParam: "logit_bias",
+		},
+		"n": {
+			params:    providers.CompletionParams{Extra: map[string]any{"n": 2}},
+			wantParam: "n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run("rejects "+name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(config.WithAPIKey(testAPIKey))
+			require.NoError(t, err)
+
+			params := tc.params
+			params.Model = "llama-3.1-8b-instant"
+			params.Messages = testutil.SimpleMessages()
+
+			_, err = provider.Completion(context.Background(), params)
+			require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+			var paramErr *errors.UnsupportedParamError
+			require.ErrorAs(t, err, &paramErr)
+			require.Equal(t, tc.wantParam, paramErr.Param)
+		})
+	}
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("groq") {
+		t.Skip("GROQ_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("groq"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
@@ -0,0 +1,247 @@
+// Package llamacpp provides a llama.cpp server provider implementation for any-llm.
+//
+// The llama.cpp server (llama-server) exposes an OpenAI-compatible API, so the provider is
+// built on openai.CompatibleProvider. It validates llama.cpp's sampling and cache parameters
+// and adds the server's health and tokenizer endpoints.
+package llamacpp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	openaisdk "github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultAPIKey  = "llamacpp" // Dummy key; llama-server only checks keys when started with --api-key.
+	defaultBaseURL = "http://localhost:8080/v1"
+	envAPIKey      = "LLAMACPP_API_KEY"
+	envBaseURL     = "LLAMACPP_BASE_URL"
+	providerName   = "llamacpp"
+)
+
+// llama.cpp API constants.
+const (
+	fieldIDSlot       = "id_slot"
+	pathAPI           = "/v1"
+	pathApplyTemplate = "/apply-template"
+	pathHealth        = "/health"
+	pathTokenize      = "/tokenize"
+	reasoningField    = "reasoning_content"
+)
+
+// Server parameters, validated before they are sent; see the Param helpers.
+// Other keys in CompletionParams.Extra are sent as-is. llama-server reads any of its native sampling
+// options, such as top_k, min_p, or mirostat, from an OpenAI request body and ignores the rest.
+const (
+	extraCachePrompt = "cache_prompt"
+	extraGrammar     = "grammar"
+	extraNProbs      = "n_probs"
+	extraSlotID      = "slot_id"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+	_ providers.TokenCounter       = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for the llama.cpp server.
+// It embeds openai.CompatibleProvider since llama-server exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// applyTemplateRequest is the request body of the apply-template endpoint.
+type applyTemplateRequest struct {
+	Messages json.RawMessage `json:"messages"`
+	Tools    json.RawMessage `json:"tools,omitempty"`
+}
+
+// applyTemplateResponse is the response body of the apply-template endpoint.
+type applyTemplateResponse struct {
+	Prompt string `json:"prompt"`
+}
+
+// tokenizeRequest is the request body of the tokenize endpoint.
+type tokenizeRequest struct {
+	Content string `json:"content"`
+	Model   string `json:"model,omitempty"`
+}
+
+// tokenizeResponse is the response body of the tokenize endpoint.
+type tokenizeResponse struct {
+	Tokens []int `json:"tokens"`
+}
+
+// New creates a provider for the llama-server at LLAMACPP_BASE_URL, or localhost:8080 by default.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		BaseURLEnvVar:  envBaseURL,
+		Capabilities:   llamacppCapabilities(),
+		DefaultAPIKey:  defaultAPIKey,
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  false,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// CountTokens returns the number of tokens of the prompt the model would be given for params.
+// The server renders the messages and tools with the model's chat template, then tokenizes the result.
+func (p *Provider) CountTokens(ctx context.Context, params providers.CompletionParams) (*providers.TokenCount, error) {
+	req, err := p.ChatCompletionRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("encoding messages: %w", err))
+	}
+
+	var body applyTemplateRequest
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("encoding messages: %w", err))
+	}
+
+	var resp applyTemplateResponse
+	if err := p.Client().Post(ctx, p.serverURL()+pathApplyTemplate, body, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	tokens, err := p.Tokenize(ctx, params.Model, resp.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &providers.TokenCount{Model: params.Model, InputTokens: len(tokens)}, nil
+}
+
+// Health returns nil once the server has loaded its model. While the model is loading, the server
+// answers with 503 and Health returns the converted error.
+func (p *Provider) Health(ctx context.Context) error {
+	var body []byte
+	if err := p.Client().Get(ctx, p.serverURL()+pathHealth, nil, &body, option.WithMaxRetries(0)); err != nil {
+		return p.ConvertError(err)
+	}
+
+	return nil
+}
+
+// Tokenize returns the IDs of the tokens of text. The model is only needed by servers that
+// serve several models; otherwise it may be empty.
+func (p *Provider) Tokenize(ctx context.Context, model string, text string) ([]int, error) {
+	req := tokenizeRequest{Content: text, Model: model}
+
+	var resp tokenizeResponse
+	if err := p.Client().Post(ctx, p.serverURL()+pathTokenize, req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return resp.Tokens, nil
+}
+
+// serverURL strips /v1 from the base URL; llama-server's native endpoints, such as /apply-template, are
+// not under the OpenAI prefix.
+func (p *Provider) serverURL() string {
+	return strings.TrimSuffix(strings.TrimSuffix(p.BaseURL(), "/"), pathAPI)
+}
+
+// llamacppCapabilities returns the capabilities for the llama.cpp server provider.
+func llamacppCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Needs a model loaded with a multimodal projector.
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // Reasoning models, with the default --reasoning-format.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                true,
+		Embedding:                  true, // Needs a server started with --embeddings.
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// prepareRequest validates llama.cpp's parameters and sends slot_id under its current name, id_slot.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	fields := maps.Clone(req.ExtraFields())
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	for _, key := range extra.Keys(params.Extra) {
+		value := params.Extra[key]
+
+		var err error
+		switch key {
+		case extraCachePrompt:
+			var v bool
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		case extraGrammar:
+			var v string
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		case extraNProbs:
+			var v int
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		case extraSlotID:
+			var v int
+			err = extra.Decode(key, value, &v)
+			delete(fields, key)
+			fields[fieldIDSlot] = v
+		}
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	if _, ok := fields[extraGrammar]; ok && req.ResponseFormat.OfJSONSchema != nil {
+		return errors.NewInvalidRequestError(
+			providerName,
+			fmt.Errorf("%s cannot be combined with a JSON schema response format", extraGrammar),
+		)
+	}
+
+	if len(fields) > 0 {
+		req.SetExtraFields(fields)
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package llamacpp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testLlamaCppAvailabilityTimeout = 5 * time.Second
+
+const chatResponse = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "qwen3-8b",
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."},
+		"finish_reason": "stop"
+	}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+func TestNew(t *testing.T) {
+	// Note: Not using t.Parallel() here because child test uses t.Setenv.
+
+	t.Run("creates provider with default settings", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "llamacpp", provider.Name())
+		require.Equal(t, "http://localhost:8080/v1", provider.BaseURL())
+	})
+
+	t.Run("creates provider from LLAMACPP_BASE_URL environment variable", func(t *testing.T) {
+		t.Setenv("LLAMACPP_BASE_URL", "http://custom-host:8080/v1")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "http://custom-host:8080/v1", provider.BaseURL())
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.CountTokens)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ListModels)
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends llama.cpp parameters", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, testutil.RecordRequest(t, &body, chatResponse))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "qwen3-8b",
+			Messages: testutil.SimpleMessages(),
+			Extra: map[string]any{
+				"cache_prompt": true,
+				"grammar":      `root ::= "yes" | "no"`,
+				"min_p":        0.05,
+				"n_probs":      float64(3),
+				"slot_id":      1,
+			},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, true, body["cache_prompt"])
+		require.Equal(t, `root ::= "yes" | "no"`, body["grammar"])
+		require.Equal(t, 0.05, body["min_p"])
+		require.Equal(t, float64(3), body["n_probs"])
+		require.Equal(t, float64(1), body["id_slot"])
+		require.NotContains(t, body, "slot_id")
+
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	t.Run("sends parameters set with helpers", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, testutil.RecordRequest(t, &body, chatResponse))
+
+		extra := Extra(CachePrompt(false), Grammar(`root ::= "no"`), NProbs(2))
+		SlotID(3)(extra)
+		extra["top_k"] = 20
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "qwen3-8b",
+			Messages: testutil.SimpleMessages(),
+			Extra:    extra,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, false, body["cache_prompt"])
+		require.Equal(t, `root ::= "no"`, body["grammar"])
+		require.Equal(t, float64(2), body["n_probs"])
+		require.Equal(t, float64(3), body["id_slot"])
+		require.Equal(t, float64(20), body["top_k"])
+	})
+
+	invalid := map[string]providers.CompletionParams{
+		"cache_prompt that is not a bool": {Extra: map[string]any{"cache_prompt": "yes"}},
+		"grammar that is not a string":    {Extra: map[string]any{"grammar": 1}},
+		"n_probs that is not an integer":  {Extra: map[string]any{"n_probs": 1.5}},
+		"slot_id that is not an integer":  {Extra: map[string]any{"slot_id": "one"}},
+		"grammar with a JSON schema format": {
+			Extra: map[string]any{"grammar": `root ::= "yes"`},
+			ResponseFormat: &providers.ResponseFormat{
+				Type:       "json_schema",
+				JSONSchema: &providers.JSONSchema{Name: "answer", Schema: map[string]any{"type": "object"}},
+			},
+		},
+	}
+
+	for name, params := range invalid {
+		t.Run("rejects "+name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New()
+			require.NoError(t, err)
+
+			params.Model = "qwen3-8b"
+			params.Messages = testutil.SimpleMessages()
+
+			_, err = provider.Completion(context.Background(), params)
+			require.ErrorIs(t, err, errors.ErrInvalidRequest)
+		})
+	}
+}
+
+func TestCountTokens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tokenizes the templated prompt", func(t *testing.T) {
+		t.Parallel()
+
+		var templateBody, tokenizeBody map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/apply-template":
+				testutil.RecordRequest(t, &templateBody, `{"prompt": "<|im_start|>user\nHello<|im_end|>\n"}`)(w, r)
+			case "/tokenize":
+				testutil.RecordRequest(t, &tokenizeBody, `{"tokens": [151644, 872, 198, 9707, 151645, 198]}`)(w, r)
+			default:
+				t.Errorf("unexpected path %q", r.URL.Path)
+			}
+		})
+
+		resp, err := provider.CountTokens(context.Background(), providers.CompletionParams{
+			Model:    "qwen3",
+			Messages: testutil.SimpleMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool()},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, templateBody["messages"], 1)
+		require.Len(t, templateBody["tools"], 1)
+		require.Equal(t, map[string]any{"content": "<|im_start|>user\nHello<|im_end|>\n", "model": "qwen3"}, tokenizeBody)
+		require.Equal(t, &providers.TokenCount{Model: "qwen3", InputTokens: 6}, resp)
+	})
+
+	t.Run("rejects invalid parameters before sending", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New()
+		require.NoError(t, err)
+
+		_, err = provider.CountTokens(context.Background(), providers.CompletionParams{Model: "qwen3"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports a ready server", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status": "ok"}`))
+		})
+
+		require.NoError(t, provider.Health(context.Background()))
+		require.Equal(t, "/health", path)
+	})
+
+	t.Run("reports a server still loading its model", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error": {"code": 503, "message": "Loading model", "type": "unavailable_error"}}`))
+		})
+
+		err := provider.Health(context.Background())
+		require.ErrorContains(t, err, "Loading model")
+	})
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	var path string
+	var body map[string]any
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		testutil.RecordRequest(t, &body, `{"tokens": [9707, 11, 1879]}`)(w, r)
+	})
+
+	tokens, err := provider.Tokenize(context.Background(), "", "Hello, world")
+	require.NoError(t, err)
+
+	require.Equal(t, "/tokenize", path)
+	require.Equal(t, map[string]any{"content": "Hello, world"}, body)
+	require.Equal(t, []int{9707, 11, 1879}, tokens)
+}
+
+func TestServerURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"strips the API prefix":           "http://custom-host:8080/v1",
+		"strips the API prefix and slash": "http://custom-host:8080/v1/",
+		"keeps a root URL":                "http://custom-host:8080",
+	}
+
+	for name, baseURL := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(config.WithBaseURL(baseURL))
+			require.NoError(t, err)
+			require.Equal(t, "http://custom-host:8080", provider.serverURL())
+		})
+	}
+}
+
+// Integration tests - only run if llama-server is available.
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	provider := llamacppProvider(t)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    "default",
+		Messages: testutil.SimpleMessages(),
+		Extra:    map[string]any{"grammar": `root ::= "yes" | "no"`, "cache_prompt": true},
+	})
+	require.NoError(t, err)
+	require.Contains(t, []string{"yes", "no"}, resp.Choices[0].Message.Content)
+}
+
+func TestIntegrationTokenize(t *testing.T) {
+	t.Parallel()
+
+	provider := llamacppProvider(t)
+
+	tokens, err := provider.Tokenize(context.Background(), "", "Hello, world")
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens)
+}
+
+// llamacppProvider returns a provider for the local llama-server, skipping the test if it is not available.
+func llamacppProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testLlamaCppAvailabilityTimeout)
+	defer cancel()
+
+	provider, err := New()
+	if err != nil {
+		t.Skip("llama.cpp server not available")
+	}
+
+	if err := provider.Health(ctx); err != nil {
+		t.Skip("llama.cpp server not available")
+	}
+
+	return provider
+}
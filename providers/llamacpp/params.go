@@ -0,0 +1,53 @@
+package llamacpp
+
+// Param sets a llama-server request parameter in a CompletionParams.Extra map.
+// Use Extra to build the map, or apply a Param to an existing, non-nil map.
+type Param func(extra map[string]any)
+
+// Extra returns a CompletionParams.Extra map holding the given parameters.
+//
+//	resp, err := provider.Completion(ctx, providers.CompletionParams{
+//		Model:    "default",
+//		Messages: messages,
+//		Extra:    llamacpp.Extra(llamacpp.Grammar(`root ::= "yes" | "no"`), llamacpp.CachePrompt(true)),
+//	})
+func Extra(params ...Param) map[string]any {
+	extra := make(map[string]any, len(params))
+	for _, param := range params {
+		if param != nil {
+			param(extra)
+		}
+	}
+
+	return extra
+}
+
+// CachePrompt sets whether the server reuses the KV cache of a previous request that shares
+// the prompt's prefix, so only the new suffix is evaluated.
+func CachePrompt(enabled bool) Param {
+	return func(extra map[string]any) {
+		extra[extraCachePrompt] = enabled
+	}
+}
+
+// Grammar constrains the output to a GBNF grammar. It cannot be combined with a JSON schema
+// ResponseFormat, which the server turns into a grammar itself.
+func Grammar(gbnf string) Param {
+	return func(extra map[string]any) {
+		extra[extraGrammar] = gbnf
+	}
+}
+
+// NProbs asks for the probabilities of the n most likely tokens at each position.
+func NProbs(n int) Param {
+	return func(extra map[string]any) {
+		extra[extraNProbs] = n
+	}
+}
+
+// SlotID runs the request in the given server slot, e.g. to keep a conversation's cache in one slot.
+func SlotID(id int) Param {
+	return func(extra map[string]any) {
+		extra[extraSlotID] = id
+	}
+}
@@ -26,6 +26,12 @@ var (
 	_ providers.Provider           = (*Provider)(nil)
 )
 
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
 // Provider implements the providers.Provider interface for Llamafile.
 // It embeds openai.CompatibleProvider since Llamafile exposes an OpenAI-compatible API.
 type Provider struct {
@@ -53,12 +59,20 @@ func New(opts ...config.Option) (*Provider, error) {
 // llamafileCapabilities returns the capabilities for the Llamafile provider.
 func llamafileCapabilities() providers.Capabilities {
 	return providers.Capabilities{
-		Completion:          true,
-		CompletionImage:     true, // Depends on the model loaded.
-		CompletionPDF:       false,
-		CompletionReasoning: false, // Llamafile doesn't support reasoning natively.
-		CompletionStreaming: true,
-		Embedding:           true,
-		ListModels:          true,
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Depends on the model loaded.
+		CompletionPDF:              false,
+		CompletionReasoning:        false, // Llamafile doesn't support reasoning natively.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
 	}
 }
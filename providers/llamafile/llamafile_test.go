@@ -2,6 +2,7 @@ package llamafile
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -62,9 +63,11 @@ func TestCapabilities(t *testing.T) {
 
 	require.True(t, caps.Completion)
 	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionStructuredOutput)
 	require.False(t, caps.CompletionReasoning)
 	require.True(t, caps.CompletionImage)
 	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
 	require.True(t, caps.Embedding)
 	require.True(t, caps.ListModels)
 }
@@ -77,6 +80,47 @@ func TestProviderName(t *testing.T) {
 	require.Equal(t, "llamafile", provider.Name())
 }
 
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "openai.(*CompatibleProvider).CompletionStream"
+
+	server := testutil.NewEndlessStreamServer(t, "text/event-stream", nil, func(i int) string {
+		return fmt.Sprintf(
+			"data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o-mini\","+
+				"\"choices\":[{\"index\":0,\"delta\":{\"content\":\"%d \"}}]}\n\n",
+			i,
+		)
+	})
+
+	provider, err := New(config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: "LLaMA_CPP", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, "0 ", chunk.Choices[0].Delta.Content)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
 // Integration tests - only run if Llamafile is available.
 
 func TestIntegrationCompletion(t *testing.T) {
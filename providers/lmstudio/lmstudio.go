@@ -0,0 +1,135 @@
+// Package lmstudio provides an LM Studio provider implementation for any-llm.
+//
+// LM Studio serves local models through an OpenAI-compatible API, so the provider is built on
+// openai.CompatibleProvider and validates LM Studio's model loading parameters.
+package lmstudio
+
+import (
+	"maps"
+
+	openaisdk "github.com/openai/openai-go"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultAPIKey  = "lm-studio" // Dummy key; LM Studio only checks keys when authentication is enabled.
+	defaultBaseURL = "http://localhost:1234/v1"
+	envAPIKey      = "LMSTUDIO_API_KEY"
+	envBaseURL     = "LMSTUDIO_BASE_URL"
+	providerName   = "lmstudio"
+)
+
+// LM Studio API constants.
+const (
+	reasoningField = "reasoning_content"
+)
+
+// Model loading parameters, validated before they are sent.
+// Other keys in CompletionParams.Extra are sent as-is; LM Studio honors the sampling fields it
+// documents beyond the OpenAI API, such as top_k and repeat_penalty.
+const (
+	extraDraftModel = "draft_model"
+	extraTTL        = "ttl"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for LM Studio.
+// It embeds openai.CompatibleProvider since LM Studio exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a provider for LM Studio's local server, at LMSTUDIO_BASE_URL or localhost:1234 by default.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		BaseURLEnvVar:  envBaseURL,
+		Capabilities:   lmstudioCapabilities(),
+		DefaultAPIKey:  defaultAPIKey,
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  false,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// lmstudioCapabilities returns the capabilities for the LM Studio provider.
+func lmstudioCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Depends on the model loaded.
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // Reasoning models, with reasoning separated from content.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true, // Needs an embedding model.
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// prepareRequest validates LM Studio's parameters: the seconds a just-in-time loaded model
+// stays loaded while idle, and the draft model for speculative decoding.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	fields := maps.Clone(req.ExtraFields())
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	for _, key := range extra.Keys(params.Extra) {
+		value := params.Extra[key]
+
+		var err error
+		switch key {
+		case extraDraftModel:
+			var v string
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		case extraTTL:
+			var v int
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		}
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	if len(fields) > 0 {
+		req.SetExtraFields(fields)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package lmstudio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testLMStudioAvailabilityTimeout = 5 * time.Second
+
+func TestNew(t *testing.T) {
+	// Note: Not using t.Parallel() here because child test uses t.Setenv.
+
+	t.Run("creates provider with default settings", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "lmstudio", provider.Name())
+		require.Equal(t, "http://localhost:1234/v1", provider.BaseURL())
+	})
+
+	t.Run("creates provider from LMSTUDIO_BASE_URL environment variable", func(t *testing.T) {
+		t.Setenv("LMSTUDIO_BASE_URL", "http://custom-host:1234/v1")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "http://custom-host:1234/v1", provider.BaseURL())
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ListModels)
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends LM Studio parameters and reads reasoning_content", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/chat/completions", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"id": "chatcmpl-1",
+				"object": "chat.completion",
+				"created": 1700000000,
+				"model": "qwen/qwen3-8b",
+				"choices": [{
+					"index": 0,
+					"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."},
+					"finish_reason": "stop"
+				}],
+				"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+			}`))
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := New(config.WithBaseURL(server.URL + "/v1"))
+		require.NoError(t, err)
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "qwen/qwen3-8b",
+			Messages: testutil.SimpleMessages(),
+			Extra:    map[string]any{"draft_model": "qwen/qwen3-0.6b", "ttl": 300},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "qwen/qwen3-0.6b", body["draft_model"])
+		require.Equal(t, float64(300), body["ttl"])
+
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	invalid := map[string]map[string]any{
+		"ttl that is not an integer":       {"ttl": "5m"},
+		"draft_model that is not a string": {"draft_model": true},
+	}
+
+	for name, extra := range invalid {
+		t.Run("rejects "+name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New()
+			require.NoError(t, err)
+
+			_, err = provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    "qwen/qwen3-8b",
+				Messages: testutil.SimpleMessages(),
+				Extra:    extra,
+			})
+			require.ErrorIs(t, err, errors.ErrInvalidRequest)
+		})
+	}
+}
+
+// Integration tests - only run if LM Studio is available.
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testLMStudioAvailabilityTimeout)
+	defer cancel()
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	models, err := provider.ListModels(ctx)
+	if err != nil || len(models.Data) == 0 {
+		t.Skip("LM Studio not available")
+	}
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    models.Data[0].ID,
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+}
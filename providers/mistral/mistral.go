@@ -0,0 +1,402 @@
+// Package mistral provides a Mistral AI provider implementation for any-llm.
+//
+// Mistral's API is close to OpenAI's, so the provider is built on openai.CompatibleProvider
+// and adjusts requests and responses where the two differ.
+package mistral
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	openaisdk "github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.mistral.ai/v1"
+	envAPIKey      = "MISTRAL_API_KEY"
+	providerName   = "mistral"
+)
+
+// Mistral API constants.
+const (
+	objectChatCompletion = "chat.completion"
+	pathFIMCompletions   = "fim/completions"
+	promptModeReasoning  = "reasoning"
+	toolCallIDLength     = 9
+)
+
+// Request fields Mistral names differently from OpenAI, or that OpenAI does not have.
+const (
+	fieldOutputDimension = "output_dimension"
+	fieldPrefix          = "prefix"
+	fieldPromptMode      = "prompt_mode"
+	fieldRandomSeed      = "random_seed"
+)
+
+// Content chunk types.
+const (
+	chunkTypeText     = "text"
+	chunkTypeThinking = "thinking"
+)
+
+// Extra parameters validated before they are sent.
+// Other keys in CompletionParams.Extra are sent as-is in the request body.
+const (
+	extraSafePrompt = "safe_prompt"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// FIMParams are the parameters of a fill-in-the-middle completion.
+// Fields are ordered alphabetically.
+type FIMParams struct {
+	// MaxTokens is the maximum number of tokens to generate.
+	MaxTokens *int
+
+	// MinTokens is the minimum number of tokens to generate.
+	MinTokens *int
+
+	// Model is the code model to use, e.g. "codestral-latest".
+	Model string
+
+	// Prompt is the code before the gap.
+	Prompt string
+
+	// Seed makes sampling deterministic.
+	Seed *int
+
+	// Stop lists sequences at which generation stops.
+	Stop []string
+
+	// Suffix is the code after the gap.
+	Suffix string
+
+	// Temperature is the sampling temperature.
+	Temperature *float64
+
+	// TopP is the nucleus sampling probability.
+	TopP *float64
+}
+
+// Provider implements the providers.Provider interface for Mistral AI.
+// It embeds openai.CompatibleProvider since Mistral exposes an OpenAI-like API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// contentChunk is an element of array-valued message content, as returned by reasoning models.
+type contentChunk struct {
+	Text     string         `json:"text"`
+	Thinking []contentChunk `json:"thinking"`
+	Type     string         `json:"type"`
+}
+
+// fimRequest is the request body of the FIM completions endpoint.
+type fimRequest struct {
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	MinTokens   *int     `json:"min_tokens,omitempty"`
+	Model       string   `json:"model"`
+	Prompt      string   `json:"prompt"`
+	RandomSeed  *int     `json:"random_seed,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+	Suffix      string   `json:"suffix,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+// New creates a new Mistral provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:            envAPIKey,
+		Capabilities:            mistralCapabilities(),
+		ConvertChunk:            convertChunk,
+		ConvertResponse:         convertResponse,
+		DefaultBaseURL:          defaultBaseURL,
+		Name:                    providerName,
+		PrepareEmbeddingRequest: prepareEmbeddingRequest,
+		PrepareRequest:          prepareRequest,
+		RequireAPIKey:           true,
+		UseMaxTokens:            true, // Mistral has no max_completion_tokens.
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// FIMCompletion fills in the code between params.Prompt and params.Suffix.
+// The generated code is returned as the content of the first choice's message.
+func (p *Provider) FIMCompletion(ctx context.Context, params FIMParams) (*providers.ChatCompletion, error) {
+	if params.Model == "" {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("model is required"))
+	}
+
+	req := fimRequest{
+		MaxTokens:   params.MaxTokens,
+		MinTokens:   params.MinTokens,
+		Model:       params.Model,
+		Prompt:      params.Prompt,
+		RandomSeed:  params.Seed,
+		Stop:        params.Stop,
+		Suffix:      params.Suffix,
+		Temperature: params.Temperature,
+		TopP:        params.TopP,
+	}
+
+	var resp openaisdk.ChatCompletion
+	if err := p.Client().Post(ctx, pathFIMCompletions, req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertFIMResponse(&resp), nil
+}
+
+// convertChunk extracts text and thinking from array-valued delta content.
+func convertChunk(chunk *openaisdk.ChatCompletionChunk, result *providers.ChatCompletionChunk) {
+	for i, choice := range chunk.Choices {
+		text, thinking, ok := parseContent(choice.Delta.JSON.Content.Raw())
+		if !ok {
+			continue
+		}
+
+		result.Choices[i].Delta.Content = text
+		if thinking != "" {
+			result.Choices[i].Delta.Reasoning = &providers.Reasoning{Content: thinking}
+		}
+	}
+}
+
+// convertFIMResponse converts a FIM completion response to provider format.
+func convertFIMResponse(resp *openaisdk.ChatCompletion) *providers.ChatCompletion {
+	choices := make([]providers.Choice, 0, len(resp.Choices))
+	for _, choice := range resp.Choices {
+		choices = append(choices, providers.Choice{
+			Index: int(choice.Index),
+			Message: providers.Message{
+				Role:    string(choice.Message.Role),
+				Content: choice.Message.Content,
+			},
+			FinishReason: string(choice.FinishReason),
+		})
+	}
+
+	result := &providers.ChatCompletion{
+		ID:      resp.ID,
+		Object:  objectChatCompletion,
+		Created: resp.Created,
+		Model:   resp.Model,
+		Choices: choices,
+	}
+
+	if resp.Usage.PromptTokens > 0 || resp.Usage.CompletionTokens > 0 {
+		result.Usage = &providers.Usage{
+			PromptTokens:     int(resp.Usage.PromptTokens),
+			CompletionTokens: int(resp.Usage.CompletionTokens),
+			TotalTokens:      int(resp.Usage.TotalTokens),
+		}
+	}
+
+	return result
+}
+
+// convertResponse extracts text and thinking from array-valued message content.
+func convertResponse(resp *openaisdk.ChatCompletion, result *providers.ChatCompletion) {
+	for i, choice := range resp.Choices {
+		text, thinking, ok := parseContent(choice.Message.JSON.Content.Raw())
+		if !ok {
+			continue
+		}
+
+		result.Choices[i].Message.Content = text
+		if thinking != "" {
+			result.Choices[i].Message.Reasoning = &providers.Reasoning{Content: thinking}
+		}
+	}
+}
+
+// isToolCallID reports whether id is nine alphanumeric characters.
+func isToolCallID(id string) bool {
+	if len(id) != toolCallIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mistralCapabilities returns the capabilities for the Mistral provider.
+func mistralCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true,  // Pixtral and Mistral Medium/Small models accept images.
+		CompletionPDF:              false, // Documents go through the separate OCR API.
+		CompletionReasoning:        true,  // Magistral models.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// parseContent parses array-valued message content into its text and thinking.
+// It reports false when raw is not an array, in which case the standard conversion applies.
+func parseContent(raw string) (string, string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		return "", "", false
+	}
+
+	var chunks []contentChunk
+	if err := json.Unmarshal([]byte(raw), &chunks); err != nil {
+		return "", "", false
+	}
+
+	var text, thinking strings.Builder
+	for _, chunk := range chunks {
+		switch chunk.Type {
+		case chunkTypeText:
+			text.WriteString(chunk.Text)
+		case chunkTypeThinking:
+			for _, t := range chunk.Thinking {
+				thinking.WriteString(t.Text)
+			}
+		}
+	}
+
+	return text.String(), thinking.String(), true
+}
+
+// prepareEmbeddingRequest renames the fields of an embedding request that Mistral names differently.
+func prepareEmbeddingRequest(req *openaisdk.EmbeddingNewParams, params providers.EmbeddingParams) error {
+	if params.User != "" {
+		return errors.NewUnsupportedParamError(providerName, "user")
+	}
+
+	if params.Dimensions != nil {
+		req.Dimensions = param.Opt[int64]{}
+		req.SetExtraFields(map[string]any{fieldOutputDimension: *params.Dimensions})
+	}
+
+	return nil
+}
+
+// prepareRequest adapts an OpenAI request to Mistral's chat completions API.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	if params.TopLogprobs != nil {
+		return errors.NewUnsupportedParamError(providerName, "top_logprobs")
+	}
+	if params.Logprobs {
+		return errors.NewUnsupportedParamError(providerName, "logprobs")
+	}
+	if params.User != "" {
+		return errors.NewUnsupportedParamError(providerName, "user")
+	}
+
+	if value, ok := params.Extra[extraSafePrompt]; ok {
+		var v bool
+		if err := extra.Decode(extraSafePrompt, value, &v); err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	fields := maps.Clone(req.ExtraFields())
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	if req.Seed.Valid() {
+		fields[fieldRandomSeed] = req.Seed.Value
+		req.Seed = param.Opt[int64]{}
+	}
+
+	// Magistral models always reason; the reasoning prompt mode asks for their default reasoning system prompt.
+	if req.ReasoningEffort != "" {
+		fields[fieldPromptMode] = promptModeReasoning
+		req.ReasoningEffort = ""
+	}
+
+	// Mistral reports usage in the final chunk of every stream and rejects stream_options.
+	req.StreamOptions = openaisdk.ChatCompletionStreamOptionsParam{}
+
+	if len(fields) > 0 {
+		req.SetExtraFields(fields)
+	}
+
+	prepareMessages(req.Messages)
+
+	return nil
+}
+
+// prepareMessages rewrites tool call IDs into Mistral's format and marks a trailing assistant
+// message as a prefix, which the model continues rather than answers.
+func prepareMessages(messages []openaisdk.ChatCompletionMessageParamUnion) {
+	for _, msg := range messages {
+		switch {
+		case msg.OfAssistant != nil:
+			for i := range msg.OfAssistant.ToolCalls {
+				msg.OfAssistant.ToolCalls[i].ID = toolCallID(msg.OfAssistant.ToolCalls[i].ID)
+			}
+		case msg.OfTool != nil:
+			msg.OfTool.ToolCallID = toolCallID(msg.OfTool.ToolCallID)
+		}
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	last := messages[len(messages)-1].OfAssistant
+	if last != nil && len(last.ToolCalls) == 0 {
+		last.SetExtraFields(map[string]any{fieldPrefix: true})
+	}
+}
+
+// toolCallID returns id if it is a valid Mistral tool call ID (nine alphanumeric characters).
+// Otherwise, such as for IDs recorded from other providers, it derives one from id, so that
+// a tool call and its result keep matching IDs.
+func toolCallID(id string) string {
+	if isToolCallID(id) {
+		return id
+	}
+
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:toolCallIDLength]
+}
@@ -0,0 +1,473 @@
+package mistral
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+const chatResponse = `{
+	"id": "cmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "mistral-small-latest",
+	"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hello!"}, "finish_reason": "stop"}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "mistral", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("MISTRAL_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("MISTRAL_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "mistral", missingKeyErr.Provider)
+		require.Equal(t, "MISTRAL_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(config.WithAPIKey(testAPIKey))
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ListModels)
+}
+
+func TestToolCallID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id string
+	}{
+		"OpenAI ID":    {id: "call_abc123XYZ"},
+		"Anthropic ID": {id: "toolu_01A09q90qw90lq917835lq9"},
+		"empty ID":     {id: ""},
+		"short ID":     {id: "abc"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := toolCallID(tc.id)
+			require.True(t, isToolCallID(got))
+			require.Equal(t, got, toolCallID(tc.id), "rewriting must be deterministic")
+		})
+	}
+
+	t.Run("keeps valid IDs", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "D681PevKs", toolCallID("D681PevKs"))
+	})
+
+	t.Run("maps different IDs to different IDs", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotEqual(t, toolCallID("call_1"), toolCallID("call_2"))
+	})
+}
+
+func TestParseContent(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		raw          string
+		wantText     string
+		wantThinking string
+		wantOK       bool
+	}{
+		"string content": {
+			raw: `"Hello"`,
+		},
+		"empty content": {
+			raw: "",
+		},
+		"text and thinking chunks": {
+			raw: `[
+				{"type": "thinking", "thinking": [{"type": "text", "text": "Let me "}, {"type": "text", "text": "think."}]},
+				{"type": "text", "text": "The answer is 4."}
+			]`,
+			wantText:     "The answer is 4.",
+			wantThinking: "Let me think.",
+			wantOK:       true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			text, thinking, ok := parseContent(tc.raw)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.wantText, text)
+			require.Equal(t, tc.wantThinking, thinking)
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("adapts the request to Mistral", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		handler := testutil.RecordRequest(t, &body, chatResponse)
+		provider := testutil.NewTestProvider(t, New, handler, config.WithAPIKey(testAPIKey))
+
+		maxTokens := 100
+		seed := 42
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:           "magistral-small-latest",
+			Messages:        testutil.SimpleMessages(),
+			MaxTokens:       &maxTokens,
+			Seed:            &seed,
+			ReasoningEffort: providers.ReasoningEffortMedium,
+			Extra:           map[string]any{"safe_prompt": true},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Hello!", resp.Choices[0].Message.Content)
+
+		require.Equal(t, float64(100), body["max_tokens"])
+		require.Equal(t, float64(42), body["random_seed"])
+		require.Equal(t, "reasoning", body["prompt_mode"])
+		require.Equal(t, true, body["safe_prompt"])
+		require.NotContains(t, body, "max_completion_tokens")
+		require.NotContains(t, body, "seed")
+		require.NotContains(t, body, "reasoning_effort")
+	})
+
+	t.Run("rewrites tool call IDs from other providers", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		handler := testutil.RecordRequest(t, &body, chatResponse)
+		provider := testutil.NewTestProvider(t, New, handler, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "mistral-small-latest",
+			Messages: testutil.AgentLoopMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool()},
+		})
+		require.NoError(t, err)
+
+		messages := body["messages"].([]any)
+		var callIDs, resultIDs []string
+		for _, m := range messages {
+			msg := m.(map[string]any)
+			for _, tc := range asSlice(msg["tool_calls"]) {
+				callIDs = append(callIDs, tc.(map[string]any)["id"].(string))
+			}
+			if id, ok := msg["tool_call_id"].(string); ok {
+				resultIDs = append(resultIDs, id)
+			}
+		}
+
+		require.NotEmpty(t, callIDs)
+		require.Equal(t, callIDs, resultIDs)
+		for _, id := range callIDs {
+			require.True(t, isToolCallID(id), "invalid tool call ID %q", id)
+		}
+	})
+
+	t.Run("sends a trailing assistant message as a prefix", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		handler := testutil.RecordRequest(t, &body, chatResponse)
+		provider := testutil.NewTestProvider(t, New, handler, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model: "mistral-small-latest",
+			Messages: []providers.Message{
+				{Role: providers.RoleUser, Content: "Write a haiku."},
+				{Role: providers.RoleAssistant, Content: "Autumn"},
+			},
+		})
+		require.NoError(t, err)
+
+		messages := body["messages"].([]any)
+		require.NotContains(t, messages[0].(map[string]any), "prefix")
+		require.Equal(t, true, messages[1].(map[string]any)["prefix"])
+	})
+
+	t.Run("parses thinking content", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, testutil.RecordRequest(t, &body, `{
+			"id": "cmpl-1",
+			"model": "magistral-small-latest",
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": [
+					{"type": "thinking", "thinking": [{"type": "text", "text": "2 plus 2 is 4."}]},
+					{"type": "text", "text": "4"}
+				]},
+				"finish_reason": "stop"
+			}]
+		}`), config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "magistral-small-latest",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	t.Run("rejects unsupported parameters", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "mistral-small-latest",
+			Messages: testutil.SimpleMessages(),
+			Logprobs: true,
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+
+	t.Run("rejects a non-boolean safe_prompt", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "mistral-small-latest",
+			Messages: testutil.SimpleMessages(),
+			Extra:    map[string]any{"safe_prompt": "yes"},
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.NotContains(t, body, "stream_options")
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, data := range []string{
+			`{"id":"1","model":"m","choices":[{"index":0,"delta":{"role":"assistant","content":[` +
+				`{"type":"thinking","thinking":[{"type":"text","text":"Hmm."}]}]}}]}`,
+			`{"id":"1","model":"m","choices":[{"index":0,"delta":{"content":"Hi"}}]}`,
+			`{"id":"1","model":"m","choices":[{"index":0,"delta":{"content":""},"finish_reason":"stop"}],` +
+				`"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+		} {
+			_, _ = w.Write([]byte("data: " + data + "\n\n"))
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}, config.WithAPIKey(testAPIKey))
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:         "magistral-small-latest",
+		Messages:      testutil.SimpleMessages(),
+		Stream:        true,
+		StreamOptions: &providers.StreamOptions{IncludeUsage: true},
+	})
+
+	var content, reasoning strings.Builder
+	var usage *providers.Usage
+	for chunk := range chunks {
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		content.WriteString(chunk.Choices[0].Delta.Content)
+		if chunk.Choices[0].Delta.Reasoning != nil {
+			reasoning.WriteString(chunk.Choices[0].Delta.Reasoning.Content)
+		}
+	}
+	require.NoError(t, <-errs)
+
+	require.Equal(t, "Hi", content.String())
+	require.Equal(t, "Hmm.", reasoning.String())
+	require.NotNil(t, usage)
+	require.Equal(t, 5, usage.TotalTokens)
+}
+
+func TestEmbedding(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	provider := testutil.NewTestProvider(t, New, testutil.RecordRequest(t, &body, `{
+		"id": "emb-1",
+		"object": "list",
+		"model": "mistral-embed",
+		"data": [{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}],
+		"usage": {"prompt_tokens": 2, "total_tokens": 2}
+	}`), config.WithAPIKey(testAPIKey))
+
+	dims := 256
+	resp, err := provider.Embedding(context.Background(), providers.EmbeddingParams{
+		Model:      "codestral-embed",
+		Input:      "Hello",
+		Dimensions: &dims,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 0.2}, resp.Data[0].Embedding)
+
+	require.Equal(t, float64(256), body["output_dimension"])
+	require.NotContains(t, body, "dimensions")
+}
+
+func TestFIMCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fills in the middle", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			testutil.RecordRequest(t, &body, `{
+				"id": "fim-1",
+				"object": "chat.completion",
+				"model": "codestral-latest",
+				"choices": [{"index": 0, "message": {"role": "assistant", "content": "a + b"}, "finish_reason": "stop"}],
+				"usage": {"prompt_tokens": 10, "completion_tokens": 3, "total_tokens": 13}
+			}`)(w, r)
+		}, config.WithAPIKey(testAPIKey))
+
+		maxTokens := 64
+		resp, err := provider.FIMCompletion(context.Background(), FIMParams{
+			Model:     "codestral-latest",
+			Prompt:    "def add(a, b):\n    return ",
+			Suffix:    "\n",
+			MaxTokens: &maxTokens,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "/fim/completions", path)
+		require.Equal(t, "codestral-latest", body["model"])
+		require.Equal(t, "def add(a, b):\n    return ", body["prompt"])
+		require.Equal(t, "\n", body["suffix"])
+		require.Equal(t, float64(64), body["max_tokens"])
+
+		require.Equal(t, "a + b", resp.Choices[0].Message.Content)
+		require.Equal(t, "stop", resp.Choices[0].FinishReason)
+		require.Equal(t, 13, resp.Usage.TotalTokens)
+	})
+
+	t.Run("requires a model", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+
+		_, err = provider.FIMCompletion(context.Background(), FIMParams{Prompt: "x"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+
+	t.Run("converts API errors", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Unauthorized"}`))
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.FIMCompletion(context.Background(), FIMParams{Model: "codestral-latest", Prompt: "x"})
+		require.ErrorIs(t, err, errors.ErrAuthentication)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("mistral") {
+		t.Skip("MISTRAL_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("mistral"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestIntegrationAgentLoop(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("mistral") {
+		t.Skip("MISTRAL_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("mistral"),
+		Messages: testutil.AgentLoopMessages(),
+		Tools:    []providers.Tool{testutil.WeatherTool()},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+}
+
+// asSlice returns v as a slice, or nil if it is not one.
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
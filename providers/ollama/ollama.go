@@ -8,7 +8,11 @@ import (
 	"encoding/json"
 	stderrors "errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +20,7 @@ import (
 
 	"github.com/mozilla-ai/any-llm-go/config"
 	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
 	"github.com/mozilla-ai/any-llm-go/providers"
 )
 
@@ -33,6 +38,14 @@ const (
 	doneReasonStop   = "stop"
 )
 
+// Extra parameters that map onto typed request fields.
+// Other keys in CompletionParams.Extra must be model options, which are sent in Options.
+const (
+	extraKeepAlive = "keep_alive"
+	extraShift     = "shift"
+	extraTruncate  = "truncate"
+)
+
 // Ollama option keys.
 const (
 	optionNumCtx      = "num_ctx"
@@ -79,8 +92,10 @@ const (
 
 // Content part constants.
 const (
-	contentTypeImageURL = "image_url"
-	dataImagePrefix     = "data:image/"
+	contentTypeFile       = "file"
+	contentTypeImageURL   = "image_url"
+	contentTypeInputAudio = "input_audio"
+	dataImagePrefix       = "data:image/"
 )
 
 // Ensure Provider implements the required interfaces.
@@ -90,14 +105,30 @@ var (
 	_ providers.ErrorConverter     = (*Provider)(nil)
 	_ providers.ModelLister        = (*Provider)(nil)
 	_ providers.Provider           = (*Provider)(nil)
+	_ providers.TokenCounter       = (*Provider)(nil)
 )
 
+// optionNames is the set of model options Ollama accepts in a request's Options.
+var optionNames = jsonFieldNames(reflect.TypeFor[api.Options]())
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
 // Provider implements the providers.Provider interface for Ollama.
 type Provider struct {
 	client *api.Client
 	config *config.Config
 }
 
+// headerTransport sets the configured headers on every request before sending it.
+type headerTransport struct {
+	base   http.RoundTripper
+	config *config.Config
+}
+
 // streamState tracks accumulated state during streaming.
 type streamState struct {
 	id        string
@@ -127,7 +158,15 @@ func New(opts ...config.Option) (*Provider, error) {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
 
-	client := api.NewClient(parsedURL, cfg.HTTPClient())
+	httpClient := cfg.HTTPClient()
+	if len(cfg.Headers) > 0 {
+		// The Ollama client sets its own headers, so configured headers are added by the transport.
+		withHeaders := *httpClient
+		withHeaders.Transport = &headerTransport{base: httpClient.Transport, config: cfg}
+		httpClient = &withHeaders
+	}
+
+	client := api.NewClient(parsedURL, httpClient)
 
 	return &Provider{
 		client: client,
@@ -138,13 +177,21 @@ func New(opts ...config.Option) (*Provider, error) {
 // Capabilities returns the provider's capabilities.
 func (p *Provider) Capabilities() providers.Capabilities {
 	return providers.Capabilities{
-		Completion:          true,
-		CompletionStreaming: true,
-		CompletionReasoning: true,
-		CompletionImage:     true,
-		CompletionPDF:       false,
-		Embedding:           true,
-		ListModels:          true,
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionStreaming:        true,
+		CompletionReasoning:        true,
+		CompletionImage:            true,
+		CompletionPDF:              false,
+		CompletionStructuredOutput: true,
+		CountTokens:                true,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
 	}
 }
 
@@ -153,7 +200,14 @@ func (p *Provider) Completion(
 	ctx context.Context,
 	params providers.CompletionParams,
 ) (*providers.ChatCompletion, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
 	req := p.convertParams(params)
+	if err := applyExtra(req, params.Extra); err != nil {
+		return nil, err
+	}
 
 	// Disable streaming for non-stream requests.
 	stream := false
@@ -183,13 +237,26 @@ func (p *Provider) CompletionStream(
 		defer close(chunks)
 		defer close(errs)
 
+		if err := validateParams(params); err != nil {
+			errs <- err
+			return
+		}
+
 		req := p.convertParams(params)
+		if err := applyExtra(req, params.Extra); err != nil {
+			errs <- err
+			return
+		}
+
 		state := newStreamState()
 
 		err := p.client.Chat(ctx, req, func(resp api.ChatResponse) error {
-			chunk := state.handleChunk(&resp)
-			chunks <- chunk
-			return nil
+			select {
+			case chunks <- state.handleChunk(&resp):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		})
 		if err != nil {
 			errs <- p.ConvertError(err)
@@ -228,6 +295,10 @@ func (p *Provider) ConvertError(err error) error {
 			}
 			return errors.NewInvalidRequestError(providerName, err)
 		}
+
+		providerErr := errors.NewProviderError(providerName, err)
+		providerErr.StatusCode = statusErr.StatusCode
+		return providerErr
 	}
 
 	// Network-level errors (connection refused, etc.) - string check acceptable here.
@@ -238,6 +309,38 @@ func (p *Provider) ConvertError(err error) error {
 	return errors.NewProviderError(providerName, err)
 }
 
+// CountTokens returns the number of tokens of the prompt the model would be given for params.
+// Ollama has no tokenizer endpoint, so unlike other token counters this loads the model and
+// generates a single token; the count is the number of prompt tokens Ollama reports evaluating.
+// Truncation is disabled, so a prompt longer than the context size (num_ctx) returns an
+// errors.ContextLengthError instead of a count clamped to it.
+func (p *Provider) CountTokens(ctx context.Context, params providers.CompletionParams) (*providers.TokenCount, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
+	req := p.convertParams(params)
+	if err := applyExtra(req, params.Extra); err != nil {
+		return nil, err
+	}
+
+	stream, truncate := false, false
+	req.Stream = &stream
+	req.Truncate = &truncate
+	req.Options[optionNumPredict] = 1
+
+	var response api.ChatResponse
+	err := p.client.Chat(ctx, req, func(resp api.ChatResponse) error {
+		response = resp
+		return nil
+	})
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return &providers.TokenCount{Model: params.Model, InputTokens: response.PromptEvalCount}, nil
+}
+
 // Embedding generates embeddings for the given input.
 func (p *Provider) Embedding(
 	ctx context.Context,
@@ -304,6 +407,14 @@ func (p *Provider) convertParams(params providers.CompletionParams) *api.ChatReq
 		req.Options[optionSeed] = *params.Seed
 	}
 
+	if params.Logprobs || params.TopLogprobs != nil {
+		req.Logprobs = true
+	}
+
+	if params.TopLogprobs != nil {
+		req.TopLogprobs = *params.TopLogprobs
+	}
+
 	if len(params.Tools) > 0 {
 		req.Tools = convertTools(params.Tools)
 	}
@@ -325,6 +436,19 @@ func (p *Provider) convertParams(params providers.CompletionParams) *api.ChatReq
 	return req
 }
 
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	t.config.ApplyHeaders(req.Header)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(req)
+}
+
 // newStreamState creates a new stream state.
 func newStreamState() *streamState {
 	return &streamState{
@@ -350,6 +474,7 @@ func (s *streamState) handleChunk(resp *api.ChatResponse) providers.ChatCompleti
 
 	chunk := s.chunk()
 	chunk.Choices[0].Delta = s.buildDelta(resp)
+	chunk.Choices[0].Logprobs = convertLogprobs(resp.Logprobs)
 
 	if resp.Done {
 		s.handleDone(resp, &chunk)
@@ -407,6 +532,45 @@ func (s *streamState) handleDone(resp *api.ChatResponse, chunk *providers.ChatCo
 	}
 }
 
+// applyExtra applies provider-specific parameters to req.
+// Known keys are decoded into their typed fields and model options (e.g. num_ctx, top_k,
+// min_p or repeat_penalty) are added to Options. Any other key is unsupported.
+func applyExtra(req *api.ChatRequest, params map[string]any) error {
+	for _, key := range extra.Keys(params) {
+		value := params[key]
+
+		var err error
+		switch key {
+		case extraKeepAlive:
+			var v api.Duration
+			err = extra.Decode(key, value, &v)
+			req.KeepAlive = &v
+		case extraShift:
+			var v bool
+			err = extra.Decode(key, value, &v)
+			req.Shift = &v
+		case extraTruncate:
+			var v bool
+			err = extra.Decode(key, value, &v)
+			req.Truncate = &v
+		default:
+			if !optionNames[key] {
+				return errors.NewUnsupportedParamError(providerName, key)
+			}
+			// Decode into api.Options to check the value has the option's type.
+			var opts api.Options
+			err = extra.Decode(key, map[string]any{key: value}, &opts)
+			req.Options[key] = value
+		}
+
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+	}
+
+	return nil
+}
+
 // convertAssistantMessage converts an assistant message to Ollama format.
 func convertAssistantMessage(msg providers.Message) *api.Message {
 	ollamaMsg := &api.Message{
@@ -477,6 +641,33 @@ func convertEmbeddingResponse(resp *api.EmbedResponse, model string) *providers.
 	}
 }
 
+// convertLogprobs converts Ollama token log probabilities to provider format.
+// It returns nil when there are none.
+func convertLogprobs(logprobs []api.Logprob) *providers.Logprobs {
+	if len(logprobs) == 0 {
+		return nil
+	}
+
+	result := &providers.Logprobs{Content: make([]providers.TokenLogprob, 0, len(logprobs))}
+	for _, lp := range logprobs {
+		token := providers.TokenLogprob{
+			Token:   lp.Token,
+			Bytes:   lp.Bytes,
+			Logprob: lp.Logprob,
+		}
+		for _, top := range lp.TopLogprobs {
+			token.TopLogprobs = append(token.TopLogprobs, providers.TopLogprob{
+				Token:   top.Token,
+				Bytes:   top.Bytes,
+				Logprob: top.Logprob,
+			})
+		}
+		result.Content = append(result.Content, token)
+	}
+
+	return result
+}
+
 // convertMessage converts a single message to Ollama format.
 func convertMessage(msg providers.Message) *api.Message {
 	switch msg.Role {
@@ -557,6 +748,7 @@ func convertResponse(resp *api.ChatResponse) *providers.ChatCompletion {
 			Index:        0,
 			Message:      message,
 			FinishReason: finishReason,
+			Logprobs:     convertLogprobs(resp.Logprobs),
 		}},
 		Usage: &providers.Usage{
 			PromptTokens:     resp.PromptEvalCount,
@@ -598,9 +790,21 @@ func convertToolCalls(toolCalls []api.ToolCall) []providers.ToolCall {
 			}
 		}
 
+		// Prefer the server-assigned index so calls streamed in separate chunks get distinct IDs.
+		index := tc.Function.Index
+		if index == 0 {
+			index = i
+		}
+
+		id := tc.ID
+		if id == "" {
+			id = fmt.Sprintf(toolCallIDFormat, index)
+		}
+
 		result = append(result, providers.ToolCall{
-			ID:   fmt.Sprintf(toolCallIDFormat, i),
-			Type: toolTypeFunction,
+			Index: index,
+			ID:    id,
+			Type:  toolTypeFunction,
 			Function: providers.FunctionCall{
 				Name:      tc.Function.Name,
 				Arguments: args,
@@ -750,3 +954,49 @@ func generateID() string {
 	_, _ = rand.Read(b)
 	return fmt.Sprintf("chatcmpl-%d-%s", time.Now().UnixNano(), hex.EncodeToString(b))
 }
+
+// hasContentPart reports whether any message has a content part of the given type.
+func hasContentPart(messages []providers.Message, partType string) bool {
+	for _, msg := range messages {
+		for _, part := range msg.ContentParts() {
+			if part.Type == partType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct type t,
+// including the fields of embedded structs.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous {
+			maps.Copy(names, jsonFieldNames(field.Type))
+			continue
+		}
+
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+// validateParams rejects content and parameters that Ollama cannot honor.
+func validateParams(params providers.CompletionParams) error {
+	switch {
+	case hasContentPart(params.Messages, contentTypeFile):
+		return errors.NewUnsupportedParamError(providerName, "file content")
+	case hasContentPart(params.Messages, contentTypeInputAudio):
+		return errors.NewUnsupportedParamError(providerName, contentTypeInputAudio)
+	case params.Audio != nil || slices.Contains(params.Modalities, providers.ModalityAudio):
+		return errors.NewUnsupportedParamError(providerName, "audio")
+	}
+
+	return nil
+}
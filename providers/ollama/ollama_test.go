@@ -2,8 +2,12 @@ package ollama
 
 import (
 	"context"
+	"encoding/json"
 	stderrors "errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -58,13 +62,133 @@ func TestCapabilities(t *testing.T) {
 
 	require.True(t, caps.Completion)
 	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionStructuredOutput)
 	require.True(t, caps.CompletionReasoning)
 	require.True(t, caps.CompletionImage)
 	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CountTokens)
 	require.True(t, caps.Embedding)
 	require.True(t, caps.ListModels)
 }
 
+func TestApplyExtra(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps known keys and model options", func(t *testing.T) {
+		t.Parallel()
+
+		req := &api.ChatRequest{Options: map[string]any{optionNumCtx: defaultNumCtx}}
+		err := applyExtra(req, map[string]any{
+			"keep_alive":     "10m",
+			"min_p":          0.05,
+			"num_ctx":        8192,
+			"repeat_penalty": 1.1,
+			"shift":          false,
+			"top_k":          40,
+			"truncate":       true,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, 10*time.Minute, req.KeepAlive.Duration)
+		require.False(t, *req.Shift)
+		require.True(t, *req.Truncate)
+		require.Equal(t, map[string]any{
+			"min_p":          0.05,
+			"num_ctx":        8192,
+			"repeat_penalty": 1.1,
+			"top_k":          40,
+		}, req.Options)
+	})
+
+	t.Run("accepts keep_alive in seconds", func(t *testing.T) {
+		t.Parallel()
+
+		req := &api.ChatRequest{Options: map[string]any{}}
+		require.NoError(t, applyExtra(req, map[string]any{"keep_alive": 30}))
+		require.Equal(t, 30*time.Second, req.KeepAlive.Duration)
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		t.Parallel()
+
+		req := &api.ChatRequest{Options: map[string]any{}}
+		err := applyExtra(req, map[string]any{"logit_bias": map[string]int{"1": 1}})
+
+		var unsupportedErr *errors.UnsupportedParamError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, "logit_bias", unsupportedErr.Param)
+	})
+
+	t.Run("rejects option values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		req := &api.ChatRequest{Options: map[string]any{}}
+		err := applyExtra(req, map[string]any{"top_k": "forty"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestConvertLogprobs(t *testing.T) {
+	t.Parallel()
+
+	logprobs := []api.Logprob{{
+		TokenLogprob: api.TokenLogprob{Token: "Hi", Logprob: -0.1, Bytes: []int{72, 105}},
+		TopLogprobs: []api.TokenLogprob{
+			{Token: "Hi", Logprob: -0.1, Bytes: []int{72, 105}},
+			{Token: "Hey", Logprob: -2.5},
+		},
+	}}
+	want := &providers.Logprobs{Content: []providers.TokenLogprob{{
+		Token:   "Hi",
+		Bytes:   []int{72, 105},
+		Logprob: -0.1,
+		TopLogprobs: []providers.TopLogprob{
+			{Token: "Hi", Bytes: []int{72, 105}, Logprob: -0.1},
+			{Token: "Hey", Logprob: -2.5},
+		},
+	}}}
+
+	t.Run("converts response logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		resp := convertResponse(&api.ChatResponse{Logprobs: logprobs})
+		require.Equal(t, want, resp.Choices[0].Logprobs)
+	})
+
+	t.Run("converts chunk logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := newStreamState().handleChunk(&api.ChatResponse{Logprobs: logprobs})
+		require.Equal(t, want, chunk.Choices[0].Logprobs)
+	})
+
+	t.Run("returns nil without logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, convertLogprobs(nil))
+	})
+
+	t.Run("requests logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+		messages := []providers.Message{{Role: providers.RoleUser, Content: "Hello"}}
+
+		req := p.convertParams(providers.CompletionParams{Model: "llama3.2", Messages: messages})
+		require.False(t, req.Logprobs)
+
+		req = p.convertParams(providers.CompletionParams{Model: "llama3.2", Messages: messages, Logprobs: true})
+		require.True(t, req.Logprobs)
+		require.Zero(t, req.TopLogprobs)
+
+		top := 3
+		req = p.convertParams(providers.CompletionParams{Model: "llama3.2", Messages: messages, TopLogprobs: &top})
+		require.True(t, req.Logprobs)
+		require.Equal(t, 3, req.TopLogprobs)
+	})
+}
+
 func TestConvertMessages(t *testing.T) {
 	t.Parallel()
 
@@ -602,6 +726,17 @@ func TestConvertError(t *testing.T) {
 			require.True(t, stderrors.Is(result, tc.wantSentinel))
 		})
 	}
+
+	t.Run("ProviderError carries the status code", func(t *testing.T) {
+		t.Parallel()
+
+		p := &Provider{}
+
+		var providerErr *errors.ProviderError
+		err := p.ConvertError(api.StatusError{StatusCode: 503, ErrorMessage: "server busy"})
+		require.ErrorAs(t, err, &providerErr)
+		require.Equal(t, 503, providerErr.StatusCode)
+	})
 }
 
 func TestGenerateID(t *testing.T) {
@@ -616,6 +751,173 @@ func TestGenerateID(t *testing.T) {
 	require.NotEqual(t, id1, id2) // IDs should be unique.
 }
 
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "ollama.(*Provider).CompletionStream"
+
+	server := testutil.NewEndlessStreamServer(t, "application/x-ndjson", nil, func(i int) string {
+		return fmt.Sprintf(
+			"{\"model\":\"llama3.2\",\"created_at\":\"2024-01-01T00:00:00Z\","+
+				"\"message\":{\"role\":\"assistant\",\"content\":\"%d \"},\"done\":false}\n",
+			i,
+		)
+	})
+
+	provider, err := New(config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: "llama3.2", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, "0 ", chunk.Choices[0].Delta.Content)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
+func TestCountTokens(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/chat", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"model":"llama3.2","created_at":"2024-01-01T00:00:00Z",`+
+			`"message":{"role":"assistant","content":"Hello"},"done":true,"done_reason":"length",`+
+			`"prompt_eval_count":26,"eval_count":1}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	maxTokens := 1000
+	resp, err := provider.CountTokens(context.Background(), providers.CompletionParams{
+		Model:     "llama3.2",
+		Messages:  testutil.SimpleMessages(),
+		MaxTokens: &maxTokens,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, false, body["stream"])
+	require.Equal(t, false, body["truncate"])
+	require.Equal(t, float64(1), body["options"].(map[string]any)["num_predict"])
+	require.Equal(t, &providers.TokenCount{Model: "llama3.2", InputTokens: 26}, resp)
+}
+
+func TestCountTokensContextLength(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, false, body["truncate"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error":"input length exceeds the context length"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	// Truncation is disabled even if the caller asks for it.
+	_, err = provider.CountTokens(context.Background(), providers.CompletionParams{
+		Model:    "llama3.2",
+		Messages: testutil.SimpleMessages(),
+		Extra:    map[string]any{"truncate": true},
+	})
+	require.ErrorIs(t, err, errors.ErrContextLength)
+}
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/tags", r.URL.Path)
+		require.Equal(t, "Bearer proxy-token", r.Header.Get("Authorization"))
+		require.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"models":[]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(
+		config.WithBaseURL(server.URL),
+		config.WithHeaders(map[string]string{"Authorization": "Bearer proxy-token", "X-Trace-Id": "trace-1"}),
+	)
+	require.NoError(t, err)
+
+	_, err = provider.ListModels(context.Background())
+	require.NoError(t, err)
+}
+
+func TestUnsupportedContent(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	userParts := func(parts ...providers.ContentPart) []providers.Message {
+		return []providers.Message{{Role: providers.RoleUser, Content: parts}}
+	}
+
+	tests := map[string]providers.CompletionParams{
+		"file content": {Messages: userParts(providers.ContentPart{
+			Type: "file", File: &providers.File{Data: "JVBERi0="},
+		})},
+		"input_audio": {Messages: userParts(providers.ContentPart{
+			Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg==", Format: "wav"},
+		})},
+		"audio": {Messages: testutil.SimpleMessages(), Modalities: []string{providers.ModalityAudio}},
+	}
+
+	for param, params := range tests {
+		t.Run(param, func(t *testing.T) {
+			t.Parallel()
+
+			params.Model = "llama3.2"
+
+			var unsupportedErr *errors.UnsupportedParamError
+			_, err := provider.Completion(context.Background(), params)
+			require.ErrorAs(t, err, &unsupportedErr)
+			require.Equal(t, param, unsupportedErr.Param)
+
+			_, err = provider.CountTokens(context.Background(), params)
+			require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+			_, err = providers.Collect(provider.CompletionStream(context.Background(), params))
+			require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+		})
+	}
+}
+
 // Integration tests - only run if Ollama is available.
 
 func TestIntegrationCompletion(t *testing.T) {
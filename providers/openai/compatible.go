@@ -3,16 +3,27 @@
 package openai
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	stderrors "errors"
 	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/packages/respjson"
 	"github.com/openai/openai-go/shared"
 
 	"github.com/mozilla-ai/any-llm-go/config"
 	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
 	"github.com/mozilla-ai/any-llm-go/providers"
 )
 
@@ -37,8 +48,36 @@ const (
 
 // Content part types.
 const (
-	contentTypeImageURL = "image_url"
-	contentTypeText     = "text"
+	contentTypeFile       = "file"
+	contentTypeImageURL   = "image_url"
+	contentTypeInputAudio = "input_audio"
+	contentTypeText       = "text"
+	defaultFileMIMEType   = "application/pdf"
+)
+
+// Streaming delta fields that the SDK does not decode.
+const fieldAudio = "audio"
+
+// Image upload names and types. Masks must be PNG images.
+const (
+	defaultImageFilename = "image.png"
+	maskFilename         = "mask.png"
+	mimeTypePNG          = "image/png"
+)
+
+// Extra parameters that map onto typed request fields.
+// Other keys in CompletionParams.Extra are sent as-is in the request body.
+const (
+	extraFrequencyPenalty = "frequency_penalty"
+	extraLogitBias        = "logit_bias"
+	extraMaxTokens        = "max_tokens"
+	extraMetadata         = "metadata"
+	extraN                = "n"
+	extraPresencePenalty  = "presence_penalty"
+	extraPromptCacheKey   = "prompt_cache_key"
+	extraSafetyIdentifier = "safety_identifier"
+	extraServiceTier      = "service_tier"
+	extraStore            = "store"
 )
 
 // Response format types.
@@ -59,6 +98,22 @@ type CompatibleConfig struct {
 	// Capabilities describes what the provider supports.
 	Capabilities providers.Capabilities
 
+	// ClientOptions are appended to the options of the underlying OpenAI SDK client,
+	// e.g. to add middleware that rewrites requests for the provider.
+	ClientOptions []option.RequestOption
+
+	// ConvertChunk, if set, is called with each streaming chunk after the standard conversion,
+	// to map fields the OpenAI format does not have.
+	ConvertChunk func(chunk *openai.ChatCompletionChunk, result *providers.ChatCompletionChunk)
+
+	// ConvertError, if set, is tried before the standard error conversion, to recognize
+	// provider-specific error payloads. It returns nil for errors it does not recognize.
+	ConvertError func(err error) error
+
+	// ConvertResponse, if set, is called with each completion after the standard conversion,
+	// to map fields the OpenAI format does not have.
+	ConvertResponse func(resp *openai.ChatCompletion, result *providers.ChatCompletion)
+
 	// DefaultAPIKey is used when RequireAPIKey is false (e.g., for local servers).
 	DefaultAPIKey string
 
@@ -68,22 +123,41 @@ type CompatibleConfig struct {
 	// Name is the provider name used in error messages.
 	Name string
 
+	// PrepareEmbeddingRequest, if set, adjusts an embedding request before it is sent.
+	PrepareEmbeddingRequest func(req *openai.EmbeddingNewParams, params providers.EmbeddingParams) error
+
+	// PrepareRequest, if set, adjusts a completion request before it is sent, after the standard
+	// conversion and Extra have been applied. Its errors are returned unchanged.
+	PrepareRequest func(req *openai.ChatCompletionNewParams, params providers.CompletionParams) error
+
+	// ReasoningField, if set, names the non-standard message field that carries reasoning,
+	// e.g. "reasoning_content". Its text is returned in Message.Reasoning and ChunkDelta.Reasoning.
+	ReasoningField string
+
 	// RequireAPIKey indicates whether an API key is required.
 	RequireAPIKey bool
+
+	// UseMaxTokens sends MaxTokens as max_tokens, for servers that do not accept max_completion_tokens.
+	UseMaxTokens bool
 }
 
 // Ensure CompatibleProvider implements the required interfaces.
 var (
-	_ providers.CapabilityProvider = (*CompatibleProvider)(nil)
-	_ providers.EmbeddingProvider  = (*CompatibleProvider)(nil)
-	_ providers.ErrorConverter     = (*CompatibleProvider)(nil)
-	_ providers.ModelLister        = (*CompatibleProvider)(nil)
-	_ providers.Provider           = (*CompatibleProvider)(nil)
+	_ providers.CapabilityProvider      = (*CompatibleProvider)(nil)
+	_ providers.EmbeddingProvider       = (*CompatibleProvider)(nil)
+	_ providers.ErrorConverter          = (*CompatibleProvider)(nil)
+	_ providers.ImageGenerationProvider = (*CompatibleProvider)(nil)
+	_ providers.ModelLister             = (*CompatibleProvider)(nil)
+	_ providers.ModerationProvider      = (*CompatibleProvider)(nil)
+	_ providers.Provider                = (*CompatibleProvider)(nil)
+	_ providers.SpeechProvider          = (*CompatibleProvider)(nil)
+	_ providers.TranscriptionProvider   = (*CompatibleProvider)(nil)
 )
 
 // CompatibleProvider implements the providers.Provider interface for OpenAI-compatible APIs.
 // It can be embedded by other providers that use OpenAI-compatible endpoints.
 type CompatibleProvider struct {
+	baseURL          string
 	compatibleConfig CompatibleConfig
 	client           openai.Client
 }
@@ -122,17 +196,43 @@ func NewCompatible(compatCfg CompatibleConfig, opts ...config.Option) (*Compatib
 		clientOpts = append(clientOpts, option.WithBaseURL(baseURL))
 	}
 
+	clientOpts = append(clientOpts, compatCfg.ClientOptions...)
+	clientOpts = append(clientOpts, headerOptions(cfg.Headers)...)
+
 	return &CompatibleProvider{
+		baseURL:          baseURL,
 		compatibleConfig: compatCfg,
 		client:           openai.NewClient(clientOpts...),
 	}, nil
 }
 
+// BaseURL returns the API base URL requests are sent to, or "" for the OpenAI SDK default.
+func (p *CompatibleProvider) BaseURL() string {
+	return p.baseURL
+}
+
 // Capabilities returns the provider's capabilities.
 func (p *CompatibleProvider) Capabilities() providers.Capabilities {
 	return p.compatibleConfig.Capabilities
 }
 
+// ChatCompletionRequest returns the request Completion would send for params, for providers
+// that send the converted messages and tools to endpoints of their own.
+func (p *CompatibleProvider) ChatCompletionRequest(
+	params providers.CompletionParams,
+) (openai.ChatCompletionNewParams, error) {
+	if err := validateCompletionParams(params); err != nil {
+		return openai.ChatCompletionNewParams{}, err
+	}
+
+	return p.newRequest(params)
+}
+
+// Client returns the underlying OpenAI SDK client, for endpoints the CompatibleProvider does not cover.
+func (p *CompatibleProvider) Client() *openai.Client {
+	return &p.client
+}
+
 // Completion performs a chat completion request.
 func (p *CompatibleProvider) Completion(
 	ctx context.Context,
@@ -142,14 +242,36 @@ func (p *CompatibleProvider) Completion(
 		return nil, err
 	}
 
-	req := convertParams(params)
+	req, err := p.newRequest(params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := p.client.Chat.Completions.New(ctx, req)
 	if err != nil {
 		return nil, p.ConvertError(err)
 	}
 
-	return convertResponse(resp), nil
+	result := convertResponse(resp)
+	if params.Audio != nil {
+		for _, choice := range result.Choices {
+			if choice.Message.Audio != nil {
+				choice.Message.Audio.Format = params.Audio.Format
+			}
+		}
+	}
+	if field := p.compatibleConfig.ReasoningField; field != "" {
+		for i, choice := range resp.Choices {
+			if reasoning := reasoningFromField(choice.Message.JSON.ExtraFields, field); reasoning != nil {
+				result.Choices[i].Message.Reasoning = reasoning
+			}
+		}
+	}
+	if p.compatibleConfig.ConvertResponse != nil {
+		p.compatibleConfig.ConvertResponse(resp, result)
+	}
+
+	return result, nil
 }
 
 // CompletionStream performs a streaming chat completion request.
@@ -169,13 +291,37 @@ func (p *CompatibleProvider) CompletionStream(
 			return
 		}
 
-		req := convertParams(params)
+		req, err := p.newRequest(params)
+		if err != nil {
+			errs <- err
+			return
+		}
+
 		stream := p.client.Chat.Completions.NewStreaming(ctx, req)
 
 		for stream.Next() {
 			chunk := stream.Current()
+			result := convertChunk(&chunk)
+			if params.Audio != nil {
+				for _, choice := range result.Choices {
+					if choice.Delta.Audio != nil {
+						choice.Delta.Audio.Format = params.Audio.Format
+					}
+				}
+			}
+			if field := p.compatibleConfig.ReasoningField; field != "" {
+				for i, choice := range chunk.Choices {
+					if reasoning := reasoningFromField(choice.Delta.JSON.ExtraFields, field); reasoning != nil {
+						result.Choices[i].Delta.Reasoning = reasoning
+					}
+				}
+			}
+			if p.compatibleConfig.ConvertChunk != nil {
+				p.compatibleConfig.ConvertChunk(&chunk, &result)
+			}
+
 			select {
-			case chunks <- convertChunk(&chunk):
+			case chunks <- result:
 			case <-ctx.Done():
 				return
 			}
@@ -196,6 +342,12 @@ func (p *CompatibleProvider) ConvertError(err error) error {
 		return nil
 	}
 
+	if p.compatibleConfig.ConvertError != nil {
+		if converted := p.compatibleConfig.ConvertError(err); converted != nil {
+			return converted
+		}
+	}
+
 	name := p.compatibleConfig.Name
 
 	// Check for OpenAI API error type.
@@ -216,6 +368,11 @@ func (p *CompatibleProvider) Embedding(
 	params providers.EmbeddingParams,
 ) (*providers.EmbeddingResponse, error) {
 	req := convertEmbeddingParams(params)
+	if p.compatibleConfig.PrepareEmbeddingRequest != nil {
+		if err := p.compatibleConfig.PrepareEmbeddingRequest(&req, params); err != nil {
+			return nil, err
+		}
+	}
 
 	resp, err := p.client.Embeddings.New(ctx, req)
 	if err != nil {
@@ -225,6 +382,138 @@ func (p *CompatibleProvider) Embedding(
 	return convertEmbeddingResponse(resp), nil
 }
 
+// ImageEdit edits an image as described by the prompt, only where the mask is transparent when a mask is set.
+// It returns an UnsupportedParamError unless the provider's ImageGeneration capability is set.
+func (p *CompatibleProvider) ImageEdit(
+	ctx context.Context,
+	params providers.ImageEditParams,
+) (*providers.ImageResponse, error) {
+	switch {
+	case !p.compatibleConfig.Capabilities.ImageGeneration:
+		return nil, errors.NewUnsupportedParamError(p.compatibleConfig.Name, "image_edit")
+	case params.Model == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("model is required"))
+	case params.Prompt == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("prompt is required"))
+	case params.Image == nil:
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("image is required"))
+	}
+
+	req := openai.ImageEditParams{
+		Image:  openai.ImageEditParamsImageUnion{OfFile: imageFile(params.Image, params.Filename)},
+		Model:  params.Model,
+		Prompt: params.Prompt,
+	}
+	if params.Mask != nil {
+		req.Mask = openai.File(params.Mask, maskFilename, mimeTypePNG)
+	}
+	if params.N != nil {
+		req.N = openai.Int(int64(*params.N))
+	}
+	if params.Size != "" {
+		req.Size = openai.ImageEditParamsSize(params.Size)
+	}
+	if params.Quality != "" {
+		req.Quality = openai.ImageEditParamsQuality(params.Quality)
+	}
+	if params.ResponseFormat != "" {
+		req.ResponseFormat = openai.ImageEditParamsResponseFormat(params.ResponseFormat)
+	}
+	if params.User != "" {
+		req.User = openai.String(params.User)
+	}
+
+	resp, err := p.client.Images.Edit(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertImageResponse(resp), nil
+}
+
+// ImageGeneration generates images from a prompt.
+// It returns an UnsupportedParamError unless the provider's ImageGeneration capability is set.
+func (p *CompatibleProvider) ImageGeneration(
+	ctx context.Context,
+	params providers.ImageGenerationParams,
+) (*providers.ImageResponse, error) {
+	switch {
+	case !p.compatibleConfig.Capabilities.ImageGeneration:
+		return nil, errors.NewUnsupportedParamError(p.compatibleConfig.Name, "image_generation")
+	case params.Model == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("model is required"))
+	case params.Prompt == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("prompt is required"))
+	}
+
+	req := openai.ImageGenerateParams{
+		Model:  params.Model,
+		Prompt: params.Prompt,
+	}
+	if params.N != nil {
+		req.N = openai.Int(int64(*params.N))
+	}
+	if params.Size != "" {
+		req.Size = openai.ImageGenerateParamsSize(params.Size)
+	}
+	if params.Quality != "" {
+		req.Quality = openai.ImageGenerateParamsQuality(params.Quality)
+	}
+	if params.ResponseFormat != "" {
+		req.ResponseFormat = openai.ImageGenerateParamsResponseFormat(params.ResponseFormat)
+	}
+	if params.User != "" {
+		req.User = openai.String(params.User)
+	}
+
+	resp, err := p.client.Images.Generate(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertImageResponse(resp), nil
+}
+
+// ImageVariation creates variations of an image.
+// It returns an UnsupportedParamError unless the provider's ImageGeneration capability is set.
+func (p *CompatibleProvider) ImageVariation(
+	ctx context.Context,
+	params providers.ImageVariationParams,
+) (*providers.ImageResponse, error) {
+	switch {
+	case !p.compatibleConfig.Capabilities.ImageGeneration:
+		return nil, errors.NewUnsupportedParamError(p.compatibleConfig.Name, "image_variation")
+	case params.Model == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("model is required"))
+	case params.Image == nil:
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("image is required"))
+	}
+
+	req := openai.ImageNewVariationParams{
+		Image: imageFile(params.Image, params.Filename),
+		Model: params.Model,
+	}
+	if params.N != nil {
+		req.N = openai.Int(int64(*params.N))
+	}
+	if params.Size != "" {
+		req.Size = openai.ImageNewVariationParamsSize(params.Size)
+	}
+	if params.ResponseFormat != "" {
+		req.ResponseFormat = openai.ImageNewVariationParamsResponseFormat(params.ResponseFormat)
+	}
+	if params.User != "" {
+		req.User = openai.String(params.User)
+	}
+
+	resp, err := p.client.Images.NewVariation(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertImageResponse(resp), nil
+}
+
 // ListModels returns a list of available models.
 func (p *CompatibleProvider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
 	resp, err := p.client.Models.List(ctx)
@@ -248,11 +537,291 @@ func (p *CompatibleProvider) ListModels(ctx context.Context) (*providers.ModelsR
 	}, nil
 }
 
+// Moderation classifies each input as harmful or not, using the provider's default model when none is set.
+// It returns an UnsupportedParamError unless the provider's Moderation capability is set.
+func (p *CompatibleProvider) Moderation(
+	ctx context.Context,
+	params providers.ModerationParams,
+) (*providers.ModerationResponse, error) {
+	switch {
+	case !p.compatibleConfig.Capabilities.Moderation:
+		return nil, errors.NewUnsupportedParamError(p.compatibleConfig.Name, "moderation")
+	case len(params.Input) == 0:
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("input is required"))
+	}
+
+	req := openai.ModerationNewParams{
+		Input: openai.ModerationNewParamsInputUnion{OfStringArray: params.Input},
+	}
+	if params.Model != "" {
+		req.Model = params.Model
+	}
+
+	resp, err := p.client.Moderations.New(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertModerationResponse(resp), nil
+}
+
 // Name returns the provider name.
 func (p *CompatibleProvider) Name() string {
 	return p.compatibleConfig.Name
 }
 
+// Speech synthesizes speech from text, returning the audio, which the caller must close.
+// It returns an UnsupportedParamError unless the provider's Speech capability is set.
+func (p *CompatibleProvider) Speech(ctx context.Context, params providers.SpeechParams) (io.ReadCloser, error) {
+	switch {
+	case !p.compatibleConfig.Capabilities.Speech:
+		return nil, errors.NewUnsupportedParamError(p.compatibleConfig.Name, "speech")
+	case params.Model == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("model is required"))
+	case params.Input == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("input is required"))
+	}
+
+	req := openai.AudioSpeechNewParams{
+		Input: params.Input,
+		Model: params.Model,
+		Voice: openai.AudioSpeechNewParamsVoice(params.Voice),
+	}
+	if params.Format != "" {
+		req.ResponseFormat = openai.AudioSpeechNewParamsResponseFormat(params.Format)
+	}
+	if params.Speed != nil {
+		req.Speed = openai.Float(*params.Speed)
+	}
+	if params.Instructions != "" {
+		req.Instructions = openai.String(params.Instructions)
+	}
+
+	resp, err := p.client.Audio.Speech.New(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return resp.Body, nil
+}
+
+// Transcription transcribes speech to text.
+// Segments, words, the language, and the duration are only returned when TimestampGranularities is set,
+// which requests the verbose response format. It returns an UnsupportedParamError unless the provider's
+// Transcription capability is set.
+func (p *CompatibleProvider) Transcription(
+	ctx context.Context,
+	params providers.TranscriptionParams,
+) (*providers.Transcription, error) {
+	switch {
+	case !p.compatibleConfig.Capabilities.Transcription:
+		return nil, errors.NewUnsupportedParamError(p.compatibleConfig.Name, "transcription")
+	case params.Model == "":
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("model is required"))
+	case params.Audio == nil:
+		return nil, errors.NewInvalidRequestError(p.compatibleConfig.Name, fmt.Errorf("audio is required"))
+	}
+
+	req := openai.AudioTranscriptionNewParams{
+		File:  openai.File(params.Audio, params.Filename, ""),
+		Model: params.Model,
+	}
+	if params.Language != "" {
+		req.Language = openai.String(params.Language)
+	}
+	if params.Prompt != "" {
+		req.Prompt = openai.String(params.Prompt)
+	}
+	if params.Temperature != nil {
+		req.Temperature = openai.Float(*params.Temperature)
+	}
+	if len(params.TimestampGranularities) > 0 {
+		req.ResponseFormat = openai.AudioResponseFormatVerboseJSON
+		req.TimestampGranularities = params.TimestampGranularities
+	}
+
+	resp, err := p.client.Audio.Transcriptions.New(ctx, req)
+	if err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return convertTranscription(resp), nil
+}
+
+// newRequest converts params to an OpenAI request, applying Extra and the PrepareRequest hook.
+func (p *CompatibleProvider) newRequest(params providers.CompletionParams) (openai.ChatCompletionNewParams, error) {
+	if err := p.validateFiles(params.Messages); err != nil {
+		return openai.ChatCompletionNewParams{}, err
+	}
+	if err := p.validateAudio(params); err != nil {
+		return openai.ChatCompletionNewParams{}, err
+	}
+
+	req := convertParams(params)
+	if err := applyExtra(&req, params.Extra); err != nil {
+		return req, errors.NewInvalidRequestError(p.compatibleConfig.Name, err)
+	}
+
+	// max_tokens set through Extra takes precedence.
+	if p.compatibleConfig.UseMaxTokens && req.MaxCompletionTokens.Valid() {
+		if !req.MaxTokens.Valid() {
+			req.MaxTokens = req.MaxCompletionTokens
+		}
+		req.MaxCompletionTokens = param.Opt[int64]{}
+	}
+
+	if p.compatibleConfig.PrepareRequest != nil {
+		if err := p.compatibleConfig.PrepareRequest(&req, params); err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+// validateAudio rejects audio input and output when the provider does not support audio.
+func (p *CompatibleProvider) validateAudio(params providers.CompletionParams) error {
+	if p.compatibleConfig.Capabilities.CompletionAudio {
+		return nil
+	}
+
+	if params.Audio != nil || slices.Contains(params.Modalities, providers.ModalityAudio) {
+		return errors.NewUnsupportedParamError(p.compatibleConfig.Name, "audio")
+	}
+	for _, msg := range params.Messages {
+		for _, part := range msg.ContentParts() {
+			if part.Type == contentTypeInputAudio {
+				return errors.NewUnsupportedParamError(p.compatibleConfig.Name, contentTypeInputAudio)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFiles rejects file content parts when the provider cannot read documents, and files
+// given by URL, which chat completions cannot fetch.
+func (p *CompatibleProvider) validateFiles(messages []providers.Message) error {
+	for _, msg := range messages {
+		for _, part := range msg.ContentParts() {
+			if part.Type != contentTypeFile {
+				continue
+			}
+			switch {
+			case !p.compatibleConfig.Capabilities.CompletionPDF:
+				return errors.NewUnsupportedParamError(p.compatibleConfig.Name, "file content")
+			case part.File != nil && part.File.Data == "" && part.File.FileID == "" && part.File.URL != "":
+				return errors.NewUnsupportedParamError(p.compatibleConfig.Name, "file URL")
+			case part.File == nil || (part.File.Data == "" && part.File.FileID == ""):
+				return errors.NewInvalidRequestError(
+					p.compatibleConfig.Name,
+					fmt.Errorf("file content part requires data or a file ID"),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyExtra applies provider-specific parameters to req.
+// Known keys are decoded into their typed fields; unknown keys are merged into the request
+// body so that OpenAI-compatible servers receive their own parameters (e.g. top_k or min_p).
+func applyExtra(req *openai.ChatCompletionNewParams, params map[string]any) error {
+	body := make(map[string]any)
+
+	for _, key := range extra.Keys(params) {
+		value := params[key]
+
+		switch key {
+		case extraFrequencyPenalty:
+			var v float64
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.FrequencyPenalty = openai.Float(v)
+		case extraLogitBias:
+			var v map[string]int64
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.LogitBias = v
+		case extraMaxTokens:
+			var v int64
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.MaxTokens = openai.Int(v)
+		case extraMetadata:
+			var v shared.Metadata
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.Metadata = v
+		case extraN:
+			var v int64
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.N = openai.Int(v)
+		case extraPresencePenalty:
+			var v float64
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.PresencePenalty = openai.Float(v)
+		case extraPromptCacheKey:
+			var v string
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.PromptCacheKey = openai.String(v)
+		case extraSafetyIdentifier:
+			var v string
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.SafetyIdentifier = openai.String(v)
+		case extraServiceTier:
+			var v string
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.ServiceTier = openai.ChatCompletionNewParamsServiceTier(v)
+		case extraStore:
+			var v bool
+			if err := extra.Decode(key, value, &v); err != nil {
+				return err
+			}
+			req.Store = openai.Bool(v)
+		default:
+			body[key] = value
+		}
+	}
+
+	if len(body) > 0 {
+		req.SetExtraFields(body)
+	}
+
+	return nil
+}
+
+// audioFromField returns the audio in the "audio" field of a streaming delta, which the SDK does not decode,
+// or nil if the field is missing or malformed.
+func audioFromField(fields map[string]respjson.Field) *providers.Audio {
+	field, ok := fields[fieldAudio]
+	if !ok {
+		return nil
+	}
+
+	var audio providers.Audio
+	if err := json.Unmarshal([]byte(field.Raw()), &audio); err != nil {
+		return nil
+	}
+
+	return &audio
+}
+
 // convertAPIError converts an OpenAI API error to a unified error type.
 func convertAPIError(name string, apiErr *openai.Error, originalErr error) error {
 	switch apiErr.StatusCode {
@@ -269,7 +838,7 @@ func convertAPIError(name string, apiErr *openai.Error, originalErr error) error
 	case 404:
 		return errors.NewModelNotFoundError(name, originalErr)
 	case 429:
-		return errors.NewRateLimitError(name, originalErr)
+		return newRateLimitError(name, apiErr, originalErr)
 	}
 
 	// Check error code for additional classification.
@@ -279,14 +848,26 @@ func convertAPIError(name string, apiErr *openai.Error, originalErr error) error
 	case apiCodeModelNotFound:
 		return errors.NewModelNotFoundError(name, originalErr)
 	case apiCodeRateLimitExceeded:
-		return errors.NewRateLimitError(name, originalErr)
+		return newRateLimitError(name, apiErr, originalErr)
 	}
 
-	return errors.NewProviderError(name, originalErr)
+	providerErr := errors.NewProviderError(name, originalErr)
+	providerErr.StatusCode = apiErr.StatusCode
+	return providerErr
 }
 
 // convertAssistantMessage converts an assistant message to OpenAI format.
 func convertAssistantMessage(msg providers.Message) openai.ChatCompletionMessageParamUnion {
+	result := convertAssistantContent(msg)
+	if msg.Audio != nil && msg.Audio.ID != "" {
+		result.OfAssistant.Audio = openai.ChatCompletionAssistantMessageParamAudio{ID: msg.Audio.ID}
+	}
+
+	return result
+}
+
+// convertAssistantContent converts the content and tool calls of an assistant message to OpenAI format.
+func convertAssistantContent(msg providers.Message) openai.ChatCompletionMessageParamUnion {
 	if len(msg.ToolCalls) > 0 {
 		toolCalls := make([]openai.ChatCompletionMessageToolCallParam, 0, len(msg.ToolCalls))
 		for _, tc := range msg.ToolCalls {
@@ -310,6 +891,20 @@ func convertAssistantMessage(msg providers.Message) openai.ChatCompletionMessage
 	return openai.AssistantMessage(msg.ContentString())
 }
 
+// convertBytes converts the UTF-8 bytes of a token to ints.
+func convertBytes(b []int64) []int {
+	if len(b) == 0 {
+		return nil
+	}
+
+	result := make([]int, len(b))
+	for i, v := range b {
+		result[i] = int(v)
+	}
+
+	return result
+}
+
 // convertChunk converts an OpenAI streaming chunk to provider format.
 func convertChunk(chunk *openai.ChatCompletionChunk) providers.ChatCompletionChunk {
 	choices := make([]providers.ChunkChoice, 0, len(chunk.Choices))
@@ -319,16 +914,19 @@ func convertChunk(chunk *openai.ChatCompletionChunk) providers.ChatCompletionChu
 			Delta: providers.ChunkDelta{
 				Role:    string(choice.Delta.Role),
 				Content: choice.Delta.Content,
+				Audio:   audioFromField(choice.Delta.JSON.ExtraFields),
 			},
 			FinishReason: string(choice.FinishReason),
+			Logprobs:     convertLogprobs(choice.Logprobs.Content),
 		}
 
 		if len(choice.Delta.ToolCalls) > 0 {
 			chunkChoice.Delta.ToolCalls = make([]providers.ToolCall, 0, len(choice.Delta.ToolCalls))
 			for _, tc := range choice.Delta.ToolCalls {
 				chunkChoice.Delta.ToolCalls = append(chunkChoice.Delta.ToolCalls, providers.ToolCall{
-					ID:   tc.ID,
-					Type: string(tc.Type),
+					Index: int(tc.Index),
+					ID:    tc.ID,
+					Type:  string(tc.Type),
 					Function: providers.FunctionCall{
 						Name:      tc.Function.Name,
 						Arguments: tc.Function.Arguments,
@@ -354,6 +952,7 @@ func convertChunk(chunk *openai.ChatCompletionChunk) providers.ChatCompletionChu
 			PromptTokens:     int(chunk.Usage.PromptTokens),
 			CompletionTokens: int(chunk.Usage.CompletionTokens),
 			TotalTokens:      int(chunk.Usage.TotalTokens),
+			CacheReadTokens:  int(chunk.Usage.PromptTokensDetails.CachedTokens),
 		}
 	}
 
@@ -426,6 +1025,77 @@ func convertEmbeddingResponse(resp *openai.CreateEmbeddingResponse) *providers.E
 	return result
 }
 
+// convertFile converts a file content part to OpenAI format, sending data as a data URL.
+func convertFile(file *providers.File) openai.ChatCompletionContentPartFileFileParam {
+	var param openai.ChatCompletionContentPartFileFileParam
+	if file.FileID != "" {
+		param.FileID = openai.String(file.FileID)
+	}
+	if file.Data != "" {
+		mimeType := cmp.Or(file.MIMEType, defaultFileMIMEType)
+		param.FileData = openai.String("data:" + mimeType + ";base64," + file.Data)
+	}
+	if file.Filename != "" {
+		param.Filename = openai.String(file.Filename)
+	}
+
+	return param
+}
+
+// convertImageResponse converts an OpenAI images response to provider format.
+func convertImageResponse(resp *openai.ImagesResponse) *providers.ImageResponse {
+	data := make([]providers.GeneratedImage, 0, len(resp.Data))
+	for _, image := range resp.Data {
+		data = append(data, providers.GeneratedImage{
+			URL:           image.URL,
+			B64JSON:       image.B64JSON,
+			RevisedPrompt: image.RevisedPrompt,
+		})
+	}
+
+	result := &providers.ImageResponse{
+		Created: resp.Created,
+		Data:    data,
+	}
+
+	if resp.Usage.TotalTokens > 0 {
+		result.Usage = &providers.ImageUsage{
+			InputTokens:  int(resp.Usage.InputTokens),
+			OutputTokens: int(resp.Usage.OutputTokens),
+			TotalTokens:  int(resp.Usage.TotalTokens),
+		}
+	}
+
+	return result
+}
+
+// convertLogprobs converts OpenAI token log probabilities to provider format.
+// It returns nil when there are none.
+func convertLogprobs(content []openai.ChatCompletionTokenLogprob) *providers.Logprobs {
+	if len(content) == 0 {
+		return nil
+	}
+
+	result := &providers.Logprobs{Content: make([]providers.TokenLogprob, 0, len(content))}
+	for _, lp := range content {
+		token := providers.TokenLogprob{
+			Token:   lp.Token,
+			Bytes:   convertBytes(lp.Bytes),
+			Logprob: lp.Logprob,
+		}
+		for _, top := range lp.TopLogprobs {
+			token.TopLogprobs = append(token.TopLogprobs, providers.TopLogprob{
+				Token:   top.Token,
+				Bytes:   convertBytes(top.Bytes),
+				Logprob: top.Logprob,
+			})
+		}
+		result.Content = append(result.Content, token)
+	}
+
+	return result
+}
+
 // convertMessage converts a single message to OpenAI format.
 func convertMessage(msg providers.Message) (openai.ChatCompletionMessageParamUnion, error) {
 	switch msg.Role {
@@ -434,7 +1104,7 @@ func convertMessage(msg providers.Message) (openai.ChatCompletionMessageParamUni
 	case providers.RoleSystem:
 		return openai.SystemMessage(msg.ContentString()), nil
 	case providers.RoleTool:
-		return openai.ToolMessage(msg.ToolCallID, msg.ContentString()), nil
+		return openai.ToolMessage(msg.ContentString(), msg.ToolCallID), nil
 	case providers.RoleUser:
 		return convertUserMessage(msg), nil
 	default:
@@ -455,6 +1125,25 @@ func convertMessages(messages []providers.Message) ([]openai.ChatCompletionMessa
 	return result, nil
 }
 
+// convertModerationResponse converts an OpenAI moderation response to provider format.
+// The SDK decodes categories into fixed fields, so they are read from the raw results by name.
+func convertModerationResponse(resp *openai.ModerationNewResponse) *providers.ModerationResponse {
+	results := make([]providers.ModerationResult, 0, len(resp.Results))
+	for _, moderation := range resp.Results {
+		var result providers.ModerationResult
+		if err := json.Unmarshal([]byte(moderation.RawJSON()), &result); err != nil {
+			result = providers.ModerationResult{Flagged: moderation.Flagged}
+		}
+		results = append(results, result)
+	}
+
+	return &providers.ModerationResponse{
+		ID:      resp.ID,
+		Model:   resp.Model,
+		Results: results,
+	}
+}
+
 // convertParams converts providers.CompletionParams to OpenAI request parameters.
 func convertParams(params providers.CompletionParams) openai.ChatCompletionNewParams {
 	messages, _ := convertMessages(params.Messages) // Error already checked in validateCompletionParams
@@ -502,6 +1191,14 @@ func convertParams(params providers.CompletionParams) openai.ChatCompletionNewPa
 		req.Seed = openai.Int(int64(*params.Seed))
 	}
 
+	if params.Logprobs || params.TopLogprobs != nil {
+		req.Logprobs = openai.Bool(true)
+	}
+
+	if params.TopLogprobs != nil {
+		req.TopLogprobs = openai.Int(int64(*params.TopLogprobs))
+	}
+
 	if params.User != "" {
 		req.User = openai.String(params.User)
 	}
@@ -510,6 +1207,17 @@ func convertParams(params providers.CompletionParams) openai.ChatCompletionNewPa
 		req.ReasoningEffort = shared.ReasoningEffort(params.ReasoningEffort)
 	}
 
+	if len(params.Modalities) > 0 {
+		req.Modalities = params.Modalities
+	}
+
+	if params.Audio != nil {
+		req.Audio = openai.ChatCompletionAudioParam{
+			Format: openai.ChatCompletionAudioParamFormat(params.Audio.Format),
+			Voice:  openai.ChatCompletionAudioParamVoice(params.Audio.Voice),
+		}
+	}
+
 	if params.StreamOptions != nil && params.StreamOptions.IncludeUsage {
 		req.StreamOptions = openai.ChatCompletionStreamOptionsParam{
 			IncludeUsage: openai.Bool(true),
@@ -527,6 +1235,7 @@ func convertResponse(resp *openai.ChatCompletion) *providers.ChatCompletion {
 			Index:        int(choice.Index),
 			Message:      convertResponseMessage(choice.Message),
 			FinishReason: string(choice.FinishReason),
+			Logprobs:     convertLogprobs(choice.Logprobs.Content),
 		})
 	}
 
@@ -548,6 +1257,7 @@ func convertResponse(resp *openai.ChatCompletion) *providers.ChatCompletion {
 		if resp.Usage.CompletionTokensDetails.ReasoningTokens > 0 {
 			result.Usage.ReasoningTokens = int(resp.Usage.CompletionTokensDetails.ReasoningTokens)
 		}
+		result.Usage.CacheReadTokens = int(resp.Usage.PromptTokensDetails.CachedTokens)
 	}
 
 	return result
@@ -592,6 +1302,15 @@ func convertResponseMessage(msg openai.ChatCompletionMessage) providers.Message
 		Content: msg.Content,
 	}
 
+	if msg.Audio.ID != "" {
+		result.Audio = &providers.Audio{
+			ID:         msg.Audio.ID,
+			Data:       msg.Audio.Data,
+			Transcript: msg.Audio.Transcript,
+			ExpiresAt:  msg.Audio.ExpiresAt,
+		}
+	}
+
 	if len(msg.ToolCalls) > 0 {
 		result.ToolCalls = make([]providers.ToolCall, 0, len(msg.ToolCalls))
 		for _, tc := range msg.ToolCalls {
@@ -645,6 +1364,17 @@ func convertTools(tools []providers.Tool) []openai.ChatCompletionToolParam {
 	return result
 }
 
+// convertTranscription converts an OpenAI transcription to provider format.
+// The SDK decodes only the text, so the verbose fields are read from the raw response.
+func convertTranscription(resp *openai.Transcription) *providers.Transcription {
+	var result providers.Transcription
+	if err := json.Unmarshal([]byte(resp.RawJSON()), &result); err != nil {
+		return &providers.Transcription{Text: resp.Text}
+	}
+
+	return &result
+}
+
 // convertUserMessage converts a user message to OpenAI format.
 func convertUserMessage(msg providers.Message) openai.ChatCompletionMessageParamUnion {
 	if msg.IsMultiModal() {
@@ -659,6 +1389,17 @@ func convertUserMessage(msg providers.Message) openai.ChatCompletionMessageParam
 						URL: part.ImageURL.URL,
 					}))
 				}
+			case contentTypeFile:
+				if part.File != nil {
+					parts = append(parts, openai.FileContentPart(convertFile(part.File)))
+				}
+			case contentTypeInputAudio:
+				if part.InputAudio != nil {
+					parts = append(parts, openai.InputAudioContentPart(openai.ChatCompletionContentPartInputAudioInputAudioParam{
+						Data:   part.InputAudio.Data,
+						Format: part.InputAudio.Format,
+					}))
+				}
 			}
 		}
 		return openai.UserMessage(parts)
@@ -666,6 +1407,41 @@ func convertUserMessage(msg providers.Message) openai.ChatCompletionMessageParam
 	return openai.UserMessage(msg.ContentString())
 }
 
+// headerOptions returns client options that set headers, replacing any the client sets by default.
+func headerOptions(headers http.Header) []option.RequestOption {
+	opts := make([]option.RequestOption, 0, len(headers))
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		opts = append(opts, option.WithHeaderDel(key))
+		for _, value := range headers[key] {
+			opts = append(opts, option.WithHeaderAdd(key, value))
+		}
+	}
+
+	return opts
+}
+
+// imageFile names an uploaded image and sets its content type from the name's extension, since the
+// images API rejects images sent as application/octet-stream.
+func imageFile(image io.Reader, filename string) io.Reader {
+	if filename == "" {
+		if named, ok := image.(interface{ Name() string }); ok {
+			filename = path.Base(named.Name())
+		}
+	}
+	filename = cmp.Or(filename, defaultImageFilename)
+
+	return openai.File(image, filename, cmp.Or(mime.TypeByExtension(path.Ext(filename)), mimeTypePNG))
+}
+
+// newRateLimitError creates a RateLimitError, populating RetryAfter from the response headers.
+func newRateLimitError(name string, apiErr *openai.Error, originalErr error) *errors.RateLimitError {
+	rateLimitErr := errors.NewRateLimitError(name, originalErr)
+	if apiErr.Response != nil {
+		rateLimitErr.RetryAfter = errors.RetryAfterFromHeader(apiErr.Response.Header)
+	}
+	return rateLimitErr
+}
+
 // resolveAPIKey resolves the API key from config or environment.
 func resolveAPIKey(cfg *config.Config, compatCfg CompatibleConfig) string {
 	if compatCfg.APIKeyEnvVar != "" {
@@ -674,6 +1450,22 @@ func resolveAPIKey(cfg *config.Config, compatCfg CompatibleConfig) string {
 	return cfg.APIKey
 }
 
+// reasoningFromField returns the reasoning in the named non-standard field of a message or delta,
+// or nil if the field is missing, empty, or not a string.
+func reasoningFromField(fields map[string]respjson.Field, name string) *providers.Reasoning {
+	field, ok := fields[name]
+	if !ok {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal([]byte(field.Raw()), &text); err != nil || text == "" {
+		return nil
+	}
+
+	return &providers.Reasoning{Content: text}
+}
+
 // validateCompatibleConfig validates the compatible provider configuration.
 func validateCompatibleConfig(cfg CompatibleConfig) error {
 	if cfg.Name == "" {
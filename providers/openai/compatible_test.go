@@ -2,8 +2,16 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mozilla-ai/any-llm-go/config"
@@ -89,6 +97,7 @@ func TestNewCompatible(t *testing.T) {
 		provider, err := NewCompatible(baseCfg, config.WithBaseURL("http://custom:9090/v1"))
 		require.NoError(t, err)
 		require.NotNil(t, provider)
+		require.Equal(t, "http://custom:9090/v1", provider.BaseURL())
 	})
 
 	t.Run("uses environment variable for base URL", func(t *testing.T) {
@@ -104,6 +113,7 @@ func TestNewCompatible(t *testing.T) {
 		provider, err := NewCompatible(baseCfg)
 		require.NoError(t, err)
 		require.NotNil(t, provider)
+		require.Equal(t, "http://env:8080/v1", provider.BaseURL())
 	})
 }
 
@@ -186,6 +196,751 @@ func TestValidateCompletionParams(t *testing.T) {
 	})
 }
 
+func TestCompatibleFileContent(t *testing.T) {
+	t.Parallel()
+
+	fileParams := func(file *providers.File) providers.CompletionParams {
+		return providers.CompletionParams{
+			Model: "test-model",
+			Messages: []providers.Message{{
+				Role:    providers.RoleUser,
+				Content: []providers.ContentPart{{Type: "file", File: file}},
+			}},
+		}
+	}
+
+	newProvider := func(t *testing.T, pdf bool) *CompatibleProvider {
+		t.Helper()
+
+		provider, err := NewCompatible(CompatibleConfig{
+			Name:          "test-provider",
+			DefaultAPIKey: "test-key",
+			Capabilities:  providers.Capabilities{Completion: true, CompletionPDF: pdf},
+		})
+		require.NoError(t, err)
+		return provider
+	}
+
+	t.Run("sends files when supported", func(t *testing.T) {
+		t.Parallel()
+
+		req, err := newProvider(t, true).ChatCompletionRequest(fileParams(&providers.File{Data: "JVBERi0="}))
+		require.NoError(t, err)
+
+		parts := req.Messages[0].OfUser.Content.OfArrayOfContentParts
+		require.Len(t, parts, 1)
+		require.Equal(t, "data:application/pdf;base64,JVBERi0=", parts[0].OfFile.File.FileData.Value)
+	})
+
+	t.Run("rejects files when unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newProvider(t, false).ChatCompletionRequest(fileParams(&providers.File{Data: "JVBERi0="}))
+
+		var unsupportedErr *errors.UnsupportedParamError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, "test-provider", unsupportedErr.Provider)
+	})
+
+	t.Run("rejects file URLs", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newProvider(t, true).ChatCompletionRequest(fileParams(&providers.File{URL: "https://example.com/a.pdf"}))
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+
+	t.Run("rejects files without data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newProvider(t, true).ChatCompletionRequest(fileParams(&providers.File{Filename: "a.pdf"}))
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestCompatibleAudio(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+
+		if gotBody["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test-model",` +
+				`"choices":[{"index":0,"delta":{"role":"assistant","audio":{"id":"audio_1","transcript":"Hi"}}}]}` +
+				"\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test-model",` +
+				`"choices":[{"index":0,"delta":{"audio":{"data":"UklG","expires_at":1700003600}}}]}` + "\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test-model",` +
+				`"choices":[{"index":0,"delta":{"audio":{"data":"RiQA"}},"finish_reason":"stop"}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"model": "test-model",
+			"choices": [{
+				"index": 0,
+				"message": {
+					"role": "assistant",
+					"content": null,
+					"audio": {"id": "audio_1", "data": "UklGRiQA", "transcript": "Hi", "expires_at": 1700003600}
+				},
+				"finish_reason": "stop"
+			}]
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	newProvider := func(t *testing.T, audio bool) *CompatibleProvider {
+		t.Helper()
+
+		provider, err := NewCompatible(CompatibleConfig{
+			Name:           "test-provider",
+			DefaultBaseURL: server.URL,
+			DefaultAPIKey:  "test-key",
+			Capabilities:   providers.Capabilities{Completion: true, CompletionAudio: audio},
+		})
+		require.NoError(t, err)
+		return provider
+	}
+
+	params := providers.CompletionParams{
+		Model:      "test-model",
+		Messages:   []providers.Message{{Role: providers.RoleUser, Content: "Say hi"}},
+		Modalities: []string{providers.ModalityText, providers.ModalityAudio},
+		Audio:      &providers.AudioParams{Voice: "alloy", Format: "wav"},
+	}
+	want := &providers.Audio{
+		ID:         "audio_1",
+		Data:       "UklGRiQA",
+		Transcript: "Hi",
+		Format:     "wav",
+		ExpiresAt:  1700003600,
+	}
+
+	t.Run("returns audio", func(t *testing.T) {
+		resp, err := newProvider(t, true).Completion(context.Background(), params)
+		require.NoError(t, err)
+		require.Equal(t, want, resp.Choices[0].Message.Audio)
+		require.Equal(t, []any{"text", "audio"}, gotBody["modalities"])
+		require.Equal(t, map[string]any{"voice": "alloy", "format": "wav"}, gotBody["audio"])
+	})
+
+	t.Run("streams audio deltas", func(t *testing.T) {
+		provider := newProvider(t, true)
+		resp, err := providers.Collect(provider.CompletionStream(context.Background(), params))
+		require.NoError(t, err)
+		require.Equal(t, want, resp.Choices[0].Message.Audio)
+	})
+
+	t.Run("rejects audio when unsupported", func(t *testing.T) {
+		provider := newProvider(t, false)
+
+		_, err := provider.Completion(context.Background(), params)
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model: "test-model",
+			Messages: []providers.Message{{
+				Role: providers.RoleUser,
+				Content: []providers.ContentPart{
+					{Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg==", Format: "wav"}},
+				},
+			}},
+		})
+
+		var unsupportedErr *errors.UnsupportedParamError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, "input_audio", unsupportedErr.Param)
+	})
+}
+
+func TestCompatibleTranscription(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/audio/transcriptions", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+
+		if r.FormValue("model") == "missing-model" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"message": "model not found", "code": "model_not_found"}}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(r.MultipartForm.Value["timestamp_granularities[]"]) == 0 {
+			require.Empty(t, r.FormValue("response_format"))
+			_, _ = w.Write([]byte(`{"text": "Hello there."}`))
+			return
+		}
+
+		require.Equal(t, "verbose_json", r.FormValue("response_format"))
+		require.Equal(t, []string{"segment", "word"}, r.MultipartForm.Value["timestamp_granularities[]"])
+		require.Equal(t, "en", r.FormValue("language"))
+		_, _ = w.Write([]byte(`{
+			"task": "transcribe",
+			"language": "english",
+			"duration": 1.5,
+			"text": "Hello there.",
+			"segments": [{"id": 0, "seek": 0, "start": 0.0, "end": 1.5, "text": " Hello there."}],
+			"words": [{"word": "Hello", "start": 0.0, "end": 0.6}, {"word": "there", "start": 0.7, "end": 1.2}]
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Capabilities:   providers.Capabilities{Transcription: true},
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	t.Run("returns text", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := provider.Transcription(context.Background(), providers.TranscriptionParams{
+			Model:    "whisper-1",
+			Audio:    strings.NewReader("RIFF"),
+			Filename: "hello.wav",
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.Transcription{Text: "Hello there."}, resp)
+	})
+
+	t.Run("returns segments and words", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := provider.Transcription(context.Background(), providers.TranscriptionParams{
+			Model:    "whisper-1",
+			Audio:    strings.NewReader("RIFF"),
+			Filename: "hello.wav",
+			Language: "en",
+			TimestampGranularities: []string{
+				providers.TimestampGranularitySegment,
+				providers.TimestampGranularityWord,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.Transcription{
+			Text:     "Hello there.",
+			Language: "english",
+			Duration: 1.5,
+			Segments: []providers.TranscriptionSegment{{ID: 0, Start: 0, End: 1.5, Text: " Hello there."}},
+			Words: []providers.TranscriptionWord{
+				{Word: "Hello", Start: 0, End: 0.6},
+				{Word: "there", Start: 0.7, End: 1.2},
+			},
+		}, resp)
+	})
+
+	t.Run("normalizes errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.Transcription(context.Background(), providers.TranscriptionParams{
+			Model:    "missing-model",
+			Audio:    strings.NewReader("RIFF"),
+			Filename: "hello.wav",
+		})
+		require.ErrorIs(t, err, errors.ErrModelNotFound)
+	})
+
+	t.Run("requires model and audio", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.Transcription(context.Background(), providers.TranscriptionParams{Model: "whisper-1"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.Transcription(context.Background(), providers.TranscriptionParams{
+			Audio: strings.NewReader("RIFF"),
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+	t.Run("rejects providers without transcription", func(t *testing.T) {
+		t.Parallel()
+
+		unsupported, err := NewCompatible(CompatibleConfig{
+			Name:           "test-provider",
+			DefaultBaseURL: server.URL,
+			DefaultAPIKey:  "test-key",
+		})
+		require.NoError(t, err)
+
+		_, err = unsupported.Transcription(context.Background(), providers.TranscriptionParams{
+			Model: "whisper-1",
+			Audio: strings.NewReader("audio"),
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompatibleSpeech(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/audio/speech", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if body["voice"] == "unknown" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"message": "invalid voice", "type": "invalid_request_error"}}`))
+			return
+		}
+
+		require.Equal(t, map[string]any{
+			"model":           "tts-1",
+			"input":           "Hello there.",
+			"voice":           "alloy",
+			"response_format": "wav",
+			"speed":           1.25,
+			"instructions":    "Speak cheerfully.",
+		}, body)
+
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write([]byte("RIFF"))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Capabilities:   providers.Capabilities{Speech: true},
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	t.Run("returns audio", func(t *testing.T) {
+		t.Parallel()
+
+		speed := 1.25
+		audio, err := provider.Speech(context.Background(), providers.SpeechParams{
+			Model:        "tts-1",
+			Input:        "Hello there.",
+			Voice:        "alloy",
+			Format:       "wav",
+			Speed:        &speed,
+			Instructions: "Speak cheerfully.",
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = audio.Close() })
+
+		data, err := io.ReadAll(audio)
+		require.NoError(t, err)
+		require.Equal(t, "RIFF", string(data))
+	})
+
+	t.Run("normalizes errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.Speech(context.Background(), providers.SpeechParams{
+			Model: "tts-1",
+			Input: "Hello there.",
+			Voice: "unknown",
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+
+	t.Run("requires model and input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.Speech(context.Background(), providers.SpeechParams{Input: "Hello there."})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.Speech(context.Background(), providers.SpeechParams{Model: "tts-1"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+	t.Run("rejects providers without speech", func(t *testing.T) {
+		t.Parallel()
+
+		unsupported, err := NewCompatible(CompatibleConfig{
+			Name:           "test-provider",
+			DefaultBaseURL: server.URL,
+			DefaultAPIKey:  "test-key",
+		})
+		require.NoError(t, err)
+
+		_, err = unsupported.Speech(context.Background(), providers.SpeechParams{
+			Model: "tts-1",
+			Input: "Hello there.",
+			Voice: "alloy",
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompatibleImages(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/images/generations":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			if body["size"] == "1x1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": {"message": "invalid size", "type": "invalid_request_error"}}`))
+				return
+			}
+
+			require.Equal(t, map[string]any{
+				"model":           "dall-e-3",
+				"prompt":          "A lighthouse at dusk.",
+				"n":               float64(1),
+				"size":            "1024x1024",
+				"quality":         "hd",
+				"response_format": "url",
+			}, body)
+
+			_, _ = w.Write([]byte(`{"created": 1700000000, "data": [{"url": "https://example.com/lighthouse.png",
+				"revised_prompt": "A red lighthouse at dusk."}]}`))
+		case "/images/edits":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			require.Equal(t, "gpt-image-1", r.FormValue("model"))
+			require.Equal(t, "Add a boat.", r.FormValue("prompt"))
+			require.Equal(t, "2", r.FormValue("n"))
+
+			image := r.MultipartForm.File["image"]
+			require.Len(t, image, 1)
+			require.Equal(t, "harbor.jpg", image[0].Filename)
+			require.Equal(t, "image/jpeg", image[0].Header.Get("Content-Type"))
+
+			mask := r.MultipartForm.File["mask"]
+			require.Len(t, mask, 1)
+			require.Equal(t, "image/png", mask[0].Header.Get("Content-Type"))
+
+			_, _ = w.Write([]byte(`{"created": 1700000000, "data": [{"b64_json": "aW1hZ2Ux"}, {"b64_json": "aW1hZ2Uy"}],
+				"usage": {"input_tokens": 50, "output_tokens": 100, "total_tokens": 150,
+				"input_tokens_details": {"image_tokens": 40, "text_tokens": 10}}}`))
+		case "/images/variations":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			require.Equal(t, "dall-e-2", r.FormValue("model"))
+			require.Equal(t, "b64_json", r.FormValue("response_format"))
+
+			image := r.MultipartForm.File["image"]
+			require.Len(t, image, 1)
+			require.Equal(t, "image.png", image[0].Filename)
+			require.Equal(t, "image/png", image[0].Header.Get("Content-Type"))
+
+			_, _ = w.Write([]byte(`{"created": 1700000000, "data": [{"b64_json": "dmFyaWF0aW9u"}]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Capabilities:   providers.Capabilities{ImageGeneration: true},
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	t.Run("generates images", func(t *testing.T) {
+		t.Parallel()
+
+		n := 1
+		resp, err := provider.ImageGeneration(context.Background(), providers.ImageGenerationParams{
+			Model:          "dall-e-3",
+			Prompt:         "A lighthouse at dusk.",
+			N:              &n,
+			Size:           "1024x1024",
+			Quality:        "hd",
+			ResponseFormat: providers.ImageResponseFormatURL,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.ImageResponse{
+			Created: 1700000000,
+			Data: []providers.GeneratedImage{{
+				URL:           "https://example.com/lighthouse.png",
+				RevisedPrompt: "A red lighthouse at dusk.",
+			}},
+		}, resp)
+	})
+
+	t.Run("edits images with a mask", func(t *testing.T) {
+		t.Parallel()
+
+		n := 2
+		resp, err := provider.ImageEdit(context.Background(), providers.ImageEditParams{
+			Model:    "gpt-image-1",
+			Prompt:   "Add a boat.",
+			Image:    strings.NewReader("jpeg"),
+			Filename: "harbor.jpg",
+			Mask:     strings.NewReader("png"),
+			N:        &n,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.ImageResponse{
+			Created: 1700000000,
+			Data:    []providers.GeneratedImage{{B64JSON: "aW1hZ2Ux"}, {B64JSON: "aW1hZ2Uy"}},
+			Usage:   &providers.ImageUsage{InputTokens: 50, OutputTokens: 100, TotalTokens: 150},
+		}, resp)
+	})
+
+	t.Run("creates variations", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := provider.ImageVariation(context.Background(), providers.ImageVariationParams{
+			Model:          "dall-e-2",
+			Image:          strings.NewReader("png"),
+			ResponseFormat: providers.ImageResponseFormatB64JSON,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []providers.GeneratedImage{{B64JSON: "dmFyaWF0aW9u"}}, resp.Data)
+		require.Nil(t, resp.Usage)
+	})
+
+	t.Run("normalizes errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.ImageGeneration(context.Background(), providers.ImageGenerationParams{
+			Model:  "dall-e-3",
+			Prompt: "A lighthouse at dusk.",
+			Size:   "1x1",
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+
+	t.Run("requires model, prompt, and image", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		image := strings.NewReader("png")
+
+		_, err := provider.ImageGeneration(ctx, providers.ImageGenerationParams{Prompt: "A lighthouse."})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.ImageGeneration(ctx, providers.ImageGenerationParams{Model: "dall-e-3"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.ImageEdit(ctx, providers.ImageEditParams{Model: "gpt-image-1", Image: image})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.ImageEdit(ctx, providers.ImageEditParams{Model: "gpt-image-1", Prompt: "Add a boat."})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.ImageVariation(ctx, providers.ImageVariationParams{Image: image})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+		_, err = provider.ImageVariation(ctx, providers.ImageVariationParams{Model: "dall-e-2"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+	t.Run("rejects providers without image generation", func(t *testing.T) {
+		t.Parallel()
+
+		unsupported, err := NewCompatible(CompatibleConfig{
+			Name:           "test-provider",
+			DefaultBaseURL: server.URL,
+			DefaultAPIKey:  "test-key",
+		})
+		require.NoError(t, err)
+
+		ctx := context.Background()
+
+		_, err = unsupported.ImageGeneration(ctx, providers.ImageGenerationParams{Model: "dall-e-3", Prompt: "A lighthouse."})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+		_, err = unsupported.ImageEdit(ctx, providers.ImageEditParams{
+			Model:  "gpt-image-1",
+			Prompt: "Add a boat.",
+			Image:  strings.NewReader("png"),
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+
+		_, err = unsupported.ImageVariation(ctx, providers.ImageVariationParams{
+			Model: "dall-e-2",
+			Image: strings.NewReader("png"),
+		})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompatibleModeration(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/moderations", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		if body["model"] == "unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"message": "model not found", "type": "invalid_request_error",
+				"code": "model_not_found"}}`))
+			return
+		}
+
+		require.Equal(t, map[string]any{
+			"model": "omni-moderation-latest",
+			"input": []any{"Hello.", "Something harmful."},
+		}, body)
+
+		_, _ = w.Write([]byte(`{"id": "modr-1", "model": "omni-moderation-latest", "results": [
+			{"flagged": false, "categories": {"violence": false, "violence/graphic": false},
+				"category_scores": {"violence": 0.001, "violence/graphic": 0.0002}},
+			{"flagged": true, "categories": {"violence": true, "violence/graphic": false},
+				"category_scores": {"violence": 0.92, "violence/graphic": 0.05}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Capabilities:   providers.Capabilities{Moderation: true},
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	t.Run("returns a result per input", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := provider.Moderation(context.Background(), providers.ModerationParams{
+			Model: "omni-moderation-latest",
+			Input: []string{"Hello.", "Something harmful."},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &providers.ModerationResponse{
+			ID:    "modr-1",
+			Model: "omni-moderation-latest",
+			Results: []providers.ModerationResult{
+				{
+					Categories:     map[string]bool{"violence": false, "violence/graphic": false},
+					CategoryScores: map[string]float64{"violence": 0.001, "violence/graphic": 0.0002},
+				},
+				{
+					Flagged:        true,
+					Categories:     map[string]bool{"violence": true, "violence/graphic": false},
+					CategoryScores: map[string]float64{"violence": 0.92, "violence/graphic": 0.05},
+				},
+			},
+		}, resp)
+	})
+
+	t.Run("normalizes errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.Moderation(context.Background(), providers.ModerationParams{
+			Model: "unknown",
+			Input: []string{"Hello."},
+		})
+		require.ErrorIs(t, err, errors.ErrModelNotFound)
+	})
+
+	t.Run("requires input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := provider.Moderation(context.Background(), providers.ModerationParams{Model: "omni-moderation-latest"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+	t.Run("rejects providers without moderation", func(t *testing.T) {
+		t.Parallel()
+
+		unsupported, err := NewCompatible(CompatibleConfig{
+			Name:           "test-provider",
+			DefaultBaseURL: server.URL,
+			DefaultAPIKey:  "test-key",
+		})
+		require.NoError(t, err)
+
+		_, err = unsupported.Moderation(context.Background(), providers.ModerationParams{Input: []string{"Hello."}})
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestConvertLogprobs(t *testing.T) {
+	t.Parallel()
+
+	logprobs := []openai.ChatCompletionTokenLogprob{{
+		Token:   "Hi",
+		Bytes:   []int64{72, 105},
+		Logprob: -0.1,
+		TopLogprobs: []openai.ChatCompletionTokenLogprobTopLogprob{
+			{Token: "Hi", Bytes: []int64{72, 105}, Logprob: -0.1},
+			{Token: "Hey", Logprob: -2.5},
+		},
+	}}
+	want := &providers.Logprobs{Content: []providers.TokenLogprob{{
+		Token:   "Hi",
+		Bytes:   []int{72, 105},
+		Logprob: -0.1,
+		TopLogprobs: []providers.TopLogprob{
+			{Token: "Hi", Bytes: []int{72, 105}, Logprob: -0.1},
+			{Token: "Hey", Logprob: -2.5},
+		},
+	}}}
+
+	t.Run("converts response logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		resp := convertResponse(&openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{
+			Logprobs: openai.ChatCompletionChoiceLogprobs{Content: logprobs},
+		}}})
+		require.Equal(t, want, resp.Choices[0].Logprobs)
+	})
+
+	t.Run("converts chunk logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := convertChunk(&openai.ChatCompletionChunk{Choices: []openai.ChatCompletionChunkChoice{{
+			Logprobs: openai.ChatCompletionChunkChoiceLogprobs{Content: logprobs},
+		}}})
+		require.Equal(t, want, chunk.Choices[0].Logprobs)
+	})
+
+	t.Run("returns nil without logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		resp := convertResponse(&openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{}}})
+		require.Nil(t, resp.Choices[0].Logprobs)
+	})
+}
+
+func TestConvertParamsLogprobs(t *testing.T) {
+	t.Parallel()
+
+	messages := []providers.Message{{Role: providers.RoleUser, Content: "Hello"}}
+
+	t.Run("omits logprobs by default", func(t *testing.T) {
+		t.Parallel()
+
+		req := convertParams(providers.CompletionParams{Model: "gpt-4o", Messages: messages})
+		require.False(t, req.Logprobs.Valid())
+		require.False(t, req.TopLogprobs.Valid())
+	})
+
+	t.Run("requests logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		req := convertParams(providers.CompletionParams{Model: "gpt-4o", Messages: messages, Logprobs: true})
+		require.True(t, req.Logprobs.Value)
+		require.False(t, req.TopLogprobs.Valid())
+	})
+
+	t.Run("top logprobs implies logprobs", func(t *testing.T) {
+		t.Parallel()
+
+		top := 3
+		req := convertParams(providers.CompletionParams{Model: "gpt-4o", Messages: messages, TopLogprobs: &top})
+		require.True(t, req.Logprobs.Value)
+		require.Equal(t, int64(3), req.TopLogprobs.Value)
+	})
+}
+
 func TestConvertResponseFormat(t *testing.T) {
 	t.Parallel()
 
@@ -321,3 +1076,205 @@ func TestStreamingContextCancellation(t *testing.T) {
 		// Test passes if it doesn't hang.
 	})
 }
+
+func TestCompatibleHooks(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"model": "test-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}]
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+		ConvertResponse: func(resp *openai.ChatCompletion, result *providers.ChatCompletion) {
+			result.Choices[0].Message.Content = resp.Choices[0].Message.Content + "!"
+		},
+		PrepareRequest: func(req *openai.ChatCompletionNewParams, params providers.CompletionParams) error {
+			if params.User == "reject" {
+				return errors.NewUnsupportedParamError("test-provider", "user")
+			}
+			req.SetExtraFields(map[string]any{"prepared": true})
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{
+		Model:    "test-model",
+		Messages: []providers.Message{{Role: providers.RoleUser, Content: "Hello"}},
+	}
+
+	t.Run("adjusts the request and response", func(t *testing.T) {
+		resp, err := provider.Completion(context.Background(), params)
+		require.NoError(t, err)
+		require.Equal(t, true, gotBody["prepared"])
+		require.Equal(t, "Hi!", resp.Choices[0].Message.Content)
+	})
+
+	t.Run("returns PrepareRequest errors unchanged", func(t *testing.T) {
+		rejected := params
+		rejected.User = "reject"
+
+		_, err := provider.Completion(context.Background(), rejected)
+		require.ErrorIs(t, err, errors.ErrUnsupportedParam)
+	})
+}
+
+func TestCompatibleClientOptionsAndConvertError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "present", r.Header.Get("X-Test-Middleware"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": "custom_block", "message": "Blocked."}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+		ClientOptions: []option.RequestOption{
+			option.WithMiddleware(func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+				req.Header.Set("X-Test-Middleware", "present")
+				return next(req)
+			}),
+		},
+		ConvertError: func(err error) error {
+			var apiErr *openai.Error
+			if stderrors.As(err, &apiErr) && apiErr.Code == "custom_block" {
+				return errors.NewContentFilterError("test-provider", err)
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    "test-model",
+		Messages: []providers.Message{{Role: providers.RoleUser, Content: "Hello"}},
+	})
+	require.ErrorIs(t, err, errors.ErrContentFilter)
+
+	// Errors the hook does not recognize fall back to the standard conversion.
+	require.ErrorIs(t, provider.ConvertError(stderrors.New("connection reset")), errors.ErrProvider)
+}
+
+func TestCompatibleHeaders(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.Equal(t, "org-1", r.Header.Get("OpenAI-Organization"))
+		require.Equal(t, "my-app/1.0", r.Header.Get("User-Agent"))
+		require.Equal(t, "from-config", r.Header.Get("X-Title"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object": "list", "data": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(
+		CompatibleConfig{
+			Name:           "test-provider",
+			DefaultBaseURL: server.URL,
+			DefaultAPIKey:  "test-key",
+			ClientOptions:  []option.RequestOption{option.WithHeader("X-Title", "from-provider")},
+		},
+		config.WithHeader("OpenAI-Organization", "org-1"),
+		config.WithHeader("User-Agent", "my-app/1.0"),
+		config.WithHeader("X-Title", "from-config"),
+	)
+	require.NoError(t, err)
+
+	_, err = provider.ListModels(context.Background())
+	require.NoError(t, err)
+}
+
+func TestCompatibleReasoningFieldAndMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+
+		if gotBody["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test-model",` +
+				`"choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"Thinking"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test-model",` +
+				`"choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"model": "test-model",
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "Hi", "reasoning_content": "Thinking"},
+				"finish_reason": "stop"
+			}]
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := NewCompatible(CompatibleConfig{
+		Name:           "test-provider",
+		DefaultBaseURL: server.URL,
+		DefaultAPIKey:  "test-key",
+		ReasoningField: "reasoning_content",
+		UseMaxTokens:   true,
+	})
+	require.NoError(t, err)
+
+	maxTokens := 50
+	params := providers.CompletionParams{
+		Model:     "test-model",
+		Messages:  []providers.Message{{Role: providers.RoleUser, Content: "Hello"}},
+		MaxTokens: &maxTokens,
+	}
+
+	t.Run("returns reasoning from the field", func(t *testing.T) {
+		resp, err := provider.Completion(context.Background(), params)
+		require.NoError(t, err)
+		require.Equal(t, "Hi", resp.Choices[0].Message.Content)
+		require.Equal(t, "Thinking", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	t.Run("sends max_tokens", func(t *testing.T) {
+		_, err := provider.Completion(context.Background(), params)
+		require.NoError(t, err)
+		require.Equal(t, float64(50), gotBody["max_tokens"])
+		require.NotContains(t, gotBody, "max_completion_tokens")
+	})
+
+	t.Run("returns reasoning deltas from the field", func(t *testing.T) {
+		acc := providers.NewAccumulator()
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			acc.Add(chunk)
+		}
+
+		completion := acc.ChatCompletion()
+		require.Equal(t, "Hi", completion.Choices[0].Message.Content)
+		require.Equal(t, "Thinking", completion.Choices[0].Message.Reasoning.Content)
+	})
+}
@@ -14,13 +14,23 @@ const (
 
 // Ensure Provider implements the required interfaces.
 var (
-	_ providers.CapabilityProvider = (*Provider)(nil)
-	_ providers.EmbeddingProvider  = (*Provider)(nil)
-	_ providers.ErrorConverter     = (*Provider)(nil)
-	_ providers.ModelLister        = (*Provider)(nil)
-	_ providers.Provider           = (*Provider)(nil)
+	_ providers.CapabilityProvider      = (*Provider)(nil)
+	_ providers.EmbeddingProvider       = (*Provider)(nil)
+	_ providers.ErrorConverter          = (*Provider)(nil)
+	_ providers.ImageGenerationProvider = (*Provider)(nil)
+	_ providers.ModelLister             = (*Provider)(nil)
+	_ providers.ModerationProvider      = (*Provider)(nil)
+	_ providers.Provider                = (*Provider)(nil)
+	_ providers.SpeechProvider          = (*Provider)(nil)
+	_ providers.TranscriptionProvider   = (*Provider)(nil)
 )
 
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
 // Provider implements the providers.Provider interface for OpenAI.
 // It embeds CompatibleProvider which handles the OpenAI SDK integration.
 type Provider struct {
@@ -46,12 +56,20 @@ func New(opts ...config.Option) (*Provider, error) {
 // openAICapabilities returns the capabilities for the OpenAI provider.
 func openAICapabilities() providers.Capabilities {
 	return providers.Capabilities{
-		Completion:          true,
-		CompletionImage:     true,
-		CompletionPDF:       false,
-		CompletionReasoning: true,
-		CompletionStreaming: true,
-		Embedding:           true,
-		ListModels:          true,
+		Completion:                 true,
+		CompletionAudio:            true,
+		CompletionImage:            true,
+		CompletionPDF:              true,
+		CompletionReasoning:        true,
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            true,
+		ListModels:                 true,
+		Moderation:                 true,
+		Rerank:                     false,
+		Speech:                     true,
+		Transcription:              true,
 	}
 }
@@ -2,11 +2,14 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	stderrors "errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/stretchr/testify/require"
@@ -57,10 +60,91 @@ func TestCapabilities(t *testing.T) {
 
 	require.True(t, caps.Completion)
 	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionStructuredOutput)
 	require.True(t, caps.CompletionReasoning)
 	require.True(t, caps.CompletionImage)
+	require.True(t, caps.CompletionPDF)
+	require.True(t, caps.CompletionAudio)
 	require.True(t, caps.Embedding)
+	require.True(t, caps.ImageGeneration)
 	require.True(t, caps.ListModels)
+	require.True(t, caps.Moderation)
+	require.True(t, caps.Speech)
+	require.True(t, caps.Transcription)
+}
+
+func TestApplyExtra(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps known keys onto typed fields", func(t *testing.T) {
+		t.Parallel()
+
+		req := convertParams(providers.CompletionParams{Model: "gpt-4", Messages: testutil.SimpleMessages()})
+		err := applyExtra(&req, map[string]any{
+			"frequency_penalty": 0.5,
+			"logit_bias":        map[string]any{"50256": -100},
+			"max_tokens":        64,
+			"metadata":          map[string]string{"team": "search"},
+			"n":                 float64(2),
+			"presence_penalty":  1,
+			"prompt_cache_key":  "session-1",
+			"safety_identifier": "user-hash",
+			"service_tier":      "flex",
+			"store":             true,
+		})
+		require.NoError(t, err)
+
+		require.InDelta(t, 0.5, req.FrequencyPenalty.Value, 0)
+		require.Equal(t, map[string]int64{"50256": -100}, req.LogitBias)
+		require.Equal(t, int64(64), req.MaxTokens.Value)
+		require.Equal(t, "search", req.Metadata["team"])
+		require.Equal(t, int64(2), req.N.Value)
+		require.InDelta(t, 1.0, req.PresencePenalty.Value, 0)
+		require.Equal(t, "session-1", req.PromptCacheKey.Value)
+		require.Equal(t, "user-hash", req.SafetyIdentifier.Value)
+		require.Equal(t, "flex", string(req.ServiceTier))
+		require.True(t, req.Store.Value)
+		require.Empty(t, req.ExtraFields())
+	})
+
+	t.Run("merges unknown keys into the request body", func(t *testing.T) {
+		t.Parallel()
+
+		req := convertParams(providers.CompletionParams{Model: "local", Messages: testutil.SimpleMessages()})
+		err := applyExtra(&req, map[string]any{"top_k": 40, "min_p": 0.05, "presence_penalty": 0.1})
+		require.NoError(t, err)
+
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(body, &got))
+		require.InDelta(t, 40.0, got["top_k"], 0)
+		require.InDelta(t, 0.05, got["min_p"], 0)
+		require.InDelta(t, 0.1, got["presence_penalty"], 0)
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		req := convertParams(providers.CompletionParams{Model: "gpt-4", Messages: testutil.SimpleMessages()})
+		err := applyExtra(&req, map[string]any{"n": "two"})
+		require.ErrorContains(t, err, `"n"`)
+	})
+
+	t.Run("returns invalid request errors from Completion", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey("test-key"), config.WithBaseURL("http://127.0.0.1:0"))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "gpt-4",
+			Messages: testutil.SimpleMessages(),
+			Extra:    map[string]any{"store": "yes"},
+		})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
 }
 
 func TestConvertParams(t *testing.T) {
@@ -81,6 +165,44 @@ func TestConvertParams(t *testing.T) {
 		require.Len(t, req.Messages, 1)
 	})
 
+	t.Run("converts audio output options", func(t *testing.T) {
+		t.Parallel()
+
+		params := providers.CompletionParams{
+			Model:      "gpt-4o-audio-preview",
+			Messages:   []providers.Message{{Role: providers.RoleUser, Content: "Hello"}},
+			Modalities: []string{providers.ModalityText, providers.ModalityAudio},
+			Audio:      &providers.AudioParams{Voice: "alloy", Format: "wav"},
+		}
+
+		req := convertParams(params)
+
+		require.Equal(t, []string{"text", "audio"}, req.Modalities)
+		require.Equal(t, openai.ChatCompletionAudioParamVoice("alloy"), req.Audio.Voice)
+		require.Equal(t, openai.ChatCompletionAudioParamFormat("wav"), req.Audio.Format)
+	})
+
+	t.Run("ignores cache markers", func(t *testing.T) {
+		t.Parallel()
+
+		tool := testutil.WeatherTool()
+		tool.CacheControl = &providers.CacheControl{TTL: providers.CacheTTL1h}
+		params := providers.CompletionParams{
+			Model: "gpt-4o",
+			Messages: []providers.Message{
+				{Role: providers.RoleSystem, Content: "Be brief.", CacheControl: &providers.CacheControl{}},
+				{Role: providers.RoleUser, Content: []providers.ContentPart{
+					{Type: "text", Text: "Hello", CacheControl: &providers.CacheControl{}},
+				}},
+			},
+			Tools: []providers.Tool{tool},
+		}
+
+		data, err := json.Marshal(convertParams(params))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "cache_control")
+	})
+
 	t.Run("converts temperature and top_p", func(t *testing.T) {
 		t.Parallel()
 
@@ -312,7 +434,9 @@ func TestConvertMessage(t *testing.T) {
 		}
 		result, err := convertMessage(msg)
 		require.NoError(t, err)
-		require.NotNil(t, result)
+		require.NotNil(t, result.OfTool)
+		require.Equal(t, "call_123", result.OfTool.ToolCallID)
+		require.Equal(t, "sunny, 22°C", result.OfTool.Content.OfString.Value)
 	})
 
 	t.Run("converts multimodal user message", func(t *testing.T) {
@@ -330,6 +454,60 @@ func TestConvertMessage(t *testing.T) {
 		require.NotNil(t, result)
 	})
 
+	t.Run("converts file parts", func(t *testing.T) {
+		t.Parallel()
+
+		msg := providers.Message{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "text", Text: "Summarize these."},
+				{Type: "file", File: &providers.File{Data: "JVBERi0=", Filename: "report.pdf"}},
+				{Type: "file", File: &providers.File{Data: "aGVsbG8=", MIMEType: "text/plain"}},
+				{Type: "file", File: &providers.File{FileID: "file-123"}},
+			},
+		}
+		result, err := convertMessage(msg)
+		require.NoError(t, err)
+
+		parts := result.OfUser.Content.OfArrayOfContentParts
+		require.Len(t, parts, 4)
+		require.Equal(t, "data:application/pdf;base64,JVBERi0=", parts[1].OfFile.File.FileData.Value)
+		require.Equal(t, "report.pdf", parts[1].OfFile.File.Filename.Value)
+		require.Equal(t, "data:text/plain;base64,aGVsbG8=", parts[2].OfFile.File.FileData.Value)
+		require.Equal(t, "file-123", parts[3].OfFile.File.FileID.Value)
+		require.False(t, parts[3].OfFile.File.FileData.Valid())
+	})
+
+	t.Run("converts input audio parts", func(t *testing.T) {
+		t.Parallel()
+
+		msg := providers.Message{
+			Role: providers.RoleUser,
+			Content: []providers.ContentPart{
+				{Type: "input_audio", InputAudio: &providers.InputAudio{Data: "UklGRg==", Format: "wav"}},
+			},
+		}
+		result, err := convertMessage(msg)
+		require.NoError(t, err)
+
+		parts := result.OfUser.Content.OfArrayOfContentParts
+		require.Len(t, parts, 1)
+		require.Equal(t, "UklGRg==", parts[0].OfInputAudio.InputAudio.Data)
+		require.Equal(t, "wav", parts[0].OfInputAudio.InputAudio.Format)
+	})
+
+	t.Run("refers to previous audio by ID", func(t *testing.T) {
+		t.Parallel()
+
+		msg := providers.Message{
+			Role:  providers.RoleAssistant,
+			Audio: &providers.Audio{ID: "audio_123", Data: "UklGRg==", Transcript: "Hi"},
+		}
+		result, err := convertMessage(msg)
+		require.NoError(t, err)
+		require.Equal(t, "audio_123", result.OfAssistant.Audio.ID)
+	})
+
 	t.Run("returns error for unknown role", func(t *testing.T) {
 		t.Parallel()
 
@@ -348,6 +526,77 @@ func TestConvertResponse(t *testing.T) {
 		// We can't easily test this without mocking the OpenAI SDK response.
 		// This would be tested in integration tests.
 	})
+
+	t.Run("reports cached prompt tokens", func(t *testing.T) {
+		t.Parallel()
+
+		var resp openai.ChatCompletion
+		require.NoError(t, json.Unmarshal([]byte(`{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4o",`+
+			`"choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":2006,"completion_tokens":3,"total_tokens":2009,`+
+			`"prompt_tokens_details":{"cached_tokens":1920}}}`), &resp))
+
+		result := convertResponse(&resp)
+		require.Equal(t, &providers.Usage{
+			PromptTokens:     2006,
+			CompletionTokens: 3,
+			TotalTokens:      2009,
+			CacheReadTokens:  1920,
+		}, result.Usage)
+	})
+
+	t.Run("reports cached prompt tokens in the usage chunk", func(t *testing.T) {
+		t.Parallel()
+
+		var chunk openai.ChatCompletionChunk
+		require.NoError(t, json.Unmarshal([]byte(`{"id":"chatcmpl-1","object":"chat.completion.chunk",`+
+			`"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":2006,"completion_tokens":3,`+
+			`"total_tokens":2009,"prompt_tokens_details":{"cached_tokens":1920}}}`), &chunk))
+
+		result := convertChunk(&chunk)
+		require.Equal(t, 1920, result.Usage.CacheReadTokens)
+	})
+}
+
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "openai.(*CompatibleProvider).CompletionStream"
+
+	server := testutil.NewEndlessStreamServer(t, "text/event-stream", nil, func(i int) string {
+		return fmt.Sprintf(
+			"data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o-mini\","+
+				"\"choices\":[{\"index\":0,\"delta\":{\"content\":\"%d \"}}]}\n\n",
+			i,
+		)
+	})
+
+	provider, err := New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	params := providers.CompletionParams{Model: "gpt-4o-mini", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, "0 ", chunk.Choices[0].Delta.Content)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
 }
 
 // Integration tests - only run if API key is available.
@@ -651,6 +900,29 @@ func TestConvertError(t *testing.T) {
 			require.Contains(t, result.Error(), "["+providerName+"]")
 		})
 	}
+
+	t.Run("429 populates RetryAfter from headers", func(t *testing.T) {
+		t.Parallel()
+
+		apiErr := newTestAPIError(t, 429, "")
+		apiErr.Response.Header = http.Header{"Retry-After-Ms": []string{"2500"}}
+
+		p := &CompatibleProvider{compatibleConfig: CompatibleConfig{Name: providerName}}
+
+		var rateLimitErr *errors.RateLimitError
+		require.ErrorAs(t, p.ConvertError(apiErr), &rateLimitErr)
+		require.Equal(t, 3, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("ProviderError carries the status code", func(t *testing.T) {
+		t.Parallel()
+
+		p := &CompatibleProvider{compatibleConfig: CompatibleConfig{Name: providerName}}
+
+		var providerErr *errors.ProviderError
+		require.ErrorAs(t, p.ConvertError(newTestAPIError(t, 503, "")), &providerErr)
+		require.Equal(t, 503, providerErr.StatusCode)
+	})
 }
 
 // newTestAPIError creates an OpenAI API error for testing.
@@ -0,0 +1,170 @@
+// Package openrouter provides an OpenRouter provider implementation for any-llm.
+//
+// OpenRouter routes requests to many model providers through an OpenAI-compatible API, so the
+// provider is built on openai.CompatibleProvider. Applications can identify themselves to
+// OpenRouter with WithAppName and WithAppURL.
+package openrouter
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	openaisdk "github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://openrouter.ai/api/v1"
+	envAPIKey      = "OPENROUTER_API_KEY"
+	providerName   = "openrouter"
+)
+
+// Provider-specific configuration keys, set with WithAppName and WithAppURL.
+const (
+	configAppName = "openrouter_app_name"
+	configAppURL  = "openrouter_app_url"
+)
+
+// OpenRouter API constants.
+const (
+	headerReferer  = "HTTP-Referer"
+	headerTitle    = "X-Title"
+	paramReasoning = "reasoning"
+	reasoningField = "reasoning"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for OpenRouter.
+// It embeds openai.CompatibleProvider since OpenRouter exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a new OpenRouter provider.
+func New(opts ...config.Option) (*Provider, error) {
+	cfg, err := config.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	var clientOpts []option.RequestOption
+	if appURL := configString(cfg, configAppURL); appURL != "" {
+		clientOpts = append(clientOpts, option.WithHeader(headerReferer, appURL))
+	}
+	if appName := configString(cfg, configAppName); appName != "" {
+		clientOpts = append(clientOpts, option.WithHeader(headerTitle, appName))
+	}
+
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   openrouterCapabilities(),
+		ClientOptions:  clientOpts,
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// WithAppName sets the application name OpenRouter shows in its rankings, sent as X-Title.
+func WithAppName(name string) config.Option {
+	return func(c *config.Config) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("app name cannot be empty")
+		}
+
+		return config.WithExtra(configAppName, name)(c)
+	}
+}
+
+// WithAppURL sets the application URL OpenRouter attributes requests to, sent as HTTP-Referer.
+func WithAppURL(url string) config.Option {
+	return func(c *config.Config) error {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			return fmt.Errorf("app URL cannot be empty")
+		}
+
+		return config.WithExtra(configAppURL, url)(c)
+	}
+}
+
+// configString returns the string stored under key, or "".
+func configString(cfg *config.Config, key string) string {
+	v, _ := cfg.ExtraValue(key)
+	s, _ := v.(string)
+	return s
+}
+
+// openrouterCapabilities returns the capabilities for the OpenRouter provider.
+func openrouterCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true,
+		CompletionPDF:              false,
+		CompletionReasoning:        true,
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  false,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
+
+// prepareRequest sends reasoning effort as OpenRouter's unified reasoning object, which it
+// translates for each upstream provider. A reasoning object set through Extra is kept.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	if req.ReasoningEffort == "" {
+		return nil
+	}
+	req.ReasoningEffort = ""
+
+	fields := maps.Clone(req.ExtraFields())
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+	if _, ok := fields[paramReasoning]; ok {
+		return nil
+	}
+
+	if params.ReasoningEffort == providers.ReasoningEffortAuto {
+		fields[paramReasoning] = map[string]any{"enabled": true}
+	} else {
+		fields[paramReasoning] = map[string]any{"effort": string(params.ReasoningEffort)}
+	}
+	req.SetExtraFields(fields)
+
+	return nil
+}
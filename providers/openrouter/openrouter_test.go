@@ -0,0 +1,194 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+const chatResponse = `{
+	"id": "gen-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "deepseek/deepseek-r1",
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "content": "4", "reasoning": "2 plus 2 is 4."},
+		"finish_reason": "stop"
+	}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+// request is a request received by the test server.
+type request struct {
+	body   map[string]any
+	header http.Header
+}
+
+// record returns a handler standing in for the OpenRouter API that records the last request into got.
+func record(t *testing.T, got *request) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/chat/completions", r.URL.Path)
+		got.header = r.Header.Clone()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got.body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(chatResponse))
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "openrouter", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("OPENROUTER_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("OPENROUTER_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "openrouter", missingKeyErr.Provider)
+		require.Equal(t, "OPENROUTER_API_KEY", missingKeyErr.EnvVar)
+	})
+
+	t.Run("rejects empty app settings", func(t *testing.T) {
+		_, err := New(config.WithAPIKey(testAPIKey), WithAppName(" "))
+		require.ErrorContains(t, err, "app name cannot be empty")
+
+		_, err = New(config.WithAPIKey(testAPIKey), WithAppURL(""))
+		require.ErrorContains(t, err, "app URL cannot be empty")
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends attribution headers", func(t *testing.T) {
+		t.Parallel()
+
+		var got request
+		provider := testutil.NewTestProvider(t, New, record(t, &got),
+			config.WithAPIKey(testAPIKey),
+			WithAppName("My App"),
+			WithAppURL("https://example.com"),
+		)
+
+		resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "deepseek/deepseek-r1",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "My App", got.header.Get("X-Title"))
+		require.Equal(t, "https://example.com", got.header.Get("HTTP-Referer"))
+		require.Equal(t, "Bearer "+testAPIKey, got.header.Get("Authorization"))
+
+		require.Equal(t, "4", resp.Choices[0].Message.Content)
+		require.NotNil(t, resp.Choices[0].Message.Reasoning)
+		require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+	})
+
+	t.Run("omits attribution headers by default", func(t *testing.T) {
+		t.Parallel()
+
+		var got request
+		provider := testutil.NewTestProvider(t, New, record(t, &got), config.WithAPIKey(testAPIKey))
+
+		_, err := provider.Completion(context.Background(), providers.CompletionParams{
+			Model:    "deepseek/deepseek-r1",
+			Messages: testutil.SimpleMessages(),
+		})
+		require.NoError(t, err)
+
+		require.Empty(t, got.header.Get("X-Title"))
+		require.Empty(t, got.header.Get("HTTP-Referer"))
+		require.NotContains(t, got.body, "reasoning")
+	})
+
+	tests := map[string]struct {
+		effort        providers.ReasoningEffort
+		extra         map[string]any
+		wantReasoning any
+	}{
+		"effort level": {
+			effort:        providers.ReasoningEffortHigh,
+			wantReasoning: map[string]any{"effort": "high"},
+		},
+		"automatic effort": {
+			effort:        providers.ReasoningEffortAuto,
+			wantReasoning: map[string]any{"enabled": true},
+		},
+		"reasoning set through Extra": {
+			effort:        providers.ReasoningEffortLow,
+			extra:         map[string]any{"reasoning": map[string]any{"max_tokens": 2000}},
+			wantReasoning: map[string]any{"max_tokens": float64(2000)},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run("maps reasoning for "+name, func(t *testing.T) {
+			t.Parallel()
+
+			var got request
+			provider := testutil.NewTestProvider(t, New, record(t, &got), config.WithAPIKey(testAPIKey))
+
+			_, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:           "deepseek/deepseek-r1",
+				Messages:        testutil.SimpleMessages(),
+				ReasoningEffort: tc.effort,
+				Extra:           tc.extra,
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.wantReasoning, got.body["reasoning"])
+			require.NotContains(t, got.body, "reasoning_effort")
+		})
+	}
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("openrouter") {
+		t.Skip("OPENROUTER_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("openrouter"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,8 +20,10 @@ import (
 	"github.com/mozilla-ai/any-llm-go/config"
 	"github.com/mozilla-ai/any-llm-go/errors"
 	"github.com/mozilla-ai/any-llm-go/providers"
-	"github.com/mozilla-ai/any-llm-go/providers/anthropic"
-	"github.com/mozilla-ai/any-llm-go/providers/openai"
+
+	// Register the providers the platform can proxy to.
+	_ "github.com/mozilla-ai/any-llm-go/providers/anthropic"
+	_ "github.com/mozilla-ai/any-llm-go/providers/openai"
 )
 
 const (
@@ -30,18 +33,9 @@ const (
 	defaultPlatformURL = "https://platform-api.any-llm.ai/api/v1"
 )
 
-// newProviderFunc creates a provider with the given options.
-type newProviderFunc func(opts ...config.Option) (providers.Provider, error)
-
-// supportedProviders maps provider names to their constructors.
-var supportedProviders = map[string]newProviderFunc{
-	"openai": func(opts ...config.Option) (providers.Provider, error) {
-		return openai.New(opts...)
-	},
-	"anthropic": func(opts ...config.Option) (providers.Provider, error) {
-		return anthropic.New(opts...)
-	},
-}
+// supportedProviders lists the providers the platform can proxy to.
+// Other registered providers, including the platform itself, are rejected.
+var supportedProviders = []string{"anthropic", "openai"}
 
 // Provider implements the providers.Provider interface for the ANY LLM platform.
 // It proxies requests to underlying providers (OpenAI, Anthropic, etc.) after
@@ -68,6 +62,12 @@ var (
 	_ providers.CapabilityProvider = (*Provider)(nil)
 )
 
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
 // New creates a new platform provider.
 func New(opts ...config.Option) (*Provider, error) {
 	cfg, err := config.New(opts...)
@@ -119,13 +119,21 @@ func (p *Provider) Name() string {
 func (p *Provider) Capabilities() providers.Capabilities {
 	// Return full capabilities since we can proxy to any provider.
 	return providers.Capabilities{
-		Completion:          true,
-		CompletionStreaming: true,
-		CompletionReasoning: true,
-		CompletionImage:     true,
-		CompletionPDF:       true,
-		Embedding:           true,
-		ListModels:          true,
+		Completion:                 true,
+		CompletionAudio:            true,
+		CompletionStreaming:        true,
+		CompletionReasoning:        true,
+		CompletionImage:            true,
+		CompletionPDF:              true,
+		CompletionStructuredOutput: false, // Not every underlying provider honors response_format.
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
 	}
 }
 
@@ -135,6 +143,15 @@ func (p *Provider) initializeProvider(ctx context.Context, providerName string)
 		return nil // Already initialized for this provider
 	}
 
+	if !slices.Contains(supportedProviders, strings.ToLower(providerName)) {
+		return errors.NewUnsupportedProviderError(providerName)
+	}
+
+	constructor, ok := providers.Lookup(providerName)
+	if !ok {
+		return errors.NewUnsupportedProviderError(providerName)
+	}
+
 	// Get decrypted provider key from the platform
 	result, err := p.platformClient.GetDecryptedProviderKey(ctx, p.anyLLMKey, providerName)
 	if err != nil {
@@ -144,12 +161,15 @@ func (p *Provider) initializeProvider(ctx context.Context, providerName string)
 	p.providerKeyID = result.ProviderKeyID.String()
 	p.projectID = result.ProjectID.String()
 
-	// Create the underlying provider using the decrypted API key.
-	constructor, ok := supportedProviders[strings.ToLower(providerName)]
-	if !ok {
-		return fmt.Errorf("unsupported provider: %s", providerName)
-	}
-	provider, err := constructor(config.WithAPIKey(result.APIKey))
+	// Create the underlying provider using the decrypted API key, passing on the HTTP client and headers.
+	provider, err := constructor(
+		config.WithAPIKey(result.APIKey),
+		config.WithHTTPClient(p.config.HTTPClient()),
+		func(c *config.Config) error {
+			c.Headers = p.config.Headers.Clone()
+			return nil
+		},
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create provider %q: %w", providerName, err)
 	}
@@ -159,13 +179,14 @@ func (p *Provider) initializeProvider(ctx context.Context, providerName string)
 	return nil
 }
 
-// parseModelString parses a model string in the format "provider:model" or just "model".
-func parseModelString(model string) (providerName, modelID string) {
-	parts := strings.SplitN(model, ":", 2)
-	if len(parts) == 2 {
-		return parts[0], parts[1]
+// splitModel splits a model string in the format "provider:model" using providers.ParseModelString.
+// The platform needs both parts, so a bare name, which ParseModelString treats as a provider, is rejected.
+func splitModel(model string) (providerName, modelID string, err error) {
+	providerName, modelID = providers.ParseModelString(model)
+	if providerName == "" || modelID == "" {
+		return "", "", fmt.Errorf("model must be in format 'provider:model', got %q", model)
 	}
-	return "", model
+	return providerName, modelID, nil
 }
 
 // Completion performs a chat completion request.
@@ -176,9 +197,9 @@ func (p *Provider) Completion(
 	startTime := time.Now()
 
 	// Parse the model to get the provider name
-	providerName, modelID := parseModelString(params.Model)
-	if providerName == "" {
-		return nil, fmt.Errorf("model must be in format 'provider:model', got %q", params.Model)
+	providerName, modelID, err := splitModel(params.Model)
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize the underlying provider
@@ -218,9 +239,9 @@ func (p *Provider) CompletionStream(
 		startTime := time.Now()
 
 		// Parse the model to get the provider name
-		providerName, modelID := parseModelString(params.Model)
-		if providerName == "" {
-			errs <- fmt.Errorf("model must be in format 'provider:model', got %q", params.Model)
+		providerName, modelID, err := splitModel(params.Model)
+		if err != nil {
+			errs <- err
 			return
 		}
 
@@ -250,7 +271,8 @@ func (p *Provider) CompletionStream(
 
 		// Track streaming metrics.
 		var (
-			collectedChunks     []providers.ChatCompletionChunk
+			accumulator         = providers.NewAccumulator()
+			chunksReceived      int
 			timeToFirstTokenMs  *float64
 			timeToLastContentMs *float64
 			previousChunkTime   *time.Time
@@ -280,8 +302,14 @@ func (p *Provider) CompletionStream(
 			}
 			previousChunkTime = &currentTime
 
-			collectedChunks = append(collectedChunks, chunk)
-			chunks <- chunk
+			accumulator.Add(chunk)
+			chunksReceived++
+
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				return
+			}
 		}
 
 		// Check for upstream errors
@@ -291,15 +319,15 @@ func (p *Provider) CompletionStream(
 		}
 
 		// Post usage event with streaming metrics
-		if len(collectedChunks) > 0 {
+		if chunksReceived > 0 {
 			totalDurationMs := float64(time.Since(startTime).Milliseconds())
-			completion := combineChunks(collectedChunks)
+			completion := accumulator.ChatCompletion()
 
 			metrics := &streamingMetrics{
 				TimeToFirstTokenMs: timeToFirstTokenMs,
 				TimeToLastTokenMs:  timeToLastContentMs,
 				TotalDurationMs:    totalDurationMs,
-				ChunksReceived:     len(collectedChunks),
+				ChunksReceived:     chunksReceived,
 			}
 
 			// Calculate tokens per second if we have usage data
@@ -308,7 +336,7 @@ func (p *Provider) CompletionStream(
 				tps := float64(completion.Usage.CompletionTokens*1000) / *timeToLastContentMs
 				metrics.TokensPerSecond = &tps
 
-				avgChunkSize := float64(completion.Usage.CompletionTokens) / float64(len(collectedChunks))
+				avgChunkSize := float64(completion.Usage.CompletionTokens) / float64(chunksReceived)
 				metrics.AvgChunkSize = &avgChunkSize
 			}
 
@@ -360,24 +388,6 @@ func calculateVariance(values []float64) float64 {
 	return sumSquaredDiff / float64(len(values)-1)
 }
 
-// combineChunks combines streaming chunks into a ChatCompletion for usage tracking.
-func combineChunks(chunks []providers.ChatCompletionChunk) *providers.ChatCompletion {
-	if len(chunks) == 0 {
-		return nil
-	}
-
-	lastChunk := chunks[len(chunks)-1]
-
-	return &providers.ChatCompletion{
-		ID:      lastChunk.ID,
-		Object:  "chat.completion",
-		Created: lastChunk.Created,
-		Model:   lastChunk.Model,
-		Choices: []providers.Choice{},
-		Usage:   lastChunk.Usage,
-	}
-}
-
 // usageEventPayload represents the payload for usage events.
 type usageEventPayload struct {
 	ProviderKeyID string         `json:"provider_key_id"`
@@ -2,6 +2,7 @@ package platform
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/mozilla-ai/any-llm-go/config"
 	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
 	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
 )
 
 func TestNew(t *testing.T) {
@@ -64,29 +67,38 @@ func TestProvider_Capabilities(t *testing.T) {
 
 	require.True(t, caps.Completion)
 	require.True(t, caps.CompletionStreaming)
+	require.False(t, caps.CompletionStructuredOutput)
 	require.True(t, caps.CompletionReasoning)
 	require.True(t, caps.Embedding)
 }
 
-func TestParseModelString(t *testing.T) {
+func TestSplitModel(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		input        string
 		wantProvider string
 		wantModel    string
+		wantErr      bool
 	}{
-		{"openai:gpt-4o-mini", "openai", "gpt-4o-mini"},
-		{"anthropic:claude-3-5-haiku-latest", "anthropic", "claude-3-5-haiku-latest"},
-		{"gpt-4o-mini", "", "gpt-4o-mini"},
-		{"provider:model:with:colons", "provider", "model:with:colons"},
+		{input: "openai:gpt-4o-mini", wantProvider: "openai", wantModel: "gpt-4o-mini"},
+		{input: "anthropic:claude-3-5-haiku-latest", wantProvider: "anthropic", wantModel: "claude-3-5-haiku-latest"},
+		{input: "provider:model:with:colons", wantProvider: "provider", wantModel: "model:with:colons"},
+		{input: "gpt-4o-mini", wantErr: true},
+		{input: "openai:", wantErr: true},
+		{input: ":gpt-4o-mini", wantErr: true},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.input, func(t *testing.T) {
 			t.Parallel()
 
-			provider, model := parseModelString(tc.input)
+			provider, model, err := splitModel(tc.input)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 			require.Equal(t, tc.wantProvider, provider)
 			require.Equal(t, tc.wantModel, model)
 		})
@@ -155,6 +167,76 @@ func TestCompletionStreamDoesNotMutateParams(t *testing.T) {
 	require.Equal(t, false, params.StreamOptions.IncludeUsage)
 }
 
+func TestCompletionUnsupportedProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(config.WithAPIKey("ANY.v1.test.fingerprint-dGVzdHByaXZhdGVrZXkxMjM0NTY3ODkwMTI="))
+	require.NoError(t, err)
+
+	// The platform itself is registered but must not proxy to itself.
+	for _, name := range []string{"unknown", "platform"} {
+		params := providers.CompletionParams{
+			Model:    name + ":some-model",
+			Messages: []providers.Message{{Role: providers.RoleUser, Content: "Hello"}},
+		}
+
+		_, err = provider.Completion(context.Background(), params)
+		require.ErrorIs(t, err, errors.ErrUnsupportedProvider)
+
+		var unsupportedErr *errors.UnsupportedProviderError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, name, unsupportedErr.Provider)
+	}
+}
+
+func TestCompletionStreamStopsWithoutLeaking(t *testing.T) {
+	// Note: Not using t.Parallel() here because the leak check inspects every goroutine.
+
+	const streamFunc = "platform.(*Provider).CompletionStream"
+
+	server := testutil.NewEndlessStreamServer(t, "text/event-stream", nil, func(i int) string {
+		return fmt.Sprintf(
+			"data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o-mini\","+
+				"\"choices\":[{\"index\":0,\"delta\":{\"content\":\"%d \"}}]}\n\n",
+			i,
+		)
+	})
+
+	underlying, err := openai.New(config.WithAPIKey("test-api-key"), config.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	provider, err := New(config.WithAPIKey("ANY.v1.test.fingerprint-dGVzdHByaXZhdGVrZXkxMjM0NTY3ODkwMTI="))
+	require.NoError(t, err)
+
+	// Skip the platform key exchange by pre-initializing the underlying provider.
+	provider.underlyingProvider = underlying
+	provider.underlyingName = "openai"
+
+	params := providers.CompletionParams{Model: "openai:gpt-4o-mini", Messages: testutil.SimpleMessages()}
+
+	t.Run("breaking out of Stream", func(t *testing.T) {
+		for chunk, err := range providers.Stream(context.Background(), provider, params) {
+			require.NoError(t, err)
+			require.Equal(t, "0 ", chunk.Choices[0].Delta.Content)
+			break
+		}
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+
+	t.Run("canceling without reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		chunks, _ := provider.CompletionStream(ctx, params)
+		<-chunks
+
+		// Stop reading and give the producer time to block on its next send before canceling.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		testutil.RequireGoroutineExits(t, streamFunc)
+	})
+}
+
 // Integration tests - require actual platform connection and ANY_LLM_KEY
 
 func TestIntegrationOpenAICompletion(t *testing.T) {
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+)
+
+// Factory creates a provider configured with the given options.
+type Factory func(opts ...config.Option) (Provider, error)
+
+// registry holds the provider factories registered by name.
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider factory available under the given name.
+// Provider packages call it from an init function, so importing a provider
+// package is enough to make it available by name.
+// Names are case-insensitive. Register panics if the name is empty, the factory
+// is nil, or the name is already registered.
+func Register(name string, factory Factory) {
+	name = normalizeProviderName(name)
+	if name == "" {
+		panic("providers: Register called with empty name")
+	}
+	if factory == nil {
+		panic("providers: Register factory is nil for " + name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("providers: Register called twice for %q", name))
+	}
+	registry[name] = factory
+}
+
+// Lookup returns the factory registered under the given name.
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := registry[normalizeProviderName(name)]
+	return factory, ok
+}
+
+// Registered returns the sorted names of all registered providers.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// ParseModelString parses a model string in the format "provider:model".
+// Only the first colon separates the provider, so model IDs containing colons
+// (e.g., "ollama:llama3.2:3b") are preserved. A string without a colon is
+// treated as a bare provider name with an empty model ID.
+func ParseModelString(model string) (providerName, modelID string) {
+	parts := strings.SplitN(model, ":", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// normalizeProviderName returns the canonical registry key for a provider name.
+func normalizeProviderName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
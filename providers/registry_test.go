@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	factory := func(opts ...config.Option) (Provider, error) { return nil, nil }
+
+	t.Run("registers and looks up a factory case-insensitively", func(t *testing.T) {
+		t.Parallel()
+
+		registerOnce(t, "Test-Register-Lookup", factory)
+
+		got, ok := Lookup("test-register-lookup")
+		require.True(t, ok)
+		require.NotNil(t, got)
+		require.Contains(t, Registered(), "test-register-lookup")
+	})
+
+	t.Run("panics on duplicate registration", func(t *testing.T) {
+		t.Parallel()
+
+		registerOnce(t, "test-register-duplicate", factory)
+
+		require.Panics(t, func() { Register("TEST-REGISTER-DUPLICATE", factory) })
+	})
+
+	t.Run("panics on empty name", func(t *testing.T) {
+		t.Parallel()
+
+		require.Panics(t, func() { Register("  ", factory) })
+	})
+
+	t.Run("panics on nil factory", func(t *testing.T) {
+		t.Parallel()
+
+		require.Panics(t, func() { Register("test-register-nil", nil) })
+	})
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	_, ok := Lookup("test-lookup-unknown")
+	require.False(t, ok)
+}
+
+func TestRegistered(t *testing.T) {
+	t.Parallel()
+
+	factory := func(opts ...config.Option) (Provider, error) { return nil, nil }
+	registerOnce(t, "test-registered-b", factory)
+	registerOnce(t, "test-registered-a", factory)
+
+	names := Registered()
+	require.IsNonDecreasing(t, names)
+	require.Contains(t, names, "test-registered-a")
+	require.Contains(t, names, "test-registered-b")
+}
+
+func TestParseModelString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input        string
+		wantProvider string
+		wantModel    string
+	}{
+		{"openai:gpt-4o", "openai", "gpt-4o"},
+		{"ollama:llama3.2:3b", "ollama", "llama3.2:3b"},
+		{"anthropic", "anthropic", ""},
+		{"openai:", "openai", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			provider, model := ParseModelString(tc.input)
+			require.Equal(t, tc.wantProvider, provider)
+			require.Equal(t, tc.wantModel, model)
+		})
+	}
+}
+
+// registerOnce registers factory unless name is already registered, so tests can run repeatedly
+// against the process-wide registry (e.g. with -count).
+func registerOnce(t *testing.T, name string, factory Factory) {
+	t.Helper()
+
+	if _, ok := Lookup(name); !ok {
+		Register(name, factory)
+	}
+}
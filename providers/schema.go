@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"github.com/mozilla-ai/any-llm-go/schema"
+)
+
+// Types of generated tools and response formats.
+const (
+	responseFormatJSONSchema = "json_schema"
+	toolTypeFunction         = "function"
+)
+
+// ResponseFormatFor returns a json_schema response format whose schema is generated from T.
+// See the schema package for the struct tags that refine the generated schema.
+func ResponseFormatFor[T any](name string) (*ResponseFormat, error) {
+	s, err := schema.For[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResponseFormat{
+		Type: responseFormatJSONSchema,
+		JSONSchema: &JSONSchema{
+			Name:   name,
+			Schema: s,
+		},
+	}, nil
+}
+
+// ToolFor returns a function tool whose parameters schema is generated from T.
+// See the schema package for the struct tags that refine the generated schema.
+func ToolFor[T any](name, description string) (Tool, error) {
+	s, err := schema.For[T]()
+	if err != nil {
+		return Tool{}, err
+	}
+
+	return Tool{
+		Type: toolTypeFunction,
+		Function: Function{
+			Name:        name,
+			Description: description,
+			Parameters:  s,
+		},
+	}, nil
+}
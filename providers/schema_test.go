@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type weatherQuery struct {
+	Location string `json:"location" description:"City name"`
+	Unit     string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+}
+
+func TestResponseFormatFor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds a json_schema response format", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := ResponseFormatFor[weatherQuery]("weather_query")
+		require.NoError(t, err)
+		require.Equal(t, responseFormatJSONSchema, got.Type)
+		require.Equal(t, "weather_query", got.JSONSchema.Name)
+		require.Equal(t, "object", got.JSONSchema.Schema["type"])
+		require.Equal(t, []string{"location"}, got.JSONSchema.Schema["required"])
+	})
+
+	t.Run("reports unsupported types", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ResponseFormatFor[func()]("invalid")
+		require.Error(t, err)
+	})
+}
+
+func TestToolFor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds a function tool", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := ToolFor[weatherQuery]("get_weather", "Get the current weather")
+		require.NoError(t, err)
+		require.Equal(t, toolTypeFunction, got.Type)
+		require.Equal(t, "get_weather", got.Function.Name)
+		require.Equal(t, "Get the current weather", got.Function.Description)
+
+		properties := got.Function.Parameters["properties"].(map[string]any)
+		require.Equal(t, map[string]any{"type": "string", "description": "City name"}, properties["location"])
+	})
+
+	t.Run("reports unsupported types", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ToolFor[chan int]("invalid", "")
+		require.Error(t, err)
+	})
+}
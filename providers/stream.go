@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"iter"
+)
+
+// Stream performs a streaming chat completion request and returns an iterator over its chunks.
+//
+// The iterator yields each chunk with a nil error. If the stream fails, the final iteration
+// yields a zero chunk and the error. Breaking out of the loop cancels the upstream request,
+// so callers can stop early without leaking the provider's goroutine:
+//
+//	for chunk, err := range providers.Stream(ctx, provider, params) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(chunk.Choices[0].Delta.Content)
+//	}
+//
+// The iterator is single-use; each call to Stream starts a new request when iterated.
+func Stream(ctx context.Context, provider Provider, params CompletionParams) iter.Seq2[ChatCompletionChunk, error] {
+	return func(yield func(ChatCompletionChunk, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		chunks, errs := provider.CompletionStream(ctx, params)
+		for chunk := range chunks {
+			if !yield(chunk, nil) {
+				cancel()
+				// Let the producer finish in the background; it exits promptly once it sees
+				// the canceled context, and draining guarantees it is never stuck on a send.
+				go drain(chunks, errs)
+				return
+			}
+		}
+
+		if err := <-errs; err != nil {
+			yield(ChatCompletionChunk{}, err)
+		}
+	}
+}
+
+// StreamChannels adapts an iterator produced by Stream back to the channel pair used by
+// Provider.CompletionStream. The iterator stops, canceling its upstream request, when ctx is done.
+func StreamChannels(
+	ctx context.Context,
+	seq iter.Seq2[ChatCompletionChunk, error],
+) (<-chan ChatCompletionChunk, <-chan error) {
+	chunks := make(chan ChatCompletionChunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		for chunk, err := range seq {
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return chunks, errs
+}
+
+// drain discards everything left on a stream's channels.
+func drain(chunks <-chan ChatCompletionChunk, errs <-chan error) {
+	for range chunks {
+		// Discard.
+	}
+	<-errs
+}
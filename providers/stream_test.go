@@ -0,0 +1,179 @@
+package providers
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("yields every chunk", func(t *testing.T) {
+		t.Parallel()
+
+		p := &endlessProvider{limit: 3}
+
+		var contents []string
+		for chunk, err := range Stream(context.Background(), p, CompletionParams{}) {
+			require.NoError(t, err)
+			contents = append(contents, chunk.Choices[0].Delta.Content)
+		}
+
+		require.Equal(t, []string{"0", "1", "2"}, contents)
+		p.requireExited(t)
+	})
+
+	t.Run("yields the stream error last", func(t *testing.T) {
+		t.Parallel()
+
+		p := &endlessProvider{limit: 1, err: stderrors.New("boom")}
+
+		var (
+			chunks int
+			errs   []error
+		)
+		for _, err := range Stream(context.Background(), p, CompletionParams{}) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			chunks++
+		}
+
+		require.Equal(t, 1, chunks)
+		require.Len(t, errs, 1)
+		require.EqualError(t, errs[0], "boom")
+	})
+
+	t.Run("breaking out cancels the producer", func(t *testing.T) {
+		t.Parallel()
+
+		p := &endlessProvider{}
+
+		for chunk, err := range Stream(context.Background(), p, CompletionParams{}) {
+			require.NoError(t, err)
+			require.Equal(t, "0", chunk.Choices[0].Delta.Content)
+			break
+		}
+
+		p.requireExited(t)
+	})
+
+	t.Run("drains producers that ignore cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		p := &endlessProvider{limit: 100, ignoreContext: true}
+
+		for range Stream(context.Background(), p, CompletionParams{}) {
+			break
+		}
+
+		p.requireExited(t)
+	})
+}
+
+func TestStreamChannels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trips chunks and errors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		p := &endlessProvider{limit: 2, err: stderrors.New("boom")}
+
+		chunks, errs := StreamChannels(ctx, Stream(ctx, p, CompletionParams{}))
+
+		var received int
+		for range chunks {
+			received++
+		}
+		require.Equal(t, 2, received)
+		require.EqualError(t, <-errs, "boom")
+	})
+
+	t.Run("stops the iterator when the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p := &endlessProvider{}
+
+		chunks, errs := StreamChannels(ctx, Stream(ctx, p, CompletionParams{}))
+		<-chunks
+		cancel()
+
+		for range chunks {
+			// Discard anything sent before cancellation was observed.
+		}
+		require.ErrorIs(t, <-errs, context.Canceled)
+		p.requireExited(t)
+	})
+}
+
+// endlessProvider streams numbered chunks until limit is reached (or forever when limit is zero),
+// then reports err. It records when its producer goroutine exits.
+type endlessProvider struct {
+	err           error
+	exited        chan struct{}
+	ignoreContext bool
+	limit         int
+}
+
+func (p *endlessProvider) Name() string { return "endless" }
+
+func (p *endlessProvider) Completion(context.Context, CompletionParams) (*ChatCompletion, error) {
+	return nil, stderrors.New("not implemented")
+}
+
+func (p *endlessProvider) CompletionStream(
+	ctx context.Context,
+	_ CompletionParams,
+) (<-chan ChatCompletionChunk, <-chan error) {
+	chunks := make(chan ChatCompletionChunk)
+	errs := make(chan error, 1)
+	p.exited = make(chan struct{})
+
+	go func() {
+		defer close(p.exited)
+		defer close(chunks)
+		defer close(errs)
+
+		for i := 0; p.limit == 0 || i < p.limit; i++ {
+			chunk := ChatCompletionChunk{
+				Choices: []ChunkChoice{{Delta: ChunkDelta{Content: string(rune('0' + i%10))}}},
+			}
+
+			if p.ignoreContext {
+				chunks <- chunk
+				continue
+			}
+
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if p.err != nil {
+			errs <- p.err
+		}
+	}()
+
+	return chunks, errs
+}
+
+// requireExited fails the test if the producer goroutine does not exit promptly.
+func (p *endlessProvider) requireExited(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-p.exited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("producer goroutine did not exit")
+	}
+}
@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mozilla-ai/any-llm-go/schema"
+)
+
+// Structured output defaults.
+const (
+	defaultRepairAttempts = 2
+	defaultSchemaName     = "response"
+)
+
+// codeFence matches a fenced code block, capturing its body.
+var codeFence = regexp.MustCompile("(?s)```[A-Za-z0-9_-]*[ \t]*\r?\n?(.*?)```")
+
+// IntoOption configures CompletionInto.
+type IntoOption func(*intoOptions) error
+
+// intoOptions holds the CompletionInto configuration.
+type intoOptions struct {
+	name           string
+	repairAttempts int
+}
+
+// CompletionInto performs a chat completion request and decodes the response into a T.
+//
+// The JSON Schema of T is generated with the schema package and sent as a json_schema
+// response format. Providers whose capabilities report no native structured output
+// support, such as Anthropic, are instead forced to call a single tool whose parameters
+// are the schema, and the tool call's arguments are decoded. ReasoningEffort is cleared
+// in that case, since Anthropic rejects extended thinking with a forced tool choice.
+//
+// Fenced code blocks are stripped from the response before it is decoded. If the response
+// is not valid JSON or does not match the schema, the model is told what was wrong and asked
+// again, up to WithRepairAttempts times. The returned ChatCompletion is the last response
+// received. T should be a struct type, since providers require an object schema.
+func CompletionInto[T any](
+	ctx context.Context,
+	provider Provider,
+	params CompletionParams,
+	opts ...IntoOption,
+) (T, *ChatCompletion, error) {
+	var zero T
+
+	o := &intoOptions{
+		name:           defaultSchemaName,
+		repairAttempts: defaultRepairAttempts,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(o); err != nil {
+			return zero, nil, err
+		}
+	}
+
+	s, err := schema.For[T]()
+	if err != nil {
+		return zero, nil, err
+	}
+
+	native := supportsStructuredOutput(provider)
+	params.Messages = append([]Message(nil), params.Messages...)
+	if native {
+		params.ResponseFormat = &ResponseFormat{
+			Type:       responseFormatJSONSchema,
+			JSONSchema: &JSONSchema{Name: o.name, Schema: s},
+		}
+	} else {
+		params.Tools = append(append([]Tool(nil), params.Tools...), Tool{
+			Type:     toolTypeFunction,
+			Function: Function{Name: o.name, Description: "Respond with the requested data.", Parameters: s},
+		})
+		params.ToolChoice = ToolChoice{Type: toolTypeFunction, Function: &ToolChoiceFunction{Name: o.name}}
+		params.ReasoningEffort = ""
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := provider.Completion(ctx, params)
+		if err != nil {
+			return zero, resp, err
+		}
+		if len(resp.Choices) == 0 {
+			return zero, resp, fmt.Errorf("provider %s returned no choices", provider.Name())
+		}
+
+		message := resp.Choices[0].Message
+		raw, call := structuredContent(message, o.name, native)
+
+		value, err := decodeInto[T](raw, s)
+		if err == nil {
+			return value, resp, nil
+		}
+		if attempt == o.repairAttempts {
+			return zero, resp, fmt.Errorf("structured output invalid after %d attempts: %w", attempt+1, err)
+		}
+
+		params.Messages = append(params.Messages, repairMessages(message, call, err)...)
+	}
+}
+
+// WithRepairAttempts sets how many times CompletionInto asks the model to fix an invalid
+// response. Zero disables repairs.
+func WithRepairAttempts(n int) IntoOption {
+	return func(o *intoOptions) error {
+		if n < 0 {
+			return fmt.Errorf("repair attempts cannot be negative, got %d", n)
+		}
+
+		o.repairAttempts = n
+		return nil
+	}
+}
+
+// WithSchemaName sets the name of the response format or tool sent to the model.
+func WithSchemaName(name string) IntoOption {
+	return func(o *intoOptions) error {
+		if name == "" {
+			return fmt.Errorf("schema name cannot be empty")
+		}
+
+		o.name = name
+		return nil
+	}
+}
+
+// decodeInto validates raw against s and decodes it into a T.
+func decodeInto[T any](raw string, s map[string]any) (T, error) {
+	var value T
+
+	data := []byte(stripCodeFence(raw))
+	if err := schema.ValidateJSON(s, data); err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// repairMessages returns the messages that echo an invalid response and ask the model to fix it.
+// Tool calls are answered with tool results, since providers require every call to get one.
+func repairMessages(message Message, call *ToolCall, err error) []Message {
+	feedback := fmt.Sprintf(
+		"The response does not match the required JSON schema: %v. Respond again with corrected JSON.", err,
+	)
+
+	messages := []Message{message}
+	if len(message.ToolCalls) == 0 {
+		return append(messages, Message{Role: RoleUser, Content: feedback})
+	}
+
+	for _, tc := range message.ToolCalls {
+		content := "This tool call was not used."
+		if call != nil && tc.ID == call.ID {
+			content = feedback
+		}
+		messages = append(messages, Message{Role: RoleTool, Content: content, ToolCallID: tc.ID})
+	}
+	if call == nil {
+		messages = append(messages, Message{Role: RoleUser, Content: feedback})
+	}
+
+	return messages
+}
+
+// stripCodeFence returns the body of the first fenced code block in s, or s itself
+// if it contains none.
+func stripCodeFence(s string) string {
+	if m := codeFence.FindStringSubmatch(s); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// structuredContent returns the raw JSON of a structured response, along with the tool call
+// it came from when the tool fallback is used.
+func structuredContent(message Message, name string, native bool) (string, *ToolCall) {
+	if !native {
+		for i, tc := range message.ToolCalls {
+			if tc.Function.Name == name {
+				return tc.Function.Arguments, &message.ToolCalls[i]
+			}
+		}
+	}
+
+	return message.ContentString(), nil
+}
+
+// supportsStructuredOutput reports whether provider honors json_schema response formats.
+// Providers that do not report their capabilities are assumed to.
+func supportsStructuredOutput(provider Provider) bool {
+	cp, ok := provider.(CapabilityProvider)
+	return !ok || cp.Capabilities().CompletionStructuredOutput
+}
@@ -0,0 +1,206 @@
+package providers
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/schema"
+)
+
+type city struct {
+	Name       string `json:"name"`
+	Population int    `json:"population" min:"0"`
+}
+
+func TestCompletionInto(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes a native structured response", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{native: true, replies: []Message{textReply(`{"name":"Paris","population":2100000}`)}}
+
+		got, resp, err := CompletionInto[city](context.Background(), p, CompletionParams{Model: "m"})
+		require.NoError(t, err)
+		require.Equal(t, city{Name: "Paris", Population: 2100000}, got)
+		require.NotNil(t, resp)
+
+		require.Len(t, p.calls, 1)
+		format := p.calls[0].ResponseFormat
+		require.Equal(t, responseFormatJSONSchema, format.Type)
+		require.Equal(t, defaultSchemaName, format.JSONSchema.Name)
+		require.Equal(t, []string{"name", "population"}, format.JSONSchema.Schema["required"])
+		require.Empty(t, p.calls[0].Tools)
+	})
+
+	t.Run("strips fenced code blocks", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{native: true, replies: []Message{
+			textReply("Here you go:\n```json\n{\"name\":\"Rome\",\"population\":1}\n```"),
+		}}
+
+		got, _, err := CompletionInto[city](context.Background(), p, CompletionParams{})
+		require.NoError(t, err)
+		require.Equal(t, "Rome", got.Name)
+	})
+
+	t.Run("re-prompts with the validation error", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{native: true, replies: []Message{
+			textReply(`{"name":"Oslo","population":-5}`),
+			textReply(`{"name":"Oslo","population":700000}`),
+		}}
+		messages := []Message{{Role: RoleUser, Content: "Describe Oslo."}}
+
+		got, _, err := CompletionInto[city](context.Background(), p, CompletionParams{Messages: messages})
+		require.NoError(t, err)
+		require.Equal(t, 700000, got.Population)
+		require.Len(t, messages, 1, "caller's messages must not be modified")
+
+		require.Len(t, p.calls, 2)
+		retry := p.calls[1].Messages
+		require.Len(t, retry, 3)
+		require.Equal(t, RoleAssistant, retry[1].Role)
+		require.Equal(t, RoleUser, retry[2].Role)
+		require.Contains(t, retry[2].ContentString(), "/population")
+	})
+
+	t.Run("gives up after the repair attempts", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{native: true, replies: []Message{textReply(`not json`), textReply(`still not json`)}}
+
+		_, resp, err := CompletionInto[city](context.Background(), p, CompletionParams{}, WithRepairAttempts(1))
+		require.ErrorContains(t, err, "after 2 attempts")
+
+		var validationErr *schema.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, "still not json", resp.Choices[0].Message.Content)
+		require.Len(t, p.calls, 2)
+	})
+
+	t.Run("forces a tool call without native support", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{replies: []Message{
+			toolReply("toolu_1", "city_info", `{"name":"Lima","population":-1}`),
+			toolReply("toolu_2", "city_info", `{"name":"Lima","population":10000000}`),
+		}}
+
+		params := CompletionParams{Tools: []Tool{{Type: "function"}}, ReasoningEffort: ReasoningEffortHigh}
+		got, _, err := CompletionInto[city](context.Background(), p, params, WithSchemaName("city_info"))
+		require.NoError(t, err)
+		require.Equal(t, city{Name: "Lima", Population: 10000000}, got)
+
+		first := p.calls[0]
+		require.Nil(t, first.ResponseFormat)
+		require.Empty(t, first.ReasoningEffort)
+		require.Len(t, first.Tools, 2)
+		require.Equal(t, "city_info", first.Tools[1].Function.Name)
+		require.Equal(t, ToolChoice{Type: "function", Function: &ToolChoiceFunction{Name: "city_info"}}, first.ToolChoice)
+
+		retry := p.calls[1].Messages
+		require.Len(t, retry, 2)
+		require.Equal(t, RoleTool, retry[1].Role)
+		require.Equal(t, "toolu_1", retry[1].ToolCallID)
+		require.Contains(t, retry[1].ContentString(), "minimum")
+	})
+
+	t.Run("returns provider errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{err: stderrors.New("boom")}
+
+		_, _, err := CompletionInto[city](context.Background(), p, CompletionParams{})
+		require.EqualError(t, err, "boom")
+	})
+
+	t.Run("rejects invalid options and types", func(t *testing.T) {
+		t.Parallel()
+
+		p := &scriptedProvider{}
+
+		_, _, err := CompletionInto[city](context.Background(), p, CompletionParams{}, WithRepairAttempts(-1))
+		require.Error(t, err)
+
+		_, _, err = CompletionInto[city](context.Background(), p, CompletionParams{}, WithSchemaName(""))
+		require.Error(t, err)
+
+		_, _, err = CompletionInto[chan int](context.Background(), p, CompletionParams{})
+		require.Error(t, err)
+		require.Empty(t, p.calls)
+	})
+}
+
+func TestStripCodeFence(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"plain JSON":        {input: ` {"a":1} `, want: `{"a":1}`},
+		"json fence":        {input: "```json\n{\"a\":1}\n```", want: `{"a":1}`},
+		"bare fence":        {input: "```\n[1]\n```", want: `[1]`},
+		"surrounding prose": {input: "Sure!\n```json\n{}\n```\nAnything else?", want: `{}`},
+		"single line fence": {input: "```{\"a\":1}```", want: `{"a":1}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, stripCodeFence(tc.input))
+		})
+	}
+}
+
+// scriptedProvider replies to completion requests with replies in order and records the requests.
+type scriptedProvider struct {
+	calls   []CompletionParams
+	err     error
+	native  bool
+	replies []Message
+}
+
+func (p *scriptedProvider) Name() string { return "scripted" }
+
+func (p *scriptedProvider) Capabilities() Capabilities {
+	return Capabilities{Completion: true, CompletionStructuredOutput: p.native}
+}
+
+func (p *scriptedProvider) Completion(_ context.Context, params CompletionParams) (*ChatCompletion, error) {
+	p.calls = append(p.calls, params)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	return &ChatCompletion{Choices: []Choice{{Message: reply, FinishReason: FinishReasonStop}}}, nil
+}
+
+func (p *scriptedProvider) CompletionStream(
+	context.Context,
+	CompletionParams,
+) (<-chan ChatCompletionChunk, <-chan error) {
+	return nil, nil
+}
+
+// textReply returns an assistant message with text content.
+func textReply(content string) Message {
+	return Message{Role: RoleAssistant, Content: content}
+}
+
+// toolReply returns an assistant message with a single tool call.
+func toolReply(id, name, arguments string) Message {
+	return Message{
+		Role:      RoleAssistant,
+		ToolCalls: []ToolCall{{ID: id, Type: "function", Function: FunctionCall{Name: name, Arguments: arguments}}},
+	}
+}
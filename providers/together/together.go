@@ -0,0 +1,118 @@
+// Package together provides a Together AI provider implementation for any-llm.
+//
+// Together AI exposes an OpenAI-compatible API, so the provider is built on openai.CompatibleProvider.
+// Its models endpoint returns a bare JSON array rather than a list object, so ListModels is its own.
+package together
+
+import (
+	"context"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.together.xyz/v1"
+	envAPIKey      = "TOGETHER_API_KEY"
+	providerName   = "together"
+)
+
+// Together AI API constants.
+const (
+	objectList     = "list"
+	objectModel    = "model"
+	pathModels     = "models"
+	reasoningField = "reasoning"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for Together AI.
+// It embeds openai.CompatibleProvider since Together AI exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// model is a model in Together AI's models response.
+type model struct {
+	Created      int64  `json:"created"`
+	ID           string `json:"id"`
+	Organization string `json:"organization"`
+}
+
+// New creates a new Together AI provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   togetherCapabilities(),
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+		UseMaxTokens:   true, // Together AI has no max_completion_tokens.
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// ListModels lists the models available from Together AI.
+func (p *Provider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
+	var resp []model
+	if err := p.Client().Get(ctx, pathModels, nil, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	models := make([]providers.Model, 0, len(resp))
+	for _, m := range resp {
+		models = append(models, providers.Model{
+			ID:      m.ID,
+			Object:  objectModel,
+			Created: m.Created,
+			OwnedBy: m.Organization,
+		})
+	}
+
+	return &providers.ModelsResponse{
+		Object: objectList,
+		Data:   models,
+	}, nil
+}
+
+// togetherCapabilities returns the capabilities for the Together AI provider.
+func togetherCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Vision models such as Llama 4.
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // DeepSeek R1 and Qwen 3 models.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  true,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
@@ -0,0 +1,165 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "together", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("TOGETHER_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("TOGETHER_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "together", missingKeyErr.Provider)
+		require.Equal(t, "TOGETHER_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/chat/completions", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"created": 1700000000,
+			"model": "Qwen/Qwen3-235B-A22B-fp8-tput",
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "4", "reasoning": "2 plus 2 is 4."},
+				"finish_reason": "stop"
+			}],
+			"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+		}`))
+	}, config.WithAPIKey(testAPIKey))
+
+	maxTokens := 100
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:     "Qwen/Qwen3-235B-A22B-fp8-tput",
+		Messages:  testutil.SimpleMessages(),
+		MaxTokens: &maxTokens,
+		Extra:     map[string]any{"repetition_penalty": 1.1},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, float64(100), body["max_tokens"])
+	require.NotContains(t, body, "max_completion_tokens")
+	require.Equal(t, 1.1, body["repetition_penalty"])
+
+	require.Equal(t, "4", resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Choices[0].Message.Reasoning)
+	require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+}
+
+func TestListModels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads the bare model array", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/models", r.URL.Path)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+				{"id": "meta-llama/Llama-3.3-70B-Instruct-Turbo", "object": "model", "created": 1733443200,
+					"type": "chat", "display_name": "Llama 3.3 70B", "organization": "Meta"},
+				{"id": "BAAI/bge-large-en-v1.5", "object": "model", "created": 0, "type": "embedding",
+					"organization": "BAAI"}
+			]`))
+		}, config.WithAPIKey(testAPIKey))
+
+		resp, err := provider.ListModels(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, "list", resp.Object)
+		require.Equal(t, []providers.Model{
+			{ID: "meta-llama/Llama-3.3-70B-Instruct-Turbo", Object: "model", Created: 1733443200, OwnedBy: "Meta"},
+			{ID: "BAAI/bge-large-en-v1.5", Object: "model", OwnedBy: "BAAI"},
+		}, resp.Data)
+	})
+
+	t.Run("converts API errors", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": {"message": "Invalid API key", "type": "invalid_request_error"}}`))
+		}, config.WithAPIKey(testAPIKey))
+
+		_, err := provider.ListModels(context.Background())
+		require.ErrorIs(t, err, errors.ErrAuthentication)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("together") {
+		t.Skip("TOGETHER_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("together"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestIntegrationListModels(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("together") {
+		t.Skip("TOGETHER_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Data)
+}
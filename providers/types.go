@@ -4,6 +4,20 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"io"
+)
+
+// Cache control types and lifetimes. The default lifetime is CacheTTL5m.
+const (
+	CacheControlTypeEphemeral = "ephemeral"
+	CacheTTL1h                = "1h"
+	CacheTTL5m                = "5m"
+)
+
+// Citation source types.
+const (
+	CitationSourceDocument = "document"
+	CitationSourceTool     = "tool"
 )
 
 // Finish reasons.
@@ -14,6 +28,18 @@ const (
 	FinishReasonToolCalls     = "tool_calls"
 )
 
+// Image response formats.
+const (
+	ImageResponseFormatB64JSON = "b64_json"
+	ImageResponseFormatURL     = "url"
+)
+
+// Output modalities.
+const (
+	ModalityAudio = "audio"
+	ModalityText  = "text"
+)
+
 // Reasoning effort levels for extended thinking.
 const (
 	ReasoningEffortAuto   ReasoningEffort = "auto"
@@ -31,6 +57,12 @@ const (
 	RoleUser      = "user"
 )
 
+// Timestamp granularities of a transcription.
+const (
+	TimestampGranularitySegment = "segment"
+	TimestampGranularityWord    = "word"
+)
+
 // CapabilityProvider is an optional interface for providers to report capabilities.
 type CapabilityProvider interface {
 	Provider
@@ -51,12 +83,27 @@ type ErrorConverter interface {
 	ConvertError(err error) error
 }
 
+// ImageGenerationProvider is an optional interface for providers that generate images from a prompt,
+// edit images, and create variations of images.
+type ImageGenerationProvider interface {
+	Provider
+	ImageEdit(ctx context.Context, params ImageEditParams) (*ImageResponse, error)
+	ImageGeneration(ctx context.Context, params ImageGenerationParams) (*ImageResponse, error)
+	ImageVariation(ctx context.Context, params ImageVariationParams) (*ImageResponse, error)
+}
+
 // ModelLister is an optional interface for providers that support listing models.
 type ModelLister interface {
 	Provider
 	ListModels(ctx context.Context) (*ModelsResponse, error)
 }
 
+// ModerationProvider is an optional interface for providers that classify text as harmful.
+type ModerationProvider interface {
+	Provider
+	Moderation(ctx context.Context, params ModerationParams) (*ModerationResponse, error)
+}
+
 // Provider is the core interface that all LLM providers must implement.
 type Provider interface {
 	// Name returns the provider's identifier (e.g., "openai", "anthropic").
@@ -69,21 +116,82 @@ type Provider interface {
 	CompletionStream(ctx context.Context, params CompletionParams) (<-chan ChatCompletionChunk, <-chan error)
 }
 
+// RerankProvider is an optional interface for providers that rerank documents by relevance to a query.
+type RerankProvider interface {
+	Provider
+	Rerank(ctx context.Context, params RerankParams) (*RerankResponse, error)
+}
+
+// SpeechProvider is an optional interface for providers that synthesize speech from text.
+// The caller must close the returned audio.
+type SpeechProvider interface {
+	Provider
+	Speech(ctx context.Context, params SpeechParams) (io.ReadCloser, error)
+}
+
+// TokenCounter is an optional interface for providers that count the input tokens of a request
+// without running it. Ollama, which has no tokenizer endpoint, is the exception and generates one token.
+type TokenCounter interface {
+	Provider
+	CountTokens(ctx context.Context, params CompletionParams) (*TokenCount, error)
+}
+
+// TranscriptionProvider is an optional interface for providers that transcribe speech to text.
+type TranscriptionProvider interface {
+	Provider
+	Transcription(ctx context.Context, params TranscriptionParams) (*Transcription, error)
+}
+
 // ReasoningEffort levels for extended thinking.
 type ReasoningEffort string
 
 // Capabilities describes what features a provider supports.
 type Capabilities struct {
-	Completion          bool
-	CompletionImage     bool
-	CompletionPDF       bool
-	CompletionReasoning bool
-	CompletionStreaming bool
-	Embedding           bool
-	ListModels          bool
+	Completion                 bool
+	CompletionAudio            bool
+	CompletionImage            bool
+	CompletionPDF              bool
+	CompletionReasoning        bool
+	CompletionStreaming        bool
+	CompletionStructuredOutput bool
+	CountTokens                bool
+	Embedding                  bool
+	ImageGeneration            bool
+	ListModels                 bool
+	Moderation                 bool
+	Rerank                     bool
+	Speech                     bool
+	Transcription              bool
+}
+
+// Audio is audio generated by the model, returned on assistant messages when ModalityAudio is requested.
+// In streams, each delta carries the next piece of Data and Transcript. Sending the message back in a
+// later turn refers to the audio by ID until ExpiresAt, a Unix timestamp.
+type Audio struct {
+	ID         string `json:"id,omitempty"`
+	Data       string `json:"data,omitempty"` // Base64-encoded.
+	Transcript string `json:"transcript,omitempty"`
+	Format     string `json:"format,omitempty"`
+	ExpiresAt  int64  `json:"expires_at,omitempty"`
+}
+
+// AudioParams configures the audio output of a completion, such as Voice "alloy" and Format "wav".
+type AudioParams struct {
+	Voice  string `json:"voice"`
+	Format string `json:"format"`
+}
+
+// CacheControl marks the end of a cacheable prompt prefix: providers with prompt caching cache the
+// request up to and including the marked message, content part, or tool. Providers that cache
+// prompts automatically, or not at all, ignore it. Type defaults to CacheControlTypeEphemeral.
+type CacheControl struct {
+	Type string `json:"type,omitempty"`
+	TTL  string `json:"ttl,omitempty"`
 }
 
 // ChatCompletion represents a chat completion response in OpenAI format.
+// Provider is not part of the OpenAI format; it names the provider that served the
+// request and is set by wrappers such as fallback chains.
 type ChatCompletion struct {
 	ID                string   `json:"id"`
 	Object            string   `json:"object"`
@@ -92,6 +200,7 @@ type ChatCompletion struct {
 	Choices           []Choice `json:"choices"`
 	Usage             *Usage   `json:"usage,omitempty"`
 	SystemFingerprint string   `json:"system_fingerprint,omitempty"`
+	Provider          string   `json:"provider,omitempty"`
 }
 
 // ChatCompletionChunk represents a streaming chunk in OpenAI format.
@@ -103,13 +212,15 @@ type ChatCompletionChunk struct {
 	Choices           []ChunkChoice `json:"choices"`
 	Usage             *Usage        `json:"usage,omitempty"`
 	SystemFingerprint string        `json:"system_fingerprint,omitempty"`
+	Provider          string        `json:"provider,omitempty"`
 }
 
 // Choice represents a completion choice.
 type Choice struct {
-	Index        int     `json:"index"`
-	Message      Message `json:"message"`
-	FinishReason string  `json:"finish_reason,omitempty"`
+	Index        int       `json:"index"`
+	Message      Message   `json:"message"`
+	FinishReason string    `json:"finish_reason,omitempty"`
+	Logprobs     *Logprobs `json:"logprobs,omitempty"`
 }
 
 // ChunkChoice represents a choice in a streaming chunk.
@@ -117,6 +228,7 @@ type ChunkChoice struct {
 	Index        int        `json:"index"`
 	Delta        ChunkDelta `json:"delta"`
 	FinishReason string     `json:"finish_reason,omitempty"`
+	Logprobs     *Logprobs  `json:"logprobs,omitempty"`
 }
 
 // ChunkDelta represents the delta content in a streaming chunk.
@@ -125,6 +237,26 @@ type ChunkDelta struct {
 	Content   string     `json:"content,omitempty"`
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 	Reasoning *Reasoning `json:"reasoning,omitempty"`
+	Audio     *Audio     `json:"audio,omitempty"`
+	Citations []Citation `json:"citations,omitempty"`
+}
+
+// Citation attributes a span of a message's content to the sources that support it.
+// Start and End are character offsets into the content, and Text is the cited span.
+type Citation struct {
+	Start   int              `json:"start"`
+	End     int              `json:"end"`
+	Text    string           `json:"text"`
+	Sources []CitationSource `json:"sources,omitempty"`
+}
+
+// CitationSource is a document or tool result cited by a Citation.
+// Type is CitationSourceDocument or CitationSourceTool, and ID identifies the document or tool call.
+// Data holds the cited document or tool output, when the provider returns it.
+type CitationSource struct {
+	Type string         `json:"type"`
+	ID   string         `json:"id,omitempty"`
+	Data map[string]any `json:"data,omitempty"`
 }
 
 // CompletionParams represents normalized parameters for chat completion requests.
@@ -144,14 +276,29 @@ type CompletionParams struct {
 	ReasoningEffort   ReasoningEffort `json:"reasoning_effort,omitempty"`
 	Seed              *int            `json:"seed,omitempty"`
 	User              string          `json:"user,omitempty"`
+	Logprobs          bool            `json:"logprobs,omitempty"`
+	TopLogprobs       *int            `json:"top_logprobs,omitempty"`
+	Modalities        []string        `json:"modalities,omitempty"`
+	Audio             *AudioParams    `json:"audio,omitempty"`
 	Extra             map[string]any  `json:"-"`
 }
 
 // ContentPart represents a part of a multi-modal message.
+// Type is "text", "image_url", "file", or "input_audio".
 type ContentPart struct {
-	Type     string    `json:"type"`
-	Text     string    `json:"text,omitempty"`
-	ImageURL *ImageURL `json:"image_url,omitempty"`
+	Type         string        `json:"type"`
+	Text         string        `json:"text,omitempty"`
+	ImageURL     *ImageURL     `json:"image_url,omitempty"`
+	File         *File         `json:"file,omitempty"`
+	InputAudio   *InputAudio   `json:"input_audio,omitempty"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// Document is a source supplied with a message for the model to ground its response in.
+// Citations refer to it by ID; providers assign IDs to documents without one.
+type Document struct {
+	ID   string         `json:"id,omitempty"`
+	Data map[string]any `json:"data"`
 }
 
 // EmbeddingData represents a single embedding.
@@ -184,6 +331,16 @@ type EmbeddingUsage struct {
 	TotalTokens  int `json:"total_tokens"`
 }
 
+// File is a document, such as a PDF, sent in a "file" content part. It carries one of Data,
+// URL, or FileID, the ID of a file uploaded to the provider. MIMEType defaults to "application/pdf".
+type File struct {
+	Data     string `json:"data,omitempty"` // Base64-encoded content.
+	URL      string `json:"url,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	MIMEType string `json:"mime_type,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
 // Function represents a function definition for tool calling.
 type Function struct {
 	Name        string         `json:"name"`
@@ -197,12 +354,82 @@ type FunctionCall struct {
 	Arguments string `json:"arguments"`
 }
 
+// GeneratedImage is an image in an image response. URL or B64JSON is set, depending on the requested
+// response format. RevisedPrompt is the prompt the model used, when the provider rewrote it.
+type GeneratedImage struct {
+	URL           string `json:"url,omitempty"`
+	B64JSON       string `json:"b64_json,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// ImageEditParams represents parameters for image edit requests.
+// Filename names the image, and its extension tells the provider the image format; it defaults to the
+// name of Image when Image has a Name method, as *os.File does. Mask is a PNG whose fully transparent
+// areas mark where the image should be edited.
+type ImageEditParams struct {
+	Model          string    `json:"model"`
+	Prompt         string    `json:"prompt"`
+	Image          io.Reader `json:"-"`
+	Filename       string    `json:"filename,omitempty"`
+	Mask           io.Reader `json:"-"`
+	N              *int      `json:"n,omitempty"`
+	Size           string    `json:"size,omitempty"`
+	Quality        string    `json:"quality,omitempty"`
+	ResponseFormat string    `json:"response_format,omitempty"`
+	User           string    `json:"user,omitempty"`
+}
+
+// ImageGenerationParams represents parameters for image generation requests.
+// N is the number of images to generate, and ResponseFormat is ImageResponseFormatURL or
+// ImageResponseFormatB64JSON.
+type ImageGenerationParams struct {
+	Model          string `json:"model"`
+	Prompt         string `json:"prompt"`
+	N              *int   `json:"n,omitempty"`
+	Size           string `json:"size,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+// ImageResponse represents an image generation, edit, or variation response.
+type ImageResponse struct {
+	Created int64            `json:"created"`
+	Data    []GeneratedImage `json:"data"`
+	Usage   *ImageUsage      `json:"usage,omitempty"`
+}
+
 // ImageURL represents an image URL in a message.
 type ImageURL struct {
 	URL    string `json:"url"`
 	Detail string `json:"detail,omitempty"`
 }
 
+// ImageUsage represents token usage information for models that bill images by token.
+type ImageUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// ImageVariationParams represents parameters for image variation requests.
+// Filename names the image as in ImageEditParams.
+type ImageVariationParams struct {
+	Model          string    `json:"model"`
+	Image          io.Reader `json:"-"`
+	Filename       string    `json:"filename,omitempty"`
+	N              *int      `json:"n,omitempty"`
+	Size           string    `json:"size,omitempty"`
+	ResponseFormat string    `json:"response_format,omitempty"`
+	User           string    `json:"user,omitempty"`
+}
+
+// InputAudio is audio sent in an "input_audio" content part.
+type InputAudio struct {
+	Data   string `json:"data"`   // Base64-encoded.
+	Format string `json:"format"` // "wav" or "mp3".
+}
+
 // JSONSchema for structured output.
 type JSONSchema struct {
 	Name        string         `json:"name"`
@@ -211,14 +438,24 @@ type JSONSchema struct {
 	Strict      *bool          `json:"strict,omitempty"`
 }
 
+// Logprobs holds the log probabilities of the tokens of a choice.
+// In streaming chunks it covers only the tokens of that chunk.
+type Logprobs struct {
+	Content []TokenLogprob `json:"content"`
+}
+
 // Message represents a chat message in OpenAI format.
 type Message struct {
-	Role       string     `json:"role"`
-	Content    any        `json:"content"`
-	Name       string     `json:"name,omitempty"`
-	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
-	ToolCallID string     `json:"tool_call_id,omitempty"`
-	Reasoning  *Reasoning `json:"reasoning,omitempty"`
+	Role         string        `json:"role"`
+	Content      any           `json:"content"`
+	Name         string        `json:"name,omitempty"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
+	ToolCallID   string        `json:"tool_call_id,omitempty"`
+	Reasoning    *Reasoning    `json:"reasoning,omitempty"`
+	Audio        *Audio        `json:"audio,omitempty"`
+	Citations    []Citation    `json:"citations,omitempty"`
+	Documents    []Document    `json:"documents,omitempty"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // Model represents a model from the list models API.
@@ -235,33 +472,119 @@ type ModelsResponse struct {
 	Data   []Model `json:"data"`
 }
 
+// ModerationParams represents parameters for moderation requests.
+// Each input is classified separately.
+type ModerationParams struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// ModerationResponse represents a moderation response, with one result per input in input order.
+type ModerationResponse struct {
+	ID      string             `json:"id,omitempty"`
+	Model   string             `json:"model"`
+	Results []ModerationResult `json:"results"`
+}
+
+// ModerationResult is the classification of one moderation input.
+// Categories and CategoryScores are keyed by the provider's category names, such as "hate" or
+// "violence/graphic", and scores range from 0 to 1.
+type ModerationResult struct {
+	Flagged        bool               `json:"flagged"`
+	Categories     map[string]bool    `json:"categories"`
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
+
 // Reasoning represents extended thinking/reasoning content.
 type Reasoning struct {
 	Content string `json:"content,omitempty"`
 }
 
+// RerankParams represents parameters for rerank requests.
+type RerankParams struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            *int     `json:"top_n,omitempty"`
+	MaxTokensPerDoc *int     `json:"max_tokens_per_doc,omitempty"`
+}
+
+// RerankResponse represents a rerank response. Results are ordered by decreasing relevance.
+type RerankResponse struct {
+	ID      string         `json:"id,omitempty"`
+	Model   string         `json:"model"`
+	Results []RerankResult `json:"results"`
+	Usage   *RerankUsage   `json:"usage,omitempty"`
+}
+
+// RerankResult is the relevance of one document. Index is its position in RerankParams.Documents.
+type RerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+	Document       string  `json:"document"`
+}
+
+// RerankUsage represents the billed usage of a rerank request.
+type RerankUsage struct {
+	SearchUnits int `json:"search_units,omitempty"`
+	TotalTokens int `json:"total_tokens,omitempty"`
+}
+
 // ResponseFormat specifies the format of the response.
 type ResponseFormat struct {
 	Type       string      `json:"type"`
 	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
 }
 
+// SpeechParams represents parameters for speech synthesis requests.
+// Format is the audio format, such as "mp3", "wav", or "opus", and Speed scales the speaking rate,
+// where 1.0 is normal.
+type SpeechParams struct {
+	Model        string   `json:"model"`
+	Input        string   `json:"input"`
+	Voice        string   `json:"voice"`
+	Format       string   `json:"response_format,omitempty"`
+	Speed        *float64 `json:"speed,omitempty"`
+	Instructions string   `json:"instructions,omitempty"`
+}
+
 // StreamOptions contains options for streaming responses.
 type StreamOptions struct {
 	IncludeUsage bool `json:"include_usage,omitempty"`
 }
 
+// TokenCount represents the number of input tokens of a completion request.
+type TokenCount struct {
+	Model       string `json:"model"`
+	InputTokens int    `json:"input_tokens"`
+}
+
+// TokenLogprob is the log probability of a generated token.
+// TopLogprobs holds the most likely alternatives at its position when requested.
+type TokenLogprob struct {
+	Token       string       `json:"token"`
+	Bytes       []int        `json:"bytes,omitempty"`
+	Logprob     float64      `json:"logprob"`
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"`
+}
+
 // Tool represents a tool/function that can be called.
 type Tool struct {
-	Type     string   `json:"type"`
-	Function Function `json:"function"`
+	Type         string        `json:"type"`
+	Function     Function      `json:"function"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // ToolCall represents a tool call made by the assistant.
+// Index is the position of the call within a streaming delta and is only meaningful in chunks.
+// Signature is an opaque value some providers attach to a call, such as Gemini's thought signature;
+// it must be sent back unchanged with the call in later turns.
 type ToolCall struct {
-	ID       string       `json:"id"`
-	Type     string       `json:"type"`
-	Function FunctionCall `json:"function"`
+	Index     int          `json:"index,omitempty"`
+	ID        string       `json:"id"`
+	Type      string       `json:"type"`
+	Function  FunctionCall `json:"function"`
+	Signature string       `json:"signature,omitempty"`
 }
 
 // ToolChoice represents a specific tool choice.
@@ -275,12 +598,61 @@ type ToolChoiceFunction struct {
 	Name string `json:"name"`
 }
 
+// TopLogprob is the log probability of an alternative token.
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Bytes   []int   `json:"bytes,omitempty"`
+	Logprob float64 `json:"logprob"`
+}
+
+// Transcription represents a transcription response.
+// Language, Duration, Segments, and Words are set when the provider reports them.
+type Transcription struct {
+	Text     string                 `json:"text"`
+	Language string                 `json:"language,omitempty"`
+	Duration float64                `json:"duration,omitempty"` // Seconds.
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+}
+
+// TranscriptionParams represents parameters for transcription requests.
+// Filename names the audio, and its extension tells the provider the audio format; it defaults to
+// the name of Audio when Audio has a Name method, as *os.File does.
+type TranscriptionParams struct {
+	Model                  string    `json:"model"`
+	Audio                  io.Reader `json:"-"`
+	Filename               string    `json:"filename,omitempty"`
+	Language               string    `json:"language,omitempty"` // ISO-639-1 code of the spoken language.
+	Prompt                 string    `json:"prompt,omitempty"`
+	Temperature            *float64  `json:"temperature,omitempty"`
+	TimestampGranularities []string  `json:"timestamp_granularities,omitempty"`
+}
+
+// TranscriptionSegment is a segment of a transcription. Start and End are offsets in seconds.
+type TranscriptionSegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// TranscriptionWord is a word of a transcription. Start and End are offsets in seconds.
+type TranscriptionWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
 // Usage represents token usage information.
+// CacheReadTokens and CacheWriteTokens are the prompt tokens read from and written to the
+// provider's prompt cache; they are included in PromptTokens.
 type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
 	ReasoningTokens  int `json:"reasoning_tokens,omitempty"`
+	CacheReadTokens  int `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
 }
 
 // ContentParts extracts content parts from a message.
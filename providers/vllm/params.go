@@ -0,0 +1,52 @@
+package vllm
+
+// Param sets a vLLM request parameter in a CompletionParams.Extra map.
+// Use Extra to build the map, or apply a Param to an existing, non-nil map.
+type Param func(extra map[string]any)
+
+// Extra returns a CompletionParams.Extra map holding the given parameters.
+//
+//	resp, err := provider.Completion(ctx, providers.CompletionParams{
+//		Model:    "Qwen/Qwen3-8B",
+//		Messages: messages,
+//		Extra:    vllm.Extra(vllm.GuidedChoice("positive", "negative")),
+//	})
+func Extra(params ...Param) map[string]any {
+	extra := make(map[string]any, len(params))
+	for _, param := range params {
+		if param != nil {
+			param(extra)
+		}
+	}
+
+	return extra
+}
+
+// GuidedChoice constrains the output to exactly one of choices.
+func GuidedChoice(choices ...string) Param {
+	return func(extra map[string]any) {
+		extra[extraGuidedChoice] = choices
+	}
+}
+
+// GuidedGrammar constrains the output to a context-free grammar in EBNF.
+func GuidedGrammar(grammar string) Param {
+	return func(extra map[string]any) {
+		extra[extraGuidedGrammar] = grammar
+	}
+}
+
+// GuidedJSON constrains the output to JSON matching schema. Unlike a json_schema
+// ResponseFormat, it needs no schema name and is not checked for strict mode.
+func GuidedJSON(schema map[string]any) Param {
+	return func(extra map[string]any) {
+		extra[extraGuidedJSON] = schema
+	}
+}
+
+// GuidedRegex constrains the output to text matching pattern.
+func GuidedRegex(pattern string) Param {
+	return func(extra map[string]any) {
+		extra[extraGuidedRegex] = pattern
+	}
+}
@@ -0,0 +1,284 @@
+// Package vllm provides a vLLM provider implementation for any-llm.
+//
+// vLLM serves models through an OpenAI-compatible API, so the provider is built on
+// openai.CompatibleProvider. It validates vLLM's guided decoding parameters and adds
+// the server's health and tokenizer endpoints.
+package vllm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	openaisdk "github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/extra"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultAPIKey  = "vllm" // Dummy key; vLLM only checks keys when started with --api-key.
+	defaultBaseURL = "http://localhost:8000/v1"
+	envAPIKey      = "VLLM_API_KEY"
+	envBaseURL     = "VLLM_BASE_URL"
+	providerName   = "vllm"
+)
+
+// vLLM API constants.
+const (
+	pathAPI        = "/v1"
+	pathHealth     = "/health"
+	pathTokenize   = "/tokenize"
+	reasoningField = "reasoning_content"
+)
+
+// Guided decoding parameters, validated before they are sent; see the Param helpers.
+// Other keys in CompletionParams.Extra are sent as-is. vLLM reads its extra sampling parameters,
+// such as top_k, min_p, and repetition_penalty, from the body and ignores unknown fields with a warning.
+const (
+	extraGuidedChoice  = "guided_choice"
+	extraGuidedGrammar = "guided_grammar"
+	extraGuidedJSON    = "guided_json"
+	extraGuidedRegex   = "guided_regex"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider    = (*Provider)(nil)
+	_ providers.EmbeddingProvider     = (*Provider)(nil)
+	_ providers.ErrorConverter        = (*Provider)(nil)
+	_ providers.ModelLister           = (*Provider)(nil)
+	_ providers.Provider              = (*Provider)(nil)
+	_ providers.TokenCounter          = (*Provider)(nil)
+	_ providers.TranscriptionProvider = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for vLLM.
+// It embeds openai.CompatibleProvider since vLLM exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// chatMessages holds the messages and tools of an encoded chat completion request.
+type chatMessages struct {
+	Messages json.RawMessage `json:"messages"`
+	Tools    json.RawMessage `json:"tools,omitempty"`
+}
+
+// tokenizeChatRequest is the request body of the tokenize endpoint for chat messages,
+// which applies the model's chat template before tokenizing.
+type tokenizeChatRequest struct {
+	AddGenerationPrompt bool            `json:"add_generation_prompt"`
+	Messages            json.RawMessage `json:"messages"`
+	Model               string          `json:"model"`
+	Tools               json.RawMessage `json:"tools,omitempty"`
+}
+
+// tokenizeRequest is the request body of the tokenize endpoint.
+type tokenizeRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// tokenizeResponse is the response body of the tokenize endpoint.
+type tokenizeResponse struct {
+	Count       int   `json:"count"`
+	MaxModelLen int   `json:"max_model_len"`
+	Tokens      []int `json:"tokens"`
+}
+
+// New creates a vLLM provider for the server at VLLM_BASE_URL, or localhost:8000 by default.
+// An API key is only needed for servers started with --api-key.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		BaseURLEnvVar:  envBaseURL,
+		Capabilities:   vllmCapabilities(),
+		DefaultAPIKey:  defaultAPIKey,
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  false,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// CountTokens returns the number of tokens of the prompt the model would be given for params,
+// after vLLM applies the model's chat template to the messages and tools.
+func (p *Provider) CountTokens(ctx context.Context, params providers.CompletionParams) (*providers.TokenCount, error) {
+	req, err := p.ChatCompletionRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	chat, err := encodeChatMessages(req)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError(providerName, err)
+	}
+
+	body := tokenizeChatRequest{
+		AddGenerationPrompt: true,
+		Messages:            chat.Messages,
+		Model:               params.Model,
+		Tools:               chat.Tools,
+	}
+
+	var resp tokenizeResponse
+	if err := p.Client().Post(ctx, p.serverURL()+pathTokenize, body, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return &providers.TokenCount{Model: params.Model, InputTokens: resp.Count}, nil
+}
+
+// Health returns nil if the server's engine is healthy. It makes a single attempt, so that a
+// readiness probe fails fast while the engine is down.
+func (p *Provider) Health(ctx context.Context) error {
+	var body []byte
+	if err := p.Client().Get(ctx, p.serverURL()+pathHealth, nil, &body, option.WithMaxRetries(0)); err != nil {
+		return p.ConvertError(err)
+	}
+
+	return nil
+}
+
+// Tokenize returns the IDs of the tokens of text in the tokenizer of model.
+func (p *Provider) Tokenize(ctx context.Context, model string, text string) ([]int, error) {
+	if model == "" {
+		return nil, errors.NewInvalidRequestError(providerName, fmt.Errorf("model is required"))
+	}
+
+	req := tokenizeRequest{Model: model, Prompt: text}
+
+	var resp tokenizeResponse
+	if err := p.Client().Post(ctx, p.serverURL()+pathTokenize, req, &resp); err != nil {
+		return nil, p.ConvertError(err)
+	}
+
+	return resp.Tokens, nil
+}
+
+// serverURL returns the base URL without its /v1 suffix, since vLLM serves /health and /tokenize at the root.
+func (p *Provider) serverURL() string {
+	return strings.TrimSuffix(strings.TrimSuffix(p.BaseURL(), "/"), pathAPI)
+}
+
+// decodeGuidedJSON returns the schema of a guided_json parameter, given as a schema or its JSON text.
+func decodeGuidedJSON(value any) (map[string]any, error) {
+	var schema map[string]any
+
+	if text, ok := value.(string); ok {
+		if err := json.Unmarshal([]byte(text), &schema); err != nil {
+			return nil, fmt.Errorf("extra parameter %q: %w", extraGuidedJSON, err)
+		}
+		return schema, nil
+	}
+
+	if err := extra.Decode(extraGuidedJSON, value, &schema); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// encodeChatMessages returns the messages and tools of req in the OpenAI wire format.
+func encodeChatMessages(req openaisdk.ChatCompletionNewParams) (chatMessages, error) {
+	var chat chatMessages
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return chat, fmt.Errorf("encoding messages: %w", err)
+	}
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return chat, fmt.Errorf("encoding messages: %w", err)
+	}
+
+	return chat, nil
+}
+
+// prepareRequest validates vLLM's guided decoding parameters, of which at most one may be set.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	fields := maps.Clone(req.ExtraFields())
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	var guided []string
+	for _, key := range extra.Keys(params.Extra) {
+		value := params.Extra[key]
+
+		var err error
+		switch key {
+		case extraGuidedChoice:
+			var v []string
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		case extraGuidedGrammar, extraGuidedRegex:
+			var v string
+			err = extra.Decode(key, value, &v)
+			fields[key] = v
+		case extraGuidedJSON:
+			var v map[string]any
+			v, err = decodeGuidedJSON(value)
+			fields[key] = v
+		default:
+			continue
+		}
+		if err != nil {
+			return errors.NewInvalidRequestError(providerName, err)
+		}
+		guided = append(guided, key)
+	}
+
+	if len(guided) > 1 {
+		return errors.NewInvalidRequestError(
+			providerName,
+			fmt.Errorf("only one guided decoding parameter may be set, got %s", strings.Join(guided, ", ")),
+		)
+	}
+
+	if len(fields) > 0 {
+		req.SetExtraFields(fields)
+	}
+
+	return nil
+}
+
+// vllmCapabilities returns the capabilities for the vLLM provider.
+func vllmCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true, // Depends on the model served.
+		CompletionPDF:              false,
+		CompletionReasoning:        true, // Needs a server started with --reasoning-parser.
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                true,
+		Embedding:                  true, // Needs an embedding model.
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              true, // Needs a speech-to-text model.
+	}
+}
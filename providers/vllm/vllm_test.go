@@ -0,0 +1,397 @@
+package vllm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testVLLMAvailabilityTimeout = 5 * time.Second
+
+const chatResponse = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "Qwen/Qwen3-8B",
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."},
+		"finish_reason": "stop"
+	}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+func TestNew(t *testing.T) {
+	// Note: Not using t.Parallel() here because child test uses t.Setenv.
+
+	t.Run("creates provider with default settings", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "vllm", provider.Name())
+		require.Equal(t, "http://localhost:8000/v1", provider.BaseURL())
+	})
+
+	t.Run("creates provider from environment variables", func(t *testing.T) {
+		t.Setenv("VLLM_BASE_URL", "http://gpu-host:8000/v1")
+		t.Setenv("VLLM_API_KEY", "env-api-key")
+
+		var auth string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.Equal(t, "http://gpu-host:8000/v1", provider.BaseURL())
+
+		provider, err = New(config.WithBaseURL(server.URL))
+		require.NoError(t, err)
+		require.NoError(t, provider.Health(context.Background()))
+		require.Equal(t, "Bearer env-api-key", auth)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	caps := provider.Capabilities()
+	require.True(t, caps.Completion)
+	require.True(t, caps.CompletionStreaming)
+	require.True(t, caps.CompletionReasoning)
+	require.True(t, caps.CompletionImage)
+	require.False(t, caps.CompletionPDF)
+	require.False(t, caps.CompletionAudio)
+	require.True(t, caps.CompletionStructuredOutput)
+	require.True(t, caps.CountTokens)
+	require.True(t, caps.Embedding)
+	require.True(t, caps.ListModels)
+	require.False(t, caps.Speech)
+	require.True(t, caps.Transcription)
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		extra     map[string]any
+		wantField string
+		wantValue any
+	}{
+		"guided_json schema": {
+			extra:     map[string]any{"guided_json": map[string]any{"type": "object"}},
+			wantField: "guided_json",
+			wantValue: map[string]any{"type": "object"},
+		},
+		"guided_json text": {
+			extra:     map[string]any{"guided_json": `{"type": "object"}`},
+			wantField: "guided_json",
+			wantValue: map[string]any{"type": "object"},
+		},
+		"guided_regex": {
+			extra:     map[string]any{"guided_regex": `\d+`},
+			wantField: "guided_regex",
+			wantValue: `\d+`,
+		},
+		"guided_choice": {
+			extra:     map[string]any{"guided_choice": []string{"yes", "no"}},
+			wantField: "guided_choice",
+			wantValue: []any{"yes", "no"},
+		},
+		"guided_grammar": {
+			extra:     map[string]any{"guided_grammar": `root ::= "yes" | "no"`},
+			wantField: "guided_grammar",
+			wantValue: `root ::= "yes" | "no"`,
+		},
+		"other parameters": {
+			extra:     map[string]any{"top_k": 40},
+			wantField: "top_k",
+			wantValue: float64(40),
+		},
+		"GuidedChoice": {
+			extra:     Extra(GuidedChoice("yes", "no")),
+			wantField: "guided_choice",
+			wantValue: []any{"yes", "no"},
+		},
+		"GuidedGrammar": {
+			extra:     Extra(GuidedGrammar(`root ::= "yes" | "no"`)),
+			wantField: "guided_grammar",
+			wantValue: `root ::= "yes" | "no"`,
+		},
+		"GuidedJSON": {
+			extra:     Extra(GuidedJSON(map[string]any{"type": "object"})),
+			wantField: "guided_json",
+			wantValue: map[string]any{"type": "object"},
+		},
+		"GuidedRegex": {
+			extra:     Extra(GuidedRegex(`\d+`)),
+			wantField: "guided_regex",
+			wantValue: `\d+`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run("sends "+name, func(t *testing.T) {
+			t.Parallel()
+
+			var body map[string]any
+			provider := testutil.NewTestProvider(t, New, testutil.RecordRequest(t, &body, chatResponse))
+
+			resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    "Qwen/Qwen3-8B",
+				Messages: testutil.SimpleMessages(),
+				Extra:    tc.extra,
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.wantValue, body[tc.wantField])
+			require.Equal(t, "4", resp.Choices[0].Message.Content)
+			require.NotNil(t, resp.Choices[0].Message.Reasoning)
+			require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+		})
+	}
+
+	invalid := map[string]map[string]any{
+		"guided_json that is not an object": {"guided_json": "[1, 2]"},
+		"guided_choice that is not a list":  {"guided_choice": "yes"},
+		"guided_regex that is not a string": {"guided_regex": 1},
+		"more than one guided parameter":    {"guided_regex": `\d+`, "guided_choice": []string{"1"}},
+		"more than one guided helper":       Extra(GuidedRegex(`\d+`), GuidedChoice("1")),
+	}
+
+	for name, extra := range invalid {
+		t.Run("rejects "+name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New()
+			require.NoError(t, err)
+
+			_, err = provider.Completion(context.Background(), providers.CompletionParams{
+				Model:    "Qwen/Qwen3-8B",
+				Messages: testutil.SimpleMessages(),
+				Extra:    extra,
+			})
+			require.ErrorIs(t, err, errors.ErrInvalidRequest)
+		})
+	}
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, data := range []string{
+			`{"id":"1","model":"m","choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"Hmm."}}]}`,
+			`{"id":"1","model":"m","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`,
+		} {
+			_, _ = w.Write([]byte("data: " + data + "\n\n"))
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	})
+
+	chunks, errs := provider.CompletionStream(context.Background(), providers.CompletionParams{
+		Model:    "Qwen/Qwen3-8B",
+		Messages: testutil.SimpleMessages(),
+		Stream:   true,
+	})
+
+	var content, reasoning strings.Builder
+	for chunk := range chunks {
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		content.WriteString(chunk.Choices[0].Delta.Content)
+		if chunk.Choices[0].Delta.Reasoning != nil {
+			reasoning.WriteString(chunk.Choices[0].Delta.Reasoning.Content)
+		}
+	}
+	require.NoError(t, <-errs)
+
+	require.Equal(t, "Hi", content.String())
+	require.Equal(t, "Hmm.", reasoning.String())
+}
+
+func TestCountTokens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tokenizes the chat messages and tools", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			testutil.RecordRequest(t, &body, `{"count": 42, "max_model_len": 32768, "tokens": []}`)(w, r)
+		})
+
+		resp, err := provider.CountTokens(context.Background(), providers.CompletionParams{
+			Model:    "Qwen/Qwen3-8B",
+			Messages: testutil.SimpleMessages(),
+			Tools:    []providers.Tool{testutil.WeatherTool()},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "/tokenize", path)
+		require.Equal(t, "Qwen/Qwen3-8B", body["model"])
+		require.Equal(t, true, body["add_generation_prompt"])
+		require.Len(t, body["messages"], 1)
+		require.Len(t, body["tools"], 1)
+		require.NotContains(t, body, "prompt")
+		require.Equal(t, &providers.TokenCount{Model: "Qwen/Qwen3-8B", InputTokens: 42}, resp)
+	})
+
+	t.Run("rejects invalid parameters before sending", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New()
+		require.NoError(t, err)
+
+		_, err = provider.CountTokens(context.Background(), providers.CompletionParams{Model: "Qwen/Qwen3-8B"})
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports a ready server", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+		})
+
+		require.NoError(t, provider.Health(context.Background()))
+		require.Equal(t, "/health", path)
+	})
+
+	t.Run("reports an unavailable server", func(t *testing.T) {
+		t.Parallel()
+
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		require.Error(t, provider.Health(context.Background()))
+	})
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tokenizes text", func(t *testing.T) {
+		t.Parallel()
+
+		var path string
+		var body map[string]any
+		provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			testutil.RecordRequest(t, &body, `{"count": 3, "max_model_len": 32768, "tokens": [9707, 11, 1879]}`)(w, r)
+		})
+
+		tokens, err := provider.Tokenize(context.Background(), "Qwen/Qwen3-8B", "Hello, world")
+		require.NoError(t, err)
+
+		require.Equal(t, "/tokenize", path)
+		require.Equal(t, map[string]any{"model": "Qwen/Qwen3-8B", "prompt": "Hello, world"}, body)
+		require.Equal(t, []int{9707, 11, 1879}, tokens)
+	})
+
+	t.Run("requires a model", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New()
+		require.NoError(t, err)
+
+		_, err = provider.Tokenize(context.Background(), "", "Hello")
+		require.ErrorIs(t, err, errors.ErrInvalidRequest)
+	})
+}
+
+func TestServerURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"strips the API prefix":           "http://gpu-host:8000/v1",
+		"strips the API prefix and slash": "http://gpu-host:8000/v1/",
+		"keeps a root URL":                "http://gpu-host:8000",
+	}
+
+	for name, baseURL := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(config.WithBaseURL(baseURL))
+			require.NoError(t, err)
+			require.Equal(t, "http://gpu-host:8000", provider.serverURL())
+		})
+	}
+}
+
+// Integration tests - only run if a vLLM server is available.
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	provider, model := vllmProvider(t)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    model,
+		Messages: testutil.SimpleMessages(),
+		Extra:    map[string]any{"guided_choice": []string{"yes", "no"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, []string{"yes", "no"}, resp.Choices[0].Message.Content)
+}
+
+func TestIntegrationTokenize(t *testing.T) {
+	t.Parallel()
+
+	provider, model := vllmProvider(t)
+
+	tokens, err := provider.Tokenize(context.Background(), model, "Hello, world")
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens)
+}
+
+// vllmProvider returns a provider for the local vLLM server and the model it serves,
+// skipping the test if no server is available.
+func vllmProvider(t *testing.T) (*Provider, string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testVLLMAvailabilityTimeout)
+	defer cancel()
+
+	provider, err := New()
+	if err != nil {
+		t.Skip("vLLM not available")
+	}
+
+	if err := provider.Health(ctx); err != nil {
+		t.Skip("vLLM not available")
+	}
+
+	models, err := provider.ListModels(ctx)
+	if err != nil || len(models.Data) == 0 {
+		t.Skip("vLLM not serving a model")
+	}
+
+	return provider, models.Data[0].ID
+}
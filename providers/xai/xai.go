@@ -0,0 +1,102 @@
+// Package xai provides an xAI provider implementation for any-llm.
+//
+// xAI serves Grok models through an OpenAI-compatible API, so the provider is built on
+// openai.CompatibleProvider.
+package xai
+
+import (
+	openaisdk "github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+// Provider configuration constants.
+const (
+	defaultBaseURL = "https://api.x.ai/v1"
+	envAPIKey      = "XAI_API_KEY"
+	providerName   = "xai"
+)
+
+// xAI API constants.
+const (
+	paramReasoningEffort = "reasoning_effort"
+	reasoningField       = "reasoning_content"
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+func init() {
+	providers.Register(providerName, func(opts ...config.Option) (providers.Provider, error) {
+		return New(opts...)
+	})
+}
+
+// Provider implements the providers.Provider interface for xAI.
+// It embeds openai.CompatibleProvider since xAI exposes an OpenAI-compatible API.
+type Provider struct {
+	*openai.CompatibleProvider
+}
+
+// New creates a new xAI provider.
+func New(opts ...config.Option) (*Provider, error) {
+	base, err := openai.NewCompatible(openai.CompatibleConfig{
+		APIKeyEnvVar:   envAPIKey,
+		Capabilities:   xaiCapabilities(),
+		DefaultBaseURL: defaultBaseURL,
+		Name:           providerName,
+		PrepareRequest: prepareRequest,
+		ReasoningField: reasoningField,
+		RequireAPIKey:  true,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{CompatibleProvider: base}, nil
+}
+
+// prepareRequest adapts reasoning effort to xAI, which accepts only low and high.
+// Automatic effort leaves the choice to the model.
+func prepareRequest(req *openaisdk.ChatCompletionNewParams, params providers.CompletionParams) error {
+	switch params.ReasoningEffort {
+	case providers.ReasoningEffortAuto:
+		req.ReasoningEffort = ""
+	case providers.ReasoningEffortMedium:
+		return errors.NewUnsupportedParamError(providerName, paramReasoningEffort)
+	case providers.ReasoningEffortHigh, providers.ReasoningEffortLow:
+		req.ReasoningEffort = shared.ReasoningEffort(params.ReasoningEffort)
+	}
+
+	return nil
+}
+
+// xaiCapabilities returns the capabilities for the xAI provider.
+func xaiCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		Completion:                 true,
+		CompletionAudio:            false,
+		CompletionImage:            true,
+		CompletionPDF:              false,
+		CompletionReasoning:        true,
+		CompletionStreaming:        true,
+		CompletionStructuredOutput: true,
+		CountTokens:                false,
+		Embedding:                  false,
+		ImageGeneration:            false,
+		ListModels:                 true,
+		Moderation:                 false,
+		Rerank:                     false,
+		Speech:                     false,
+		Transcription:              false,
+	}
+}
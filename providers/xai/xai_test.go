@@ -0,0 +1,138 @@
+package xai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/config"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+const testAPIKey = "test-api-key"
+
+const chatResponse = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1700000000,
+	"model": "grok-3-mini",
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "content": "4", "reasoning_content": "2 plus 2 is 4."},
+		"finish_reason": "stop"
+	}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+func TestNew(t *testing.T) {
+	t.Run("creates provider with API key", func(t *testing.T) {
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		require.Equal(t, "xai", provider.Name())
+	})
+
+	t.Run("creates provider from environment variable", func(t *testing.T) {
+		t.Setenv("XAI_API_KEY", "env-api-key")
+
+		provider, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+	})
+
+	t.Run("returns error when API key is missing", func(t *testing.T) {
+		t.Setenv("XAI_API_KEY", "")
+
+		provider, err := New()
+		require.Nil(t, provider)
+
+		var missingKeyErr *errors.MissingAPIKeyError
+		require.ErrorAs(t, err, &missingKeyErr)
+		require.Equal(t, "xai", missingKeyErr.Provider)
+		require.Equal(t, "XAI_API_KEY", missingKeyErr.EnvVar)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		effort     providers.ReasoningEffort
+		wantEffort any
+	}{
+		"low effort":  {effort: providers.ReasoningEffortLow, wantEffort: "low"},
+		"high effort": {effort: providers.ReasoningEffortHigh, wantEffort: "high"},
+		"auto effort": {effort: providers.ReasoningEffortAuto},
+		"no effort":   {},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var body map[string]any
+			provider := testutil.NewTestProvider(t, New, func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/chat/completions", r.URL.Path)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(chatResponse))
+			}, config.WithAPIKey(testAPIKey))
+
+			resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+				Model:           "grok-3-mini",
+				Messages:        testutil.SimpleMessages(),
+				ReasoningEffort: tc.effort,
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.wantEffort, body["reasoning_effort"])
+			require.Equal(t, "4", resp.Choices[0].Message.Content)
+			require.NotNil(t, resp.Choices[0].Message.Reasoning)
+			require.Equal(t, "2 plus 2 is 4.", resp.Choices[0].Message.Reasoning.Content)
+		})
+	}
+
+	t.Run("rejects medium effort", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := New(config.WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+
+		_, err = provider.Completion(context.Background(), providers.CompletionParams{
+			Model:           "grok-3-mini",
+			Messages:        testutil.SimpleMessages(),
+			ReasoningEffort: providers.ReasoningEffortMedium,
+		})
+
+		var paramErr *errors.UnsupportedParamError
+		require.ErrorAs(t, err, &paramErr)
+		require.Equal(t, "reasoning_effort", paramErr.Param)
+	})
+}
+
+func TestIntegrationCompletion(t *testing.T) {
+	t.Parallel()
+
+	if testutil.SkipIfNoAPIKey("xai") {
+		t.Skip("XAI_API_KEY not set")
+	}
+
+	provider, err := New()
+	require.NoError(t, err)
+
+	resp, err := provider.Completion(context.Background(), providers.CompletionParams{
+		Model:    testutil.TestModel("xai"),
+		Messages: testutil.SimpleMessages(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	require.NotEmpty(t, resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Usage)
+	require.Greater(t, resp.Usage.TotalTokens, 0)
+}
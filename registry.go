@@ -0,0 +1,35 @@
+package anyllm
+
+import (
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// NewProvider creates a provider from a model string in the format "provider:model"
+// and returns it together with the provider-specific model ID.
+//
+// The provider package must be imported (a blank import is enough) so that it
+// registers itself:
+//
+//	import _ "github.com/mozilla-ai/any-llm-go/providers/openai"
+//
+//	provider, model, err := anyllm.NewProvider("openai:gpt-4o", anyllm.WithAPIKey("sk-..."))
+//	response, err := provider.Completion(ctx, anyllm.CompletionParams{Model: model, ...})
+//
+// A string without a colon is treated as a bare provider name and returns an empty model ID.
+// Unknown provider names return an *UnsupportedProviderError.
+func NewProvider(model string, opts ...Option) (Provider, string, error) {
+	name, modelID := providers.ParseModelString(model)
+
+	factory, ok := providers.Lookup(name)
+	if !ok {
+		return nil, "", errors.NewUnsupportedProviderError(name)
+	}
+
+	provider, err := factory(opts...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return provider, modelID, nil
+}
@@ -0,0 +1,72 @@
+package anyllm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	anyllm "github.com/mozilla-ai/any-llm-go"
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers/ollama"
+	"github.com/mozilla-ai/any-llm-go/providers/openai"
+)
+
+func TestNewProvider(t *testing.T) {
+	// Note: Not using t.Parallel() here because child test uses t.Setenv.
+
+	t.Run("creates a registered provider from a model string", func(t *testing.T) {
+		t.Parallel()
+
+		provider, model, err := anyllm.NewProvider("openai:gpt-4o", anyllm.WithAPIKey("test-key"))
+		require.NoError(t, err)
+		require.IsType(t, &openai.Provider{}, provider)
+		require.Equal(t, "openai", provider.Name())
+		require.Equal(t, "gpt-4o", model)
+	})
+
+	t.Run("preserves colons in the model ID", func(t *testing.T) {
+		t.Parallel()
+
+		provider, model, err := anyllm.NewProvider("ollama:llama3.2:3b")
+		require.NoError(t, err)
+		require.IsType(t, &ollama.Provider{}, provider)
+		require.Equal(t, "llama3.2:3b", model)
+	})
+
+	t.Run("accepts a bare provider name", func(t *testing.T) {
+		t.Parallel()
+
+		provider, model, err := anyllm.NewProvider("OpenAI", anyllm.WithAPIKey("test-key"))
+		require.NoError(t, err)
+		require.Equal(t, "openai", provider.Name())
+		require.Empty(t, model)
+	})
+
+	t.Run("returns UnsupportedProviderError for unknown providers", func(t *testing.T) {
+		t.Parallel()
+
+		provider, model, err := anyllm.NewProvider("unknown:model")
+		require.Nil(t, provider)
+		require.Empty(t, model)
+		require.ErrorIs(t, err, anyllm.ErrUnsupportedProvider)
+
+		var unsupportedErr *errors.UnsupportedProviderError
+		require.ErrorAs(t, err, &unsupportedErr)
+		require.Equal(t, "unknown", unsupportedErr.Provider)
+	})
+
+	t.Run("returns constructor errors", func(t *testing.T) {
+		t.Setenv("OPENAI_API_KEY", "")
+
+		_, _, err := anyllm.NewProvider("openai:gpt-4o")
+		require.ErrorIs(t, err, anyllm.ErrMissingAPIKey)
+	})
+}
+
+func TestRegisteredProviders(t *testing.T) {
+	t.Parallel()
+
+	names := anyllm.RegisteredProviders()
+	require.Contains(t, names, "openai")
+	require.Contains(t, names, "ollama")
+}
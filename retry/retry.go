@@ -0,0 +1,432 @@
+// Package retry provides a provider wrapper that retries transient failures with
+// jittered exponential backoff.
+//
+// Wrap any provider to retry rate limits and transient server errors:
+//
+//	base, err := openai.New()
+//	provider, err := retry.New(base, retry.WithMaxAttempts(5))
+//	response, err := provider.Completion(ctx, params)
+//
+// Rate limit errors that carry a Retry-After hint (errors.RateLimitError.RetryAfter)
+// wait at least that long before the next attempt. Streams are only retried while
+// establishing the connection; once the first chunk has been delivered, errors are
+// passed through unchanged.
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// Default retry policy values.
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultJitter         = 0.5
+	defaultMaxAttempts    = 3
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMaxElapsed     = 2 * time.Minute
+	defaultMultiplier     = 2.0
+)
+
+// Ensure Provider implements the required interfaces.
+var (
+	_ providers.CapabilityProvider = (*Provider)(nil)
+	_ providers.EmbeddingProvider  = (*Provider)(nil)
+	_ providers.ErrorConverter     = (*Provider)(nil)
+	_ providers.ModelLister        = (*Provider)(nil)
+	_ providers.Provider           = (*Provider)(nil)
+)
+
+// Provider wraps another provider and retries failed requests.
+type Provider struct {
+	opts     *options
+	provider providers.Provider
+}
+
+// OnRetryFunc is called before each retry with the attempt number that failed
+// (starting at 1), the error it returned, and the delay before the next attempt.
+type OnRetryFunc func(attempt int, err error, delay time.Duration)
+
+// Option configures the retry policy.
+type Option func(*options) error
+
+// options holds the retry policy.
+type options struct {
+	initialBackoff time.Duration
+	jitter         float64
+	maxAttempts    int
+	maxBackoff     time.Duration
+	maxElapsed     time.Duration
+	onRetry        OnRetryFunc
+	retryIf        func(error) bool
+	// sleep waits for d or until ctx is done; replaceable in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New wraps provider with the retry policy described by opts.
+func New(provider providers.Provider, opts ...Option) (*Provider, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("provider cannot be nil")
+	}
+
+	o := &options{
+		initialBackoff: defaultInitialBackoff,
+		jitter:         defaultJitter,
+		maxAttempts:    defaultMaxAttempts,
+		maxBackoff:     defaultMaxBackoff,
+		maxElapsed:     defaultMaxElapsed,
+		retryIf:        IsRetryable,
+		sleep:          sleep,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Provider{opts: o, provider: provider}, nil
+}
+
+// WithBackoff sets the delay before the first retry and the cap applied to
+// subsequent exponentially growing delays.
+func WithBackoff(initial, maxBackoff time.Duration) Option {
+	return func(o *options) error {
+		if initial <= 0 {
+			return fmt.Errorf("initial backoff must be positive, got %v", initial)
+		}
+		if maxBackoff < initial {
+			return fmt.Errorf("max backoff %v must not be less than initial backoff %v", maxBackoff, initial)
+		}
+
+		o.initialBackoff = initial
+		o.maxBackoff = maxBackoff
+		return nil
+	}
+}
+
+// WithJitter sets the fraction (0 to 1) of each delay that is randomized.
+// A jitter of 0.5 waits between 50% and 100% of the computed backoff.
+func WithJitter(fraction float64) Option {
+	return func(o *options) error {
+		if fraction < 0 || fraction > 1 {
+			return fmt.Errorf("jitter must be between 0 and 1, got %v", fraction)
+		}
+
+		o.jitter = fraction
+		return nil
+	}
+}
+
+// WithMaxAttempts sets the total number of attempts, including the first one.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) error {
+		if n < 1 {
+			return fmt.Errorf("max attempts must be at least 1, got %d", n)
+		}
+
+		o.maxAttempts = n
+		return nil
+	}
+}
+
+// WithMaxElapsed sets the total time budget across all attempts.
+// A retry is not attempted if its delay would exceed the remaining budget.
+func WithMaxElapsed(d time.Duration) Option {
+	return func(o *options) error {
+		if d <= 0 {
+			return fmt.Errorf("max elapsed must be positive, got %v", d)
+		}
+
+		o.maxElapsed = d
+		return nil
+	}
+}
+
+// WithOnRetry registers a callback invoked before each retry, e.g. for logging.
+func WithOnRetry(fn OnRetryFunc) Option {
+	return func(o *options) error {
+		if fn == nil {
+			return fmt.Errorf("retry callback cannot be nil")
+		}
+
+		o.onRetry = fn
+		return nil
+	}
+}
+
+// WithRetryIf replaces the predicate that decides whether an error is retryable.
+// The default is IsRetryable.
+func WithRetryIf(fn func(error) bool) Option {
+	return func(o *options) error {
+		if fn == nil {
+			return fmt.Errorf("retry predicate cannot be nil")
+		}
+
+		o.retryIf = fn
+		return nil
+	}
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// rate limits, 408 and 5xx provider errors, and dropped or timed-out connections.
+// Context cancellation is never retried.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if stderrors.Is(err, errors.ErrRateLimit) {
+		return true
+	}
+
+	var providerErr *errors.ProviderError
+	if stderrors.As(err, &providerErr) {
+		if providerErr.StatusCode == 408 || providerErr.StatusCode >= 500 {
+			return true
+		}
+		if providerErr.StatusCode != 0 {
+			return false
+		}
+	}
+
+	return isConnectionError(err)
+}
+
+// Capabilities returns the capabilities of the wrapped provider.
+func (p *Provider) Capabilities() providers.Capabilities {
+	if cp, ok := p.provider.(providers.CapabilityProvider); ok {
+		return cp.Capabilities()
+	}
+
+	return providers.Capabilities{
+		Completion:          true,
+		CompletionStreaming: true,
+	}
+}
+
+// Completion performs a chat completion request, retrying transient failures.
+func (p *Provider) Completion(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (*providers.ChatCompletion, error) {
+	return do(ctx, p.opts, func(ctx context.Context) (*providers.ChatCompletion, error) {
+		return p.provider.Completion(ctx, params)
+	})
+}
+
+// CompletionStream performs a streaming chat completion request.
+// Opening the stream is retried until the first chunk arrives; errors after that
+// point are forwarded without retrying, since chunks have already been delivered.
+func (p *Provider) CompletionStream(
+	ctx context.Context,
+	params providers.CompletionParams,
+) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		start := time.Now()
+		for attempt := 1; ; attempt++ {
+			upstreamChunks, upstreamErrs := p.provider.CompletionStream(ctx, params)
+
+			first, ok := <-upstreamChunks
+			if !ok {
+				err := <-upstreamErrs
+				if err == nil {
+					return
+				}
+
+				delay, retry := p.opts.next(attempt, start, err)
+				if !retry {
+					errs <- err
+					return
+				}
+				if p.opts.onRetry != nil {
+					p.opts.onRetry(attempt, err, delay)
+				}
+				if err := p.opts.sleep(ctx, delay); err != nil {
+					errs <- err
+					return
+				}
+				continue
+			}
+
+			if !send(ctx, chunks, first) {
+				errs <- ctx.Err()
+				return
+			}
+			for chunk := range upstreamChunks {
+				if !send(ctx, chunks, chunk) {
+					errs <- ctx.Err()
+					return
+				}
+			}
+			if err := <-upstreamErrs; err != nil {
+				errs <- err
+			}
+			return
+		}
+	}()
+
+	return chunks, errs
+}
+
+// ConvertError delegates to the wrapped provider when it implements providers.ErrorConverter.
+func (p *Provider) ConvertError(err error) error {
+	if ec, ok := p.provider.(providers.ErrorConverter); ok {
+		return ec.ConvertError(err)
+	}
+
+	return err
+}
+
+// Embedding generates embeddings, retrying transient failures.
+func (p *Provider) Embedding(
+	ctx context.Context,
+	params providers.EmbeddingParams,
+) (*providers.EmbeddingResponse, error) {
+	ep, ok := p.provider.(providers.EmbeddingProvider)
+	if !ok {
+		return nil, errors.NewUnsupportedParamError(p.provider.Name(), "embedding")
+	}
+
+	return do(ctx, p.opts, func(ctx context.Context) (*providers.EmbeddingResponse, error) {
+		return ep.Embedding(ctx, params)
+	})
+}
+
+// ListModels lists the wrapped provider's models, retrying transient failures.
+func (p *Provider) ListModels(ctx context.Context) (*providers.ModelsResponse, error) {
+	ml, ok := p.provider.(providers.ModelLister)
+	if !ok {
+		return nil, errors.NewUnsupportedParamError(p.provider.Name(), "list_models")
+	}
+
+	return do(ctx, p.opts, ml.ListModels)
+}
+
+// Name returns the name of the wrapped provider.
+func (p *Provider) Name() string {
+	return p.provider.Name()
+}
+
+// Unwrap returns the wrapped provider.
+func (p *Provider) Unwrap() providers.Provider {
+	return p.provider
+}
+
+// backoff returns the jittered delay to wait after the given failed attempt.
+func (o *options) backoff(attempt int) time.Duration {
+	delay := float64(o.initialBackoff)
+	for range attempt - 1 {
+		delay *= defaultMultiplier
+		if delay >= float64(o.maxBackoff) {
+			delay = float64(o.maxBackoff)
+			break
+		}
+	}
+
+	if o.jitter > 0 {
+		delay -= delay * o.jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// next decides whether the failed attempt should be retried and how long to wait first.
+func (o *options) next(attempt int, start time.Time, err error) (time.Duration, bool) {
+	if attempt >= o.maxAttempts || !o.retryIf(err) {
+		return 0, false
+	}
+
+	delay := o.backoff(attempt)
+
+	var rateLimitErr *errors.RateLimitError
+	if stderrors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+		delay = max(delay, time.Duration(rateLimitErr.RetryAfter)*time.Second)
+	}
+
+	if time.Since(start)+delay > o.maxElapsed {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// do calls fn until it succeeds, returns a non-retryable error, or the retry budget is spent.
+func do[T any](ctx context.Context, o *options, fn func(context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		result, err := fn(ctx)
+		if err == nil {
+			return result, nil
+		}
+
+		delay, retry := o.next(attempt, start, err)
+		if !retry {
+			return result, err
+		}
+		if o.onRetry != nil {
+			o.onRetry(attempt, err, delay)
+		}
+		if err := o.sleep(ctx, delay); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// isConnectionError reports whether err indicates a dropped or timed-out connection.
+func isConnectionError(err error) bool {
+	if stderrors.Is(err, syscall.ECONNRESET) ||
+		stderrors.Is(err, syscall.EPIPE) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return stderrors.As(err, &netErr) && netErr.Timeout()
+}
+
+// send delivers chunk unless ctx is done first.
+func send(ctx context.Context, chunks chan<- providers.ChatCompletionChunk, chunk providers.ChatCompletionChunk) bool {
+	select {
+	case chunks <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sleep waits for d or until ctx is done, returning the context error in the latter case.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
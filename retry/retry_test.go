@@ -0,0 +1,431 @@
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mozilla-ai/any-llm-go/errors"
+	"github.com/mozilla-ai/any-llm-go/internal/testutil"
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil provider", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := New(nil)
+		require.Nil(t, p)
+		require.Error(t, err)
+	})
+
+	t.Run("applies defaults", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := New(testutil.NewMockProvider())
+		require.NoError(t, err)
+		require.Equal(t, defaultMaxAttempts, p.opts.maxAttempts)
+		require.Equal(t, defaultInitialBackoff, p.opts.initialBackoff)
+		require.Equal(t, defaultMaxBackoff, p.opts.maxBackoff)
+		require.Equal(t, defaultMaxElapsed, p.opts.maxElapsed)
+		require.Equal(t, "mock", p.Name())
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			opt  Option
+		}{
+			{name: "zero max attempts", opt: WithMaxAttempts(0)},
+			{name: "non-positive initial backoff", opt: WithBackoff(0, time.Second)},
+			{name: "max backoff below initial", opt: WithBackoff(time.Second, time.Millisecond)},
+			{name: "negative jitter", opt: WithJitter(-0.1)},
+			{name: "jitter above one", opt: WithJitter(1.5)},
+			{name: "non-positive max elapsed", opt: WithMaxElapsed(0)},
+			{name: "nil retry predicate", opt: WithRetryIf(nil)},
+			{name: "nil retry callback", opt: WithOnRetry(nil)},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := New(testutil.NewMockProvider(), tc.opt)
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	providerErrWithStatus := func(status int) error {
+		err := errors.NewProviderError("test", stderrors.New("boom"))
+		err.StatusCode = status
+		return err
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "rate limit", err: errors.NewRateLimitError("test", stderrors.New("slow down")), want: true},
+		{name: "500", err: providerErrWithStatus(500), want: true},
+		{name: "503", err: providerErrWithStatus(503), want: true},
+		{name: "408", err: providerErrWithStatus(408), want: true},
+		{name: "409", err: providerErrWithStatus(409), want: false},
+		{
+			name: "connection reset",
+			err:  errors.NewProviderError("test", fmt.Errorf("read: %w", syscall.ECONNRESET)),
+			want: true,
+		},
+		{
+			name: "unexpected EOF",
+			err:  errors.NewProviderError("test", io.ErrUnexpectedEOF),
+			want: true,
+		},
+		{name: "generic provider error", err: errors.NewProviderError("test", stderrors.New("boom")), want: false},
+		{name: "authentication", err: errors.NewAuthenticationError("test", stderrors.New("nope")), want: false},
+		{name: "invalid request", err: errors.NewInvalidRequestError("test", stderrors.New("bad")), want: false},
+		{name: "context canceled", err: context.Canceled, want: false},
+		{
+			name: "wrapped deadline exceeded",
+			err:  errors.NewProviderError("test", context.DeadlineExceeded),
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, IsRetryable(tc.err))
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("grows exponentially up to the cap", func(t *testing.T) {
+		t.Parallel()
+
+		o := &options{initialBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+		require.Equal(t, 100*time.Millisecond, o.backoff(1))
+		require.Equal(t, 200*time.Millisecond, o.backoff(2))
+		require.Equal(t, 400*time.Millisecond, o.backoff(3))
+		require.Equal(t, 800*time.Millisecond, o.backoff(4))
+		require.Equal(t, time.Second, o.backoff(5))
+		require.Equal(t, time.Second, o.backoff(50))
+	})
+
+	t.Run("jitter stays within bounds", func(t *testing.T) {
+		t.Parallel()
+
+		o := &options{initialBackoff: time.Second, maxBackoff: time.Second, jitter: 0.5}
+		for range 100 {
+			d := o.backoff(1)
+			require.GreaterOrEqual(t, d, 500*time.Millisecond)
+			require.LessOrEqual(t, d, time.Second)
+		}
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		calls := 0
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.NewRateLimitError("mock", stderrors.New("slow down"))
+			}
+			return testutil.MockChatCompletion("ok"), nil
+		}
+
+		var retried []int
+		p, sleeps := newTestProvider(t, mock, WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			retried = append(retried, attempt)
+			require.ErrorIs(t, err, errors.ErrRateLimit)
+		}))
+
+		resp, err := p.Completion(context.Background(), providers.CompletionParams{Model: "m"})
+		require.NoError(t, err)
+		require.Equal(t, "ok", resp.Choices[0].Message.Content)
+		require.Equal(t, 3, calls)
+		require.Equal(t, []int{1, 2}, retried)
+		require.Len(t, *sleeps, 2)
+	})
+
+	t.Run("does not retry non-retryable errors", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewAuthenticationError("mock", stderrors.New("bad key"))
+		}
+
+		p, sleeps := newTestProvider(t, mock)
+
+		_, err := p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrAuthentication)
+		require.Len(t, mock.CompletionCalls, 1)
+		require.Empty(t, *sleeps)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			err := errors.NewProviderError("mock", stderrors.New("unavailable"))
+			err.StatusCode = 503
+			return nil, err
+		}
+
+		p, _ := newTestProvider(t, mock, WithMaxAttempts(4))
+
+		_, err := p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrProvider)
+		require.Len(t, mock.CompletionCalls, 4)
+	})
+
+	t.Run("honors RetryAfter", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		calls := 0
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			calls++
+			if calls == 1 {
+				err := errors.NewRateLimitError("mock", stderrors.New("slow down"))
+				err.RetryAfter = 7
+				return nil, err
+			}
+			return testutil.MockChatCompletion("ok"), nil
+		}
+
+		p, sleeps := newTestProvider(t, mock)
+
+		_, err := p.Completion(context.Background(), providers.CompletionParams{})
+		require.NoError(t, err)
+		require.Equal(t, []time.Duration{7 * time.Second}, *sleeps)
+	})
+
+	t.Run("stops when RetryAfter exceeds the elapsed budget", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			err := errors.NewRateLimitError("mock", stderrors.New("slow down"))
+			err.RetryAfter = 60
+			return nil, err
+		}
+
+		p, sleeps := newTestProvider(t, mock, WithMaxElapsed(10*time.Second))
+
+		_, err := p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrRateLimit)
+		require.Len(t, mock.CompletionCalls, 1)
+		require.Empty(t, *sleeps)
+	})
+
+	t.Run("stops waiting when the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewRateLimitError("mock", stderrors.New("slow down"))
+		}
+
+		p, err := New(mock, WithBackoff(time.Hour, time.Hour), WithMaxElapsed(2*time.Hour))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err = p.Completion(ctx, providers.CompletionParams{})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Len(t, mock.CompletionCalls, 1)
+	})
+
+	t.Run("uses a custom retry predicate", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionFunc = func(ctx context.Context, params providers.CompletionParams) (*providers.ChatCompletion, error) {
+			return nil, errors.NewRateLimitError("mock", stderrors.New("slow down"))
+		}
+
+		p, _ := newTestProvider(t, mock, WithRetryIf(func(error) bool { return false }))
+
+		_, err := p.Completion(context.Background(), providers.CompletionParams{})
+		require.ErrorIs(t, err, errors.ErrRateLimit)
+		require.Len(t, mock.CompletionCalls, 1)
+	})
+}
+
+func TestCompletionStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries until the stream opens", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		defaultStream := mock.CompletionStreamFunc
+		calls := 0
+		mock.CompletionStreamFunc = func(
+			ctx context.Context,
+			params providers.CompletionParams,
+		) (<-chan providers.ChatCompletionChunk, <-chan error) {
+			calls++
+			if calls == 1 {
+				return failedStream(errors.NewRateLimitError("mock", stderrors.New("slow down")))
+			}
+			return defaultStream(ctx, params)
+		}
+
+		p, sleeps := newTestProvider(t, mock)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{Model: "m"})
+
+		var content string
+		for chunk := range chunks {
+			content += chunk.Choices[0].Delta.Content
+		}
+		require.NoError(t, <-errs)
+		require.Equal(t, "Hello World", content)
+		require.Equal(t, 2, calls)
+		require.Len(t, *sleeps, 1)
+	})
+
+	t.Run("does not retry after the first chunk", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionStreamFunc = func(
+			ctx context.Context,
+			params providers.CompletionParams,
+		) (<-chan providers.ChatCompletionChunk, <-chan error) {
+			chunks := make(chan providers.ChatCompletionChunk, 1)
+			errs := make(chan error, 1)
+			chunks <- providers.ChatCompletionChunk{
+				Choices: []providers.ChunkChoice{{Delta: providers.ChunkDelta{Content: "partial"}}},
+			}
+			errs <- errors.NewRateLimitError("mock", stderrors.New("slow down"))
+			close(chunks)
+			close(errs)
+			return chunks, errs
+		}
+
+		p, sleeps := newTestProvider(t, mock)
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{})
+
+		var received int
+		for range chunks {
+			received++
+		}
+		require.ErrorIs(t, <-errs, errors.ErrRateLimit)
+		require.Equal(t, 1, received)
+		require.Len(t, mock.CompletionStreamCalls, 1)
+		require.Empty(t, *sleeps)
+	})
+
+	t.Run("returns the last error when attempts are exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		mock := testutil.NewMockProvider()
+		mock.CompletionStreamFunc = func(
+			ctx context.Context,
+			params providers.CompletionParams,
+		) (<-chan providers.ChatCompletionChunk, <-chan error) {
+			return failedStream(errors.NewRateLimitError("mock", stderrors.New("slow down")))
+		}
+
+		p, _ := newTestProvider(t, mock, WithMaxAttempts(2))
+
+		chunks, errs := p.CompletionStream(context.Background(), providers.CompletionParams{})
+		for range chunks {
+			t.Fatal("expected no chunks")
+		}
+		require.ErrorIs(t, <-errs, errors.ErrRateLimit)
+		require.Len(t, mock.CompletionStreamCalls, 2)
+	})
+}
+
+func TestEmbedding(t *testing.T) {
+	t.Parallel()
+
+	mock := testutil.NewMockProvider()
+	calls := 0
+	defaultEmbedding := mock.EmbeddingFunc
+	mock.EmbeddingFunc = func(ctx context.Context, params providers.EmbeddingParams) (*providers.EmbeddingResponse, error) {
+		calls++
+		if calls == 1 {
+			err := errors.NewProviderError("mock", stderrors.New("bad gateway"))
+			err.StatusCode = 502
+			return nil, err
+		}
+		return defaultEmbedding(ctx, params)
+	}
+
+	p, _ := newTestProvider(t, mock)
+
+	resp, err := p.Embedding(context.Background(), providers.EmbeddingParams{Model: "e", Input: "hi"})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, 2, calls)
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(testutil.NewMockProvider())
+	require.NoError(t, err)
+	require.True(t, p.Capabilities().Embedding)
+}
+
+// failedStream returns a stream that closes without chunks and reports err.
+func failedStream(err error) (<-chan providers.ChatCompletionChunk, <-chan error) {
+	chunks := make(chan providers.ChatCompletionChunk)
+	errs := make(chan error, 1)
+	errs <- err
+	close(chunks)
+	close(errs)
+	return chunks, errs
+}
+
+// newTestProvider creates a retry provider that records delays instead of sleeping.
+func newTestProvider(t *testing.T, mock *testutil.MockProvider, opts ...Option) (*Provider, *[]time.Duration) {
+	t.Helper()
+
+	opts = append([]Option{WithJitter(0)}, opts...)
+	p, err := New(mock, opts...)
+	require.NoError(t, err)
+
+	var sleeps []time.Duration
+	p.opts.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+
+	return p, &sleeps
+}
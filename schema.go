@@ -0,0 +1,37 @@
+package anyllm
+
+import (
+	"context"
+
+	"github.com/mozilla-ai/any-llm-go/providers"
+)
+
+// IntoOption configures CompletionInto.
+type IntoOption = providers.IntoOption
+
+// Structured output options.
+var (
+	WithRepairAttempts = providers.WithRepairAttempts
+	WithSchemaName     = providers.WithSchemaName
+)
+
+// CompletionInto performs a chat completion request and decodes the response into a T.
+// See providers.CompletionInto for details.
+func CompletionInto[T any](
+	ctx context.Context,
+	provider Provider,
+	params CompletionParams,
+	opts ...IntoOption,
+) (T, *ChatCompletion, error) {
+	return providers.CompletionInto[T](ctx, provider, params, opts...)
+}
+
+// ResponseFormatFor returns a json_schema response format whose schema is generated from T.
+func ResponseFormatFor[T any](name string) (*ResponseFormat, error) {
+	return providers.ResponseFormatFor[T](name)
+}
+
+// ToolFor returns a function tool whose parameters schema is generated from T.
+func ToolFor[T any](name, description string) (Tool, error) {
+	return providers.ToolFor[T](name, description)
+}
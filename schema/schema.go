@@ -0,0 +1,472 @@
+// Package schema generates JSON Schemas from Go types.
+//
+// Struct fields are named after their json tags and are required unless tagged
+// omitempty or omitzero. Additional struct tags refine the schema of a field:
+//
+//	type Forecast struct {
+//		City  string   `json:"city" description:"City name"`
+//		Unit  string   `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+//		Days  int      `json:"days" min:"1" max:"14"`
+//		Email string   `json:"email,omitempty" format:"email"`
+//		Tags  []string `json:"tags" max:"5"`
+//	}
+//
+// The min and max tags apply to values of numbers, to the length of strings, and
+// to the number of items of slices and arrays. The enum tag of a slice or array
+// lists the values allowed for its items. Nested structs are inlined, while
+// recursive types are emitted once under "$defs" and referenced with "$ref".
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct tags understood by the generator.
+const (
+	tagDescription = "description"
+	tagEnum        = "enum"
+	tagFormat      = "format"
+	tagJSON        = "json"
+	tagMax         = "max"
+	tagMin         = "min"
+)
+
+// Schema keywords and types.
+const (
+	defsPrefix     = "#/$defs/"
+	formatDateTime = "date-time"
+	keyDefs        = "$defs"
+	keyRef         = "$ref"
+	rootRef        = "#"
+	typeArray      = "array"
+	typeBoolean    = "boolean"
+	typeInteger    = "integer"
+	typeNumber     = "number"
+	typeObject     = "object"
+	typeString     = "string"
+)
+
+// Types with dedicated schemas.
+var (
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+// invalidDefChars matches characters that are not allowed in definition names.
+var invalidDefChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// generator holds the state of a single schema generation.
+type generator struct {
+	// defNames maps recursive types to their name under $defs.
+	defNames map[reflect.Type]string
+	// defs holds the generated definitions.
+	defs map[string]any
+	// recursive is the set of types that reference themselves.
+	recursive map[reflect.Type]bool
+	// root is the type the schema is generated for.
+	root reflect.Type
+}
+
+// For returns the JSON Schema of T.
+func For[T any]() (map[string]any, error) {
+	return Generate(reflect.TypeFor[T]())
+}
+
+// Generate returns the JSON Schema of t.
+func Generate(t reflect.Type) (map[string]any, error) {
+	if t == nil {
+		return nil, fmt.Errorf("schema: type cannot be nil")
+	}
+
+	t = deref(t)
+	g := &generator{
+		defNames:  map[reflect.Type]string{},
+		defs:      map[string]any{},
+		recursive: map[reflect.Type]bool{},
+		root:      t,
+	}
+	g.findRecursive(t, map[reflect.Type]bool{})
+
+	s, err := g.inline(t)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	if len(g.defs) > 0 {
+		s[keyDefs] = g.defs
+	}
+
+	return s, nil
+}
+
+// deref returns the type pointed to by t, following any number of pointers.
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// findRecursive records every struct type reachable from t that refers back to itself.
+func (g *generator) findRecursive(t reflect.Type, stack map[reflect.Type]bool) {
+	t = deref(t)
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		g.findRecursive(t.Elem(), stack)
+	case reflect.Struct:
+		if t == timeType {
+			return
+		}
+		if stack[t] {
+			g.recursive[t] = true
+			return
+		}
+
+		stack[t] = true
+		for _, f := range fields(t) {
+			g.findRecursive(f.typ, stack)
+		}
+		delete(stack, t)
+	default:
+	}
+}
+
+// inline returns the schema of t without turning t itself into a reference.
+func (g *generator) inline(t reflect.Type) (map[string]any, error) {
+	t = deref(t)
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": typeString, "format": formatDateTime}, nil
+	case t == rawMessageType:
+		return map[string]any{}, nil
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": typeString}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": typeBoolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": typeInteger}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": typeNumber}, nil
+	case reflect.String:
+		return map[string]any{"type": typeString}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+	case reflect.Array:
+		items, err := g.ref(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": typeArray, "items": items, "minItems": t.Len(), "maxItems": t.Len()}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings.
+			return map[string]any{"type": typeString, "contentEncoding": "base64"}, nil
+		}
+		items, err := g.ref(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": typeArray, "items": items}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !t.Key().Implements(textMarshalerType) {
+				return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+			}
+		}
+		values, err := g.ref(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": typeObject, "additionalProperties": values}, nil
+	case reflect.Struct:
+		return g.object(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// object returns the schema of the struct type t.
+func (g *generator) object(t reflect.Type) (map[string]any, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	for _, f := range fields(t) {
+		s, err := g.ref(f.typ)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", t.Name(), f.goName, err)
+		}
+		if f.asString {
+			s = map[string]any{"type": typeString}
+		}
+		if err := applyTags(s, f); err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", t.Name(), f.goName, err)
+		}
+
+		properties[f.name] = s
+		if !f.optional {
+			required = append(required, f.name)
+		}
+	}
+
+	return map[string]any{
+		"type":                 typeObject,
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}, nil
+}
+
+// ref returns the schema of t, referencing it through $defs if it is recursive.
+func (g *generator) ref(t reflect.Type) (map[string]any, error) {
+	t = deref(t)
+	if !g.recursive[t] {
+		return g.inline(t)
+	}
+	if t == g.root {
+		return map[string]any{keyRef: rootRef}, nil
+	}
+
+	name, ok := g.defNames[t]
+	if !ok {
+		name = g.defName(t)
+		g.defNames[t] = name
+		// Reserve the name before generating so that self-references resolve.
+		g.defs[name] = nil
+
+		s, err := g.inline(t)
+		if err != nil {
+			return nil, err
+		}
+		g.defs[name] = s
+	}
+
+	return map[string]any{keyRef: defsPrefix + name}, nil
+}
+
+// defName returns a unique definition name for t.
+func (g *generator) defName(t reflect.Type) string {
+	base := invalidDefChars.ReplaceAllString(t.Name(), "_")
+	if base == "" {
+		base = "def"
+	}
+
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := g.defs[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+// field is a struct field as seen by encoding/json.
+type field struct {
+	asString bool
+	goName   string
+	name     string
+	optional bool
+	tag      reflect.StructTag
+	typ      reflect.Type
+}
+
+// fields returns the fields of the struct type t that encoding/json would encode,
+// promoting the fields of embedded structs that have no json name. Fields declared
+// directly on t take precedence over promoted ones.
+func fields(t reflect.Type) []field {
+	direct := map[string]bool{}
+	for i := range t.NumField() {
+		if name, ok := fieldName(t.Field(i)); ok {
+			direct[name] = true
+		}
+	}
+
+	var result []field
+	for i := range t.NumField() {
+		sf := t.Field(i)
+
+		if embedded := deref(sf.Type); isPromoted(sf) && embedded.Kind() == reflect.Struct {
+			for _, promoted := range fields(embedded) {
+				if !direct[promoted.name] {
+					direct[promoted.name] = true
+					result = append(result, promoted)
+				}
+			}
+			continue
+		}
+
+		name, ok := fieldName(sf)
+		if !ok {
+			continue
+		}
+
+		_, opts, _ := strings.Cut(sf.Tag.Get(tagJSON), ",")
+		result = append(result, field{
+			asString: hasOption(opts, "string") && isScalar(sf.Type),
+			goName:   sf.Name,
+			name:     name,
+			optional: hasOption(opts, "omitempty") || hasOption(opts, "omitzero"),
+			tag:      sf.Tag,
+			typ:      sf.Type,
+		})
+	}
+
+	return result
+}
+
+// applyTags adds the description, enum, format, min and max tags of f to s.
+func applyTags(s map[string]any, f field) error {
+	if description := f.tag.Get(tagDescription); description != "" {
+		s["description"] = description
+	}
+	if format := f.tag.Get(tagFormat); format != "" {
+		s["format"] = format
+	}
+
+	kind := deref(f.typ).Kind()
+	if f.asString {
+		kind = reflect.String
+	}
+
+	if enum, ok := f.tag.Lookup(tagEnum); ok {
+		// An enum on a slice or array constrains each of its items.
+		target, enumKind := s, kind
+		if items, ok := s["items"].(map[string]any); ok && (kind == reflect.Slice || kind == reflect.Array) {
+			target, enumKind = items, deref(deref(f.typ).Elem()).Kind()
+		}
+
+		values := make([]any, 0)
+		for raw := range strings.SplitSeq(enum, ",") {
+			v, err := parseValue(enumKind, strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("enum: %w", err)
+			}
+			values = append(values, v)
+		}
+		target["enum"] = values
+	}
+
+	if err := applyBound(s, kind, f.tag, tagMin, "minimum", "minLength", "minItems"); err != nil {
+		return err
+	}
+	if err := applyBound(s, kind, f.tag, tagMax, "maximum", "maxLength", "maxItems"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applyBound adds the min or max tag to s, using the keyword that matches the kind of value:
+// value for numbers, length for strings, and item count for slices and arrays.
+func applyBound(s map[string]any, kind reflect.Kind, tag reflect.StructTag, key, value, length, items string) error {
+	raw, ok := tag.Lookup(key)
+	if !ok {
+		return nil
+	}
+
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if kind == reflect.String {
+			s[length] = n
+		} else {
+			s[items] = n
+		}
+	default:
+		v, err := parseValue(kind, raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		s[value] = v
+	}
+
+	return nil
+}
+
+// fieldName returns the JSON name of a field, or false if encoding/json skips it
+// or promotes its fields instead.
+func fieldName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get(tagJSON)
+	if tag == "-" {
+		return "", false
+	}
+	if isPromoted(sf) && deref(sf.Type).Kind() == reflect.Struct {
+		return "", false
+	}
+	if !sf.IsExported() {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+
+	return name, true
+}
+
+// hasOption reports whether the comma-separated json tag options contain option.
+func hasOption(opts string, option string) bool {
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isPromoted reports whether sf is an embedded field without a json name.
+func isPromoted(sf reflect.StructField) bool {
+	name, _, _ := strings.Cut(sf.Tag.Get(tagJSON), ",")
+	return sf.Anonymous && name == "" && sf.Tag.Get(tagJSON) != "-"
+}
+
+// isScalar reports whether the json ",string" option applies to values of type t.
+func isScalar(t reflect.Type) bool {
+	switch deref(t).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseValue parses a tag value as a JSON value of the given kind.
+func parseValue(kind reflect.Kind, raw string) (any, error) {
+	switch kind {
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(raw, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.ParseUint(raw, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	case reflect.String:
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("not supported for %s fields", kind)
+	}
+}
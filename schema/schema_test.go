@@ -0,0 +1,257 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City    string `json:"city"`
+	Country string `json:"country,omitempty"`
+}
+
+type base struct {
+	ID      string `json:"id"`
+	Created string `json:"created"`
+}
+
+type node struct {
+	Children []node `json:"children,omitempty"`
+	Value    int    `json:"value"`
+}
+
+type tree struct {
+	Name string `json:"name"`
+	Root *node  `json:"root"`
+}
+
+type user struct {
+	base
+
+	Address  address           `json:"address"`
+	Age      int               `json:"age" min:"0" max:"150"`
+	Created  time.Time         `json:"created"`
+	Email    string            `json:"email,omitempty" format:"email" description:"Contact address"`
+	Ignored  string            `json:"-"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Nickname *string           `json:"nickname"`
+	Role     string            `json:"role" enum:"admin, member"`
+	Tags     []string          `json:"tags" min:"1" max:"3"`
+	Username string            `json:"username" min:"3"`
+
+	internal string
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("generates an object schema from struct tags", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := For[user]()
+		require.NoError(t, err)
+
+		requireJSON(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"id": {"type": "string"},
+				"address": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {"city": {"type": "string"}, "country": {"type": "string"}},
+					"required": ["city"]
+				},
+				"age": {"type": "integer", "minimum": 0, "maximum": 150},
+				"created": {"type": "string", "format": "date-time"},
+				"email": {"type": "string", "format": "email", "description": "Contact address"},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"nickname": {"type": "string"},
+				"role": {"type": "string", "enum": ["admin", "member"]},
+				"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": 3},
+				"username": {"type": "string", "minLength": 3}
+			},
+			"required": ["id", "address", "age", "created", "nickname", "role", "tags", "username"]
+		}`, got)
+	})
+
+	t.Run("references recursive types through $defs", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := For[tree]()
+		require.NoError(t, err)
+
+		requireJSON(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"name": {"type": "string"},
+				"root": {"$ref": "#/$defs/node"}
+			},
+			"required": ["name", "root"],
+			"$defs": {
+				"node": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {
+						"children": {"type": "array", "items": {"$ref": "#/$defs/node"}},
+						"value": {"type": "integer"}
+					},
+					"required": ["value"]
+				}
+			}
+		}`, got)
+	})
+
+	t.Run("references a recursive root type with #", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := For[*node]()
+		require.NoError(t, err)
+		require.NotContains(t, got, keyDefs)
+		require.Equal(t, map[string]any{keyRef: rootRef}, got["properties"].(map[string]any)["children"].(map[string]any)["items"])
+	})
+
+	t.Run("maps scalar and special types", func(t *testing.T) {
+		t.Parallel()
+
+		type scalars struct {
+			Any     any             `json:"any"`
+			Bytes   []byte          `json:"bytes"`
+			Fixed   [2]float64      `json:"fixed"`
+			Flag    bool            `json:"flag"`
+			Quoted  int64           `json:"quoted,string"`
+			Raw     json.RawMessage `json:"raw"`
+			Ratio   float32         `json:"ratio" enum:"0.5,1"`
+			Unnamed uint8
+			Zero    int `json:"zero,omitzero"`
+		}
+
+		got, err := For[scalars]()
+		require.NoError(t, err)
+
+		requireJSON(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"any": {},
+				"bytes": {"type": "string", "contentEncoding": "base64"},
+				"fixed": {"type": "array", "items": {"type": "number"}, "minItems": 2, "maxItems": 2},
+				"flag": {"type": "boolean"},
+				"quoted": {"type": "string"},
+				"raw": {},
+				"ratio": {"type": "number", "enum": [0.5, 1]},
+				"Unnamed": {"type": "integer"},
+				"zero": {"type": "integer"}
+			},
+			"required": ["any", "bytes", "fixed", "flag", "quoted", "raw", "ratio", "Unnamed"]
+		}`, got)
+	})
+
+	t.Run("applies enums on slices and arrays to their items", func(t *testing.T) {
+		t.Parallel()
+
+		type lists struct {
+			Sizes [2]int   `json:"sizes" enum:"1,2,3"`
+			Tags  []string `json:"tags" enum:"a, b" max:"2"`
+		}
+
+		got, err := For[lists]()
+		require.NoError(t, err)
+
+		requireJSON(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"sizes": {
+					"type": "array",
+					"items": {"type": "integer", "enum": [1, 2, 3]},
+					"minItems": 2,
+					"maxItems": 2
+				},
+				"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}, "maxItems": 2}
+			},
+			"required": ["sizes", "tags"]
+		}`, got)
+	})
+
+	t.Run("generates schemas for non-struct types", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := For[[]address]()
+		require.NoError(t, err)
+		require.Equal(t, typeArray, got["type"])
+
+		got, err = For[map[int]bool]()
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"type": typeObject, "additionalProperties": map[string]any{"type": typeBoolean}}, got)
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		typ     reflect.Type
+		wantErr string
+	}{
+		"nil type": {
+			typ:     nil,
+			wantErr: "type cannot be nil",
+		},
+		"unsupported field type": {
+			typ:     reflect.TypeFor[struct{ C chan int }](),
+			wantErr: "unsupported type chan int",
+		},
+		"unsupported map key": {
+			typ:     reflect.TypeFor[map[[2]int]string](),
+			wantErr: "unsupported map key type",
+		},
+		"invalid enum value": {
+			typ: reflect.TypeFor[struct {
+				N int `json:"n" enum:"one"`
+			}](),
+			wantErr: "enum",
+		},
+		"invalid enum item": {
+			typ: reflect.TypeFor[struct {
+				N []int `json:"n" enum:"one"`
+			}](),
+			wantErr: "enum",
+		},
+		"enum on bytes": {
+			typ: reflect.TypeFor[struct {
+				B []byte `json:"b" enum:"a"`
+			}](),
+			wantErr: "not supported for slice fields",
+		},
+		"invalid bound": {
+			typ: reflect.TypeFor[struct {
+				S string `json:"s" max:"ten"`
+			}](),
+			wantErr: "max",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Generate(tc.typ)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+// requireJSON asserts that got encodes to the same JSON as want.
+func requireJSON(t *testing.T, want string, got map[string]any) {
+	t.Helper()
+
+	data, err := json.Marshal(got)
+	require.NoError(t, err)
+	require.JSONEq(t, want, string(data))
+}
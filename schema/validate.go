@@ -0,0 +1,335 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes why a value does not match a schema.
+type ValidationError struct {
+	// Message describes the mismatch.
+	Message string
+
+	// Path is the JSON Pointer of the offending value, or "" for the root.
+	Path string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+// validator holds the state of a single validation.
+type validator struct {
+	root map[string]any
+}
+
+// Validate checks a decoded JSON value against a schema.
+//
+// The value must have the shape produced by json.Unmarshal into an any. Validate supports
+// the keywords emitted by Generate: type, properties, required, additionalProperties,
+// items, enum, minimum, maximum, minLength, maxLength, minItems, maxItems, and local
+// $ref. Other keywords, such as format, are ignored.
+func Validate(schema map[string]any, value any) error {
+	v := &validator{root: schema}
+	return v.validate(schema, value, "")
+}
+
+// ValidateJSON decodes data and checks it against a schema.
+func ValidateJSON(schema map[string]any, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	return Validate(schema, value)
+}
+
+// validate checks value against s.
+func (v *validator) validate(s map[string]any, value any, path string) error {
+	if ref, ok := s[keyRef].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			return &ValidationError{Message: err.Error(), Path: path}
+		}
+		return v.validate(target, value, path)
+	}
+
+	if t, ok := s["type"]; ok {
+		if err := checkType(t, value, path); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := s["enum"]; ok && !containsValue(enum, value) {
+		return &ValidationError{Message: fmt.Sprintf("value %s is not one of %s", encode(value), encode(enum)), Path: path}
+	}
+
+	switch val := value.(type) {
+	case float64:
+		return checkNumber(s, val, path)
+	case string:
+		return checkLength(s, "minLength", "maxLength", utf8.RuneCountInString(val), "characters", path)
+	case []any:
+		return v.validateArray(s, val, path)
+	case map[string]any:
+		return v.validateObject(s, val, path)
+	default:
+		return nil
+	}
+}
+
+// validateArray checks the items and item count of an array.
+func (v *validator) validateArray(s map[string]any, value []any, path string) error {
+	if err := checkLength(s, "minItems", "maxItems", len(value), "items", path); err != nil {
+		return err
+	}
+
+	items, ok := s["items"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for i, item := range value {
+		if err := v.validate(items, item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateObject checks the required, declared, and additional properties of an object.
+func (v *validator) validateObject(s map[string]any, value map[string]any, path string) error {
+	for _, name := range stringList(s["required"]) {
+		if _, ok := value[name]; !ok {
+			return &ValidationError{Message: fmt.Sprintf("missing required property %q", name), Path: path}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]any)
+
+	// Check properties in a stable order so that errors are deterministic.
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		childPath := path + "/" + escapePointer(name)
+
+		if property, ok := properties[name].(map[string]any); ok {
+			if err := v.validate(property, value[name], childPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return &ValidationError{Message: fmt.Sprintf("unexpected property %q", name), Path: path}
+			}
+		case map[string]any:
+			if err := v.validate(additional, value[name], childPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the schema a local $ref points to.
+func (v *validator) resolve(ref string) (map[string]any, error) {
+	if ref == rootRef {
+		return v.root, nil
+	}
+
+	name, ok := strings.CutPrefix(ref, defsPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+
+	defs, _ := v.root[keyDefs].(map[string]any)
+	target, ok := defs[name].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+
+	return target, nil
+}
+
+// checkLength checks a length against the min and max keywords of s.
+func checkLength(s map[string]any, minKey, maxKey string, n int, unit, path string) error {
+	if limit, ok := toFloat(s[minKey]); ok && float64(n) < limit {
+		return &ValidationError{Message: fmt.Sprintf("has %d %s, want at least %v", n, unit, limit), Path: path}
+	}
+	if limit, ok := toFloat(s[maxKey]); ok && float64(n) > limit {
+		return &ValidationError{Message: fmt.Sprintf("has %d %s, want at most %v", n, unit, limit), Path: path}
+	}
+
+	return nil
+}
+
+// checkNumber checks a number against the minimum and maximum keywords of s.
+func checkNumber(s map[string]any, value float64, path string) error {
+	if limit, ok := toFloat(s["minimum"]); ok && value < limit {
+		return &ValidationError{Message: fmt.Sprintf("value %v is less than the minimum %v", value, limit), Path: path}
+	}
+	if limit, ok := toFloat(s["maximum"]); ok && value > limit {
+		return &ValidationError{Message: fmt.Sprintf("value %v is greater than the maximum %v", value, limit), Path: path}
+	}
+
+	return nil
+}
+
+// checkType checks value against the type keyword, which may be a single type or a list of types.
+func checkType(t any, value any, path string) error {
+	types := stringList(t)
+	for _, name := range types {
+		if hasType(name, value) {
+			return nil
+		}
+	}
+
+	return &ValidationError{
+		Message: fmt.Sprintf("got %s, want %s", typeOf(value), strings.Join(types, " or ")),
+		Path:    path,
+	}
+}
+
+// containsValue reports whether the enum list contains value.
+func containsValue(enum any, value any) bool {
+	list := reflect.ValueOf(enum)
+	if list.Kind() != reflect.Slice {
+		return true
+	}
+
+	for i := range list.Len() {
+		if equalValues(list.Index(i).Interface(), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encode returns the JSON encoding of v for error messages.
+func encode(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+// equalValues compares a schema value with a decoded JSON value, treating all numbers alike.
+func equalValues(want any, got any) bool {
+	if w, ok := toFloat(want); ok {
+		g, ok := got.(float64)
+		return ok && w == g
+	}
+
+	return reflect.DeepEqual(want, got)
+}
+
+// escapePointer escapes a property name for use in a JSON Pointer.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// hasType reports whether value is of the named JSON type.
+func hasType(name string, value any) bool {
+	switch name {
+	case typeArray:
+		_, ok := value.([]any)
+		return ok
+	case typeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case typeInteger:
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return value == nil
+	case typeNumber:
+		_, ok := value.(float64)
+		return ok
+	case typeObject:
+		_, ok := value.(map[string]any)
+		return ok
+	case typeString:
+		_, ok := value.(string)
+		return ok
+	default:
+		return false
+	}
+}
+
+// stringList converts a []string or a decoded JSON []any of strings to a []string.
+// A single string becomes a one-element list.
+func stringList(v any) []string {
+	switch list := v.(type) {
+	case string:
+		return []string{list}
+	case []string:
+		return list
+	case []any:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// toFloat converts a numeric schema value to a float64.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// typeOf returns the JSON type name of a decoded value.
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return typeBoolean
+	case float64:
+		return typeNumber
+	case string:
+		return typeString
+	case []any:
+		return typeArray
+	case map[string]any:
+		return typeObject
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
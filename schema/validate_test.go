@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	ID       string  `json:"id" min:"2"`
+	Items    []item  `json:"items" min:"1"`
+	Note     string  `json:"note,omitempty"`
+	Priority string  `json:"priority" enum:"low,high"`
+	Total    float64 `json:"total" min:"0"`
+}
+
+type item struct {
+	Quantity int    `json:"quantity" min:"1" max:"10"`
+	SKU      string `json:"sku"`
+}
+
+func TestValidateJSON(t *testing.T) {
+	t.Parallel()
+
+	orderSchema, err := For[order]()
+	require.NoError(t, err)
+
+	nodeSchema, err := For[tree]()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		schema   map[string]any
+		data     string
+		wantPath string
+		wantErr  string
+	}{
+		"valid object": {
+			schema: orderSchema,
+			data:   `{"id":"A1","items":[{"quantity":2,"sku":"X"}],"priority":"low","total":9.5}`,
+		},
+		"invalid JSON": {
+			schema:  orderSchema,
+			data:    `{"id":`,
+			wantErr: "invalid JSON",
+		},
+		"wrong root type": {
+			schema:  orderSchema,
+			data:    `[]`,
+			wantErr: "got array, want object",
+		},
+		"missing required property": {
+			schema:  orderSchema,
+			data:    `{"id":"A1","items":[{"quantity":2,"sku":"X"}],"total":1}`,
+			wantErr: `missing required property "priority"`,
+		},
+		"unexpected property": {
+			schema:  orderSchema,
+			data:    `{"id":"A1","items":[{"quantity":2,"sku":"X"}],"priority":"low","total":1,"extra":true}`,
+			wantErr: `unexpected property "extra"`,
+		},
+		"value not in enum": {
+			schema:   orderSchema,
+			data:     `{"id":"A1","items":[{"quantity":2,"sku":"X"}],"priority":"urgent","total":1}`,
+			wantPath: "/priority",
+			wantErr:  `value "urgent" is not one of ["low","high"]`,
+		},
+		"string too short": {
+			schema:   orderSchema,
+			data:     `{"id":"A","items":[{"quantity":2,"sku":"X"}],"priority":"low","total":1}`,
+			wantPath: "/id",
+			wantErr:  "has 1 characters, want at least 2",
+		},
+		"too few items": {
+			schema:   orderSchema,
+			data:     `{"id":"A1","items":[],"priority":"low","total":1}`,
+			wantPath: "/items",
+			wantErr:  "want at least 1",
+		},
+		"number below minimum": {
+			schema:   orderSchema,
+			data:     `{"id":"A1","items":[{"quantity":2,"sku":"X"}],"priority":"low","total":-1}`,
+			wantPath: "/total",
+			wantErr:  "less than the minimum",
+		},
+		"nested value above maximum": {
+			schema:   orderSchema,
+			data:     `{"id":"A1","items":[{"quantity":20,"sku":"X"}],"priority":"low","total":1}`,
+			wantPath: "/items/0/quantity",
+			wantErr:  "greater than the maximum",
+		},
+		"fractional integer": {
+			schema:   orderSchema,
+			data:     `{"id":"A1","items":[{"quantity":1.5,"sku":"X"}],"priority":"low","total":1}`,
+			wantPath: "/items/0/quantity",
+			wantErr:  "got number, want integer",
+		},
+		"valid recursive value": {
+			schema: nodeSchema,
+			data:   `{"name":"t","root":{"value":1,"children":[{"value":2,"children":[{"value":3}]}]}}`,
+		},
+		"invalid recursive value": {
+			schema:   nodeSchema,
+			data:     `{"name":"t","root":{"value":1,"children":[{"value":"two"}]}}`,
+			wantPath: "/root/children/0/value",
+			wantErr:  "got string, want integer",
+		},
+		"unresolved reference": {
+			schema:  map[string]any{"$ref": "#/$defs/missing"},
+			data:    `{}`,
+			wantErr: "unresolved $ref",
+		},
+		"type list": {
+			schema: map[string]any{"type": []any{"string", "null"}},
+			data:   `null`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateJSON(tc.schema, []byte(tc.data))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Contains(t, validationErr.Message, tc.wantErr)
+			require.Equal(t, tc.wantPath, validationErr.Path)
+		})
+	}
+}